@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ActivityController struct {
+	activityService services.ActivityServiceInterface
+	logger          logger.Logger
+	validator       *validator.Validate
+}
+
+func NewActivityController(activityService services.ActivityServiceInterface, logger logger.Logger) *ActivityController {
+	return &ActivityController{
+		activityService: activityService,
+		logger:          logger,
+		validator:       validator.New(),
+	}
+}
+
+func (h *ActivityController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters")
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// GetActivity handles GET /api/v1/jobs/{id}/activity
+// @Summary Get job activity
+// @Description Get the activity feed of a job, newest first: field changes, status changes, assignments, checklist items, attachments and comments. Pass the nextCursor of a page as cursor to get the next page.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Entries per page (default 50, max 100)"
+// @Success 200 {object} models.APIResponse{data=models.ActivityPage} "Activity retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid cursor"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/activity [get]
+func (h *ActivityController) GetActivity(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	limit := 0
+	if limitParam := c.Query("limit"); limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	page, err := h.activityService.GetActivity(c.Request.Context(), id, c.Query("cursor"), limit)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get job activity", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job activity",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Activity retrieved successfully",
+		Data:    page,
+	})
+}
+
+// CreateComment handles POST /api/v1/jobs/{id}/comments
+// @Summary Comment on a job
+// @Description Post a comment to the activity feed of a job. Users of the job's organization are mentioned with @username. Set parentID to reply to another comment of the job.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.CreateCommentRequest true "Comment"
+// @Success 201 {object} models.APIResponse{data=models.Activity} "Comment posted successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job or parent comment not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/comments [post]
+func (h *ActivityController) CreateComment(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	comment, err := h.activityService.CreateComment(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to post comment", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to post comment",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Comment posted successfully",
+		Data:    comment,
+	})
+}
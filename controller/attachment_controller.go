@@ -0,0 +1,466 @@
+package controller
+
+import (
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/logger"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadFiles is the number of files one upload request may carry
+const maxUploadFiles = 10
+
+type AttachmentController struct {
+	attachmentService services.AttachmentServiceInterface
+	store             blob.BlobStore
+	logger            logger.Logger
+}
+
+func NewAttachmentController(attachmentService services.AttachmentServiceInterface, store blob.BlobStore, logger logger.Logger) *AttachmentController {
+	return &AttachmentController{
+		attachmentService: attachmentService,
+		store:             store,
+		logger:            logger,
+	}
+}
+
+// UploadAttachment handles POST /api/v1/jobs/{id}/attachments
+// @Summary Upload job attachments
+// @Description Upload up to 10 photos or documents to a job as multipart/form-data. The content type is detected from the content: before and after photos must be JPEG, PNG, GIF or WebP images, documents may also be PDF or plain text. Images get a thumbnail. Download links in the response are signed and expire after a few minutes.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param category formData string true "Attachment category" Enums(before, after, document)
+// @Param file formData file true "File to upload, may be repeated"
+// @Success 201 {object} models.APIResponse{data=[]models.Attachment} "Attachments uploaded successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 413 {object} models.APIResponse "File too large"
+// @Failure 415 {object} models.APIResponse "Unsupported file type"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/attachments [post]
+func (h *AttachmentController) UploadAttachment(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	maxSize := h.attachmentService.MaxUploadSize()
+	// Leave room for the multipart framing around the files
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize*maxUploadFiles+1<<20)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		h.logger.Error("Failed to parse multipart form:", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	category := models.AttachmentCategory(strings.TrimSpace(c.PostForm("category")))
+	switch category {
+	case models.AttachmentCategoryBefore, models.AttachmentCategoryAfter, models.AttachmentCategoryDocument:
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "category must be one of: before, after, document",
+			},
+		})
+		return
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 || len(files) > maxUploadFiles {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "between 1 and 10 files are required in the file field",
+			},
+		})
+		return
+	}
+
+	for _, file := range files {
+		if file.Size > maxSize {
+			c.JSON(http.StatusRequestEntityTooLarge, models.APIResponse{
+				Status:  "error",
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: "File too large",
+				Error: &models.APIError{
+					Type:    "ValidationError",
+					Details: file.Filename + " exceeds the upload size limit",
+				},
+			})
+			return
+		}
+	}
+
+	attachments := make([]*models.Attachment, 0, len(files))
+	for _, file := range files {
+		body, err := file.Open()
+		if err != nil {
+			h.logger.Error("Failed to open uploaded file", err)
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Invalid request",
+				Error: &models.APIError{
+					Type:    "ValidationError",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+
+		attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), id, category, file.Filename, body, jwtClaims.UserID)
+		body.Close()
+		if err != nil {
+			statusCode := databaseErrorStatus(err)
+			h.logger.Error("Failed to upload attachment", err)
+			c.JSON(statusCode, models.APIResponse{
+				Status:  "error",
+				Code:    statusCode,
+				Message: "Failed to upload " + file.Filename,
+				Data:    attachments,
+				Error: &models.APIError{
+					Type:    "UploadError",
+					Details: err.Error(),
+				},
+			})
+			return
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Attachments uploaded successfully",
+		Data:    attachments,
+	})
+}
+
+// GetAttachments handles GET /api/v1/jobs/{id}/attachments
+// @Summary List job attachments
+// @Description List the photos and documents of a job with signed download links
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.APIResponse{data=[]models.Attachment} "Attachments retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/attachments [get]
+func (h *AttachmentController) GetAttachments(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get attachments", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get attachments",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attachments retrieved successfully",
+		Data:    attachments,
+	})
+}
+
+// GetAttachment handles GET /api/v1/jobs/{id}/attachments/{attachmentID}
+// @Summary Get job attachment
+// @Description Get an attachment of a job with fresh signed download links
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {object} models.APIResponse{data=models.Attachment} "Attachment retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Job or attachment not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/attachments/{attachmentID} [get]
+func (h *AttachmentController) GetAttachment(c *gin.Context) {
+	id := c.Param("id")
+	attachmentID := c.Param("attachmentID")
+	if id == "" || attachmentID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID and attachment ID are required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID or attachment ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get attachment", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get attachment",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attachment retrieved successfully",
+		Data:    attachment,
+	})
+}
+
+// DeleteAttachment handles DELETE /api/v1/jobs/{id}/attachments/{attachmentID}
+// @Summary Delete job attachment
+// @Description Delete an attachment of a job and remove its files from storage. Sign-off attachments, the customer signature and completion summary, cannot be deleted.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Attachment deleted successfully"
+// @Failure 404 {object} models.APIResponse "Job or attachment not found"
+// @Failure 409 {object} models.APIResponse "Sign-off attachments cannot be deleted"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/attachments/{attachmentID} [delete]
+func (h *AttachmentController) DeleteAttachment(c *gin.Context) {
+	id := c.Param("id")
+	attachmentID := c.Param("attachmentID")
+	if id == "" || attachmentID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID and attachment ID are required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID or attachment ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.attachmentService.DeleteAttachment(c.Request.Context(), id, attachmentID, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete attachment", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete attachment",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Attachment deleted successfully",
+	})
+}
+
+// DownloadBlob handles GET /api/v1/blobs/{key}
+// @Summary Download a file
+// @Description Serve a file of the local blob store through a signed link handed out with an attachment. Not used with S3 storage, whose links point at the bucket.
+// @Tags Job Management
+// @Produce octet-stream
+// @Param key path string true "Blob key"
+// @Param expires query string true "Expiry of the link (Unix seconds)"
+// @Param signature query string true "Signature of the link"
+// @Success 200 {file} file "File content"
+// @Failure 403 {object} models.APIResponse "Invalid or expired link"
+// @Failure 404 {object} models.APIResponse "File not found"
+// @Router /blobs/{key} [get]
+func (h *AttachmentController) DownloadBlob(c *gin.Context) {
+	local, ok := h.store.(*blob.LocalStore)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "File not found",
+			Error: &models.APIError{
+				Type:    "NotFoundError",
+				Details: "Files are served by the blob store",
+			},
+		})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "Invalid download link",
+			Error: &models.APIError{
+				Type:    "AuthorizationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	path, err := local.Path(key)
+	if err == nil {
+		_, err = os.Stat(path)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusNotFound,
+			Message: "File not found",
+			Error: &models.APIError{
+				Type:    "NotFoundError",
+				Details: "The file no longer exists",
+			},
+		})
+		return
+	}
+
+	// Files are served as uploaded; the sandbox keeps SVG signatures and
+	// other documents from running script when opened directly
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+	c.Header("Cache-Control", "private, no-store")
+	c.File(path)
+}
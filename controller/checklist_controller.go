@@ -0,0 +1,449 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ChecklistController struct {
+	checklistService services.ChecklistServiceInterface
+	logger           logger.Logger
+	validator        *validator.Validate
+}
+
+func NewChecklistController(checklistService services.ChecklistServiceInterface, logger logger.Logger) *ChecklistController {
+	return &ChecklistController{
+		checklistService: checklistService,
+		logger:           logger,
+		validator:        validator.New(),
+	}
+}
+
+func (h *ChecklistController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters/items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters/items")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// CreateChecklistTemplate handles POST /api/v1/checklist-templates
+// @Summary Create a checklist template
+// @Description Create a checklist template of an organization. Its items are copied onto every job of its job type, or onto every job when no job type is given, created afterwards.
+// @Tags Checklists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateChecklistTemplateRequest true "Create checklist template request"
+// @Success 201 {object} models.APIResponse{data=models.ChecklistTemplate} "Checklist template created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid template"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Checklist template creation failed"
+// @Router /checklist-templates [post]
+func (h *ChecklistController) CreateChecklistTemplate(c *gin.Context) {
+	var req models.CreateChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	template, err := h.checklistService.CreateChecklistTemplate(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create checklist template", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create checklist template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Checklist template created successfully",
+		Data:    template,
+	})
+}
+
+// GetChecklistTemplates handles GET /api/v1/checklist-templates
+// @Summary List checklist templates
+// @Description Retrieve the checklist templates of an organization
+// @Tags Checklists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Success 200 {object} models.APIResponse{data=[]models.ChecklistTemplate} "Checklist templates retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Organization ID missing"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve checklist templates"
+// @Router /checklist-templates [get]
+func (h *ChecklistController) GetChecklistTemplates(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	templates, err := h.checklistService.GetChecklistTemplates(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get checklist templates", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get checklist templates",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Checklist templates retrieved successfully",
+		Data:    templates,
+	})
+}
+
+// GetChecklistTemplate handles GET /api/v1/checklist-templates/{id}
+// @Summary Get checklist template by ID
+// @Description Get a specific checklist template by its ID
+// @Tags Checklists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist template ID"
+// @Success 200 {object} models.APIResponse{data=models.ChecklistTemplate} "Checklist template retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Checklist template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /checklist-templates/{id} [get]
+func (h *ChecklistController) GetChecklistTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Checklist template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Checklist template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	template, err := h.checklistService.GetChecklistTemplate(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get checklist template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get checklist template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Checklist template retrieved successfully",
+		Data:    template,
+	})
+}
+
+// UpdateChecklistTemplate handles PUT /api/v1/checklist-templates/{id}
+// @Summary Update checklist template
+// @Description Update a checklist template. Items, when given, replace the items of the template. Jobs created before keep their checklist.
+// @Tags Checklists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist template ID"
+// @Param request body models.UpdateChecklistTemplateRequest true "Update checklist template request"
+// @Success 200 {object} models.APIResponse{data=models.ChecklistTemplate} "Checklist template updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Checklist template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /checklist-templates/{id} [put]
+func (h *ChecklistController) UpdateChecklistTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Checklist template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Checklist template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	template, err := h.checklistService.UpdateChecklistTemplate(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update checklist template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update checklist template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Checklist template updated successfully",
+		Data:    template,
+	})
+}
+
+// DeleteChecklistTemplate handles DELETE /api/v1/checklist-templates/{id}
+// @Summary Delete checklist template
+// @Description Soft-delete a checklist template. Jobs keep the items copied from it.
+// @Tags Checklists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Checklist template ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Checklist template deleted successfully"
+// @Failure 404 {object} models.APIResponse "Checklist template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /checklist-templates/{id} [delete]
+func (h *ChecklistController) DeleteChecklistTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Checklist template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Checklist template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.checklistService.DeleteChecklistTemplate(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete checklist template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete checklist template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Checklist template deleted successfully",
+	})
+}
@@ -7,6 +7,10 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/repository"
 	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/geo"
+	"fieldfuze-backend/utils/notify"
+	"fieldfuze-backend/utils/search"
 
 	"fieldfuze-backend/utils/swagger"
 	"net/http"
@@ -22,9 +26,20 @@ type Controller struct {
 	Infrastructure *InfrastructureController
 	Organization   *OrganizationController
 	Job            *JobController
+	RecurringJob   *RecurringJobController
+	Checklist      *ChecklistController
+	Attachment     *AttachmentController
+	Activity       *ActivityController
+	Timesheet      *TimesheetController
+	Dispatch       *DispatchController
+	SLAPolicy      *SLAPolicyController
+	JobTemplate    *JobTemplateController
+	Project        *ProjectController
 }
 
-func NewController(ctx context.Context, cfg *models.Config, log logger.Logger) *Controller {
+// NewController creates the controllers. Jobs are indexed for search in
+// jobIndex, shared with the workers of the process.
+func NewController(ctx context.Context, cfg *models.Config, log logger.Logger, jobIndex search.Index) *Controller {
 	// Initialize DAL container
 	dalContainer, err := dal.NewDALContainer(cfg, log)
 	if err != nil {
@@ -32,31 +47,77 @@ func NewController(ctx context.Context, cfg *models.Config, log logger.Logger) *
 	}
 
 	// Initialize repository container
-	repoContainer := repository.NewRepository(dalContainer, cfg, log)
+	repoContainer := repository.NewRepository(dalContainer, cfg, log, jobIndex)
+
+	// Initialize blob storage for job attachments
+	blobStore, err := blob.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
+
+	// Initialize the travel estimates of route optimization
+	routeMatrix, err := geo.NewDistanceMatrix(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize distance matrix: %v", err)
+	}
+
+	// Initialize the delivery of alerts such as SLA breaches
+	notifier, err := notify.New(cfg, log)
+	if err != nil {
+		log.Fatalf("Failed to initialize notifier: %v", err)
+	}
 
 	// Initialize service container
-	serviceContainer := services.NewService(ctx, repoContainer, dalContainer, log, cfg)
+	serviceContainer := services.NewService(ctx, repoContainer, dalContainer, blobStore, routeMatrix, notifier, log, cfg)
 
-	// JWT Manager still needs concrete user repository for authentication
-	userRepo := repository.NewUserRepository(dalContainer.GetDatabaseClient(), cfg, log)
-	jwtManager := middelware.NewJWTManager(cfg, log, userRepo)
+	// JWT Manager shares the cached user repository so writes made through
+	// the services invalidate the records used for authentication
+	jwtManager := middelware.NewJWTManager(cfg, log, repoContainer.GetUserRepository())
 
 	return &Controller{
-		User:           NewUserController(ctx, serviceContainer.GetUserService(), log, jwtManager),
-		Role:           NewRoleController(ctx, serviceContainer.GetRoleService(), log),
-		Infrastructure: NewInfrastructureController(ctx, serviceContainer.GetInfrastructureService(), log),
-		Organization:   NewOrganizationController(ctx, serviceContainer.GetOrganizationService(), log),
-		Job:            NewJobController(ctx, serviceContainer.GetJobService(), log),
+		User:           NewUserController(serviceContainer.GetUserService(), log, jwtManager),
+		Role:           NewRoleController(serviceContainer.GetRoleService(), log),
+		Infrastructure: NewInfrastructureController(serviceContainer.GetInfrastructureService(), log),
+		Organization:   NewOrganizationController(serviceContainer.GetOrganizationService(), log),
+		Job:            NewJobController(serviceContainer.GetJobService(), log),
+		RecurringJob:   NewRecurringJobController(serviceContainer.GetRecurringJobService(), log),
+		Checklist:      NewChecklistController(serviceContainer.GetChecklistService(), log),
+		Attachment:     NewAttachmentController(serviceContainer.GetAttachmentService(), blobStore, log),
+		Activity:       NewActivityController(serviceContainer.GetActivityService(), log),
+		Timesheet:      NewTimesheetController(serviceContainer.GetTimesheetService(), log),
+		Dispatch:       NewDispatchController(serviceContainer.GetDispatchService(), log),
+		SLAPolicy:      NewSLAPolicyController(serviceContainer.GetSLAService(), log),
+		JobTemplate:    NewJobTemplateController(serviceContainer.GetJobTemplateService(), log),
+		Project:        NewProjectController(serviceContainer.GetProjectService(), log),
 	}
 }
 
 func (c *Controller) RegisterRoutes(ctx context.Context, config *models.Config, r *gin.Engine, basePath string) error {
+	c.setupRoutes(config, r, basePath)
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:    config.AppHost + ":" + config.AppPort,
+		Handler: r,
+	}
+	// Start server
+	logger := logger.NewLogger(config.LogLevel, config.LogFormat)
+	logger.Infof("🚀 Starting server on %s:%s", config.AppHost, config.AppPort)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// setupRoutes registers the middleware and routes of the API on r
+func (c *Controller) setupRoutes(config *models.Config, r *gin.Engine, basePath string) {
 	// Apply CORS middleware globally
 	corsMiddleware := middelware.NewCORSMiddleware(config)
 	r.Use(corsMiddleware.CORS())
 
 	// Add request logging middleware
 	loggingMiddleware := middelware.NewLoggingMiddleware(logger.NewLogger(config.LogLevel, config.LogFormat))
+	r.Use(loggingMiddleware.RequestID())
 	r.Use(loggingMiddleware.StructuredLogger())
 	r.Use(loggingMiddleware.Recovery())
 
@@ -105,16 +166,26 @@ func (c *Controller) RegisterRoutes(ctx context.Context, config *models.Config,
 	user.GET("/list", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("user_list"), c.User.GetUserList)          // Resource-specific: user list with department scope
 	user.PATCH("/update/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("user_update"), c.User.UpdateUser) // Resource-specific: user update with ownership check
 
+	// Technician availability routes - working hours and time off used for job assignment checks
+	user.GET("/:id/availability", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("availability_view"), c.Job.GetAvailability)   // Resource-specific: availability with level 2+ requirement
+	user.PUT("/:id/availability", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("availability_update"), c.Job.SetAvailability) // Resource-specific: availability update with level 5+ requirement
+
+	// Weekly timesheets
+	user.GET("/:id/timesheets", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("timesheet_view"), c.Timesheet.GetTimesheet)                            // Resource-specific: timesheet with level 3+ requirement
+	user.POST("/:user_id/timesheets/:week/approve", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("timesheet_approve"), c.Timesheet.ApproveTimesheet) // Resource-specific: timesheet approval with level 7+ requirement
+	user.POST("/:user_id/timesheets/:week/reopen", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("timesheet_approve"), c.Timesheet.ReopenTimesheet)   // Resource-specific: timesheet reopening with level 7+ requirement
+
 	// Role assignment routes - resource-specific permissions with level requirements
 	user.POST("/:user_id/role/:role_id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_assign"), c.User.AssignRole)   // Resource-specific: role assignment with level 7+ requirement
 	user.DELETE("/:user_id/role/:role_id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_assign"), c.User.DetachRole) // Resource-specific: role assignment with level 7+ requirement
 
 	// Role management routes - resource-specific permissions with context validation
-	user.GET("/role", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_list"), c.Role.GetRoles)            // Resource-specific: role list with department scope
-	user.POST("/role", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_create"), c.Role.CreateRole)       // Resource-specific: role creation with level 6+ requirement
-	user.GET("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_list"), c.Role.GetRole)         // Resource-specific: role details with department scope
-	user.PUT("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_update"), c.Role.UpdateRole)    // Resource-specific: role update with level 6+ requirement
-	user.DELETE("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_delete"), c.Role.DeleteRole) // Resource-specific: role deletion with level 8+ requirement
+	user.GET("/role", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_list"), c.Role.GetRoles)                    // Resource-specific: role list with department scope
+	user.POST("/role", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_create"), c.Role.CreateRole)               // Resource-specific: role creation with level 6+ requirement
+	user.GET("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_list"), c.Role.GetRole)                 // Resource-specific: role details with department scope
+	user.PUT("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_update"), c.Role.UpdateRole)            // Resource-specific: role update with level 6+ requirement
+	user.DELETE("/role/:id", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_delete"), c.Role.DeleteRole)         // Resource-specific: role deletion with level 8+ requirement
+	user.POST("/role/:id/restore", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequireResourcePermission("role_restore"), c.Role.RestoreRole) // Resource-specific: role restore with level 8+ requirement
 
 	// Infrastructure routes (require admin permissions)
 	infra := v1.Group("/infrastructure", c.User.jwtManager.AuthMiddleware(), c.User.jwtManager.RequirePermission("admin"))
@@ -136,35 +207,136 @@ func (c *Controller) RegisterRoutes(ctx context.Context, config *models.Config,
 		organization.GET("", c.Organization.GetOrganizations)
 		// organization.GET("/:id", c.Organization.GetOrganizationByID)
 		// organization.PUT("/:id", c.Organization.UpdateOrganization)
-		// organization.DELETE("/:id", c.Organization.DeleteOrganization)
+		organization.DELETE("/:id", c.Organization.DeleteOrganization)
+		organization.POST("/:id/restore", c.Organization.RestoreOrganization)
 	}
 
 	// Job management routes with role-based access control
 	jobs := v1.Group("/jobs", c.User.jwtManager.AuthMiddleware())
 	{
 		// Basic CRUD operations with specific role permissions
-		jobs.POST("", c.User.jwtManager.RequireResourcePermission("job_create"), c.Job.CreateJob)                    // Create new job - requires JobDispatcher+ role
-		jobs.GET("", c.User.jwtManager.RequireResourcePermission("job_list"), c.Job.GetJobs)                         // Get jobs with filtering/pagination - requires JobViewer+ role
-		jobs.GET("/:id", c.User.jwtManager.RequireResourcePermission("job_details"), c.Job.GetJobByID)               // Get specific job by ID - requires JobViewer+ role
-		jobs.PUT("/:id", c.User.jwtManager.RequireResourcePermission("job_update"), c.Job.UpdateJob)                 // Update job - requires FieldWorker+ role
-		jobs.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("job_delete"), c.Job.DeleteJob)              // Delete job - requires JobSupervisor+ role
-		
+		jobs.POST("", c.User.jwtManager.RequireResourcePermission("job_create"), c.Job.CreateJob)               // Create new job - requires JobDispatcher+ role
+		jobs.GET("", c.User.jwtManager.RequireResourcePermission("job_list"), c.Job.GetJobs)                    // Get jobs with filtering/pagination - requires JobViewer+ role
+		jobs.GET("/calendar", c.User.jwtManager.RequireResourcePermission("job_list"), c.Job.GetCalendar)       // Dispatcher calendar by technician and day - requires JobViewer+ role
+		jobs.GET("/:id", c.User.jwtManager.RequireResourcePermission("job_details"), c.Job.GetJobByID)          // Get specific job by ID - requires JobViewer+ role
+		jobs.PUT("/:id", c.User.jwtManager.RequireResourcePermission("job_update"), c.Job.UpdateJob)            // Update job - requires FieldWorker+ role
+		jobs.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("job_delete"), c.Job.DeleteJob)         // Delete job - requires JobSupervisor+ role
+		jobs.POST("/:id/restore", c.User.jwtManager.RequireResourcePermission("job_restore"), c.Job.RestoreJob) // Restore deleted job - requires JobSupervisor+ role
+
 		// Job status management with granular permissions
-		jobs.POST("/:id/start", c.User.jwtManager.RequireResourcePermission("job_start"), c.Job.StartJob)            // Start a job - requires FieldWorker+ role
-		jobs.POST("/:id/complete", c.User.jwtManager.RequireResourcePermission("job_complete"), c.Job.CompleteJob)   // Complete a job - requires FieldWorker+ role
-		jobs.POST("/:id/cancel", c.User.jwtManager.RequireResourcePermission("job_cancel"), c.Job.CancelJob)         // Cancel a job - requires JobManager+ role
+		jobs.POST("/:id/start", c.User.jwtManager.RequireResourcePermission("job_start"), c.Job.StartJob)          // Start a job - requires FieldWorker+ role
+		jobs.POST("/:id/complete", c.User.jwtManager.RequireResourcePermission("job_complete"), c.Job.CompleteJob) // Complete a job - requires FieldWorker+ role
+		jobs.POST("/:id/cancel", c.User.jwtManager.RequireResourcePermission("job_cancel"), c.Job.CancelJob)       // Cancel a job - requires JobManager+ role
+		jobs.POST("/:id/hold", c.User.jwtManager.RequireResourcePermission("job_hold"), c.Job.HoldJob)             // Put a job on hold - requires JobManager+ role
+		jobs.POST("/:id/resume", c.User.jwtManager.RequireResourcePermission("job_hold"), c.Job.ResumeJob)         // Resume a job on hold - requires JobManager+ role
+
+		// Completion summaries of signed-off jobs are emailed to the customer
+		jobs.POST("/:id/completion-summary/send", c.User.jwtManager.RequireResourcePermission("job_completion_summary"), c.Job.SendCompletionSummary) // Email the completion summary again - requires FieldWorker+ role
+
+		// Supervisors let technicians start jobs away from the site under the
+		// override geofence policy
+		jobs.POST("/:id/geofence-override", c.User.jwtManager.RequireResourcePermission("job_geofence_override"), c.Job.GrantGeofenceOverride) // Override the geofence of the next start - requires Supervisor+ role
+
+		// Checklist items are completed one at a time by the technicians on site
+		jobs.PUT("/:id/checklist/:itemID", c.User.jwtManager.RequireResourcePermission("job_checklist"), c.Job.UpdateChecklistItem) // Complete or reopen a checklist item - requires FieldWorker+ role
+
+		// Photos and documents attached to jobs
+		jobs.POST("/:id/attachments", c.User.jwtManager.RequireResourcePermission("attachment_upload"), c.Attachment.UploadAttachment)                 // Upload attachments - requires FieldWorker+ role
+		jobs.GET("/:id/attachments", c.User.jwtManager.RequireResourcePermission("attachment_list"), c.Attachment.GetAttachments)                      // List attachments with signed links - requires JobViewer+ role
+		jobs.GET("/:id/attachments/:attachmentID", c.User.jwtManager.RequireResourcePermission("attachment_list"), c.Attachment.GetAttachment)         // Get an attachment with signed links - requires JobViewer+ role
+		jobs.DELETE("/:id/attachments/:attachmentID", c.User.jwtManager.RequireResourcePermission("attachment_delete"), c.Attachment.DeleteAttachment) // Delete an attachment - requires JobManager+ role
+
+		// Activity feed and comments
+		jobs.GET("/:id/activity", c.User.jwtManager.RequireResourcePermission("job_activity"), c.Activity.GetActivity)   // Get the activity feed - requires JobViewer+ role
+		jobs.POST("/:id/comments", c.User.jwtManager.RequireResourcePermission("job_comment"), c.Activity.CreateComment) // Post a comment - requires FieldWorker+ role
+
+		// Technician time tracking
+		jobs.POST("/:id/time/clock-in", c.User.jwtManager.RequireResourcePermission("time_tracking"), c.Timesheet.ClockIn)   // Clock in as labor or travel - requires FieldWorker+ role
+		jobs.POST("/:id/time/pause", c.User.jwtManager.RequireResourcePermission("time_tracking"), c.Timesheet.PauseTime)    // Pause running time - requires FieldWorker+ role
+		jobs.POST("/:id/time/resume", c.User.jwtManager.RequireResourcePermission("time_tracking"), c.Timesheet.ResumeTime)  // Resume paused time - requires FieldWorker+ role
+		jobs.POST("/:id/time/clock-out", c.User.jwtManager.RequireResourcePermission("time_tracking"), c.Timesheet.ClockOut) // Clock out - requires FieldWorker+ role
+		jobs.GET("/:id/time", c.User.jwtManager.RequireResourcePermission("time_entry_list"), c.Timesheet.GetJobTimeEntries) // List time on the job - requires JobViewer+ role
+
+		// Creating jobs from templates and completed jobs
+		jobs.POST("/from-template/:templateId", c.User.jwtManager.RequireResourcePermission("job_create"), c.Job.CreateJobFromTemplate) // Create a job from a template - requires JobDispatcher+ role
+		jobs.POST("/:id/clone", c.User.jwtManager.RequireResourcePermission("job_create"), c.Job.CloneJob)                              // Clone a completed job - requires JobDispatcher+ role
+
+		// Bulk import and export
+		jobs.POST("/import", c.User.jwtManager.RequireResourcePermission("job_import"), c.Job.ImportJobs)            // Import jobs from CSV - requires JobManager+ role
+		jobs.GET("/import/:importId", c.User.jwtManager.RequireResourcePermission("job_import"), c.Job.GetJobImport) // Follow a running import - requires JobManager+ role
+		jobs.GET("/export", c.User.jwtManager.RequireResourcePermission("job_export"), c.Job.ExportJobs)             // Export jobs as CSV or XLSX - requires JobDispatcher+ role
+
+		// Full-text search
+		jobs.GET("/search", c.User.jwtManager.RequireResourcePermission("job_search"), c.Job.SearchJobs) // Search the jobs of the caller's organization - requires JobViewer+ role
+
+		// Jobs waiting for other jobs
+		jobs.POST("/:id/dependencies", c.User.jwtManager.RequireResourcePermission("job_dependency"), c.Job.AddJobDependency)                 // Block a job by another job - requires JobDispatcher+ role
+		jobs.DELETE("/:id/dependencies/:blockerId", c.User.jwtManager.RequireResourcePermission("job_dependency"), c.Job.RemoveJobDependency) // Unblock a job - requires JobDispatcher+ role
 	}
 
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:    config.AppHost + ":" + config.AppPort,
-		Handler: r,
+	// Time entry corrections
+	timeEntries := v1.Group("/time-entries", c.User.jwtManager.AuthMiddleware())
+	{
+		timeEntries.PUT("/:entryID", c.User.jwtManager.RequireResourcePermission("time_entry_update"), c.Timesheet.UpdateTimeEntry) // Correct a time entry - requires JobManager+ role
 	}
-	// Start server
-	logger := logger.NewLogger(config.LogLevel, config.LogFormat)
-	logger.Infof("🚀 Starting server on %s:%s", config.AppHost, config.AppPort)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return err
+
+	// Dispatch planning
+	dispatch := v1.Group("/dispatch", c.User.jwtManager.AuthMiddleware())
+	{
+		dispatch.POST("/optimize", c.User.jwtManager.RequireResourcePermission("dispatch_optimize"), c.Dispatch.OptimizeRoutes) // Optimize technician routes - requires Dispatcher+ role
+	}
+
+	// Signed download links of the local blob store; the signature replaces authentication
+	v1.GET("/blobs/*key", c.Attachment.DownloadBlob)
+
+	// Recurring job and maintenance plan routes
+	recurringJobs := v1.Group("/recurring-jobs", c.User.jwtManager.AuthMiddleware())
+	{
+		recurringJobs.POST("", c.User.jwtManager.RequireResourcePermission("recurring_job_create"), c.RecurringJob.CreateRecurringJob)          // Create a recurring job - requires JobManager+ role
+		recurringJobs.GET("", c.User.jwtManager.RequireResourcePermission("recurring_job_list"), c.RecurringJob.GetRecurringJobs)               // List the recurring jobs of an organization - requires JobViewer+ role
+		recurringJobs.GET("/:id", c.User.jwtManager.RequireResourcePermission("recurring_job_list"), c.RecurringJob.GetRecurringJob)            // Get a recurring job - requires JobViewer+ role
+		recurringJobs.GET("/:id/occurrences", c.User.jwtManager.RequireResourcePermission("recurring_job_list"), c.RecurringJob.GetOccurrences) // Preview occurrences - requires JobViewer+ role
+		recurringJobs.PUT("/:id", c.User.jwtManager.RequireResourcePermission("recurring_job_update"), c.RecurringJob.UpdateRecurringJob)       // Update a recurring job - requires JobManager+ role
+		recurringJobs.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("recurring_job_delete"), c.RecurringJob.DeleteRecurringJob)    // Delete a recurring job and its future instances - requires JobSupervisor+ role
+	}
+
+	// Checklist template routes - standard procedures copied onto new jobs
+	checklistTemplates := v1.Group("/checklist-templates", c.User.jwtManager.AuthMiddleware())
+	{
+		checklistTemplates.POST("", c.User.jwtManager.RequireResourcePermission("checklist_template_create"), c.Checklist.CreateChecklistTemplate)       // Create a checklist template - requires JobManager+ role
+		checklistTemplates.GET("", c.User.jwtManager.RequireResourcePermission("checklist_template_list"), c.Checklist.GetChecklistTemplates)            // List the checklist templates of an organization - requires JobViewer+ role
+		checklistTemplates.GET("/:id", c.User.jwtManager.RequireResourcePermission("checklist_template_list"), c.Checklist.GetChecklistTemplate)         // Get a checklist template - requires JobViewer+ role
+		checklistTemplates.PUT("/:id", c.User.jwtManager.RequireResourcePermission("checklist_template_update"), c.Checklist.UpdateChecklistTemplate)    // Update a checklist template - requires JobManager+ role
+		checklistTemplates.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("checklist_template_delete"), c.Checklist.DeleteChecklistTemplate) // Delete a checklist template - requires JobSupervisor+ role
+	}
+
+	// SLA policy routes - response and resolution times per job type and priority
+	slaPolicies := v1.Group("/sla-policies", c.User.jwtManager.AuthMiddleware())
+	{
+		slaPolicies.POST("", c.User.jwtManager.RequireResourcePermission("sla_policy_create"), c.SLAPolicy.CreateSLAPolicy)       // Create an SLA policy - requires JobManager+ role
+		slaPolicies.GET("", c.User.jwtManager.RequireResourcePermission("sla_policy_list"), c.SLAPolicy.GetSLAPolicies)           // List the SLA policies of an organization - requires JobViewer+ role
+		slaPolicies.GET("/:id", c.User.jwtManager.RequireResourcePermission("sla_policy_list"), c.SLAPolicy.GetSLAPolicy)         // Get an SLA policy - requires JobViewer+ role
+		slaPolicies.PUT("/:id", c.User.jwtManager.RequireResourcePermission("sla_policy_update"), c.SLAPolicy.UpdateSLAPolicy)    // Update an SLA policy - requires JobManager+ role
+		slaPolicies.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("sla_policy_delete"), c.SLAPolicy.DeleteSLAPolicy) // Delete an SLA policy - requires JobSupervisor+ role
+	}
+
+	// Job template routes - jobs an organization creates over and over
+	jobTemplates := v1.Group("/job-templates", c.User.jwtManager.AuthMiddleware())
+	{
+		jobTemplates.POST("", c.User.jwtManager.RequireResourcePermission("job_template_create"), c.JobTemplate.CreateJobTemplate)       // Create a job template - requires JobDispatcher+ role
+		jobTemplates.GET("", c.User.jwtManager.RequireResourcePermission("job_template_list"), c.JobTemplate.GetJobTemplates)            // List the job templates of an organization - requires JobViewer+ role
+		jobTemplates.GET("/:id", c.User.jwtManager.RequireResourcePermission("job_template_list"), c.JobTemplate.GetJobTemplate)         // Get a job template - requires JobViewer+ role
+		jobTemplates.PUT("/:id", c.User.jwtManager.RequireResourcePermission("job_template_update"), c.JobTemplate.UpdateJobTemplate)    // Update a job template - requires JobDispatcher+ role
+		jobTemplates.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("job_template_delete"), c.JobTemplate.DeleteJobTemplate) // Delete a job template - requires JobManager+ role
+	}
+
+	// Project routes - jobs grouped into multi-visit projects
+	projects := v1.Group("/projects", c.User.jwtManager.AuthMiddleware())
+	{
+		projects.POST("", c.User.jwtManager.RequireResourcePermission("project_create"), c.Project.CreateProject)       // Create a project - requires JobDispatcher+ role
+		projects.GET("", c.User.jwtManager.RequireResourcePermission("project_list"), c.Project.GetProjects)            // List the projects of an organization with their progress - requires JobViewer+ role
+		projects.GET("/:id", c.User.jwtManager.RequireResourcePermission("project_list"), c.Project.GetProject)         // Get a project with its jobs and progress - requires JobViewer+ role
+		projects.PUT("/:id", c.User.jwtManager.RequireResourcePermission("project_update"), c.Project.UpdateProject)    // Update a project - requires JobDispatcher+ role
+		projects.DELETE("/:id", c.User.jwtManager.RequireResourcePermission("project_delete"), c.Project.DeleteProject) // Delete a project - requires JobManager+ role
 	}
-	return nil
 }
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"fieldfuze-backend/middelware"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestSetupRoutes registers every route, as gin panics on routes whose
+// wildcards conflict
+func TestSetupRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &models.Config{LogLevel: "error", LogFormat: "json"}
+	log := logger.NewLogger(cfg.LogLevel, cfg.LogFormat)
+
+	c := &Controller{User: &UserController{jwtManager: middelware.NewJWTManager(cfg, log, nil)}}
+	r := gin.New()
+	c.setupRoutes(cfg, r, "/api/v1")
+
+	for _, route := range []string{"POST /api/v1/user/:user_id/timesheets/:week/approve", "POST /api/v1/user/:user_id/role/:role_id"} {
+		found := false
+		for _, info := range r.Routes() {
+			if info.Method+" "+info.Path == route {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("route %s is not registered", route)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type DispatchController struct {
+	dispatchService services.DispatchServiceInterface
+	logger          logger.Logger
+	validator       *validator.Validate
+}
+
+func NewDispatchController(dispatchService services.DispatchServiceInterface, logger logger.Logger) *DispatchController {
+	return &DispatchController{
+		dispatchService: dispatchService,
+		logger:          logger,
+		validator:       validator.New(),
+	}
+}
+
+func (h *DispatchController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must have at least "+fieldError.Param()+" items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must have at most "+fieldError.Param()+" items")
+			case "datetime":
+				errorMessages = append(errorMessages, fieldError.Field()+" must match the format "+fieldError.Param())
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// OptimizeRoutes handles POST /api/v1/dispatch/optimize
+// @Summary Optimize technician routes
+// @Description Order the pending and active jobs each technician is scheduled for on a day into a route. Routes start at the technician's start location and working hours, keep to arrival windows where possible and minimize travel. Jobs without site coordinates are listed as unrouted. With apply the routed jobs are rescheduled to their planned times.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.OptimizeRoutesRequest true "Day and technicians"
+// @Success 200 {object} models.APIResponse{data=models.RouteOptimization} "Routes optimized successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Organization not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /dispatch/optimize [post]
+func (h *DispatchController) OptimizeRoutes(c *gin.Context) {
+	var req models.OptimizeRoutesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	optimization, err := h.dispatchService.OptimizeRoutes(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to optimize routes", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to optimize routes",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	message := "Routes optimized successfully"
+	if optimization.Applied {
+		message = "Routes optimized and applied successfully"
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    optimization,
+	})
+}
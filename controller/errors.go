@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/services"
+	"net/http"
+)
+
+// databaseErrorStatus maps a typed DAL error, or a domain error of the
+// services and repositories, to its HTTP status code. Other errors are
+// reported as internal server errors.
+func databaseErrorStatus(err error) int {
+	switch {
+	case dal.IsNotFound(err):
+		return http.StatusNotFound
+	case dal.IsConflict(err):
+		return http.StatusConflict
+	case dal.IsThrottled(err):
+		return http.StatusTooManyRequests
+	case dal.IsUnavailable(err):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, repository.ErrTenantRequired), errors.Is(err, repository.ErrInvalidTenant):
+		return http.StatusBadRequest
+	}
+	if status, ok := domainErrorStatus(err); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// domainErrors lists the sentinel errors of the services and repositories
+// with the status they are reported with. The first match wins.
+var domainErrors = []struct {
+	err    error
+	status int
+}{
+	// Jobs
+	{services.ErrInvalidJobTransition, http.StatusConflict},
+	{repository.ErrJobStatusChanged, http.StatusConflict},
+	{services.ErrAssignmentConflict, http.StatusConflict},
+	{services.ErrChecklistIncomplete, http.StatusConflict},
+	{repository.ErrChecklistItemChanged, http.StatusConflict},
+	{services.ErrStatusReasonRequired, http.StatusBadRequest},
+	{services.ErrInvalidSchedule, http.StatusBadRequest},
+	{services.ErrInvalidRecurrence, http.StatusBadRequest},
+	{services.ErrInvalidAssignee, http.StatusBadRequest},
+	{services.ErrInvalidChecklistValue, http.StatusBadRequest},
+	{services.ErrInvalidCursor, http.StatusBadRequest},
+	{services.ErrInvalidComment, http.StatusBadRequest},
+	{services.ErrGeofenceOverrideRequired, http.StatusConflict},
+	{services.ErrInvalidJobSite, http.StatusBadRequest},
+	{services.ErrJobBlocked, http.StatusConflict},
+	{repository.ErrJobDependenciesChanged, http.StatusConflict},
+	{services.ErrInvalidJobDependency, http.StatusBadRequest},
+	{services.ErrJobNotCloneable, http.StatusConflict},
+
+	// Time tracking
+	{services.ErrTimeClockConflict, http.StatusConflict},
+	{services.ErrTimesheetLocked, http.StatusConflict},
+	{repository.ErrTimeEntryChanged, http.StatusConflict},
+	{services.ErrInvalidTimeEntry, http.StatusBadRequest},
+
+	// Projects, imports and search
+	{services.ErrInvalidProject, http.StatusBadRequest},
+	{services.ErrInvalidJobImport, http.StatusBadRequest},
+	{services.ErrInvalidJobSearch, http.StatusBadRequest},
+	{services.ErrSearchUnavailable, http.StatusServiceUnavailable},
+
+	// SLA policies
+	{services.ErrSLAPolicyExists, http.StatusConflict},
+	{services.ErrInvalidSLAPolicy, http.StatusBadRequest},
+	{repository.ErrSLAPolicyChanged, http.StatusConflict},
+
+	// Attachments and sign-off
+	{services.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+	{services.ErrAttachmentEmpty, http.StatusBadRequest},
+	{services.ErrUnsupportedAttachment, http.StatusUnsupportedMediaType},
+	{services.ErrAttachmentLocked, http.StatusConflict},
+	{services.ErrNoSignOff, http.StatusConflict},
+	{services.ErrInvalidSignOff, http.StatusBadRequest},
+
+	// Users, roles and organizations
+	{repository.ErrUserEmailExists, http.StatusConflict},
+	{repository.ErrUsernameExists, http.StatusConflict},
+	{repository.ErrUserHasRole, http.StatusConflict},
+	{repository.ErrRoleExists, http.StatusConflict},
+	{services.ErrRoleIDRequired, http.StatusBadRequest},
+	{services.ErrRoleAssignmentIDRequired, http.StatusBadRequest},
+	{repository.ErrOrganizationExists, http.StatusConflict},
+	{services.ErrOrganizationAccessDenied, http.StatusForbidden},
+}
+
+// domainErrorStatus returns the status of the first domain error err
+// matches, if any
+func domainErrorStatus(err error) (int, bool) {
+	for _, domain := range domainErrors {
+		if errors.Is(err, domain.err) {
+			return domain.status, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/services"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDatabaseErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "not found", err: fmt.Errorf("failed to get job: %w", dal.ErrNotFound), want: http.StatusNotFound},
+		{name: "job not found", err: repository.ErrJobNotFound, want: http.StatusNotFound},
+		{name: "checklist item not found", err: services.ErrChecklistItemNotFound, want: http.StatusNotFound},
+		{name: "name taken", err: repository.ErrRoleExists, want: http.StatusConflict},
+		{name: "missing tenant", err: repository.ErrTenantRequired, want: http.StatusBadRequest},
+		{name: "wrapped domain error", err: fmt.Errorf("%w: end before start", services.ErrInvalidSchedule), want: http.StatusBadRequest},
+		{name: "concurrent change", err: repository.ErrJobStatusChanged, want: http.StatusConflict},
+		{name: "assignment conflict", err: &services.AssignmentError{Kind: services.ErrAssignmentConflict}, want: http.StatusConflict},
+		{name: "invalid assignee", err: &services.AssignmentError{Kind: services.ErrInvalidAssignee}, want: http.StatusBadRequest},
+		{name: "attachment too large", err: services.ErrAttachmentTooLarge, want: http.StatusRequestEntityTooLarge},
+		{name: "other organization", err: services.ErrOrganizationAccessDenied, want: http.StatusForbidden},
+		{name: "unclassified", err: errors.New("boom"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := databaseErrorStatus(tt.err); got != tt.want {
+			t.Errorf("databaseErrorStatus(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDomainErrorStatus gives every domain error a client error status
+func TestDomainErrorStatus(t *testing.T) {
+	for _, domain := range domainErrors {
+		status, ok := domainErrorStatus(domain.err)
+		if !ok || status != domain.status {
+			t.Errorf("domainErrorStatus(%v) = %d, %t, want %d", domain.err, status, ok, domain.status)
+		}
+		if domain.status < 400 || domain.status == http.StatusInternalServerError {
+			t.Errorf("%v is reported with status %d", domain.err, domain.status)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"context"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/services"
 	"fieldfuze-backend/utils/logger"
@@ -13,14 +12,12 @@ import (
 )
 
 type InfrastructureController struct {
-	ctx     context.Context
 	service services.InfrastructureServiceInterface
 	logger  logger.Logger
 }
 
-func NewInfrastructureController(ctx context.Context, service services.InfrastructureServiceInterface, logger logger.Logger) *InfrastructureController {
+func NewInfrastructureController(service services.InfrastructureServiceInterface, logger logger.Logger) *InfrastructureController {
 	return &InfrastructureController{
-		ctx:     ctx,
 		service: service,
 		logger:  logger,
 	}
@@ -39,7 +36,7 @@ func NewInfrastructureController(ctx context.Context, service services.Infrastru
 // @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve worker status"
 // @Router /infrastructure/worker/status [get]
 func (h *InfrastructureController) GetWorkerStatus(c *gin.Context) {
-	workerStatus, err := h.service.GetWorkerStatus(h.ctx)
+	workerStatus, err := h.service.GetWorkerStatus(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to get worker status", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
@@ -57,7 +54,7 @@ func (h *InfrastructureController) GetWorkerStatus(c *gin.Context) {
 	// Map worker execution status to appropriate HTTP status
 	httpStatus, apiStatus := h.mapWorkerStatusToHTTP(workerStatus)
 	message := h.getStatusMessage(workerStatus)
-	
+
 	c.JSON(httpStatus, models.APIResponse{
 		Status:  apiStatus,
 		Code:    httpStatus,
@@ -90,7 +87,7 @@ func (h *InfrastructureController) RestartWorker(c *gin.Context) {
 		}
 	}
 
-	result, err := h.service.RestartWorker(h.ctx, restartRequest.Force)
+	result, err := h.service.RestartWorker(c.Request.Context(), restartRequest.Force)
 	if err != nil {
 		// Check if it's a conflict (worker running)
 		if strings.Contains(err.Error(), "worker is running") {
@@ -187,7 +184,7 @@ func (h *InfrastructureController) CheckWorkerHealth(c *gin.Context) {
 // @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to auto-restart worker"
 // @Router /infrastructure/worker/auto-restart [post]
 func (h *InfrastructureController) AutoRestartWorker(c *gin.Context) {
-	result, err := h.service.AutoRestartIfNeeded(h.ctx)
+	result, err := h.service.AutoRestartIfNeeded(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to auto-restart worker", err)
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
@@ -226,28 +223,28 @@ func (h *InfrastructureController) mapWorkerStatusToHTTP(ws *models.ExecutionRes
 			return http.StatusOK, "success"
 		}
 		return http.StatusOK, "warning" // Completed but with issues
-		
+
 	case models.StatusFailed:
 		return http.StatusServiceUnavailable, "error" // Infrastructure not ready
-		
+
 	case models.StatusCreatingTables, models.StatusWaitingForTables,
-		 models.StatusCreatingIndexes, models.StatusWaitingForIndexes,
-		 models.StatusValidating, models.StatusFixingIssues, models.StatusRevalidating,
-		 models.StatusInitializing, models.StatusRunning:
+		models.StatusCreatingIndexes, models.StatusWaitingForIndexes,
+		models.StatusValidating, models.StatusFixingIssues, models.StatusRevalidating,
+		models.StatusInitializing, models.StatusRunning:
 		return http.StatusAccepted, "in_progress" // 202 - Accepted, processing
-		
+
 	case models.StatusRetrying:
 		return http.StatusAccepted, "retrying"
-		
+
 	case models.StatusDeleting, models.StatusDeletionScheduled:
 		return http.StatusAccepted, "deleting"
-		
+
 	case models.StatusDeleted:
 		return http.StatusOK, "deleted"
-		
+
 	case models.StatusDeletionFailed:
 		return http.StatusServiceUnavailable, "deletion_failed"
-		
+
 	default:
 		return http.StatusOK, "info"
 	}
@@ -261,31 +258,31 @@ func (h *InfrastructureController) getStatusMessage(ws *models.ExecutionResult)
 			return "Infrastructure is ready and healthy"
 		}
 		return "Infrastructure setup completed with warnings"
-		
+
 	case models.StatusFailed:
 		return "Infrastructure setup failed - manual intervention may be required"
-		
+
 	case models.StatusCreatingTables:
 		return "Creating DynamoDB tables"
-		
+
 	case models.StatusWaitingForTables:
 		return "Waiting for DynamoDB tables to become active"
-		
+
 	case models.StatusCreatingIndexes:
 		return "Creating database indexes"
-		
+
 	case models.StatusWaitingForIndexes:
 		return "Waiting for database indexes to become ready"
-		
+
 	case models.StatusValidating:
 		return "Validating infrastructure configuration"
-		
+
 	case models.StatusFixingIssues:
 		return "Fixing detected infrastructure issues"
-		
+
 	case models.StatusRevalidating:
 		return "Re-validating infrastructure after fixes"
-		
+
 	case models.StatusRetrying:
 		// Extract retry count from metadata
 		retryCount := 0
@@ -295,25 +292,25 @@ func (h *InfrastructureController) getStatusMessage(ws *models.ExecutionResult)
 			}
 		}
 		return fmt.Sprintf("Retrying infrastructure setup (attempt %d)", retryCount+1)
-		
+
 	case models.StatusInitializing:
 		return "Initializing infrastructure worker"
-		
+
 	case models.StatusRunning:
 		return "Infrastructure setup is running"
-		
+
 	case models.StatusDeleting:
 		return "Deleting infrastructure resources"
-		
+
 	case models.StatusDeletionScheduled:
 		return "Infrastructure deletion has been scheduled"
-		
+
 	case models.StatusDeleted:
 		return "Infrastructure has been successfully deleted"
-		
+
 	case models.StatusDeletionFailed:
 		return "Infrastructure deletion failed"
-		
+
 	default:
 		return "Worker status retrieved successfully"
 	}
@@ -1,10 +1,15 @@
 package controller
 
 import (
-	"context"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/services"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"fieldfuze-backend/utils/xlsx"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,15 +20,13 @@ import (
 )
 
 type JobController struct {
-	ctx        context.Context
 	jobService services.JobServiceInterface
 	logger     logger.Logger
 	validator  *validator.Validate
 }
 
-func NewJobController(ctx context.Context, jobService services.JobServiceInterface, logger logger.Logger) *JobController {
+func NewJobController(jobService services.JobServiceInterface, logger logger.Logger) *JobController {
 	return &JobController{
-		ctx:        ctx,
 		jobService: jobService,
 		logger:     logger,
 		validator:  validator.New(),
@@ -53,6 +56,31 @@ func (h *JobController) formatValidationErrors(err error) string {
 	return strings.Join(errorMessages, "; ")
 }
 
+// forceAssignmentLevel is the role level, JobSupervisor and up, that may
+// override assignment conflicts
+const forceAssignmentLevel = 7
+
+// canForceAssignment reports whether the caller may override assignment
+// conflicts
+func canForceAssignment(claims *models.JWTClaims) bool {
+	for _, role := range claims.Roles {
+		if role.DeletedData == nil && role.Level >= forceAssignmentLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// assignmentConflicts returns the conflicts carried by err as response
+// data, or nil when it carries none
+func assignmentConflicts(err error) interface{} {
+	var assignmentErr *services.AssignmentError
+	if errors.As(err, &assignmentErr) {
+		return assignmentErr.Conflicts
+	}
+	return nil
+}
+
 // CreateJob handles POST /api/v1/jobs
 // @Summary Create a new job
 // @Description Create a new job with specified details
@@ -62,7 +90,9 @@ func (h *JobController) formatValidationErrors(err error) string {
 // @Produce json
 // @Param request body models.CreateJobRequest true "Create job request"
 // @Success 201 {object} models.APIResponse "Job created successfully"
-// @Failure 400 {object} models.APIResponse "Bad Request - Invalid job data"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid job data or assignees"
+// @Failure 403 {object} models.APIResponse "Forbidden - Only supervisors may force an assignment"
+// @Failure 409 {object} models.APIResponse{data=[]models.AssignmentConflict} "Assignees are double-booked or unavailable"
 // @Failure 500 {object} models.APIResponse "Internal Server Error - Job creation failed"
 // @Router /jobs [post]
 func (h *JobController) CreateJob(c *gin.Context) {
@@ -125,13 +155,156 @@ func (h *JobController) CreateJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobService.CreateJob(h.ctx, &req, jwtClaims.UserID)
+	if req.Force && !canForceAssignment(jwtClaims) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "Insufficient permissions",
+			Error: &models.APIError{
+				Type:    "AuthorizationError",
+				Details: "Overriding assignment conflicts requires the JobSupervisor role",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.CreateJob(c.Request.Context(), &req, jwtClaims.UserID)
 	if err != nil {
 		h.logger.Error("Failed to create job", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create job",
+			Data:    assignmentConflicts(err),
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Job created successfully",
+		Data:    job,
+	})
+}
+
+// CreateJobFromTemplate handles POST /api/v1/jobs/from-template/{templateId}
+// @Summary Create a job from a template
+// @Description Create a pending job from a job template of the organization. The job copies the name, type, notes, priority, vehicles, QuickBooks line item and checklist of the template; fields given in the request override them.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Job template ID"
+// @Param request body models.CreateJobFromTemplateRequest true "Client and field overrides"
+// @Success 201 {object} models.APIResponse "Job created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid job data or assignees"
+// @Failure 403 {object} models.APIResponse "Forbidden - Only supervisors may force an assignment"
+// @Failure 404 {object} models.APIResponse "Job template not found"
+// @Failure 409 {object} models.APIResponse{data=[]models.AssignmentConflict} "Assignees are double-booked or unavailable"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Job creation failed"
+// @Router /jobs/from-template/{templateId} [post]
+func (h *JobController) CreateJobFromTemplate(c *gin.Context) {
+	templateID := c.Param("templateId")
+	if templateID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.CreateJobFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Status:  "error",
 			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	if req.Force && !canForceAssignment(jwtClaims) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "Insufficient permissions",
+			Error: &models.APIError{
+				Type:    "AuthorizationError",
+				Details: "Overriding assignment conflicts requires the JobSupervisor role",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.CreateJobFromTemplate(c.Request.Context(), templateID, &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create job from template", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
 			Message: "Failed to create job",
+			Data:    assignmentConflicts(err),
 			Error: &models.APIError{
 				Type:    "DatabaseError",
 				Details: err.Error(),
@@ -148,6 +321,135 @@ func (h *JobController) CreateJob(c *gin.Context) {
 	})
 }
 
+// CloneJob handles POST /api/v1/jobs/{id}/clone
+// @Summary Clone a completed job
+// @Description Create a pending job from a completed one. The clone keeps the client, assignees, site, QuickBooks line item and checklist, which is reopened; its status, schedule, timestamps, start and deletion records start afresh. Fields given in the request override the copied ones.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.CloneJobRequest false "Field overrides"
+// @Success 201 {object} models.APIResponse "Job cloned successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid job data or assignees"
+// @Failure 403 {object} models.APIResponse "Forbidden - Only supervisors may force an assignment"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse{data=[]models.AssignmentConflict} "Job is not completed, or assignees are double-booked or unavailable"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Job creation failed"
+// @Router /jobs/{id}/clone [post]
+func (h *JobController) CloneJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	// The overrides are optional, so is the body
+	var req models.CloneJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	if req.Force && !canForceAssignment(jwtClaims) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "Insufficient permissions",
+			Error: &models.APIError{
+				Type:    "AuthorizationError",
+				Details: "Overriding assignment conflicts requires the JobSupervisor role",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.CloneJob(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to clone job", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to clone job",
+			Data:    assignmentConflicts(err),
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Job cloned successfully",
+		Data:    job,
+	})
+}
+
 // GetJobs handles GET /api/v1/jobs
 // @Summary Get jobs with optional filtering
 // @Description Retrieve a list of jobs with optional filtering
@@ -161,9 +463,12 @@ func (h *JobController) CreateJob(c *gin.Context) {
 // @Param clientID query string false "Filter by client ID"
 // @Param jobStatus query string false "Filter by job status"
 // @Param jobType query string false "Filter by job type"
+// @Param priority query string false "Filter by priority (low, normal, high, emergency)"
+// @Param slaStatus query string false "Filter by SLA status (on_track, at_risk, breached, met)"
 // @Param createdBy query string false "Filter by creator"
 // @Param fromDate query string false "Filter from date (YYYY-MM-DD)"
 // @Param toDate query string false "Filter to date (YYYY-MM-DD)"
+// @Param includeDeleted query bool false "Include soft-deleted jobs"
 // @Success 200 {object} models.APIResponse "Jobs retrieved successfully"
 // @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve jobs"
 // @Router /jobs [get]
@@ -183,38 +488,15 @@ func (h *JobController) GetJobs(c *gin.Context) {
 		}
 	}
 
-	filter := &models.JobFilter{
-		OrgID:     c.Query("orgID"),
-		ClientID:  c.Query("clientID"),
-		CreatedBy: c.Query("createdBy"),
-	}
-
-	if jobStatus := c.Query("jobStatus"); jobStatus != "" {
-		filter.JobStatus = models.JobStatus(jobStatus)
-	}
-
-	if jobType := c.Query("jobType"); jobType != "" {
-		filter.JobType = models.JobType(jobType)
-	}
-
-	if fromDateStr := c.Query("fromDate"); fromDateStr != "" {
-		if fromDate, err := time.Parse("2006-01-02", fromDateStr); err == nil {
-			filter.FromDate = fromDate
-		}
-	}
-
-	if toDateStr := c.Query("toDate"); toDateStr != "" {
-		if toDate, err := time.Parse("2006-01-02", toDateStr); err == nil {
-			filter.ToDate = toDate
-		}
-	}
+	filter := jobFilterFromQuery(c)
 
-	jobs, err := h.jobService.GetJobs(filter)
+	jobs, err := h.jobService.GetJobs(c.Request.Context(), filter)
 	if err != nil {
 		h.logger.Error("Failed to get jobs", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get jobs",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -259,46 +541,1464 @@ func (h *JobController) GetJobs(c *gin.Context) {
 	})
 }
 
-// GetJobByID handles GET /api/v1/jobs/{id}
-// @Summary Get job by ID
-// @Description Get a specific job by its ID
+// ImportJobs handles POST /api/v1/jobs/import
+// @Summary Import jobs from a CSV file
+// @Description Import jobs from a CSV file uploaded as multipart/form-data. The optional mapping is JSON naming the column of each job field and default values for fields without one; columns headed like a field are read without a mapping, so files from GET /jobs/export import as they are. Every row is validated like a created job and rows may be historic completed or cancelled jobs. A dry run only reports the invalid rows. Otherwise, when every row is valid, the jobs are created in the background and the import can be followed with GET /jobs/import/{importId}.
 // @Tags Job Management
 // @Security BearerAuth
-// @Accept json
+// @Accept multipart/form-data
 // @Produce json
-// @Param id path string true "Job ID"
-// @Success 200 {object} models.APIResponse "Job retrieved successfully"
+// @Param file formData file true "CSV file with a header row"
+// @Param mapping formData string false "Column mapping as JSON, see models.JobImportMapping"
+// @Param orgID formData string false "Organization ID, defaults to the X-Organization-ID header"
+// @Param dryRun formData bool false "Only validate the rows"
+// @Success 200 {object} models.APIResponse{data=models.JobImportResult} "Import checked successfully"
+// @Success 202 {object} models.APIResponse{data=models.JobImportResult} "Import started"
+// @Failure 400 {object} models.APIResponse{data=models.JobImportResult} "Bad Request - Invalid file or rows"
+// @Failure 404 {object} models.APIResponse "Organization not found"
+// @Failure 413 {object} models.APIResponse "File too large"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/import [post]
+func (h *JobController) ImportJobs(c *gin.Context) {
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	maxSize := h.jobService.MaxImportSize()
+	// Leave room for the multipart framing and the other fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		h.logger.Error("Failed to parse multipart form:", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	files := form.File["file"]
+	if len(files) != 1 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "exactly one file is required in the file field",
+			},
+		})
+		return
+	}
+	file := files[0]
+
+	if file.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: "File too large",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: file.Filename + " exceeds the upload size limit",
+			},
+		})
+		return
+	}
+
+	var mapping models.JobImportMapping
+	if raw := strings.TrimSpace(c.PostForm("mapping")); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Error: &models.APIError{
+					Type:    "ValidationError",
+					Details: "mapping must be a JSON object: " + err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	dryRun := false
+	if raw := c.PostForm("dryRun"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Status:  "error",
+				Code:    http.StatusBadRequest,
+				Message: "Validation failed",
+				Error: &models.APIError{
+					Type:    "ValidationError",
+					Details: "dryRun must be true or false",
+				},
+			})
+			return
+		}
+	}
+
+	orgID := c.PostForm("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID form field or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	body, err := file.Open()
+	if err != nil {
+		h.logger.Error("Failed to open uploaded file", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+	defer body.Close()
+
+	result, err := h.jobService.ImportJobs(c.Request.Context(), orgID, file.Filename, body, &mapping, dryRun, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to import jobs", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to import jobs",
+			Data:    result,
+			Error: &models.APIError{
+				Type:    "ImportError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Status:  "success",
+			Code:    http.StatusOK,
+			Message: "Import checked successfully",
+			Data:    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusAccepted,
+		Message: "Import started",
+		Data:    result,
+	})
+}
+
+// GetJobImport handles GET /api/v1/jobs/import/{importId}
+// @Summary Get the progress of a job import
+// @Description Retrieve how far an import running in the background got, with the first rows that failed
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param importId path string true "Import ID"
+// @Success 200 {object} models.APIResponse{data=models.JobImport} "Import retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Missing import ID"
+// @Failure 404 {object} models.APIResponse "Import not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/import/{importId} [get]
+func (h *JobController) GetJobImport(c *gin.Context) {
+	id := c.Param("importId")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Import ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Import ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	jobImport, err := h.jobService.GetJobImport(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get job import", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job import",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Import retrieved successfully",
+		Data:    jobImport,
+	})
+}
+
+// ExportJobs handles GET /api/v1/jobs/export
+// @Summary Export jobs as CSV or XLSX
+// @Description Download the jobs matching the same filters as GET /jobs, without pagination. The columns are headed like the fields of an import, so the file can be imported again.
+// @Tags Job Management
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format, defaults to csv" Enums(csv, xlsx)
+// @Param orgID query string false "Filter by organization ID"
+// @Param clientID query string false "Filter by client ID"
+// @Param jobStatus query string false "Filter by job status"
+// @Param jobType query string false "Filter by job type"
+// @Param priority query string false "Filter by priority (low, normal, high, emergency)"
+// @Param slaStatus query string false "Filter by SLA status (on_track, at_risk, breached, met)"
+// @Param createdBy query string false "Filter by creator"
+// @Param fromDate query string false "Filter from date (YYYY-MM-DD)"
+// @Param toDate query string false "Filter to date (YYYY-MM-DD)"
+// @Param includeDeleted query bool false "Include soft-deleted jobs"
+// @Success 200 {file} file "Jobs exported successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid format"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to export jobs"
+// @Router /jobs/export [get]
+func (h *JobController) ExportJobs(c *gin.Context) {
+	format := models.JobExportFormat(strings.ToLower(c.DefaultQuery("format", string(models.JobExportFormatCSV))))
+	if format != models.JobExportFormatCSV && format != models.JobExportFormatXLSX {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "format must be one of: csv, xlsx",
+			},
+		})
+		return
+	}
+
+	filter := jobFilterFromQuery(c)
+
+	// Render the whole file first so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if err := h.jobService.ExportJobs(c.Request.Context(), filter, format, &buf); err != nil {
+		h.logger.Error("Failed to export jobs", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to export jobs",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == models.JobExportFormatXLSX {
+		contentType = xlsx.ContentType
+	}
+	c.Header("Content-Disposition", `attachment; filename="jobs.`+string(format)+`"`)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// SearchJobs handles GET /api/v1/jobs/search
+// @Summary Search jobs
+// @Description Full-text search over the name, notes, client and site address of the jobs of the caller's organization, taken from the X-Organization-ID header or else the user's organization. Every word must match, the last one also as the start of a longer word. Results are ordered by relevance and come with the number of all matches by status, type, assignee and date period; dates are the scheduled start, or the creation of unscheduled jobs, relative to today in the organization's timezone. An empty q lists every job of the organization.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param q query string false "Words to search for"
+// @Param jobStatus query []string false "Only jobs in one of these statuses" collectionFormat(multi)
+// @Param jobType query []string false "Only jobs of one of these types" collectionFormat(multi)
+// @Param assignee query []string false "Only jobs assigned to one of these users" collectionFormat(multi)
+// @Param date query string false "Only jobs in this period" Enums(earlier, last_7_days, today, next_7_days, later)
+// @Param page query int false "Page number, defaults to 1"
+// @Param limit query int false "Number of jobs per page, defaults to 10 and limited by the search configuration"
+// @Success 200 {object} models.APIResponse{data=models.JobSearchResult} "Jobs found successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid filter or missing organization"
+// @Failure 404 {object} models.APIResponse "Organization not found"
+// @Failure 503 {object} models.APIResponse "Service Unavailable - Job search is not available"
+// @Router /jobs/search [get]
+func (h *JobController) SearchJobs(c *gin.Context) {
+	orgID := requestctx.TenantID(c.Request.Context())
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "X-Organization-ID header is missing and the user belongs to no organization",
+			},
+		})
+		return
+	}
+
+	req := &models.JobSearchRequest{
+		OrgID:     orgID,
+		Query:     c.Query("q"),
+		Assignees: c.QueryArray("assignee"),
+		Date:      models.JobSearchDate(c.Query("date")),
+	}
+	for _, status := range c.QueryArray("jobStatus") {
+		req.Statuses = append(req.Statuses, models.JobStatus(status))
+	}
+	for _, jobType := range c.QueryArray("jobType") {
+		req.Types = append(req.Types, models.JobType(jobType))
+	}
+
+	if pageParam := c.Query("page"); pageParam != "" {
+		if p, err := strconv.Atoi(pageParam); err == nil && p > 0 {
+			req.Page = p
+		}
+	}
+
+	if limitParam := c.Query("limit"); limitParam != "" {
+		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
+			req.Limit = l
+		}
+	}
+
+	result, err := h.jobService.SearchJobs(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to search jobs", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to search jobs",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Jobs found successfully",
+		Data:    result,
+	})
+}
+
+// jobFilterFromQuery reads the job filter query parameters shared by listing
+// and exporting jobs
+func jobFilterFromQuery(c *gin.Context) *models.JobFilter {
+	filter := &models.JobFilter{
+		OrgID:     c.Query("orgID"),
+		ClientID:  c.Query("clientID"),
+		CreatedBy: c.Query("createdBy"),
+	}
+
+	if jobStatus := c.Query("jobStatus"); jobStatus != "" {
+		filter.JobStatus = models.JobStatus(jobStatus)
+	}
+
+	if jobType := c.Query("jobType"); jobType != "" {
+		filter.JobType = models.JobType(jobType)
+	}
+
+	if priority := c.Query("priority"); priority != "" {
+		filter.Priority = models.JobPriority(priority)
+	}
+
+	if slaStatus := c.Query("slaStatus"); slaStatus != "" {
+		filter.SLAStatus = models.SLAStatus(slaStatus)
+	}
+
+	if fromDateStr := c.Query("fromDate"); fromDateStr != "" {
+		if fromDate, err := time.Parse("2006-01-02", fromDateStr); err == nil {
+			filter.FromDate = fromDate
+		}
+	}
+
+	if toDateStr := c.Query("toDate"); toDateStr != "" {
+		if toDate, err := time.Parse("2006-01-02", toDateStr); err == nil {
+			filter.ToDate = toDate
+		}
+	}
+
+	if includeDeleted, err := strconv.ParseBool(c.Query("includeDeleted")); err == nil {
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	return filter
+}
+
+// GetCalendar handles GET /api/v1/jobs/calendar
+// @Summary Get the dispatcher calendar
+// @Description Retrieve the scheduled jobs of an organization grouped by technician and by day in the organization's timezone
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Param from query string false "First day (YYYY-MM-DD) or time, defaults to today"
+// @Param to query string false "Last day (YYYY-MM-DD) or time, defaults to a week after from"
+// @Param assignee query string false "Only jobs assigned to this user"
+// @Success 200 {object} models.APIResponse{data=models.JobCalendar} "Calendar retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid range"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve calendar"
+// @Router /jobs/calendar [get]
+func (h *JobController) GetCalendar(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	calendar, err := h.jobService.GetCalendar(c.Request.Context(), orgID, c.Query("from"), c.Query("to"), c.Query("assignee"))
+	if err != nil {
+		h.logger.Error("Failed to get job calendar", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job calendar",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Calendar retrieved successfully",
+		Data:    calendar,
+	})
+}
+
+// GetJobByID handles GET /api/v1/jobs/{id}
+// @Summary Get job by ID
+// @Description Get a specific job by its ID
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.APIResponse "Job retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id} [get]
+func (h *JobController) GetJobByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.GetJobByID(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job retrieved successfully",
+		Data:    job,
+	})
+}
+
+// UpdateJob handles PUT /api/v1/jobs/{id}
+// @Summary Update job
+// @Description Update an existing job
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.UpdateJobRequest true "Update job request"
+// @Success 200 {object} models.APIResponse "Job updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 403 {object} models.APIResponse "Forbidden - Only supervisors may force an assignment"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status, or assignees are double-booked or unavailable"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id} [put]
+func (h *JobController) UpdateJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	if req.Force && !canForceAssignment(jwtClaims) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusForbidden,
+			Message: "Insufficient permissions",
+			Error: &models.APIError{
+				Type:    "AuthorizationError",
+				Details: "Overriding assignment conflicts requires the JobSupervisor role",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.UpdateJob(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update job",
+			Data:    assignmentConflicts(err),
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job updated successfully",
+		Data:    job,
+	})
+}
+
+// DeleteJob handles DELETE /api/v1/jobs/{id}
+// @Summary Delete job
+// @Description Soft-delete a job by ID. The job is hidden from reads and purged after the retention period unless restored.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Job deleted successfully"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id} [delete]
+func (h *JobController) DeleteJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.jobService.DeleteJob(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job deleted successfully",
+	})
+}
+
+// RestoreJob handles POST /api/v1/jobs/{id}/restore
+// @Summary Restore a deleted job
+// @Description Restore a soft-deleted job before it is purged
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.APIResponse "Job restored successfully"
+// @Failure 404 {object} models.APIResponse "Deleted job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/restore [post]
+func (h *JobController) RestoreJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.RestoreJob(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to restore job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to restore job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job restored successfully",
+		Data:    job,
+	})
+}
+
+// StartJob handles POST /api/v1/jobs/{id}/start
+// @Summary Start a job
+// @Description Start a job by changing its status to in_progress. A job cannot start before the jobs it is blocked by are completed. The first start compares the device location with the job site geofence and records the result as startCheck. Organizations with the override geofence policy refuse starts that are not verified inside the geofence until a supervisor grants an override.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.DeviceLocation false "Device location"
+// @Success 200 {object} models.APIResponse "Job started successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status, is blocked by other jobs or a geofence override is required"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/start [post]
+func (h *JobController) StartJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	// The device location is optional; without it the geofence check is
+	// unverified
+	var req models.DeviceLocation
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.StartJob(c.Request.Context(), id, jwtClaims.UserID, &req)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to start job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to start job",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job started successfully",
+		Data:    job,
+	})
+}
+
+// CompleteJob handles POST /api/v1/jobs/{id}/complete
+// @Summary Complete a job
+// @Description Complete a job by changing its status to completed. Jobs with open required checklist items are refused unless checklists are configured as optional. The device location is compared with the job site geofence and the result recorded as completionCheck. An optional customer sign-off with a PNG or SVG signature is stored with the job as signOff, which cannot be changed afterwards, together with a completion summary PDF that is emailed to the signer when they give an email.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.CompleteJobRequest false "Device location and customer sign-off"
+// @Success 200 {object} models.APIResponse "Job completed successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status or required checklist items are open"
+// @Failure 413 {object} models.APIResponse "Signature too large"
+// @Failure 415 {object} models.APIResponse "Signature is not a PNG or SVG image"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/complete [post]
+func (h *JobController) CompleteJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	// The body is optional; without a device location the geofence check is
+	// unverified
+	var req models.CompleteJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.CompleteJob(c.Request.Context(), id, jwtClaims.UserID, &req)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to complete job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to complete job",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job completed successfully",
+		Data:    job,
+	})
+}
+
+// SendCompletionSummary handles POST /api/v1/jobs/{id}/completion-summary/send
+// @Summary Send the completion summary of a job
+// @Description Email the completion summary PDF of a signed-off job again, to the given email or else to the signer
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.SendCompletionSummaryRequest false "Recipient email"
+// @Success 200 {object} models.APIResponse "Completion summary sent successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job has no sign-off"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/completion-summary/send [post]
+func (h *JobController) SendCompletionSummary(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	// The body is optional; without an email the summary goes to the signer
+	var req models.SendCompletionSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	if err := h.jobService.SendCompletionSummary(c.Request.Context(), id, req.Email, jwtClaims.UserID); err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to send completion summary", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to send completion summary",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Completion summary sent successfully",
+	})
+}
+
+// GrantGeofenceOverride handles POST /api/v1/jobs/{id}/geofence-override
+// @Summary Override the geofence of a job
+// @Description Allow the next start of a job away from its site under the override geofence policy. The override is used up by the start and recorded on its startCheck.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.GeofenceOverrideRequest true "Override reason"
+// @Success 200 {object} models.APIResponse "Geofence override granted successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
 // @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job has already been started or cannot be started"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id} [get]
-func (h *JobController) GetJobByID(c *gin.Context) {
+// @Router /jobs/{id}/geofence-override [post]
+func (h *JobController) GrantGeofenceOverride(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.GeofenceOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.GrantGeofenceOverride(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to grant geofence override", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to grant geofence override",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Geofence override granted successfully",
+		Data:    job,
+	})
+}
+
+// AddJobDependency handles POST /api/v1/jobs/{id}/dependencies
+// @Summary Block a job by another job
+// @Description Make a job wait for another job of its organization. The job cannot start until the job it is blocked by is completed. Jobs that already started cannot be blocked and dependencies may not form a cycle.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.JobDependencyRequest true "Job the job waits for"
+// @Success 200 {object} models.APIResponse{data=models.Job} "Job dependency added successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid dependency"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Dependencies changed concurrently"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/dependencies [post]
+func (h *JobController) AddJobDependency(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusBadRequest,
-			Message: "Job ID is required",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.JobDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.AddJobDependency(c.Request.Context(), id, req.BlockedBy, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to add job dependency", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to add job dependency",
+			Error: &models.APIError{
+				Type:    "BusinessError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job dependency added successfully",
+		Data:    job,
+	})
+}
+
+// RemoveJobDependency handles DELETE /api/v1/jobs/{id}/dependencies/{blockerId}
+// @Summary Stop blocking a job by another job
+// @Description Remove a job the job waits for, for instance a blocker that was cancelled
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param blockerId path string true "ID of the job it waits for"
+// @Success 200 {object} models.APIResponse{data=models.Job} "Job dependency removed successfully"
+// @Failure 404 {object} models.APIResponse "Job or dependency not found"
+// @Failure 409 {object} models.APIResponse "Dependencies changed concurrently"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/dependencies/{blockerId} [delete]
+func (h *JobController) RemoveJobDependency(c *gin.Context) {
+	id := c.Param("id")
+	blockerID := c.Param("blockerId")
+	if id == "" || blockerID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID and blocker ID are required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID or blocker ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
 			Error: &models.APIError{
-				Type:    "ValidationError",
-				Details: "Job ID parameter is missing",
+				Type:    "TokenError",
+				Details: "Invalid token structure",
 			},
 		})
 		return
 	}
 
-	job, err := h.jobService.GetJobByID(id)
+	job, err := h.jobService.RemoveJobDependency(c.Request.Context(), id, blockerID, jwtClaims.UserID)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		}
-		h.logger.Error("Failed to get job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to remove job dependency", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to get job",
+			Message: "Failed to remove job dependency",
 			Error: &models.APIError{
-				Type:    "DatabaseError",
+				Type:    "BusinessError",
 				Details: err.Error(),
 			},
 		})
@@ -308,26 +2008,27 @@ func (h *JobController) GetJobByID(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job retrieved successfully",
+		Message: "Job dependency removed successfully",
 		Data:    job,
 	})
 }
 
-// UpdateJob handles PUT /api/v1/jobs/{id}
-// @Summary Update job
-// @Description Update an existing job
+// CancelJob handles POST /api/v1/jobs/{id}/cancel
+// @Summary Cancel a job
+// @Description Cancel a job by changing its status to cancelled
 // @Tags Job Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Job ID"
-// @Param request body models.UpdateJobRequest true "Update job request"
-// @Success 200 {object} models.APIResponse "Job updated successfully"
+// @Param request body models.JobStatusRequest true "Cancel reason"
+// @Success 200 {object} models.APIResponse "Job cancelled successfully"
 // @Failure 400 {object} models.APIResponse "Bad Request"
 // @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id} [put]
-func (h *JobController) UpdateJob(c *gin.Context) {
+// @Router /jobs/{id}/cancel [post]
+func (h *JobController) CancelJob(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
@@ -342,34 +2043,8 @@ func (h *JobController) UpdateJob(c *gin.Context) {
 		return
 	}
 
-	var req models.UpdateJobRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Error("Failed to bind JSON:", err)
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Status:  "error",
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request",
-			Error: &models.APIError{
-				Type:    "ValidationError",
-				Details: err.Error(),
-			},
-		})
-		return
-	}
-
-	if err := h.validator.Struct(&req); err != nil {
-		h.logger.Error("Validation failed:", err)
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Status:  "error",
-			Code:    http.StatusBadRequest,
-			Message: "Validation failed",
-			Error: &models.APIError{
-				Type:    "ValidationError",
-				Details: h.formatValidationErrors(err),
-			},
-		})
-		return
-	}
+	var req models.JobStatusRequest
+	c.ShouldBindJSON(&req)
 
 	claims, exists := c.Get("jwt_claims")
 	if !exists {
@@ -401,19 +2076,16 @@ func (h *JobController) UpdateJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobService.UpdateJob(h.ctx, id, &req, jwtClaims.UserID)
+	job, err := h.jobService.CancelJob(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		}
-		h.logger.Error("Failed to update job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to cancel job", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to update job",
+			Message: "Failed to cancel job",
 			Error: &models.APIError{
-				Type:    "DatabaseError",
+				Type:    "BusinessError",
 				Details: err.Error(),
 			},
 		})
@@ -423,24 +2095,27 @@ func (h *JobController) UpdateJob(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job updated successfully",
+		Message: "Job cancelled successfully",
 		Data:    job,
 	})
 }
 
-// DeleteJob handles DELETE /api/v1/jobs/{id}
-// @Summary Delete job
-// @Description Delete a job by ID
+// HoldJob handles POST /api/v1/jobs/{id}/hold
+// @Summary Put a job on hold
+// @Description Put a job on hold until it is resumed. A reason is required.
 // @Tags Job Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Job ID"
-// @Success 200 {object} models.APIResponse "Job deleted successfully"
+// @Param request body models.JobStatusRequest true "Hold reason"
+// @Success 200 {object} models.APIResponse "Job put on hold successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
 // @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id} [delete]
-func (h *JobController) DeleteJob(c *gin.Context) {
+// @Router /jobs/{id}/hold [post]
+func (h *JobController) HoldJob(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
@@ -455,19 +2130,49 @@ func (h *JobController) DeleteJob(c *gin.Context) {
 		return
 	}
 
-	err := h.jobService.DeleteJob(id)
+	var req models.JobStatusRequest
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	job, err := h.jobService.HoldJob(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		}
-		h.logger.Error("Failed to delete job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to hold job", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to delete job",
+			Message: "Failed to hold job",
 			Error: &models.APIError{
-				Type:    "DatabaseError",
+				Type:    "BusinessError",
 				Details: err.Error(),
 			},
 		})
@@ -477,24 +2182,27 @@ func (h *JobController) DeleteJob(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job deleted successfully",
+		Message: "Job put on hold successfully",
+		Data:    job,
 	})
 }
 
-// StartJob handles POST /api/v1/jobs/{id}/start
-// @Summary Start a job
-// @Description Start a job by changing its status to in_progress
+// ResumeJob handles POST /api/v1/jobs/{id}/resume
+// @Summary Resume a job on hold
+// @Description Return a job on hold to the status it was held from
 // @Tags Job Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Job ID"
-// @Success 200 {object} models.APIResponse "Job started successfully"
+// @Param request body models.JobStatusRequest false "Resume note"
+// @Success 200 {object} models.APIResponse "Job resumed successfully"
 // @Failure 400 {object} models.APIResponse "Bad Request"
 // @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Job cannot move to the requested status"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id}/start [post]
-func (h *JobController) StartJob(c *gin.Context) {
+// @Router /jobs/{id}/resume [post]
+func (h *JobController) ResumeJob(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
@@ -509,6 +2217,9 @@ func (h *JobController) StartJob(c *gin.Context) {
 		return
 	}
 
+	var req models.JobStatusRequest
+	c.ShouldBindJSON(&req)
+
 	claims, exists := c.Get("jwt_claims")
 	if !exists {
 		h.logger.Error("JWT claims not found in context")
@@ -539,19 +2250,14 @@ func (h *JobController) StartJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobService.StartJob(h.ctx, id, jwtClaims.UserID)
+	job, err := h.jobService.ResumeJob(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		} else if strings.Contains(err.Error(), "cannot be started") {
-			statusCode = http.StatusBadRequest
-		}
-		h.logger.Error("Failed to start job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to resume job", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to start job",
+			Message: "Failed to resume job",
 			Error: &models.APIError{
 				Type:    "BusinessError",
 				Details: err.Error(),
@@ -563,34 +2269,114 @@ func (h *JobController) StartJob(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job started successfully",
+		Message: "Job resumed successfully",
 		Data:    job,
 	})
 }
 
-// CompleteJob handles POST /api/v1/jobs/{id}/complete
-// @Summary Complete a job
-// @Description Complete a job by changing its status to completed
+// GetAvailability handles GET /api/v1/user/{id}/availability
+// @Summary Get technician availability
+// @Description Get the working hours and time off of a technician
 // @Tags Job Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param id path string true "Job ID"
-// @Success 200 {object} models.APIResponse "Job completed successfully"
+// @Param id path string true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.TechnicianAvailability} "Availability retrieved successfully"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /user/{id}/availability [get]
+func (h *JobController) GetAvailability(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "User ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "User ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	availability, err := h.jobService.GetAvailability(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get availability", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get availability",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Availability retrieved successfully",
+		Data:    availability,
+	})
+}
+
+// SetAvailability handles PUT /api/v1/user/{id}/availability
+// @Summary Set technician availability
+// @Description Replace the weekly working hours and time off of a technician. Jobs scheduled outside them are reported as assignment conflicts.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body models.SetAvailabilityRequest true "Availability"
+// @Success 200 {object} models.APIResponse{data=models.TechnicianAvailability} "Availability updated successfully"
 // @Failure 400 {object} models.APIResponse "Bad Request"
-// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 404 {object} models.APIResponse "User not found"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id}/complete [post]
-func (h *JobController) CompleteJob(c *gin.Context) {
+// @Router /user/{id}/availability [put]
+func (h *JobController) SetAvailability(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
 			Code:    http.StatusBadRequest,
-			Message: "Job ID is required",
+			Message: "User ID is required",
 			Error: &models.APIError{
 				Type:    "ValidationError",
-				Details: "Job ID parameter is missing",
+				Details: "User ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.SetAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
 			},
 		})
 		return
@@ -626,21 +2412,16 @@ func (h *JobController) CompleteJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobService.CompleteJob(h.ctx, id, jwtClaims.UserID)
+	availability, err := h.jobService.SetAvailability(c.Request.Context(), id, &req, jwtClaims.UserID)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		} else if strings.Contains(err.Error(), "must be in progress") {
-			statusCode = http.StatusBadRequest
-		}
-		h.logger.Error("Failed to complete job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to set availability", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to complete job",
+			Message: "Failed to set availability",
 			Error: &models.APIError{
-				Type:    "BusinessError",
+				Type:    "DatabaseError",
 				Details: err.Error(),
 			},
 		})
@@ -650,44 +2431,71 @@ func (h *JobController) CompleteJob(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job completed successfully",
-		Data:    job,
+		Message: "Availability updated successfully",
+		Data:    availability,
 	})
 }
 
-// CancelJob handles POST /api/v1/jobs/{id}/cancel
-// @Summary Cancel a job
-// @Description Cancel a job by changing its status to cancelled
+// UpdateChecklistItem handles PUT /api/v1/jobs/{id}/checklist/{itemID}
+// @Summary Complete a checklist item
+// @Description Complete or reopen a checklist item of a job. Readings take a number, yes/no items "yes" or "no" and photo items the URL of the photo as value.
 // @Tags Job Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Job ID"
-// @Param request body object{reason=string} false "Cancel reason"
-// @Success 200 {object} models.APIResponse "Job cancelled successfully"
-// @Failure 400 {object} models.APIResponse "Bad Request"
-// @Failure 404 {object} models.APIResponse "Job not found"
+// @Param itemID path string true "Checklist item ID"
+// @Param request body models.UpdateChecklistItemRequest true "Checklist item"
+// @Success 200 {object} models.APIResponse{data=models.Job} "Checklist item updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid value"
+// @Failure 404 {object} models.APIResponse "Job or checklist item not found"
+// @Failure 409 {object} models.APIResponse "Job is closed or its checklist changed"
 // @Failure 500 {object} models.APIResponse "Internal Server Error"
-// @Router /jobs/{id}/cancel [post]
-func (h *JobController) CancelJob(c *gin.Context) {
+// @Router /jobs/{id}/checklist/{itemID} [put]
+func (h *JobController) UpdateChecklistItem(c *gin.Context) {
 	id := c.Param("id")
-	if id == "" {
+	itemID := c.Param("itemID")
+	if id == "" || itemID == "" {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Status:  "error",
 			Code:    http.StatusBadRequest,
-			Message: "Job ID is required",
+			Message: "Job ID and checklist item ID are required",
 			Error: &models.APIError{
 				Type:    "ValidationError",
-				Details: "Job ID parameter is missing",
+				Details: "Job ID or checklist item ID parameter is missing",
 			},
 		})
 		return
 	}
 
-	var req struct {
-		Reason string `json:"reason,omitempty"`
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
 	}
-	c.ShouldBindJSON(&req)
 
 	claims, exists := c.Get("jwt_claims")
 	if !exists {
@@ -719,21 +2527,16 @@ func (h *JobController) CancelJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobService.CancelJob(h.ctx, id, jwtClaims.UserID, req.Reason)
+	job, err := h.jobService.UpdateChecklistItem(c.Request.Context(), id, itemID, &req, jwtClaims.UserID)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "job not found" {
-			statusCode = http.StatusNotFound
-		} else if strings.Contains(err.Error(), "cannot be cancelled") {
-			statusCode = http.StatusBadRequest
-		}
-		h.logger.Error("Failed to cancel job", err)
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update checklist item", err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
-			Message: "Failed to cancel job",
+			Message: "Failed to update checklist item",
 			Error: &models.APIError{
-				Type:    "BusinessError",
+				Type:    "DatabaseError",
 				Details: err.Error(),
 			},
 		})
@@ -743,7 +2546,7 @@ func (h *JobController) CancelJob(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
 		Code:    http.StatusOK,
-		Message: "Job cancelled successfully",
+		Message: "Checklist item updated successfully",
 		Data:    job,
 	})
-}
\ No newline at end of file
+}
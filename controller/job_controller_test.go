@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startingJobService records the device locations jobs are started and
+// completed with
+type startingJobService struct {
+	services.JobServiceInterface
+	calls int
+}
+
+func (s *startingJobService) StartJob(ctx context.Context, id string, startedBy string, location *models.DeviceLocation) (*models.Job, error) {
+	s.calls++
+	return &models.Job{JobID: id}, nil
+}
+
+func (s *startingJobService) CompleteJob(ctx context.Context, id string, completedBy string, req *models.CompleteJobRequest) (*models.Job, error) {
+	s.calls++
+	return &models.Job{JobID: id}, nil
+}
+
+// TestJobLocationBodies accepts start and completion requests without a
+// body but rejects malformed ones
+func TestJobLocationBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{name: "start without body", path: "start", body: "", want: http.StatusOK},
+		{name: "start with location", path: "start", body: `{"latitude": 52.37, "longitude": 4.89}`, want: http.StatusOK},
+		{name: "start with malformed body", path: "start", body: `{"latitude": "north"}`, want: http.StatusBadRequest},
+		{name: "complete without body", path: "complete", body: "", want: http.StatusOK},
+		{name: "complete with malformed body", path: "complete", body: `{"latitude":`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		service := &startingJobService{}
+		h := NewJobController(service, logger.NewLogger("error", "json"))
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("jwt_claims", &models.JWTClaims{UserID: "tech-1"})
+		})
+		router.POST("/jobs/:id/start", h.StartJob)
+		router.POST("/jobs/:id/complete", h.CompleteJob)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/jobs/job-1/"+tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body)
+		}
+		wantCalls := 0
+		if tt.want == http.StatusOK {
+			wantCalls = 1
+		}
+		if service.calls != wantCalls {
+			t.Errorf("%s: service called %d times, want %d", tt.name, service.calls, wantCalls)
+		}
+	}
+}
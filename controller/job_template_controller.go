@@ -0,0 +1,449 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type JobTemplateController struct {
+	jobTemplateService services.JobTemplateServiceInterface
+	logger             logger.Logger
+	validator          *validator.Validate
+}
+
+func NewJobTemplateController(jobTemplateService services.JobTemplateServiceInterface, logger logger.Logger) *JobTemplateController {
+	return &JobTemplateController{
+		jobTemplateService: jobTemplateService,
+		logger:             logger,
+		validator:          validator.New(),
+	}
+}
+
+func (h *JobTemplateController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters/items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters/items")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// CreateJobTemplate handles POST /api/v1/job-templates
+// @Summary Create a job template
+// @Description Create a job template of an organization. Jobs created from it copy its name, notes, checklist, vehicles and QuickBooks line item.
+// @Tags Job Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateJobTemplateRequest true "Create job template request"
+// @Success 201 {object} models.APIResponse{data=models.JobTemplate} "Job template created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid template"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Job template creation failed"
+// @Router /job-templates [post]
+func (h *JobTemplateController) CreateJobTemplate(c *gin.Context) {
+	var req models.CreateJobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	template, err := h.jobTemplateService.CreateJobTemplate(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create job template", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create job template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Job template created successfully",
+		Data:    template,
+	})
+}
+
+// GetJobTemplates handles GET /api/v1/job-templates
+// @Summary List job templates
+// @Description Retrieve the job templates of an organization
+// @Tags Job Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Success 200 {object} models.APIResponse{data=[]models.JobTemplate} "Job templates retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Organization ID missing"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve job templates"
+// @Router /job-templates [get]
+func (h *JobTemplateController) GetJobTemplates(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	templates, err := h.jobTemplateService.GetJobTemplates(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get job templates", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job templates",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job templates retrieved successfully",
+		Data:    templates,
+	})
+}
+
+// GetJobTemplate handles GET /api/v1/job-templates/{id}
+// @Summary Get job template by ID
+// @Description Get a specific job template by its ID
+// @Tags Job Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job template ID"
+// @Success 200 {object} models.APIResponse{data=models.JobTemplate} "Job template retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Job template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /job-templates/{id} [get]
+func (h *JobTemplateController) GetJobTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	template, err := h.jobTemplateService.GetJobTemplate(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get job template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get job template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job template retrieved successfully",
+		Data:    template,
+	})
+}
+
+// UpdateJobTemplate handles PUT /api/v1/job-templates/{id}
+// @Summary Update job template
+// @Description Update a job template. Vehicles and checklist, when given, replace those of the template. Jobs created before keep their fields.
+// @Tags Job Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job template ID"
+// @Param request body models.UpdateJobTemplateRequest true "Update job template request"
+// @Success 200 {object} models.APIResponse{data=models.JobTemplate} "Job template updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /job-templates/{id} [put]
+func (h *JobTemplateController) UpdateJobTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateJobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	template, err := h.jobTemplateService.UpdateJobTemplate(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update job template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update job template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job template updated successfully",
+		Data:    template,
+	})
+}
+
+// DeleteJobTemplate handles DELETE /api/v1/job-templates/{id}
+// @Summary Delete job template
+// @Description Soft-delete a job template. Jobs created from it are kept.
+// @Tags Job Templates
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job template ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Job template deleted successfully"
+// @Failure 404 {object} models.APIResponse "Job template not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /job-templates/{id} [delete]
+func (h *JobTemplateController) DeleteJobTemplate(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job template ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job template ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.jobTemplateService.DeleteJobTemplate(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete job template", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete job template",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Job template deleted successfully",
+	})
+}
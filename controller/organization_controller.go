@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"context"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/services"
 	"fieldfuze-backend/utils/logger"
@@ -14,15 +13,13 @@ import (
 )
 
 type OrganizationController struct {
-	ctx                 context.Context
 	organizationService services.OrganizationServiceInterface
 	logger              logger.Logger
 	validator           *validator.Validate
 }
 
-func NewOrganizationController(ctx context.Context, organizationService services.OrganizationServiceInterface, logger logger.Logger) *OrganizationController {
+func NewOrganizationController(organizationService services.OrganizationServiceInterface, logger logger.Logger) *OrganizationController {
 	return &OrganizationController{
-		ctx:                 ctx,
 		organizationService: organizationService,
 		logger:              logger,
 		validator:           validator.New(),
@@ -129,13 +126,10 @@ func (h *OrganizationController) CreateOrganization(c *gin.Context) {
 		return
 	}
 
-	organization, err := h.organizationService.CreateOrganization(h.ctx, &req, jwtClaims.UserID)
+	organization, err := h.organizationService.CreateOrganization(c.Request.Context(), &req, jwtClaims.UserID)
 	if err != nil {
 		h.logger.Error("Failed to create organization", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "organization with this name already exists" {
-			statusCode = http.StatusConflict
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -188,13 +182,14 @@ func (h *OrganizationController) GetOrganizations(c *gin.Context) {
 
 	var err error
 
-	organizations, err := h.organizationService.GetOrganizations("")
+	organizations, err := h.organizationService.GetOrganizations(c.Request.Context())
 
 	if err != nil {
 		h.logger.Error("Failed to get roles", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get roles",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -300,13 +295,10 @@ func (h *OrganizationController) UpdateOrganization(c *gin.Context) {
 		return
 	}
 
-	organization, err := h.organizationService.UpdateOrganization(req.ID, &req, jwtClaims.UserID)
+	organization, err := h.organizationService.UpdateOrganization(c.Request.Context(), req.ID, &req, jwtClaims.UserID)
 	if err != nil {
 		h.logger.Error("Failed to update organization", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "organization with this name already exists" {
-			statusCode = http.StatusConflict
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -344,12 +336,48 @@ func (h *OrganizationController) DeleteOrganization(c *gin.Context) {
 		return
 	}
 
-	err := h.organizationService.DeleteOrganization(organizationID)
-	if err != nil {
-		h.logger.Error("Failed to delete organization", err)
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Status:  "error",
 			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.organizationService.DeleteOrganization(c.Request.Context(), organizationID, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		h.logger.Error("Failed to delete organization", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
 			Message: "Failed to delete organization",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -365,3 +393,43 @@ func (h *OrganizationController) DeleteOrganization(c *gin.Context) {
 		Message: "Organization deleted successfully",
 	})
 }
+
+func (h *OrganizationController) RestoreOrganization(c *gin.Context) {
+	organizationID := c.Param("id")
+	if organizationID == "" {
+		h.logger.Error("Organization ID is required")
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Organization ID cannot be empty",
+			},
+		})
+		return
+	}
+
+	organization, err := h.organizationService.RestoreOrganization(c.Request.Context(), organizationID)
+	if err != nil {
+		h.logger.Error("Failed to restore organization", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to restore organization",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Organization restored successfully",
+		Data:    organization,
+	})
+}
@@ -0,0 +1,449 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type ProjectController struct {
+	projectService services.ProjectServiceInterface
+	logger         logger.Logger
+	validator      *validator.Validate
+}
+
+func NewProjectController(projectService services.ProjectServiceInterface, logger logger.Logger) *ProjectController {
+	return &ProjectController{
+		projectService: projectService,
+		logger:         logger,
+		validator:      validator.New(),
+	}
+}
+
+func (h *ProjectController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters/items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters/items")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// CreateProject handles POST /api/v1/projects
+// @Summary Create a project
+// @Description Create a project grouping the jobs of a multi-visit piece of work, such as a site survey, the installation and its inspection. Jobs join it with the projectID of a create or update request.
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateProjectRequest true "Create project request"
+// @Success 201 {object} models.APIResponse{data=models.Project} "Project created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid project"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Project creation failed"
+// @Router /projects [post]
+func (h *ProjectController) CreateProject(c *gin.Context) {
+	var req models.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	project, err := h.projectService.CreateProject(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create project", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create project",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Project created successfully",
+		Data:    project,
+	})
+}
+
+// GetProjects handles GET /api/v1/projects
+// @Summary List projects
+// @Description Retrieve the projects of an organization with the progress rolled up from their jobs
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Success 200 {object} models.APIResponse{data=[]models.Project} "Projects retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Organization ID missing"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve projects"
+// @Router /projects [get]
+func (h *ProjectController) GetProjects(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	projects, err := h.projectService.GetProjects(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get projects", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get projects",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Projects retrieved successfully",
+		Data:    projects,
+	})
+}
+
+// GetProject handles GET /api/v1/projects/{id}
+// @Summary Get project by ID
+// @Description Get a specific project by its ID with its jobs and the progress rolled up from their statuses
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.APIResponse{data=models.Project} "Project retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Project not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /projects/{id} [get]
+func (h *ProjectController) GetProject(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Project ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Project ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	project, err := h.projectService.GetProject(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get project", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get project",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Project retrieved successfully",
+		Data:    project,
+	})
+}
+
+// UpdateProject handles PUT /api/v1/projects/{id}
+// @Summary Update project
+// @Description Update the name, description or client of a project
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body models.UpdateProjectRequest true "Update project request"
+// @Success 200 {object} models.APIResponse{data=models.Project} "Project updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Project not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /projects/{id} [put]
+func (h *ProjectController) UpdateProject(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Project ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Project ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update project", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update project",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Project updated successfully",
+		Data:    project,
+	})
+}
+
+// DeleteProject handles DELETE /api/v1/projects/{id}
+// @Summary Delete project
+// @Description Soft-delete a project. Its jobs are kept.
+// @Tags Projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Project deleted successfully"
+// @Failure 404 {object} models.APIResponse "Project not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /projects/{id} [delete]
+func (h *ProjectController) DeleteProject(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Project ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Project ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.projectService.DeleteProject(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete project", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete project",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Project deleted successfully",
+	})
+}
@@ -0,0 +1,505 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type RecurringJobController struct {
+	recurringJobService services.RecurringJobServiceInterface
+	logger              logger.Logger
+	validator           *validator.Validate
+}
+
+func NewRecurringJobController(recurringJobService services.RecurringJobServiceInterface, logger logger.Logger) *RecurringJobController {
+	return &RecurringJobController{
+		recurringJobService: recurringJobService,
+		logger:              logger,
+		validator:           validator.New(),
+	}
+}
+
+func (h *RecurringJobController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters/items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters/items")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			case "datetime":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be a YYYY-MM-DD date")
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// CreateRecurringJob handles POST /api/v1/recurring-jobs
+// @Summary Create a recurring job
+// @Description Create a recurring job or maintenance plan from an RFC 5545 recurrence rule. Job instances are materialised a configured number of days ahead.
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateRecurringJobRequest true "Create recurring job request"
+// @Success 201 {object} models.APIResponse{data=models.RecurringJob} "Recurring job created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid recurrence"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Recurring job creation failed"
+// @Router /recurring-jobs [post]
+func (h *RecurringJobController) CreateRecurringJob(c *gin.Context) {
+	var req models.CreateRecurringJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	recurringJob, err := h.recurringJobService.CreateRecurringJob(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create recurring job", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create recurring job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Recurring job created successfully",
+		Data:    recurringJob,
+	})
+}
+
+// GetRecurringJobs handles GET /api/v1/recurring-jobs
+// @Summary List recurring jobs
+// @Description Retrieve the recurring jobs of an organization
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Success 200 {object} models.APIResponse{data=[]models.RecurringJob} "Recurring jobs retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Organization ID missing"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve recurring jobs"
+// @Router /recurring-jobs [get]
+func (h *RecurringJobController) GetRecurringJobs(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	recurringJobs, err := h.recurringJobService.GetRecurringJobs(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get recurring jobs", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get recurring jobs",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Recurring jobs retrieved successfully",
+		Data:    recurringJobs,
+	})
+}
+
+// GetRecurringJob handles GET /api/v1/recurring-jobs/{id}
+// @Summary Get recurring job by ID
+// @Description Get a specific recurring job by its ID
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring job ID"
+// @Success 200 {object} models.APIResponse{data=models.RecurringJob} "Recurring job retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Recurring job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /recurring-jobs/{id} [get]
+func (h *RecurringJobController) GetRecurringJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Recurring job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Recurring job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	recurringJob, err := h.recurringJobService.GetRecurringJob(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get recurring job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get recurring job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Recurring job retrieved successfully",
+		Data:    recurringJob,
+	})
+}
+
+// GetOccurrences handles GET /api/v1/recurring-jobs/{id}/occurrences
+// @Summary Preview recurring job occurrences
+// @Description List the occurrences of a recurring job in a period, with skipped exception dates and the instances already materialised
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring job ID"
+// @Param from query string false "First day (YYYY-MM-DD) or time, defaults to today"
+// @Param to query string false "Last day (YYYY-MM-DD) or time, defaults to a week after from"
+// @Success 200 {object} models.APIResponse{data=[]models.RecurringJobOccurrence} "Occurrences retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid range"
+// @Failure 404 {object} models.APIResponse "Recurring job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /recurring-jobs/{id}/occurrences [get]
+func (h *RecurringJobController) GetOccurrences(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Recurring job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Recurring job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	occurrences, err := h.recurringJobService.PreviewOccurrences(c.Request.Context(), id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get recurring job occurrences", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get recurring job occurrences",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Occurrences retrieved successfully",
+		Data:    occurrences,
+	})
+}
+
+// UpdateRecurringJob handles PUT /api/v1/recurring-jobs/{id}
+// @Summary Update recurring job
+// @Description Update a recurring job. With applyToFuture the pending future instances follow the change; otherwise only instances materialised from now on do.
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring job ID"
+// @Param request body models.UpdateRecurringJobRequest true "Update recurring job request"
+// @Success 200 {object} models.APIResponse{data=models.RecurringJob} "Recurring job updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Recurring job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /recurring-jobs/{id} [put]
+func (h *RecurringJobController) UpdateRecurringJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Recurring job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Recurring job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateRecurringJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	recurringJob, err := h.recurringJobService.UpdateRecurringJob(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update recurring job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update recurring job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Recurring job updated successfully",
+		Data:    recurringJob,
+	})
+}
+
+// DeleteRecurringJob handles DELETE /api/v1/recurring-jobs/{id}
+// @Summary Delete recurring job
+// @Description Soft-delete a recurring job together with its pending future instances
+// @Tags Recurring Jobs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring job ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "Recurring job deleted successfully"
+// @Failure 404 {object} models.APIResponse "Recurring job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /recurring-jobs/{id} [delete]
+func (h *RecurringJobController) DeleteRecurringJob(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Recurring job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Recurring job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.recurringJobService.DeleteRecurringJob(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete recurring job", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete recurring job",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Recurring job deleted successfully",
+	})
+}
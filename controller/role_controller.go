@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"context"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/services"
 	"fieldfuze-backend/utils/logger"
@@ -14,15 +13,13 @@ import (
 )
 
 type RoleController struct {
-	ctx         context.Context
 	roleService services.RoleServiceInterface
 	logger      logger.Logger
 	validator   *validator.Validate
 }
 
-func NewRoleController(ctx context.Context, roleService services.RoleServiceInterface, logger logger.Logger) *RoleController {
+func NewRoleController(roleService services.RoleServiceInterface, logger logger.Logger) *RoleController {
 	return &RoleController{
-		ctx:         ctx,
 		roleService: roleService,
 		logger:      logger,
 		validator:   validator.New(),
@@ -89,16 +86,17 @@ func (h *RoleController) GetRoles(c *gin.Context) {
 	var err error
 
 	if status != "" {
-		roles, err = h.roleService.GetRoleAssignmentsByStatus(status)
+		roles, err = h.roleService.GetRoleAssignmentsByStatus(c.Request.Context(), status)
 	} else {
-		roles, err = h.roleService.GetRoleAssignments()
+		roles, err = h.roleService.GetRoleAssignments(c.Request.Context())
 	}
 
 	if err != nil {
 		h.logger.Error("Failed to get roles", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get roles",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -217,13 +215,10 @@ func (h *RoleController) CreateRole(c *gin.Context) {
 		return
 	}
 
-	role, err := h.roleService.CreateRole(h.ctx, &req, jwtClaims.UserID)
+	role, err := h.roleService.CreateRole(c.Request.Context(), &req, jwtClaims.UserID)
 	if err != nil {
 		h.logger.Error("Failed to create role", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "role with this name already exists" {
-			statusCode = http.StatusConflict
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -260,13 +255,10 @@ func (h *RoleController) CreateRole(c *gin.Context) {
 func (h *RoleController) GetRole(c *gin.Context) {
 	roleID := c.Param("id")
 
-	role, err := h.roleService.GetRoleAssignmentByID(roleID)
+	role, err := h.roleService.GetRoleAssignmentByID(c.Request.Context(), roleID)
 	if err != nil {
 		h.logger.Error("Failed to get role by ID", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "role not found" || err.Error() == "role ID is required" {
-			statusCode = http.StatusNotFound
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -377,15 +369,10 @@ func (h *RoleController) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	updatedRole, err := h.roleService.UpdateRoleAssignment(roleID, &req, jwtClaims.UserID)
+	updatedRole, err := h.roleService.UpdateRoleAssignment(c.Request.Context(), roleID, &req, jwtClaims.UserID)
 	if err != nil {
 		h.logger.Error("Failed to update role", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "role not found" {
-			statusCode = http.StatusNotFound
-		} else if err.Error() == "role with this name already exists" {
-			statusCode = http.StatusConflict
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -408,12 +395,13 @@ func (h *RoleController) UpdateRole(c *gin.Context) {
 
 // DeleteRole handles DELETE /api/v1/auth/user/role/:id
 // @Summary Delete role by ID
-// @Description Delete role by ID
+// @Description Soft-delete role by ID. The role is purged after the retention period unless restored.
 // @Tags Role Management
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Role ID"
+// @Param request body object{reason=string} false "Delete reason"
 // @Success 200 {object} models.APIResponse "Role deleted successfully"
 // @Failure 400 {object} models.APIResponse "Bad Request - Invalid role ID"
 // @Failure 404 {object} models.APIResponse "Not Found - Role does not exist"
@@ -422,13 +410,45 @@ func (h *RoleController) UpdateRole(c *gin.Context) {
 func (h *RoleController) DeleteRole(c *gin.Context) {
 	roleID := c.Param("id")
 
-	err := h.roleService.DeleteRoleAssignment(roleID)
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.roleService.DeleteRoleAssignment(c.Request.Context(), roleID, jwtClaims.UserID, req.Reason)
 	if err != nil {
 		h.logger.Error("Failed to delete role", err)
-		statusCode := http.StatusInternalServerError
-		if err.Error() == "role not found" || err.Error() == "role ID is required" {
-			statusCode = http.StatusNotFound
-		}
+		statusCode := databaseErrorStatus(err)
 		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
 			Code:    statusCode,
@@ -450,3 +470,42 @@ func (h *RoleController) DeleteRole(c *gin.Context) {
 		},
 	})
 }
+
+// RestoreRole handles POST /api/v1/auth/user/role/:id/restore
+// @Summary Restore a deleted role
+// @Description Restore a soft-deleted role before it is purged
+// @Tags Role Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} models.APIResponse "Role restored successfully"
+// @Failure 404 {object} models.APIResponse "Not Found - Deleted role does not exist"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to restore role"
+// @Router /user/role/{id}/restore [post]
+func (h *RoleController) RestoreRole(c *gin.Context) {
+	roleID := c.Param("id")
+
+	role, err := h.roleService.RestoreRoleAssignment(c.Request.Context(), roleID)
+	if err != nil {
+		h.logger.Error("Failed to restore role", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to restore role",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Role restored successfully",
+		Data:    role,
+	})
+}
@@ -0,0 +1,451 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type SLAPolicyController struct {
+	slaService services.SLAServiceInterface
+	logger     logger.Logger
+	validator  *validator.Validate
+}
+
+func NewSLAPolicyController(slaService services.SLAServiceInterface, logger logger.Logger) *SLAPolicyController {
+	return &SLAPolicyController{
+		slaService: slaService,
+		logger:     logger,
+		validator:  validator.New(),
+	}
+}
+
+func (h *SLAPolicyController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters/items")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters/items")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// CreateSLAPolicy handles POST /api/v1/sla-policies
+// @Summary Create an SLA policy
+// @Description Create the SLA policy of an organization for a job type, or for every job type without a policy of its own when no job type is given. Its targets set the response and resolution times per priority of the jobs created afterwards. An organization has one policy per job type.
+// @Tags SLA Policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSLAPolicyRequest true "Create SLA policy request"
+// @Success 201 {object} models.APIResponse{data=models.SLAPolicy} "SLA policy created successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Invalid policy"
+// @Failure 409 {object} models.APIResponse "Conflict - The organization already has a policy for the job type"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - SLA policy creation failed"
+// @Router /sla-policies [post]
+func (h *SLAPolicyController) CreateSLAPolicy(c *gin.Context) {
+	var req models.CreateSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	policy, err := h.slaService.CreateSLAPolicy(c.Request.Context(), &req, jwtClaims.UserID)
+	if err != nil {
+		h.logger.Error("Failed to create SLA policy", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to create SLA policy",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "SLA policy created successfully",
+		Data:    policy,
+	})
+}
+
+// GetSLAPolicies handles GET /api/v1/sla-policies
+// @Summary List SLA policies
+// @Description Retrieve the SLA policies of an organization
+// @Tags SLA Policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orgID query string false "Organization ID, defaults to the X-Organization-ID header"
+// @Success 200 {object} models.APIResponse{data=[]models.SLAPolicy} "SLA policies retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request - Organization ID missing"
+// @Failure 500 {object} models.APIResponse "Internal Server Error - Failed to retrieve SLA policies"
+// @Router /sla-policies [get]
+func (h *SLAPolicyController) GetSLAPolicies(c *gin.Context) {
+	orgID := c.Query("orgID")
+	if orgID == "" {
+		orgID = requestctx.TenantID(c.Request.Context())
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Organization ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "orgID query parameter or X-Organization-ID header is missing",
+			},
+		})
+		return
+	}
+
+	policies, err := h.slaService.GetSLAPolicies(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get SLA policies", err)
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get SLA policies",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "SLA policies retrieved successfully",
+		Data:    policies,
+	})
+}
+
+// GetSLAPolicy handles GET /api/v1/sla-policies/{id}
+// @Summary Get SLA policy by ID
+// @Description Get a specific SLA policy by its ID
+// @Tags SLA Policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "SLA policy ID"
+// @Success 200 {object} models.APIResponse{data=models.SLAPolicy} "SLA policy retrieved successfully"
+// @Failure 404 {object} models.APIResponse "SLA policy not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /sla-policies/{id} [get]
+func (h *SLAPolicyController) GetSLAPolicy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "SLA policy ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "SLA policy ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	policy, err := h.slaService.GetSLAPolicy(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get SLA policy", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get SLA policy",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "SLA policy retrieved successfully",
+		Data:    policy,
+	})
+}
+
+// UpdateSLAPolicy handles PUT /api/v1/sla-policies/{id}
+// @Summary Update SLA policy
+// @Description Update an SLA policy. Targets, when given, replace the targets of the policy. Jobs created before keep their due dates until their priority or job type changes.
+// @Tags SLA Policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "SLA policy ID"
+// @Param request body models.UpdateSLAPolicyRequest true "Update SLA policy request"
+// @Success 200 {object} models.APIResponse{data=models.SLAPolicy} "SLA policy updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "SLA policy not found"
+// @Failure 409 {object} models.APIResponse "Conflict - The organization already has a policy for the job type"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /sla-policies/{id} [put]
+func (h *SLAPolicyController) UpdateSLAPolicy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "SLA policy ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "SLA policy ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateSLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	policy, err := h.slaService.UpdateSLAPolicy(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update SLA policy", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update SLA policy",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "SLA policy updated successfully",
+		Data:    policy,
+	})
+}
+
+// DeleteSLAPolicy handles DELETE /api/v1/sla-policies/{id}
+// @Summary Delete SLA policy
+// @Description Soft-delete an SLA policy. Jobs keep the due dates calculated from it.
+// @Tags SLA Policies
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "SLA policy ID"
+// @Param request body object{reason=string} false "Delete reason"
+// @Success 200 {object} models.APIResponse "SLA policy deleted successfully"
+// @Failure 404 {object} models.APIResponse "SLA policy not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /sla-policies/{id} [delete]
+func (h *SLAPolicyController) DeleteSLAPolicy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "SLA policy ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "SLA policy ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	c.ShouldBindJSON(&req)
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	err := h.slaService.DeleteSLAPolicy(c.Request.Context(), id, jwtClaims.UserID, req.Reason)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to delete SLA policy", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to delete SLA policy",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "SLA policy deleted successfully",
+	})
+}
@@ -0,0 +1,846 @@
+package controller
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type TimesheetController struct {
+	timesheetService services.TimesheetServiceInterface
+	logger           logger.Logger
+	validator        *validator.Validate
+}
+
+func NewTimesheetController(timesheetService services.TimesheetServiceInterface, logger logger.Logger) *TimesheetController {
+	return &TimesheetController{
+		timesheetService: timesheetService,
+		logger:           logger,
+		validator:        validator.New(),
+	}
+}
+
+func (h *TimesheetController) formatValidationErrors(err error) string {
+	var errorMessages []string
+
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, fieldError := range validationErrors {
+			switch fieldError.Tag() {
+			case "required":
+				errorMessages = append(errorMessages, fieldError.Field()+" is required")
+			case "min":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at least "+fieldError.Param()+" characters")
+			case "max":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be at most "+fieldError.Param()+" characters")
+			case "oneof":
+				errorMessages = append(errorMessages, fieldError.Field()+" must be one of: "+strings.ReplaceAll(fieldError.Param(), " ", ", "))
+			default:
+				errorMessages = append(errorMessages, fieldError.Field()+" is invalid")
+			}
+		}
+	}
+
+	return strings.Join(errorMessages, "; ")
+}
+
+// ClockIn handles POST /api/v1/jobs/{id}/time/clock-in
+// @Summary Clock in on a job
+// @Description Start tracking the caller's time on a job, as labor (default) or travel. Clocking in to the other category while clocked in on the job switches over. A technician clocked in on another job must clock out first.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.ClockInRequest false "Category and notes"
+// @Success 201 {object} models.APIResponse{data=models.TimeEntry} "Clocked in successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Conflict - Not clocked in, clocked in elsewhere or week approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/time/clock-in [post]
+func (h *TimesheetController) ClockIn(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.ClockInRequest
+	c.ShouldBindJSON(&req)
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	entry, err := h.timesheetService.ClockIn(c.Request.Context(), id, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to clock in", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to clock in",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Clocked in successfully",
+		Data:    entry,
+	})
+}
+
+// PauseTime handles POST /api/v1/jobs/{id}/time/pause
+// @Summary Pause time on a job
+// @Description End the caller's running time entry on a job until it is resumed
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.ClockOutRequest false "Notes"
+// @Success 200 {object} models.APIResponse{data=models.TimeEntry} "Time paused successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Conflict - Not clocked in, clocked in elsewhere or week approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/time/pause [post]
+func (h *TimesheetController) PauseTime(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.ClockOutRequest
+	c.ShouldBindJSON(&req)
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	entry, err := h.timesheetService.PauseTime(c.Request.Context(), id, jwtClaims.UserID, req.Notes)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to pause time", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to pause time",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Time paused successfully",
+		Data:    entry,
+	})
+}
+
+// ClockOut handles POST /api/v1/jobs/{id}/time/clock-out
+// @Summary Clock out of a job
+// @Description End the caller's running time entry on a job
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body models.ClockOutRequest false "Notes"
+// @Success 200 {object} models.APIResponse{data=models.TimeEntry} "Clocked out successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Conflict - Not clocked in, clocked in elsewhere or week approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/time/clock-out [post]
+func (h *TimesheetController) ClockOut(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.ClockOutRequest
+	c.ShouldBindJSON(&req)
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	entry, err := h.timesheetService.ClockOut(c.Request.Context(), id, jwtClaims.UserID, req.Notes)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to clock out", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to clock out",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Clocked out successfully",
+		Data:    entry,
+	})
+}
+
+// ResumeTime handles POST /api/v1/jobs/{id}/time/resume
+// @Summary Resume time on a job
+// @Description Start a new time entry of the caller on a job in the category of the paused one
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 201 {object} models.APIResponse{data=models.TimeEntry} "Time resumed successfully"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 409 {object} models.APIResponse "Conflict - Not clocked in, clocked in elsewhere or week approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/time/resume [post]
+func (h *TimesheetController) ResumeTime(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	entry, err := h.timesheetService.ResumeTime(c.Request.Context(), id, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to resume time", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to resume time",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusCreated,
+		Message: "Time resumed successfully",
+		Data:    entry,
+	})
+}
+
+// GetJobTimeEntries handles GET /api/v1/jobs/{id}/time
+// @Summary List time on a job
+// @Description List the time entries of all technicians on a job in start order
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.APIResponse{data=[]models.TimeEntry} "Time entries retrieved successfully"
+// @Failure 404 {object} models.APIResponse "Job not found"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /jobs/{id}/time [get]
+func (h *TimesheetController) GetJobTimeEntries(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Job ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Job ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	entries, err := h.timesheetService.GetJobTimeEntries(c.Request.Context(), id)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get time entries", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get time entries",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Time entries retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// UpdateTimeEntry handles PUT /api/v1/time-entries/{entryID}
+// @Summary Correct a time entry
+// @Description Correct the category, times or notes of a time entry, e.g. of a technician who forgot to clock out. Entries of approved weeks are locked.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param entryID path string true "Time entry ID"
+// @Param request body models.UpdateTimeEntryRequest true "Corrections"
+// @Success 200 {object} models.APIResponse{data=models.TimeEntry} "Time entry updated successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 404 {object} models.APIResponse "Time entry not found"
+// @Failure 409 {object} models.APIResponse "Conflict - Week approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /time-entries/{entryID} [put]
+func (h *TimesheetController) UpdateTimeEntry(c *gin.Context) {
+	entryID := c.Param("entryID")
+	if entryID == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Time entry ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "Time entry ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	entry, err := h.timesheetService.UpdateTimeEntry(c.Request.Context(), entryID, &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to update time entry", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to update time entry",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Time entry updated successfully",
+		Data:    entry,
+	})
+}
+
+// GetTimesheet handles GET /api/v1/user/{id}/timesheets
+// @Summary Get a weekly timesheet
+// @Description Get a technician's labor and travel time of a week, per day and per job, in the timezone of their working hours. Entries count towards the day and week they started in; running entries are listed but not counted.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param week query string false "A date (YYYY-MM-DD) in the week, the current week by default"
+// @Success 200 {object} models.APIResponse{data=models.TimesheetSummary} "Timesheet retrieved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /user/{id}/timesheets [get]
+func (h *TimesheetController) GetTimesheet(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "User ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "User ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	timesheet, err := h.timesheetService.GetTimesheet(c.Request.Context(), id, c.Query("week"))
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to get timesheet", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to get timesheet",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Timesheet retrieved successfully",
+		Data:    timesheet,
+	})
+}
+
+// ApproveTimesheet handles POST /api/v1/user/{user_id}/timesheets/{week}/approve
+// @Summary Approve a weekly timesheet
+// @Description Approve a technician's week once it is over and none of its entries is running. The entries of an approved week are locked until it is reopened.
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param week path string true "A date (YYYY-MM-DD) in the week"
+// @Success 200 {object} models.APIResponse{data=models.TimesheetSummary} "Timesheet approved successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 409 {object} models.APIResponse "Conflict - Week not over, entries running or already approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /user/{user_id}/timesheets/{week}/approve [post]
+func (h *TimesheetController) ApproveTimesheet(c *gin.Context) {
+	id := c.Param("user_id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "User ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "User ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	timesheet, err := h.timesheetService.ApproveTimesheet(c.Request.Context(), id, c.Param("week"), jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to approve timesheet", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to approve timesheet",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Timesheet approved successfully",
+		Data:    timesheet,
+	})
+}
+
+// ReopenTimesheet handles POST /api/v1/user/{user_id}/timesheets/{week}/reopen
+// @Summary Reopen a weekly timesheet
+// @Description Unlock an approved week of a technician so its entries can be corrected
+// @Tags Job Management
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param week path string true "A date (YYYY-MM-DD) in the week"
+// @Param request body models.ReopenTimesheetRequest true "Reopen reason"
+// @Success 200 {object} models.APIResponse{data=models.TimesheetSummary} "Timesheet reopened successfully"
+// @Failure 400 {object} models.APIResponse "Bad Request"
+// @Failure 409 {object} models.APIResponse "Conflict - Week not approved"
+// @Failure 500 {object} models.APIResponse "Internal Server Error"
+// @Router /user/{user_id}/timesheets/{week}/reopen [post]
+func (h *TimesheetController) ReopenTimesheet(c *gin.Context) {
+	id := c.Param("user_id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "User ID is required",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: "User ID parameter is missing",
+			},
+		})
+		return
+	}
+
+	var req models.ReopenTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		h.logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusBadRequest,
+			Message: "Validation failed",
+			Error: &models.APIError{
+				Type:    "ValidationError",
+				Details: h.formatValidationErrors(err),
+			},
+		})
+		return
+	}
+
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		h.logger.Error("JWT claims not found in context")
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusUnauthorized,
+			Message: "Authentication required",
+			Error: &models.APIError{
+				Type:    "AuthenticationError",
+				Details: "User not authenticated",
+			},
+		})
+		return
+	}
+
+	jwtClaims, ok := claims.(*models.JWTClaims)
+	if !ok {
+		h.logger.Error("Invalid JWT claims type")
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Status:  "error",
+			Code:    http.StatusInternalServerError,
+			Message: "Invalid token claims",
+			Error: &models.APIError{
+				Type:    "TokenError",
+				Details: "Invalid token structure",
+			},
+		})
+		return
+	}
+
+	timesheet, err := h.timesheetService.ReopenTimesheet(c.Request.Context(), id, c.Param("week"), &req, jwtClaims.UserID)
+	if err != nil {
+		statusCode := databaseErrorStatus(err)
+		h.logger.Error("Failed to reopen timesheet", err)
+		c.JSON(statusCode, models.APIResponse{
+			Status:  "error",
+			Code:    statusCode,
+			Message: "Failed to reopen timesheet",
+			Error: &models.APIError{
+				Type:    "DatabaseError",
+				Details: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Status:  "success",
+		Code:    http.StatusOK,
+		Message: "Timesheet reopened successfully",
+		Data:    timesheet,
+	})
+}
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fieldfuze-backend/middelware"
 	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
 	"fieldfuze-backend/services"
 	"fmt"
 	"net/http"
@@ -15,25 +17,23 @@ import (
 )
 
 type UserController struct {
-	ctx         context.Context
 	userService services.UserServiceInterface
 	jwtManager  *middelware.JWTManager
 	logger      logger.Logger
 }
 
-func NewUserController(ctx context.Context, userService services.UserServiceInterface, logger logger.Logger, jwtManager *middelware.JWTManager) *UserController {
+func NewUserController(userService services.UserServiceInterface, logger logger.Logger, jwtManager *middelware.JWTManager) *UserController {
 	return &UserController{
-		ctx:         ctx,
 		userService: userService,
 		logger:      logger,
 		jwtManager:  jwtManager,
 	}
 }
 
-// invalidateUserPermissions clears permission cache and logs security events
-func (h *UserController) invalidateUserPermissions(userID, operation string) {
-	// Clear permission cache through JWT manager
-	h.jwtManager.ClearPermissionCache()
+// invalidateUserPermissions clears the cached user and permissions and logs security events
+func (h *UserController) invalidateUserPermissions(ctx context.Context, userID, operation string) {
+	// Clear cached user record and permission cache through JWT manager
+	h.jwtManager.InvalidateUser(ctx, userID)
 
 	// Log security event for audit trail
 	h.logger.Infof("SECURITY EVENT: Permission cache cleared for user %s due to %s", userID, operation)
@@ -67,12 +67,13 @@ func (h *UserController) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error("Failed to create user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to create user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -106,12 +107,13 @@ func (h *UserController) Register(c *gin.Context) {
 func (h *UserController) GetUser(c *gin.Context) {
 	userID := c.Param("id")
 
-	user, err := h.userService.GetUserByID(userID)
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user by ID", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get user by ID",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -165,12 +167,13 @@ func (h *UserController) GetUserList(c *gin.Context) {
 	}
 
 	// Get all users
-	allUsers, err := h.userService.GetUsers()
+	allUsers, err := h.userService.GetUsers(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to get user list", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get user list",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -275,12 +278,13 @@ func (h *UserController) UpdateUser(c *gin.Context) {
 	req.Roles = nil
 
 	// Update user in the repository
-	updatedUser, err := h.userService.UpdateUser(userID, &req)
+	updatedUser, err := h.userService.UpdateUser(c.Request.Context(), userID, &req)
 	if err != nil {
 		h.logger.Error("Failed to update user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to update user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -463,12 +467,13 @@ func (h *UserController) AssignRole(c *gin.Context) {
 	}
 
 	// Check if user exists
-	_, err := h.userService.GetUserByID(userID)
+	_, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -479,9 +484,9 @@ func (h *UserController) AssignRole(c *gin.Context) {
 	}
 
 	// Assign role to user using the existing method
-	updatedUser, err := h.userService.AssignRoleToUser(userID, roleID)
+	updatedUser, err := h.userService.AssignRoleToUser(c.Request.Context(), userID, roleID)
 	if err != nil {
-		if err.Error() == "user already has this role" {
+		if errors.Is(err, repository.ErrUserHasRole) {
 			c.JSON(http.StatusConflict, models.APIResponse{
 				Status:  "error",
 				Code:    http.StatusConflict,
@@ -494,7 +499,7 @@ func (h *UserController) AssignRole(c *gin.Context) {
 			return
 		}
 
-		if err.Error() == "role not found" {
+		if errors.Is(err, repository.ErrRoleNotFound) {
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Status:  "error",
 				Code:    http.StatusNotFound,
@@ -508,9 +513,10 @@ func (h *UserController) AssignRole(c *gin.Context) {
 		}
 
 		h.logger.Error("Failed to assign role to user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to assign role to user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -521,7 +527,7 @@ func (h *UserController) AssignRole(c *gin.Context) {
 	}
 
 	// Clear permission cache for the user after role assignment
-	h.invalidateUserPermissions(userID, "role_assignment")
+	h.invalidateUserPermissions(c.Request.Context(), userID, "role_assignment")
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
@@ -579,12 +585,13 @@ func (h *UserController) DetachRole(c *gin.Context) {
 	}
 
 	// Check if user exists
-	_, err := h.userService.GetUserByID(userID)
+	_, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		h.logger.Error("Failed to get user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to get user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -595,9 +602,9 @@ func (h *UserController) DetachRole(c *gin.Context) {
 	}
 
 	// Remove role from user using the existing method
-	updatedUser, err := h.userService.RemoveRoleFromUser(userID, roleID)
+	updatedUser, err := h.userService.RemoveRoleFromUser(c.Request.Context(), userID, roleID)
 	if err != nil {
-		if err.Error() == "role not found for user" {
+		if errors.Is(err, repository.ErrUserRoleNotFound) {
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Status:  "error",
 				Code:    http.StatusNotFound,
@@ -611,9 +618,10 @@ func (h *UserController) DetachRole(c *gin.Context) {
 		}
 
 		h.logger.Error("Failed to remove role from user", fmt.Errorf("error: %v", err))
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+		statusCode := databaseErrorStatus(err)
+		c.JSON(statusCode, models.APIResponse{
 			Status:  "error",
-			Code:    http.StatusInternalServerError,
+			Code:    statusCode,
 			Message: "Failed to remove role from user",
 			Error: &models.APIError{
 				Type:    "DatabaseError",
@@ -624,7 +632,7 @@ func (h *UserController) DetachRole(c *gin.Context) {
 	}
 
 	// Clear permission cache for the user after role removal
-	h.invalidateUserPermissions(userID, "role_removal")
+	h.invalidateUserPermissions(c.Request.Context(), userID, "role_removal")
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Status:  "success",
@@ -0,0 +1,148 @@
+package dal
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is the underlying error of the ErrUnavailable returned while
+// the circuit breaker is rejecting requests
+var ErrCircuitOpen = errors.New("circuit breaker is open, database requests are temporarily rejected")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitBreaker stops calls to DynamoDB while the error rate is high so a
+// struggling table is not hammered by retries. It counts requests in fixed
+// windows; once a window has at least minRequests and the failure ratio
+// reaches the threshold the breaker opens. After openTimeout a single probe
+// request is let through: success closes the breaker, failure reopens it.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	failureRatio float64
+	minRequests  int
+	window       time.Duration
+	openTimeout  time.Duration
+
+	state       string
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(failureRatio float64, minRequests int, window, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureRatio: failureRatio,
+		minRequests:  minRequests,
+		window:       window,
+		openTimeout:  openTimeout,
+		state:        CircuitClosed,
+		windowStart:  time.Now(),
+		now:          time.Now,
+	}
+}
+
+// Allow returns an ErrUnavailable error when the request must not be sent
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return newError(ErrUnavailable, ErrCircuitOpen)
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return newError(ErrUnavailable, ErrCircuitOpen)
+		}
+		b.probing = true
+		return nil
+	}
+
+	return nil
+}
+
+// Record reports the outcome of a request that Allow let through
+func (b *CircuitBreaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+		if failed {
+			b.trip(now)
+		} else {
+			b.reset(now)
+		}
+		return
+	}
+
+	if b.state == CircuitOpen {
+		return
+	}
+
+	if now.Sub(b.windowStart) >= b.window {
+		b.reset(now)
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+
+	if b.requests >= b.minRequests && float64(b.failures)/float64(b.requests) >= b.failureRatio {
+		b.trip(now)
+	}
+}
+
+// Release returns a request that Allow let through without reporting an
+// outcome, such as one abandoned by its caller. A half-open probe is given
+// back so the next request probes instead.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// trip opens the breaker
+func (b *CircuitBreaker) trip(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+	b.requests = 0
+	b.failures = 0
+}
+
+// reset closes the breaker and starts a new counting window
+func (b *CircuitBreaker) reset(now time.Time) {
+	b.state = CircuitClosed
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+}
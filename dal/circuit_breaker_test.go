@@ -0,0 +1,110 @@
+package dal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testBreaker returns a breaker that opens at half of at least 4 requests
+// failing, on a clock the test moves
+func testBreaker() (*CircuitBreaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(0.5, 4, time.Minute, 10*time.Second)
+	b.windowStart = now
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+// record lets n requests through b and records them as failed or not
+func record(t *testing.T, b *CircuitBreaker, n int, failed bool) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+		b.Record(failed)
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	b, _ := testBreaker()
+
+	record(t, b, 3, true)
+	if got := b.State(); got != CircuitClosed {
+		t.Fatalf("state below min requests = %s, want %s", got, CircuitClosed)
+	}
+
+	record(t, b, 1, false)
+	if got := b.State(); got != CircuitOpen {
+		t.Fatalf("state at failure ratio = %s, want %s", got, CircuitOpen)
+	}
+
+	err := b.Allow()
+	if !IsUnavailable(err) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow() while open error = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerWindowResets(t *testing.T) {
+	b, now := testBreaker()
+
+	record(t, b, 3, true)
+	*now = now.Add(time.Minute)
+	record(t, b, 1, true)
+	if got := b.State(); got != CircuitClosed {
+		t.Fatalf("state after window reset = %s, want %s", got, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed bool
+		want   string
+	}{
+		{name: "probe succeeds", failed: false, want: CircuitClosed},
+		{name: "probe fails", failed: true, want: CircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, now := testBreaker()
+			record(t, b, 4, true)
+
+			*now = now.Add(10 * time.Second)
+			if err := b.Allow(); err != nil {
+				t.Fatalf("Allow() after open timeout error = %v", err)
+			}
+			if got := b.State(); got != CircuitHalfOpen {
+				t.Fatalf("state = %s, want %s", got, CircuitHalfOpen)
+			}
+			if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("Allow() during probe error = %v, want ErrCircuitOpen", err)
+			}
+
+			b.Record(tt.failed)
+			if got := b.State(); got != tt.want {
+				t.Fatalf("state after probe = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerReleaseProbe(t *testing.T) {
+	b, now := testBreaker()
+	record(t, b, 4, true)
+	*now = now.Add(10 * time.Second)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	b.Release()
+
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Fatalf("state after release = %s, want %s", got, CircuitHalfOpen)
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow() after released probe error = %v", err)
+	}
+}
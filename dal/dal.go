@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fieldfuze-backend/models"
 	"fmt"
+	"sort"
 
 	"fieldfuze-backend/utils/logger"
 
@@ -46,7 +47,7 @@ func NewDALContainer(cfg *models.Config, log logger.Logger) (DALContainerInterfa
 	}
 
 	return &DALContainer{
-		databaseClient: dbClient,
+		databaseClient: NewResilientClient(dbClient, cfg, log),
 	}, nil
 }
 
@@ -55,13 +56,15 @@ func (d *DALContainer) GetDatabaseClient() DatabaseClientInterface {
 	return d.databaseClient
 }
 
-// NewDynamoDBClient creates a new DynamoDB client
-func NewDynamoDBClient(cfg *models.Config, log logger.Logger) (*DynamoDBClient, error) {
-	awsCfg, err := config.LoadDefaultConfig(context.TODO(),
+// LoadAWSConfig loads the AWS configuration shared by the DynamoDB and
+// DynamoDB Streams clients, honouring the local endpoint and static
+// credentials from cfg
+func LoadAWSConfig(ctx context.Context, cfg *models.Config) (aws.Config, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(cfg.AWSRegion),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	// Override endpoint for local DynamoDB
@@ -83,6 +86,16 @@ func NewDynamoDBClient(cfg *models.Config, log logger.Logger) (*DynamoDBClient,
 		))
 	}
 
+	return awsCfg, nil
+}
+
+// NewDynamoDBClient creates a new DynamoDB client
+func NewDynamoDBClient(cfg *models.Config, log logger.Logger) (*DynamoDBClient, error) {
+	awsCfg, err := LoadAWSConfig(context.TODO(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	client := dynamodb.NewFromConfig(awsCfg)
 
 	dbClient := &DynamoDBClient{
@@ -127,8 +140,8 @@ func (db *DynamoDBClient) getSingleItemByPrimaryKey(ctx context.Context, config
 	// db.logger.Infof("DynamoDB GetItem output: %s", PrintPrettyJSON(output))
 
 	if output.Item == nil {
-		return fmt.Errorf("item not found in %s with %s=%s",
-			config.TableName, config.KeyName, config.KeyValue)
+		return newError(ErrNotFound, fmt.Errorf("item not found in %s with %s=%s",
+			config.TableName, config.KeyName, config.KeyValue))
 	}
 
 	if err := attributevalue.UnmarshalMap(output.Item, result); err != nil {
@@ -164,8 +177,8 @@ func (db *DynamoDBClient) getSingleItemByIndex(ctx context.Context, config model
 	db.logger.Infof("DynamoDB Query output: %s", PrintPrettyJSON(output))
 
 	if len(output.Items) == 0 {
-		return fmt.Errorf("item not found in %s with %s=%s using index %s",
-			config.TableName, config.KeyName, config.KeyValue, config.IndexName)
+		return newError(ErrNotFound, fmt.Errorf("item not found in %s with %s=%s using index %s",
+			config.TableName, config.KeyName, config.KeyValue, config.IndexName))
 	}
 
 	// Unmarshal the first item
@@ -192,7 +205,7 @@ func (db *DynamoDBClient) buildAttributeValue(value string, attrType models.Attr
 }
 
 // PutItem stores an item in DynamoDB
-func (db *DynamoDBClient) PutItem(ctx context.Context, tableName string, item interface{}) error {
+func (db *DynamoDBClient) PutItem(ctx context.Context, tableName string, item interface{}, conditions ...Condition) error {
 	av, err := attributevalue.MarshalMap(item)
 	if err != nil {
 		return fmt.Errorf("failed to marshal item: %w", err)
@@ -203,6 +216,17 @@ func (db *DynamoDBClient) PutItem(ctx context.Context, tableName string, item in
 		Item:      av,
 	}
 
+	if len(conditions) > 0 {
+		e := newExpressionContext()
+		conditionExpression, err := buildConditions(e, conditions)
+		if err != nil {
+			return fmt.Errorf("invalid put condition: %w", err)
+		}
+		input.ConditionExpression = aws.String(conditionExpression)
+		input.ExpressionAttributeNames = e.attributeNames()
+		input.ExpressionAttributeValues = e.attributeValues()
+	}
+
 	_, err = db.client.PutItem(ctx, input)
 	if err != nil {
 		return err
@@ -210,49 +234,46 @@ func (db *DynamoDBClient) PutItem(ctx context.Context, tableName string, item in
 	return nil
 }
 
-// UpdateItem updates an item in DynamoDB
+// UpdateItem sets the given attributes on an item in DynamoDB
 func (db *DynamoDBClient) UpdateItem(ctx context.Context, tableName, key, keyValue string, updates map[string]interface{}) error {
-	updateExpression := "SET "
-	expressionAttributeNames := make(map[string]string)
-	expressionAttributeValues := make(map[string]types.AttributeValue)
-
-	i := 0
-	for field, value := range updates {
-		if i > 0 {
-			updateExpression += ", "
-		}
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
 
-		attrName := "#" + field
-		attrValue := ":" + field
+	update := NewUpdate(tableName, key, keyValue)
+	for _, field := range fields {
+		update.Set(field, updates[field])
+	}
 
-		updateExpression += attrName + " = " + attrValue
-		expressionAttributeNames[attrName] = field
+	return db.Update(ctx, update, nil)
+}
 
-		av, err := attributevalue.Marshal(value)
-		if err != nil {
-			return err
-		}
-		expressionAttributeValues[attrValue] = av
-		i++
+// Update executes an update built with UpdateBuilder. When result is not nil
+// the item as it is after the update is unmarshalled into it. A failed
+// condition is returned as *types.ConditionalCheckFailedException.
+func (db *DynamoDBClient) Update(ctx context.Context, update *UpdateBuilder, result interface{}) error {
+	input, err := update.Build()
+	if err != nil {
+		return err
 	}
 
-	input := &dynamodb.UpdateItemInput{
-		TableName: aws.String(tableName),
-		Key: map[string]types.AttributeValue{
-			key: &types.AttributeValueMemberS{Value: keyValue},
-		},
-		UpdateExpression:          aws.String(updateExpression),
-		ExpressionAttributeNames:  expressionAttributeNames,
-		ExpressionAttributeValues: expressionAttributeValues,
-		ReturnValues:              types.ReturnValueAllNew,
+	output, err := db.client.UpdateItem(ctx, input)
+	if err != nil {
+		db.logger.Errorf("Failed to update item in %s: %v", update.TableName(), err)
+		return err
 	}
 
-	_, err := db.client.UpdateItem(ctx, input)
-	return err
+	if result != nil {
+		return attributevalue.UnmarshalMap(output.Attributes, result)
+	}
+	return nil
 }
 
-// DeleteItem deletes an item from DynamoDB
-func (db *DynamoDBClient) DeleteItem(ctx context.Context, tableName, key, value string) error {
+// DeleteItem deletes an item from DynamoDB. When conditions are given the
+// item is only deleted if all of them hold.
+func (db *DynamoDBClient) DeleteItem(ctx context.Context, tableName, key, value string, conditions ...Condition) error {
 	input := &dynamodb.DeleteItemInput{
 		TableName: aws.String(tableName),
 		Key: map[string]types.AttributeValue{
@@ -260,10 +281,44 @@ func (db *DynamoDBClient) DeleteItem(ctx context.Context, tableName, key, value
 		},
 	}
 
+	if len(conditions) > 0 {
+		e := newExpressionContext()
+		conditionExpression, err := buildConditions(e, conditions)
+		if err != nil {
+			return fmt.Errorf("invalid delete condition: %w", err)
+		}
+		input.ConditionExpression = aws.String(conditionExpression)
+		input.ExpressionAttributeNames = e.attributeNames()
+		input.ExpressionAttributeValues = e.attributeValues()
+	}
+
 	_, err := db.client.DeleteItem(ctx, input)
 	return err
 }
 
+// TransactWrite runs the given writes and condition checks atomically. A
+// failed condition cancels the whole transaction and is returned as
+// *types.TransactionCanceledException, see FailedConditions.
+func (db *DynamoDBClient) TransactWrite(ctx context.Context, items ...TransactItem) error {
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: make([]types.TransactWriteItem, 0, len(items)),
+	}
+	for _, item := range items {
+		transactItem, err := item.build()
+		if err != nil {
+			return err
+		}
+		input.TransactItems = append(input.TransactItems, transactItem)
+	}
+
+	_, err := db.client.TransactWriteItems(ctx, input)
+	if err != nil {
+		db.logger.Errorf("Failed to write transaction of %d items: %v", len(items), err)
+		return err
+	}
+	return nil
+}
+
 // QueryByIndex queries items using a global secondary index
 func (db *DynamoDBClient) QueryByIndex(ctx context.Context, tableName, indexName, keyName, keyValue string, results interface{}) error {
 	input := &dynamodb.QueryInput{
@@ -287,6 +342,39 @@ func (db *DynamoDBClient) QueryByIndex(ctx context.Context, tableName, indexName
 	return attributevalue.UnmarshalListOfMaps(output.Items, results)
 }
 
+// Query executes a query built with QueryBuilder, following pagination until
+// the builder's limit is reached or the result set is exhausted
+func (db *DynamoDBClient) Query(ctx context.Context, query *QueryBuilder, results interface{}) error {
+	input, err := query.Build()
+	if err != nil {
+		return err
+	}
+
+	limit := query.MaxItems()
+	var items []map[string]types.AttributeValue
+
+	for {
+		output, err := db.client.Query(ctx, input)
+		if err != nil {
+			db.logger.Errorf("Failed to query %s: %v", query.TableName(), err)
+			return err
+		}
+
+		items = append(items, output.Items...)
+
+		if limit > 0 && len(items) >= limit {
+			items = items[:limit]
+			break
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, results)
+}
+
 // Scan scans the entire table
 func (db *DynamoDBClient) Scan(ctx context.Context, tableName string, results interface{}) error {
 	input := &dynamodb.ScanInput{
@@ -301,6 +389,41 @@ func (db *DynamoDBClient) Scan(ctx context.Context, tableName string, results in
 	return attributevalue.UnmarshalListOfMaps(output.Items, results)
 }
 
+// ScanWhere scans the whole table, following pagination, and returns the
+// items matching filter
+func (db *DynamoDBClient) ScanWhere(ctx context.Context, tableName string, filter Condition, results interface{}) error {
+	e := newExpressionContext()
+	filterExpression, err := filter.build(e)
+	if err != nil {
+		return fmt.Errorf("invalid filter expression: %w", err)
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(tableName),
+		FilterExpression:          aws.String(filterExpression),
+		ExpressionAttributeNames:  e.attributeNames(),
+		ExpressionAttributeValues: e.attributeValues(),
+	}
+
+	var items []map[string]types.AttributeValue
+	for {
+		output, err := db.client.Scan(ctx, input)
+		if err != nil {
+			db.logger.Errorf("Failed to scan %s: %v", tableName, err)
+			return err
+		}
+
+		items = append(items, output.Items...)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, results)
+}
+
 // CreateTable creates a table
 func (db *DynamoDBClient) CreateTable(ctx context.Context, input *dynamodb.CreateTableInput) error {
 	_, err := db.client.CreateTable(ctx, input)
@@ -321,6 +444,34 @@ func (db *DynamoDBClient) DeleteTable(ctx context.Context, input *dynamodb.Delet
 	return err
 }
 
+// EnableTTL turns on time to live for the table using the given epoch-seconds
+// attribute. It is a no-op when TTL is already enabled on that attribute.
+func (db *DynamoDBClient) EnableTTL(ctx context.Context, tableName, attributeName string) error {
+	current, err := db.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return err
+	}
+
+	if description := current.TimeToLiveDescription; description != nil {
+		status := description.TimeToLiveStatus
+		if (status == types.TimeToLiveStatusEnabled || status == types.TimeToLiveStatusEnabling) &&
+			aws.ToString(description.AttributeName) == attributeName {
+			return nil
+		}
+	}
+
+	_, err = db.client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attributeName),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	return err
+}
+
 // ScanTable scans a table (alias for Scan)
 func (db *DynamoDBClient) ScanTable(ctx context.Context, tableName string, results interface{}) error {
 	return db.Scan(ctx, tableName, results)
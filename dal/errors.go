@@ -0,0 +1,120 @@
+package dal
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/aws/smithy-go"
+)
+
+// Domain errors returned by the DAL. Errors coming out of the resilient
+// client wrap one of these, so callers can branch with errors.Is while the
+// original AWS error stays reachable with errors.As.
+var (
+	// ErrNotFound is returned when the requested item or table does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a condition or transaction check fails
+	ErrConflict = errors.New("conflict")
+
+	// ErrThrottled is returned when DynamoDB rejects a request for exceeding
+	// provisioned or account throughput, after retries are exhausted
+	ErrThrottled = errors.New("throttled")
+
+	// ErrUnavailable is returned for timeouts, transient service failures and
+	// while the circuit breaker is open
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// Error is a DAL error classified into one of the domain errors. Its message
+// is the message of the underlying error so existing logging is unchanged.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+// Error returns the message of the underlying error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the domain error and the underlying error
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// newError classifies err as kind
+func newError(kind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// errorCodes maps DynamoDB error codes to domain errors
+var errorCodes = map[string]error{
+	"ResourceNotFoundException":              ErrNotFound,
+	"ConditionalCheckFailedException":        ErrConflict,
+	"TransactionCanceledException":           ErrConflict,
+	"TransactionConflictException":           ErrConflict,
+	"ProvisionedThroughputExceededException": ErrThrottled,
+	"RequestLimitExceeded":                   ErrThrottled,
+	"ThrottlingException":                    ErrThrottled,
+	"LimitExceededException":                 ErrThrottled,
+	"InternalServerError":                    ErrUnavailable,
+	"ServiceUnavailable":                     ErrUnavailable,
+}
+
+// translateError classifies err into a domain error. Errors that are already
+// classified, and errors the DAL cannot classify such as validation or
+// marshalling failures, are returned unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newError(ErrUnavailable, err)
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if kind, ok := errorCodes[apiErr.ErrorCode()]; ok {
+			return newError(kind, err)
+		}
+		if apiErr.ErrorFault() == smithy.FaultServer {
+			return newError(ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return newError(ErrUnavailable, err)
+	}
+
+	return err
+}
+
+// IsNotFound reports whether err is a DAL not found error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is a DAL conflict error
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsThrottled reports whether err is a DAL throttling error
+func IsThrottled(err error) bool {
+	return errors.Is(err, ErrThrottled)
+}
+
+// IsUnavailable reports whether err is a DAL availability error
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
@@ -0,0 +1,285 @@
+package dal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// conditionOp enumerates the comparison and logical operators supported in
+// filter and condition expressions
+type conditionOp int
+
+const (
+	opEqual conditionOp = iota
+	opNotEqual
+	opLessThan
+	opLessOrEqual
+	opGreaterThan
+	opGreaterOrEqual
+	opBetween
+	opBeginsWith
+	opContains
+	opIn
+	opAttributeExists
+	opAttributeNotExists
+	opAnd
+	opOr
+	opNot
+)
+
+// Condition is a typed DynamoDB condition used for filter expressions on
+// queries and for conditional writes. Paths may address nested attributes
+// using dot notation, e.g. "createdData.uID".
+type Condition struct {
+	op       conditionOp
+	path     string
+	values   []interface{}
+	children []Condition
+}
+
+// Equal matches items where path = value
+func Equal(path string, value interface{}) Condition {
+	return Condition{op: opEqual, path: path, values: []interface{}{value}}
+}
+
+// NotEqual matches items where path <> value
+func NotEqual(path string, value interface{}) Condition {
+	return Condition{op: opNotEqual, path: path, values: []interface{}{value}}
+}
+
+// LessThan matches items where path < value
+func LessThan(path string, value interface{}) Condition {
+	return Condition{op: opLessThan, path: path, values: []interface{}{value}}
+}
+
+// LessOrEqual matches items where path <= value
+func LessOrEqual(path string, value interface{}) Condition {
+	return Condition{op: opLessOrEqual, path: path, values: []interface{}{value}}
+}
+
+// GreaterThan matches items where path > value
+func GreaterThan(path string, value interface{}) Condition {
+	return Condition{op: opGreaterThan, path: path, values: []interface{}{value}}
+}
+
+// GreaterOrEqual matches items where path >= value
+func GreaterOrEqual(path string, value interface{}) Condition {
+	return Condition{op: opGreaterOrEqual, path: path, values: []interface{}{value}}
+}
+
+// Between matches items where lower <= path <= upper
+func Between(path string, lower, upper interface{}) Condition {
+	return Condition{op: opBetween, path: path, values: []interface{}{lower, upper}}
+}
+
+// BeginsWith matches items where the string at path starts with prefix
+func BeginsWith(path, prefix string) Condition {
+	return Condition{op: opBeginsWith, path: path, values: []interface{}{prefix}}
+}
+
+// Contains matches items where the string or set at path contains value
+func Contains(path string, value interface{}) Condition {
+	return Condition{op: opContains, path: path, values: []interface{}{value}}
+}
+
+// In matches items where path equals any of the given values
+func In(path string, values ...interface{}) Condition {
+	return Condition{op: opIn, path: path, values: values}
+}
+
+// AttributeExists matches items where path is set
+func AttributeExists(path string) Condition {
+	return Condition{op: opAttributeExists, path: path}
+}
+
+// AttributeNotExists matches items where path is not set
+func AttributeNotExists(path string) Condition {
+	return Condition{op: opAttributeNotExists, path: path}
+}
+
+// And combines conditions so that all of them must hold
+func And(conditions ...Condition) Condition {
+	return Condition{op: opAnd, children: conditions}
+}
+
+// Or combines conditions so that at least one of them must hold
+func Or(conditions ...Condition) Condition {
+	return Condition{op: opOr, children: conditions}
+}
+
+// Not negates a condition
+func Not(condition Condition) Condition {
+	return Condition{op: opNot, children: []Condition{condition}}
+}
+
+// expressionContext allocates expression attribute name and value
+// placeholders while an expression is being built
+type expressionContext struct {
+	names     map[string]string
+	values    map[string]types.AttributeValue
+	nameIndex map[string]string
+	valueSeq  int
+}
+
+func newExpressionContext() *expressionContext {
+	return &expressionContext{
+		names:     make(map[string]string),
+		values:    make(map[string]types.AttributeValue),
+		nameIndex: make(map[string]string),
+	}
+}
+
+// name returns the placeholder for an attribute path, splitting nested paths
+// on "." so that every segment is escaped individually. List indexes such as
+// "roles[2]" keep their index suffix outside the placeholder.
+func (e *expressionContext) name(path string) string {
+	segments := strings.Split(path, ".")
+	placeholders := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		attribute, index := segment, ""
+		if i := strings.Index(segment, "["); i > 0 {
+			attribute, index = segment[:i], segment[i:]
+		}
+		placeholder, ok := e.nameIndex[attribute]
+		if !ok {
+			placeholder = "#n" + strconv.Itoa(len(e.nameIndex))
+			e.nameIndex[attribute] = placeholder
+			e.names[placeholder] = attribute
+		}
+		placeholders = append(placeholders, placeholder+index)
+	}
+	return strings.Join(placeholders, ".")
+}
+
+// value marshals a Go value and returns its placeholder
+func (e *expressionContext) value(v interface{}) (string, error) {
+	av, err := marshalExpressionValue(v)
+	if err != nil {
+		return "", err
+	}
+	placeholder := ":v" + strconv.Itoa(e.valueSeq)
+	e.valueSeq++
+	e.values[placeholder] = av
+	return placeholder, nil
+}
+
+// marshalExpressionValue marshals v unless it is already an attribute value
+func marshalExpressionValue(v interface{}) (types.AttributeValue, error) {
+	if av, ok := v.(types.AttributeValue); ok {
+		return av, nil
+	}
+	av, err := attributevalue.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal expression value: %w", err)
+	}
+	return av, nil
+}
+
+// attributeNames returns nil when no names were allocated so the SDK does
+// not reject an empty map
+func (e *expressionContext) attributeNames() map[string]string {
+	if len(e.names) == 0 {
+		return nil
+	}
+	return e.names
+}
+
+// attributeValues returns nil when no values were allocated so the SDK does
+// not reject an empty map
+func (e *expressionContext) attributeValues() map[string]types.AttributeValue {
+	if len(e.values) == 0 {
+		return nil
+	}
+	return e.values
+}
+
+// build renders the condition into expression syntax
+func (c Condition) build(e *expressionContext) (string, error) {
+	switch c.op {
+	case opAnd, opOr:
+		if len(c.children) == 0 {
+			return "", fmt.Errorf("logical condition requires at least one operand")
+		}
+		joiner := " AND "
+		if c.op == opOr {
+			joiner = " OR "
+		}
+		parts := make([]string, 0, len(c.children))
+		for _, child := range c.children {
+			part, err := child.build(e)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, "("+part+")")
+		}
+		return strings.Join(parts, joiner), nil
+	case opNot:
+		inner, err := c.children[0].build(e)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + inner + ")", nil
+	case opAttributeExists:
+		return "attribute_exists(" + e.name(c.path) + ")", nil
+	case opAttributeNotExists:
+		return "attribute_not_exists(" + e.name(c.path) + ")", nil
+	}
+
+	if c.path == "" {
+		return "", fmt.Errorf("condition requires an attribute path")
+	}
+	name := e.name(c.path)
+
+	placeholders := make([]string, 0, len(c.values))
+	for _, v := range c.values {
+		placeholder, err := e.value(v)
+		if err != nil {
+			return "", err
+		}
+		placeholders = append(placeholders, placeholder)
+	}
+
+	switch c.op {
+	case opEqual:
+		return name + " = " + placeholders[0], nil
+	case opNotEqual:
+		return name + " <> " + placeholders[0], nil
+	case opLessThan:
+		return name + " < " + placeholders[0], nil
+	case opLessOrEqual:
+		return name + " <= " + placeholders[0], nil
+	case opGreaterThan:
+		return name + " > " + placeholders[0], nil
+	case opGreaterOrEqual:
+		return name + " >= " + placeholders[0], nil
+	case opBetween:
+		return name + " BETWEEN " + placeholders[0] + " AND " + placeholders[1], nil
+	case opBeginsWith:
+		return "begins_with(" + name + ", " + placeholders[0] + ")", nil
+	case opContains:
+		return "contains(" + name + ", " + placeholders[0] + ")", nil
+	case opIn:
+		if len(placeholders) == 0 {
+			return "", fmt.Errorf("IN condition on %s requires at least one value", c.path)
+		}
+		return name + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+	default:
+		return "", fmt.Errorf("unsupported condition operator %d", c.op)
+	}
+}
+
+// buildConditions ANDs a list of conditions into a single expression
+func buildConditions(e *expressionContext, conditions []Condition) (string, error) {
+	switch len(conditions) {
+	case 0:
+		return "", nil
+	case 1:
+		return conditions[0].build(e)
+	default:
+		return And(conditions...).build(e)
+	}
+}
@@ -11,22 +11,27 @@ import (
 type DatabaseClientInterface interface {
 	// Core CRUD operations
 	GetItem(ctx context.Context, config models.QueryConfig, result interface{}) error
-	PutItem(ctx context.Context, tableName string, item interface{}) error
+	PutItem(ctx context.Context, tableName string, item interface{}, conditions ...Condition) error
 	UpdateItem(ctx context.Context, tableName, key, keyValue string, updates map[string]interface{}) error
-	DeleteItem(ctx context.Context, tableName, key, value string) error
-	
+	Update(ctx context.Context, update *UpdateBuilder, result interface{}) error
+	DeleteItem(ctx context.Context, tableName, key, value string, conditions ...Condition) error
+	TransactWrite(ctx context.Context, items ...TransactItem) error
+
 	// Query and Scan operations
 	QueryByIndex(ctx context.Context, tableName, indexName, keyName, keyValue string, results interface{}) error
+	Query(ctx context.Context, query *QueryBuilder, results interface{}) error
 	Scan(ctx context.Context, tableName string, results interface{}) error
 	ScanTable(ctx context.Context, tableName string, results interface{}) error
-	
+	ScanWhere(ctx context.Context, tableName string, filter Condition, results interface{}) error
+
 	// Table management operations
 	CreateTable(ctx context.Context, input *dynamodb.CreateTableInput) error
 	DescribeTable(ctx context.Context, tableName string) (*dynamodb.DescribeTableOutput, error)
 	DeleteTable(ctx context.Context, input *dynamodb.DeleteTableInput) error
+	EnableTTL(ctx context.Context, tableName, attributeName string) error
 }
 
 // DALContainerInterface defines the contract for the DAL container
 type DALContainerInterface interface {
 	GetDatabaseClient() DatabaseClientInterface
-}
\ No newline at end of file
+}
@@ -0,0 +1,213 @@
+package dal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// sortKeyCondition holds the key condition applied to the range key
+type sortKeyCondition struct {
+	name      string
+	condition Condition
+}
+
+// QueryBuilder builds DynamoDB queries fluently.
+//
+//	query := dal.NewQuery("dev_jobs").
+//		Index("orgID-createdAt-index").
+//		WhereHashKey("orgID", orgID).
+//		SortBetween("createdAt", from, to).
+//		Filter(dal.Equal("jobStatus", "pending")).
+//		Descending().
+//		Limit(20)
+type QueryBuilder struct {
+	tableName  string
+	indexName  string
+	hashKey    string
+	hashValue  interface{}
+	sortKey    *sortKeyCondition
+	filters    []Condition
+	projection []string
+	descending bool
+	limit      int32
+	err        error
+}
+
+// NewQuery starts a query against the given table
+func NewQuery(tableName string) *QueryBuilder {
+	return &QueryBuilder{tableName: tableName}
+}
+
+// TableName returns the table the query targets
+func (q *QueryBuilder) TableName() string {
+	return q.tableName
+}
+
+// Index queries a global or local secondary index instead of the base table
+func (q *QueryBuilder) Index(indexName string) *QueryBuilder {
+	q.indexName = indexName
+	return q
+}
+
+// WhereHashKey sets the partition key equality condition (required)
+func (q *QueryBuilder) WhereHashKey(name string, value interface{}) *QueryBuilder {
+	q.hashKey = name
+	q.hashValue = value
+	return q
+}
+
+// SortEqual restricts the sort key to a single value
+func (q *QueryBuilder) SortEqual(name string, value interface{}) *QueryBuilder {
+	return q.setSortKey(name, Equal(name, value))
+}
+
+// SortBetween restricts the sort key to the inclusive range [lower, upper]
+func (q *QueryBuilder) SortBetween(name string, lower, upper interface{}) *QueryBuilder {
+	return q.setSortKey(name, Between(name, lower, upper))
+}
+
+// SortBeginsWith restricts the sort key to values starting with prefix
+func (q *QueryBuilder) SortBeginsWith(name, prefix string) *QueryBuilder {
+	return q.setSortKey(name, BeginsWith(name, prefix))
+}
+
+// SortGreaterOrEqual restricts the sort key to values >= value
+func (q *QueryBuilder) SortGreaterOrEqual(name string, value interface{}) *QueryBuilder {
+	return q.setSortKey(name, GreaterOrEqual(name, value))
+}
+
+// SortGreaterThan restricts the sort key to values > value
+func (q *QueryBuilder) SortGreaterThan(name string, value interface{}) *QueryBuilder {
+	return q.setSortKey(name, GreaterThan(name, value))
+}
+
+// SortLessOrEqual restricts the sort key to values <= value
+func (q *QueryBuilder) SortLessOrEqual(name string, value interface{}) *QueryBuilder {
+	return q.setSortKey(name, LessOrEqual(name, value))
+}
+
+// SortLessThan restricts the sort key to values < value
+func (q *QueryBuilder) SortLessThan(name string, value interface{}) *QueryBuilder {
+	return q.setSortKey(name, LessThan(name, value))
+}
+
+func (q *QueryBuilder) setSortKey(name string, condition Condition) *QueryBuilder {
+	if q.sortKey != nil {
+		q.err = fmt.Errorf("sort key condition already set on %s", q.sortKey.name)
+		return q
+	}
+	q.sortKey = &sortKeyCondition{name: name, condition: condition}
+	return q
+}
+
+// Filter adds a filter expression; multiple filters are ANDed together.
+// Filters are evaluated after items are read, so they reduce the response
+// size but not the consumed capacity.
+func (q *QueryBuilder) Filter(conditions ...Condition) *QueryBuilder {
+	q.filters = append(q.filters, conditions...)
+	return q
+}
+
+// Project limits the attributes returned for each item
+func (q *QueryBuilder) Project(attributes ...string) *QueryBuilder {
+	q.projection = append(q.projection, attributes...)
+	return q
+}
+
+// Ascending returns items in ascending sort key order (the default)
+func (q *QueryBuilder) Ascending() *QueryBuilder {
+	q.descending = false
+	return q
+}
+
+// Descending returns items in descending sort key order
+func (q *QueryBuilder) Descending() *QueryBuilder {
+	q.descending = true
+	return q
+}
+
+// Limit caps the number of items returned after filters are applied.
+// Zero means no limit.
+func (q *QueryBuilder) Limit(limit int) *QueryBuilder {
+	if limit < 0 {
+		q.err = errors.New("query limit cannot be negative")
+		return q
+	}
+	q.limit = int32(limit)
+	return q
+}
+
+// MaxItems returns the configured item limit (0 = unlimited)
+func (q *QueryBuilder) MaxItems() int {
+	return int(q.limit)
+}
+
+// Build renders the builder into a DynamoDB QueryInput
+func (q *QueryBuilder) Build() (*dynamodb.QueryInput, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.tableName == "" {
+		return nil, errors.New("query requires a table name")
+	}
+	if q.hashKey == "" {
+		return nil, errors.New("query requires a hash key condition")
+	}
+
+	e := newExpressionContext()
+
+	keyConditions := []Condition{Equal(q.hashKey, q.hashValue)}
+	if q.sortKey != nil {
+		keyConditions = append(keyConditions, q.sortKey.condition)
+	}
+	keyParts := make([]string, 0, len(keyConditions))
+	for _, condition := range keyConditions {
+		part, err := condition.build(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key condition: %w", err)
+		}
+		keyParts = append(keyParts, part)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(q.tableName),
+		KeyConditionExpression: aws.String(strings.Join(keyParts, " AND ")),
+		ScanIndexForward:       aws.Bool(!q.descending),
+	}
+
+	if q.indexName != "" {
+		input.IndexName = aws.String(q.indexName)
+	}
+
+	filterExpression, err := buildConditions(e, q.filters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+	if filterExpression != "" {
+		input.FilterExpression = aws.String(filterExpression)
+	}
+
+	if len(q.projection) > 0 {
+		projected := make([]string, 0, len(q.projection))
+		for _, attribute := range q.projection {
+			projected = append(projected, e.name(attribute))
+		}
+		input.ProjectionExpression = aws.String(strings.Join(projected, ", "))
+	}
+
+	// Without filters DynamoDB's page limit equals the result limit; with
+	// filters the page limit would be applied before filtering, so we page
+	// until enough matching items are collected instead.
+	if q.limit > 0 && len(q.filters) == 0 {
+		input.Limit = aws.Int32(q.limit)
+	}
+
+	input.ExpressionAttributeNames = e.attributeNames()
+	input.ExpressionAttributeValues = e.attributeValues()
+
+	return input, nil
+}
@@ -0,0 +1,243 @@
+package dal
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// ResilientClient decorates a DatabaseClientInterface with per-operation
+// timeouts, jittered retries of throttled and transient failures, a circuit
+// breaker and translation of AWS errors into the DAL domain errors
+type ResilientClient struct {
+	next    DatabaseClientInterface
+	breaker *CircuitBreaker
+	logger  logger.Logger
+
+	timeout     time.Duration
+	scanTimeout time.Duration
+	maxRetries  int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// NewResilientClient wraps next using the DAL resilience settings in cfg
+func NewResilientClient(next DatabaseClientInterface, cfg *models.Config, log logger.Logger) *ResilientClient {
+	return &ResilientClient{
+		next: next,
+		breaker: NewCircuitBreaker(
+			float64(cfg.DALBreakerFailurePercent)/100,
+			cfg.DALBreakerMinRequests,
+			time.Duration(cfg.DALBreakerWindowSeconds)*time.Second,
+			time.Duration(cfg.DALBreakerOpenTimeoutSeconds)*time.Second,
+		),
+		logger:      log,
+		timeout:     time.Duration(cfg.DALOperationTimeoutMs) * time.Millisecond,
+		scanTimeout: time.Duration(cfg.DALScanTimeoutMs) * time.Millisecond,
+		maxRetries:  cfg.DALMaxRetries,
+		baseDelay:   time.Duration(cfg.DALRetryBaseDelayMs) * time.Millisecond,
+		maxDelay:    time.Duration(cfg.DALRetryMaxDelayMs) * time.Millisecond,
+	}
+}
+
+// BreakerState returns the state of the circuit breaker
+func (r *ResilientClient) BreakerState() string {
+	return r.breaker.State()
+}
+
+// retryTransient retries throttled requests and transient failures. It is
+// used for reads and for writes that are safe to repeat.
+func retryTransient(err error) bool {
+	return IsThrottled(err) || (IsUnavailable(err) && !errors.Is(err, ErrCircuitOpen))
+}
+
+// retryThrottled only retries throttled requests, which DynamoDB guarantees
+// were not applied. It is used for updates that may not be idempotent, such
+// as ADD or list_append, where a timed out attempt may have succeeded.
+func retryThrottled(err error) bool {
+	return IsThrottled(err)
+}
+
+// do runs fn with a per-attempt timeout, retrying with full-jitter
+// exponential back-off while retryable reports true
+func (r *ResilientClient) do(ctx context.Context, op string, timeout time.Duration, retryable func(error) bool, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		if err := r.breaker.Allow(); err != nil {
+			r.logger.Warnf("DynamoDB %s rejected: %v", op, err)
+			return err
+		}
+
+		attemptCtx, cancel := withTimeout(ctx, timeout)
+		err := translateError(fn(attemptCtx))
+		cancel()
+
+		// A request abandoned by the caller says nothing about DynamoDB
+		// health, but must not keep holding a half-open probe
+		if ctx.Err() != nil {
+			r.breaker.Release()
+			return err
+		}
+		r.breaker.Record(IsThrottled(err) || IsUnavailable(err))
+
+		if err == nil || attempt >= r.maxRetries || !retryable(err) {
+			return err
+		}
+
+		delay := r.backoff(attempt)
+		r.logger.Warnf("DynamoDB %s failed (attempt %d/%d), retrying in %s: %v", op, attempt+1, r.maxRetries+1, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// withTimeout applies timeout to ctx unless it is disabled
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// backoff returns a random delay between zero and the exponential back-off
+// ceiling for the attempt
+func (r *ResilientClient) backoff(attempt int) time.Duration {
+	ceiling := r.maxDelay
+	if attempt < 30 {
+		if d := r.baseDelay << attempt; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// GetItem retrieves a single item
+func (r *ResilientClient) GetItem(ctx context.Context, config models.QueryConfig, result interface{}) error {
+	return r.do(ctx, "GetItem", r.timeout, retryTransient, func(ctx context.Context) error {
+		return r.next.GetItem(ctx, config, result)
+	})
+}
+
+// PutItem stores an item. Conditional puts are only retried when throttled,
+// as a repeat of an applied put would fail its condition.
+func (r *ResilientClient) PutItem(ctx context.Context, tableName string, item interface{}, conditions ...Condition) error {
+	retryable := retryTransient
+	if len(conditions) > 0 {
+		retryable = retryThrottled
+	}
+	return r.do(ctx, "PutItem", r.timeout, retryable, func(ctx context.Context) error {
+		return r.next.PutItem(ctx, tableName, item, conditions...)
+	})
+}
+
+// UpdateItem sets the given attributes on an item
+func (r *ResilientClient) UpdateItem(ctx context.Context, tableName, key, keyValue string, updates map[string]interface{}) error {
+	return r.do(ctx, "UpdateItem", r.timeout, retryTransient, func(ctx context.Context) error {
+		return r.next.UpdateItem(ctx, tableName, key, keyValue, updates)
+	})
+}
+
+// Update executes an update built with UpdateBuilder
+func (r *ResilientClient) Update(ctx context.Context, update *UpdateBuilder, result interface{}) error {
+	return r.do(ctx, "Update", r.timeout, retryThrottled, func(ctx context.Context) error {
+		return r.next.Update(ctx, update, result)
+	})
+}
+
+// DeleteItem deletes an item. Conditional deletes are only retried when
+// throttled, as a repeat of an applied delete would fail its condition.
+func (r *ResilientClient) DeleteItem(ctx context.Context, tableName, key, value string, conditions ...Condition) error {
+	retryable := retryTransient
+	if len(conditions) > 0 {
+		retryable = retryThrottled
+	}
+	return r.do(ctx, "DeleteItem", r.timeout, retryable, func(ctx context.Context) error {
+		return r.next.DeleteItem(ctx, tableName, key, value, conditions...)
+	})
+}
+
+// TransactWrite runs writes atomically. Transactions are only retried when
+// throttled, as a repeat of an applied transaction would fail its conditions.
+func (r *ResilientClient) TransactWrite(ctx context.Context, items ...TransactItem) error {
+	return r.do(ctx, "TransactWrite", r.timeout, retryThrottled, func(ctx context.Context) error {
+		return r.next.TransactWrite(ctx, items...)
+	})
+}
+
+// QueryByIndex queries items using a global secondary index
+func (r *ResilientClient) QueryByIndex(ctx context.Context, tableName, indexName, keyName, keyValue string, results interface{}) error {
+	return r.do(ctx, "QueryByIndex", r.timeout, retryTransient, func(ctx context.Context) error {
+		return r.next.QueryByIndex(ctx, tableName, indexName, keyName, keyValue, results)
+	})
+}
+
+// Query executes a query built with QueryBuilder
+func (r *ResilientClient) Query(ctx context.Context, query *QueryBuilder, results interface{}) error {
+	return r.do(ctx, "Query", r.scanTimeout, retryTransient, func(ctx context.Context) error {
+		return r.next.Query(ctx, query, results)
+	})
+}
+
+// Scan scans the entire table
+func (r *ResilientClient) Scan(ctx context.Context, tableName string, results interface{}) error {
+	return r.do(ctx, "Scan", r.scanTimeout, retryTransient, func(ctx context.Context) error {
+		return r.next.Scan(ctx, tableName, results)
+	})
+}
+
+// ScanTable scans a table (alias for Scan)
+func (r *ResilientClient) ScanTable(ctx context.Context, tableName string, results interface{}) error {
+	return r.Scan(ctx, tableName, results)
+}
+
+// ScanWhere scans the whole table and returns the items matching filter
+func (r *ResilientClient) ScanWhere(ctx context.Context, tableName string, filter Condition, results interface{}) error {
+	return r.do(ctx, "ScanWhere", r.scanTimeout, retryTransient, func(ctx context.Context) error {
+		return r.next.ScanWhere(ctx, tableName, filter, results)
+	})
+}
+
+// CreateTable creates a table
+func (r *ResilientClient) CreateTable(ctx context.Context, input *dynamodb.CreateTableInput) error {
+	return r.do(ctx, "CreateTable", r.timeout, retryThrottled, func(ctx context.Context) error {
+		return r.next.CreateTable(ctx, input)
+	})
+}
+
+// DescribeTable describes a table
+func (r *ResilientClient) DescribeTable(ctx context.Context, tableName string) (*dynamodb.DescribeTableOutput, error) {
+	var output *dynamodb.DescribeTableOutput
+	err := r.do(ctx, "DescribeTable", r.timeout, retryTransient, func(ctx context.Context) error {
+		var err error
+		output, err = r.next.DescribeTable(ctx, tableName)
+		return err
+	})
+	return output, err
+}
+
+// DeleteTable deletes a table
+func (r *ResilientClient) DeleteTable(ctx context.Context, input *dynamodb.DeleteTableInput) error {
+	return r.do(ctx, "DeleteTable", r.timeout, retryThrottled, func(ctx context.Context) error {
+		return r.next.DeleteTable(ctx, input)
+	})
+}
+
+// EnableTTL turns on time to live for the table
+func (r *ResilientClient) EnableTTL(ctx context.Context, tableName, attributeName string) error {
+	return r.do(ctx, "EnableTTL", r.timeout, retryTransient, func(ctx context.Context) error {
+		return r.next.EnableTTL(ctx, tableName, attributeName)
+	})
+}
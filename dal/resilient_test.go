@@ -0,0 +1,130 @@
+package dal
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"testing"
+	"time"
+)
+
+// fakeClient answers GetItem with getItem and leaves the other operations
+// unimplemented
+type fakeClient struct {
+	DatabaseClientInterface
+	getItem func(ctx context.Context) error
+	calls   int
+}
+
+func (f *fakeClient) GetItem(ctx context.Context, config models.QueryConfig, result interface{}) error {
+	f.calls++
+	return f.getItem(ctx)
+}
+
+func newTestResilientClient(next DatabaseClientInterface) *ResilientClient {
+	return NewResilientClient(next, &models.Config{
+		DALBreakerFailurePercent:     50,
+		DALBreakerMinRequests:        100,
+		DALBreakerWindowSeconds:      60,
+		DALBreakerOpenTimeoutSeconds: 10,
+		DALOperationTimeoutMs:        1000,
+		DALScanTimeoutMs:             1000,
+		DALMaxRetries:                2,
+		DALRetryBaseDelayMs:          1,
+		DALRetryMaxDelayMs:           2,
+	}, logger.NewLogger("error", "json"))
+}
+
+func TestResilientClientRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{name: "throttled is retried", err: newError(ErrThrottled, errors.New("slow down")), wantCalls: 3},
+		{name: "unavailable is retried", err: newError(ErrUnavailable, errors.New("timeout")), wantCalls: 3},
+		{name: "conflict is not retried", err: newError(ErrConflict, errors.New("condition failed")), wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeClient{getItem: func(ctx context.Context) error { return tt.err }}
+			r := newTestResilientClient(next)
+
+			err := r.GetItem(context.Background(), models.QueryConfig{}, nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("GetItem() error = %v, want %v", err, tt.err)
+			}
+			if next.calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", next.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResilientClientRecovers(t *testing.T) {
+	failures := 1
+	next := &fakeClient{getItem: func(ctx context.Context) error {
+		if failures > 0 {
+			failures--
+			return newError(ErrThrottled, errors.New("slow down"))
+		}
+		return nil
+	}}
+	r := newTestResilientClient(next)
+
+	if err := r.GetItem(context.Background(), models.QueryConfig{}, nil); err != nil {
+		t.Fatalf("GetItem() error = %v", err)
+	}
+	if next.calls != 2 {
+		t.Fatalf("calls = %d, want 2", next.calls)
+	}
+}
+
+func TestResilientClientCancelReleasesProbe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	next := &fakeClient{getItem: func(context.Context) error {
+		cancel()
+		return context.Canceled
+	}}
+	r := newTestResilientClient(next)
+
+	// Open the breaker long enough ago that the next request probes
+	r.breaker.trip(time.Now().Add(-time.Minute))
+
+	if err := r.GetItem(ctx, models.QueryConfig{}, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetItem() error = %v, want context.Canceled", err)
+	}
+	if got := r.BreakerState(); got != CircuitHalfOpen {
+		t.Fatalf("state = %s, want %s", got, CircuitHalfOpen)
+	}
+
+	next.getItem = func(context.Context) error { return nil }
+	if err := r.GetItem(context.Background(), models.QueryConfig{}, nil); err != nil {
+		t.Fatalf("GetItem() after cancelled probe error = %v", err)
+	}
+	if got := r.BreakerState(); got != CircuitClosed {
+		t.Fatalf("state = %s, want %s", got, CircuitClosed)
+	}
+}
+
+func TestResilientClientBackoff(t *testing.T) {
+	r := &ResilientClient{baseDelay: 10 * time.Millisecond, maxDelay: 50 * time.Millisecond}
+
+	ceilings := []time.Duration{10, 20, 40, 50, 50}
+	for attempt, ceiling := range ceilings {
+		for i := 0; i < 100; i++ {
+			if d := r.backoff(attempt); d < 0 || d >= ceiling*time.Millisecond {
+				t.Fatalf("backoff(%d) = %s, want in [0, %s)", attempt, d, ceiling*time.Millisecond)
+			}
+		}
+	}
+
+	if d := r.backoff(100); d < 0 || d >= r.maxDelay {
+		t.Fatalf("backoff(100) = %s, want in [0, %s)", d, r.maxDelay)
+	}
+	if d := (&ResilientClient{}).backoff(3); d != 0 {
+		t.Fatalf("backoff without delays = %s, want 0", d)
+	}
+}
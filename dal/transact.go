@@ -0,0 +1,126 @@
+package dal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TransactItem is one write of a transaction run with TransactWrite. It
+// either puts Item or, when Item is nil, only checks the conditions against
+// the item identified by KeyName = KeyValue.
+//
+//	err := db.TransactWrite(ctx,
+//		dal.TransactPut("dev_time_entries", entry),
+//		dal.TransactCheck("dev_timesheets", "timesheetID", id, dal.NotEqual("status", "approved")))
+type TransactItem struct {
+	TableName  string
+	KeyName    string
+	KeyValue   interface{}
+	Item       interface{}
+	Conditions []Condition
+}
+
+// TransactPut puts item as part of a transaction, provided all conditions
+// hold
+func TransactPut(tableName string, item interface{}, conditions ...Condition) TransactItem {
+	return TransactItem{TableName: tableName, Item: item, Conditions: conditions}
+}
+
+// TransactCheck makes a transaction fail unless all conditions hold for the
+// item identified by keyName = keyValue. Missing items are checked as empty
+// items.
+func TransactCheck(tableName, keyName string, keyValue interface{}, conditions ...Condition) TransactItem {
+	return TransactItem{TableName: tableName, KeyName: keyName, KeyValue: keyValue, Conditions: conditions}
+}
+
+// build renders the item into a DynamoDB TransactWriteItem
+func (t TransactItem) build() (types.TransactWriteItem, error) {
+	if t.TableName == "" {
+		return types.TransactWriteItem{}, errors.New("transaction item requires a table name")
+	}
+
+	e := newExpressionContext()
+	conditionExpression, err := buildConditions(e, t.Conditions)
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("invalid transaction condition: %w", err)
+	}
+	var condition *string
+	if conditionExpression != "" {
+		condition = aws.String(conditionExpression)
+	}
+
+	if t.Item != nil {
+		av, err := attributevalue.MarshalMap(t.Item)
+		if err != nil {
+			return types.TransactWriteItem{}, fmt.Errorf("failed to marshal item: %w", err)
+		}
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                 aws.String(t.TableName),
+			Item:                      av,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  e.attributeNames(),
+			ExpressionAttributeValues: e.attributeValues(),
+		}}, nil
+	}
+
+	if t.KeyName == "" || condition == nil {
+		return types.TransactWriteItem{}, errors.New("condition check requires a key and a condition")
+	}
+	key, err := marshalExpressionValue(t.KeyValue)
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+		TableName:                 aws.String(t.TableName),
+		Key:                       map[string]types.AttributeValue{t.KeyName: key},
+		ConditionExpression:       condition,
+		ExpressionAttributeNames:  e.attributeNames(),
+		ExpressionAttributeValues: e.attributeValues(),
+	}}, nil
+}
+
+// FailedConditions returns the positions of the transaction items whose
+// condition failed when err is a cancelled TransactWrite, or nil otherwise
+func FailedConditions(err error) []int {
+	var cancelled *types.TransactionCanceledException
+	if !errors.As(err, &cancelled) {
+		return nil
+	}
+
+	codes := make([]string, 0, len(cancelled.CancellationReasons))
+	for _, reason := range cancelled.CancellationReasons {
+		codes = append(codes, aws.ToString(reason.Code))
+	}
+	if len(codes) == 0 {
+		codes = cancellationCodes(cancelled.ErrorMessage())
+	}
+
+	var failed []int
+	for i, code := range codes {
+		if code == "ConditionalCheckFailed" {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// cancellationCodes parses the reason codes DynamoDB lists at the end of the
+// message of a cancelled transaction, e.g. "... reasons [None,
+// ConditionalCheckFailed]", for responses without CancellationReasons
+func cancellationCodes(message string) []string {
+	start, end := strings.LastIndex(message, "["), strings.LastIndex(message, "]")
+	if start < 0 || end < start {
+		return nil
+	}
+
+	codes := strings.Split(message[start+1:end], ",")
+	for i := range codes {
+		codes[i] = strings.TrimSpace(codes[i])
+	}
+	return codes
+}
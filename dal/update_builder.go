@@ -0,0 +1,220 @@
+package dal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// UpdateAction identifies the kind of change an UpdateOperation makes
+type UpdateAction string
+
+const (
+	UpdateActionSet            UpdateAction = "SET"
+	UpdateActionSetIfNotExists UpdateAction = "SET_IF_NOT_EXISTS"
+	UpdateActionListAppend     UpdateAction = "LIST_APPEND"
+	UpdateActionRemove         UpdateAction = "REMOVE"
+	UpdateActionAdd            UpdateAction = "ADD"
+)
+
+// UpdateOperation is a single typed change to an item attribute. When
+// Condition is set the whole update only succeeds if the condition holds.
+type UpdateOperation struct {
+	Action    UpdateAction
+	Path      string
+	Value     interface{}
+	Condition *Condition
+}
+
+// UpdateBuilder builds DynamoDB UpdateItem requests from typed operations.
+//
+//	update := dal.NewUpdate("dev_users1", "id", userID).
+//		Add("failed_login_attempts", 1).
+//		Remove("account_locked_until").
+//		Set("updated_at", time.Now(), dal.AttributeExists("id"))
+type UpdateBuilder struct {
+	tableName  string
+	keyName    string
+	keyValue   interface{}
+	operations []UpdateOperation
+	conditions []Condition
+}
+
+// NewUpdate starts an update of the item identified by keyName = keyValue
+func NewUpdate(tableName, keyName string, keyValue interface{}) *UpdateBuilder {
+	return &UpdateBuilder{
+		tableName: tableName,
+		keyName:   keyName,
+		keyValue:  keyValue,
+	}
+}
+
+// TableName returns the table the update targets
+func (u *UpdateBuilder) TableName() string {
+	return u.tableName
+}
+
+// ForTable retargets the update at tableName, for tables deployed once per
+// tenant
+func (u *UpdateBuilder) ForTable(tableName string) *UpdateBuilder {
+	u.tableName = tableName
+	return u
+}
+
+// Set assigns value to path
+func (u *UpdateBuilder) Set(path string, value interface{}, condition ...Condition) *UpdateBuilder {
+	return u.add(UpdateActionSet, path, value, condition)
+}
+
+// SetIfNotExists assigns value to path only when the attribute is not set yet
+func (u *UpdateBuilder) SetIfNotExists(path string, value interface{}, condition ...Condition) *UpdateBuilder {
+	return u.add(UpdateActionSetIfNotExists, path, value, condition)
+}
+
+// Append appends the elements of a slice to the list at path, creating the
+// list when it does not exist
+func (u *UpdateBuilder) Append(path string, values interface{}, condition ...Condition) *UpdateBuilder {
+	return u.add(UpdateActionListAppend, path, values, condition)
+}
+
+// Remove deletes the attribute at path
+func (u *UpdateBuilder) Remove(path string, condition ...Condition) *UpdateBuilder {
+	return u.add(UpdateActionRemove, path, nil, condition)
+}
+
+// Add atomically increments a number (use a negative value to decrement) or
+// adds elements to a set
+func (u *UpdateBuilder) Add(path string, value interface{}, condition ...Condition) *UpdateBuilder {
+	return u.add(UpdateActionAdd, path, value, condition)
+}
+
+// Apply appends pre-built operations
+func (u *UpdateBuilder) Apply(operations ...UpdateOperation) *UpdateBuilder {
+	u.operations = append(u.operations, operations...)
+	return u
+}
+
+// Condition adds a condition on the item as a whole; conditions are ANDed
+func (u *UpdateBuilder) Condition(conditions ...Condition) *UpdateBuilder {
+	u.conditions = append(u.conditions, conditions...)
+	return u
+}
+
+// Operations returns the operations added so far
+func (u *UpdateBuilder) Operations() []UpdateOperation {
+	return u.operations
+}
+
+func (u *UpdateBuilder) add(action UpdateAction, path string, value interface{}, condition []Condition) *UpdateBuilder {
+	operation := UpdateOperation{Action: action, Path: path, Value: value}
+	if len(condition) > 0 {
+		combined := condition[0]
+		if len(condition) > 1 {
+			combined = And(condition...)
+		}
+		operation.Condition = &combined
+	}
+	u.operations = append(u.operations, operation)
+	return u
+}
+
+// Build renders the builder into a DynamoDB UpdateItemInput
+func (u *UpdateBuilder) Build() (*dynamodb.UpdateItemInput, error) {
+	if u.tableName == "" || u.keyName == "" {
+		return nil, errors.New("update requires a table name and key")
+	}
+	if len(u.operations) == 0 {
+		return nil, errors.New("update requires at least one operation")
+	}
+
+	e := newExpressionContext()
+
+	var setClauses, removeClauses, addClauses []string
+	conditions := append([]Condition{}, u.conditions...)
+
+	for _, operation := range u.operations {
+		if operation.Path == "" {
+			return nil, fmt.Errorf("%s operation requires an attribute path", operation.Action)
+		}
+		name := e.name(operation.Path)
+
+		switch operation.Action {
+		case UpdateActionSet:
+			value, err := e.value(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+			setClauses = append(setClauses, name+" = "+value)
+		case UpdateActionSetIfNotExists:
+			value, err := e.value(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+			setClauses = append(setClauses, name+" = if_not_exists("+name+", "+value+")")
+		case UpdateActionListAppend:
+			value, err := e.value(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+			empty, err := e.value(&types.AttributeValueMemberL{Value: []types.AttributeValue{}})
+			if err != nil {
+				return nil, err
+			}
+			setClauses = append(setClauses, name+" = list_append(if_not_exists("+name+", "+empty+"), "+value+")")
+		case UpdateActionRemove:
+			removeClauses = append(removeClauses, name)
+		case UpdateActionAdd:
+			value, err := e.value(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+			addClauses = append(addClauses, name+" "+value)
+		default:
+			return nil, fmt.Errorf("unsupported update action %q", operation.Action)
+		}
+
+		if operation.Condition != nil {
+			conditions = append(conditions, *operation.Condition)
+		}
+	}
+
+	var clauses []string
+	if len(setClauses) > 0 {
+		clauses = append(clauses, "SET "+strings.Join(setClauses, ", "))
+	}
+	if len(removeClauses) > 0 {
+		clauses = append(clauses, "REMOVE "+strings.Join(removeClauses, ", "))
+	}
+	if len(addClauses) > 0 {
+		clauses = append(clauses, "ADD "+strings.Join(addClauses, ", "))
+	}
+
+	key, err := marshalExpressionValue(u.keyValue)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:        aws.String(u.tableName),
+		Key:              map[string]types.AttributeValue{u.keyName: key},
+		UpdateExpression: aws.String(strings.Join(clauses, " ")),
+		ReturnValues:     types.ReturnValueAllNew,
+	}
+
+	conditionExpression, err := buildConditions(e, conditions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update condition: %w", err)
+	}
+	if conditionExpression != "" {
+		input.ConditionExpression = aws.String(conditionExpression)
+	}
+
+	input.ExpressionAttributeNames = e.attributeNames()
+	input.ExpressionAttributeValues = e.attributeValues()
+
+	return input, nil
+}
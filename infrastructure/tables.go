@@ -20,6 +20,13 @@ type TableSchema struct {
 	KeySchema              []KeySchemaElement     `json:"KeySchema"`
 	ProvisionedThroughput  Throughput             `json:"ProvisionedThroughput"`
 	GlobalSecondaryIndexes []GlobalSecondaryIndex `json:"GlobalSecondaryIndexes,omitempty"`
+	StreamSpecification    *StreamSpecification   `json:"StreamSpecification,omitempty"`
+}
+
+// StreamSpecification enables DynamoDB Streams on a table
+type StreamSpecification struct {
+	StreamEnabled  bool   `json:"StreamEnabled"`
+	StreamViewType string `json:"StreamViewType"`
 }
 
 type AttributeDefinition struct {
@@ -150,6 +157,14 @@ func (ts *TableSchema) ToDynamoInput() *dynamodb.CreateTableInput {
 		gsisVals = append(gsisVals, *g)
 	}
 
+	var streamSpec *types.StreamSpecification
+	if ts.StreamSpecification != nil && ts.StreamSpecification.StreamEnabled {
+		streamSpec = &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.StreamViewType(ts.StreamSpecification.StreamViewType),
+		}
+	}
+
 	return &dynamodb.CreateTableInput{
 		TableName:            aws.String(ts.TableName),
 		AttributeDefinitions: attrDefsVals,
@@ -159,5 +174,6 @@ func (ts *TableSchema) ToDynamoInput() *dynamodb.CreateTableInput {
 			WriteCapacityUnits: aws.Int64(ts.ProvisionedThroughput.WriteCapacityUnits),
 		},
 		GlobalSecondaryIndexes: gsisVals,
+		StreamSpecification:    streamSpec,
 	}
 }
@@ -7,10 +7,12 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/utils"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/search"
 	"fieldfuze-backend/worker"
 	"fmt"
 	"log"
 	"sync"
+	_ "time/tzdata" // Organization timezones must load on hosts without zoneinfo
 
 	"github.com/gin-gonic/gin"
 )
@@ -75,7 +77,13 @@ func main() {
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
-	c := controller.NewController(context.Background(), config, logger.NewLogger(config.LogLevel, config.LogFormat))
+	// The API and the workers share the job search index
+	jobIndex, err := search.New(config)
+	if err != nil {
+		log.Fatalf("Failed to create job search index: %v", err)
+	}
+
+	c := controller.NewController(context.Background(), config, logger.NewLogger(config.LogLevel, config.LogFormat), jobIndex)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -84,7 +92,7 @@ func main() {
 	}()
 
 	// 🚀 START INFRASTRUCTURE WORKER (CRON JOB)
-	infraWorker, err := worker.NewService(ctx, config, logger.NewLogger(config.LogLevel, config.LogFormat))
+	infraWorker, err := worker.NewService(ctx, config, logger.NewLogger(config.LogLevel, config.LogFormat), jobIndex)
 	if err != nil {
 		log.Fatalf("Failed to create infrastructure worker: %v", err)
 	}
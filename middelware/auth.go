@@ -45,6 +45,7 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/repository"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -103,6 +104,9 @@ const (
 
 // No path-based detection - purely HTTP method-based standard
 
+// OrganizationHeader names the organization a request acts on
+const OrganizationHeader = "X-Organization-ID"
+
 // Performance and security constants
 const (
 	// Cache settings
@@ -177,7 +181,7 @@ type SmartPermissionEvaluator struct {
 type JWTManager struct {
 	Config            *models.Config
 	Logger            logger.Logger
-	UserRepo          *repository.UserRepository
+	UserRepo          repository.UserRepositoryInterface
 	BlacklistedTokens map[string]time.Time // Token revocation blacklist (for immediate invalidation)
 	ActiveTokens      map[string]string    // userID -> current active tokenID (single token per user)
 	TokenMutex        sync.RWMutex         // Thread safety for both maps
@@ -198,7 +202,7 @@ type JWTManager struct {
 }
 
 // NewJWTManager creates a new JWT manager with advanced Go optimizations
-func NewJWTManager(cfg *models.Config, log logger.Logger, userRepo *repository.UserRepository) *JWTManager {
+func NewJWTManager(cfg *models.Config, log logger.Logger, userRepo repository.UserRepositoryInterface) *JWTManager {
 	j := &JWTManager{
 		Config:            cfg,
 		Logger:            log,
@@ -342,6 +346,14 @@ func (j *JWTManager) initializeResourceMappings() {
 		"minimum_level":       8, // Require level 8+ for role deletion
 	})
 
+	j.resourceMapping.Store("role_restore", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "role_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       8, // Require level 8+ for restoring deleted roles
+	})
+
 	// Job management resource mappings
 	j.resourceMapping.Store("job_list", map[string]interface{}{
 		"required_permission": "read",
@@ -383,6 +395,14 @@ func (j *JWTManager) initializeResourceMappings() {
 		"minimum_level":       7, // Require level 7+ for job deletion
 	})
 
+	j.resourceMapping.Store("job_restore", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for restoring deleted jobs
+	})
+
 	j.resourceMapping.Store("job_start", map[string]interface{}{
 		"required_permission": "update",
 		"resource_type":       "job_management",
@@ -399,6 +419,22 @@ func (j *JWTManager) initializeResourceMappings() {
 		"minimum_level":       3, // Require level 3+ for completing jobs
 	})
 
+	j.resourceMapping.Store("job_completion_summary", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for sending completion summaries
+	})
+
+	j.resourceMapping.Store("job_geofence_override", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for overriding the geofence of a job start
+	})
+
 	j.resourceMapping.Store("job_cancel", map[string]interface{}{
 		"required_permission": "manage",
 		"resource_type":       "job_management",
@@ -407,6 +443,14 @@ func (j *JWTManager) initializeResourceMappings() {
 		"minimum_level":       6, // Require level 6+ for cancelling jobs
 	})
 
+	j.resourceMapping.Store("job_hold", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for holding and resuming jobs
+	})
+
 	j.resourceMapping.Store("job_assign", map[string]interface{}{
 		"required_permission": "update",
 		"resource_type":       "job_management",
@@ -415,6 +459,313 @@ func (j *JWTManager) initializeResourceMappings() {
 		"minimum_level":       5, // Require level 5+ for job assignment
 	})
 
+	// Technician availability resource mappings
+	j.resourceMapping.Store("availability_view", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for viewing technician availability
+	})
+
+	j.resourceMapping.Store("availability_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       5, // Require level 5+ for changing technician availability
+	})
+
+	// Recurring job resource mappings
+	j.resourceMapping.Store("recurring_job_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for recurring job list and details
+	})
+
+	j.resourceMapping.Store("recurring_job_create", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       5, // Require level 5+ for recurring job creation
+	})
+
+	j.resourceMapping.Store("recurring_job_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       5, // Require level 5+ for recurring job updates
+	})
+
+	j.resourceMapping.Store("recurring_job_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for recurring job deletion
+	})
+
+	// Checklist resource mappings
+	j.resourceMapping.Store("job_checklist", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for completing checklist items
+	})
+
+	j.resourceMapping.Store("checklist_template_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for checklist template list and details
+	})
+
+	j.resourceMapping.Store("checklist_template_create", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for checklist template creation
+	})
+
+	j.resourceMapping.Store("checklist_template_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for checklist template updates
+	})
+
+	j.resourceMapping.Store("checklist_template_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for checklist template deletion
+	})
+
+	j.resourceMapping.Store("attachment_upload", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for uploading job attachments
+	})
+
+	j.resourceMapping.Store("attachment_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for viewing job attachments
+	})
+
+	j.resourceMapping.Store("attachment_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for deleting job attachments
+	})
+
+	j.resourceMapping.Store("job_activity", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for viewing job activity
+	})
+
+	j.resourceMapping.Store("job_comment", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for commenting on jobs
+	})
+
+	j.resourceMapping.Store("time_tracking", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for tracking time on jobs
+	})
+
+	j.resourceMapping.Store("time_entry_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for viewing time on jobs
+	})
+
+	j.resourceMapping.Store("time_entry_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for correcting time entries
+	})
+
+	j.resourceMapping.Store("timesheet_view", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       3, // Require level 3+ for viewing timesheets
+	})
+
+	j.resourceMapping.Store("timesheet_approve", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for approving and reopening timesheets
+	})
+
+	j.resourceMapping.Store("dispatch_optimize", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       5, // Require level 5+ for optimizing and applying technician routes
+	})
+
+	j.resourceMapping.Store("sla_policy_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for SLA policy list and details
+	})
+
+	j.resourceMapping.Store("sla_policy_create", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for SLA policy creation
+	})
+
+	j.resourceMapping.Store("sla_policy_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for SLA policy updates
+	})
+
+	j.resourceMapping.Store("sla_policy_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       7, // Require level 7+ for SLA policy deletion
+	})
+
+	j.resourceMapping.Store("job_template_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for job template list and details
+	})
+
+	j.resourceMapping.Store("job_template_create", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for job template creation
+	})
+
+	j.resourceMapping.Store("job_template_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for job template updates
+	})
+
+	j.resourceMapping.Store("job_template_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for job template deletion
+	})
+
+	j.resourceMapping.Store("job_import", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for bulk job imports
+	})
+
+	j.resourceMapping.Store("job_export", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for job exports
+	})
+
+	j.resourceMapping.Store("job_dependency", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for job dependency changes
+	})
+
+	j.resourceMapping.Store("job_search", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for job search
+	})
+
+	j.resourceMapping.Store("project_list", map[string]interface{}{
+		"required_permission": "read",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       2, // Require level 2+ for project list and details
+	})
+
+	j.resourceMapping.Store("project_create", map[string]interface{}{
+		"required_permission": "create",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for project creation
+	})
+
+	j.resourceMapping.Store("project_update", map[string]interface{}{
+		"required_permission": "update",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       4, // Require level 4+ for project updates
+	})
+
+	j.resourceMapping.Store("project_delete", map[string]interface{}{
+		"required_permission": "delete",
+		"resource_type":       "job_management",
+		"context_required":    true,
+		"department_scope":    true,
+		"minimum_level":       6, // Require level 6+ for project deletion
+	})
+
 	j.Logger.Debug("Resource-specific permission mappings initialized")
 }
 
@@ -919,7 +1270,7 @@ func (j *JWTManager) invalidateCacheEntriesContaining(keyFragment string) {
 }
 
 // ValidateToken validates a JWT token and returns the claims with database cross-verification
-func (j *JWTManager) ValidateToken(tokenString string) (*models.JWTClaims, error) {
+func (j *JWTManager) ValidateToken(ctx context.Context, tokenString string) (*models.JWTClaims, error) {
 	// Parse the token
 	token, err := jwt.ParseWithClaims(tokenString, &models.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// CRITICAL: Prevent algorithm confusion attacks
@@ -983,19 +1334,12 @@ func (j *JWTManager) ValidateToken(tokenString string) (*models.JWTClaims, error
 
 	// Cross-verify with database for security
 	if j.UserRepo != nil {
-		dbUsers, err := j.UserRepo.GetUser(claims.UserID)
+		dbUser, err := j.UserRepo.GetUserByID(ctx, claims.UserID)
 		if err != nil {
 			j.Logger.Errorf("Failed to verify user in database: %v", err)
 			return nil, fmt.Errorf("user verification failed")
 		}
 
-		if len(dbUsers) == 0 {
-			j.Logger.Errorf("User %s not found in database", claims.UserID)
-			return nil, fmt.Errorf("user not found")
-		}
-
-		dbUser := dbUsers[0]
-
 		// Validate user account status
 		if err := j.validateUserStatus(dbUser); err != nil {
 			j.Logger.Errorf("User status validation failed for %s: %v", claims.UserID, err)
@@ -1119,7 +1463,7 @@ func (j *JWTManager) AuthMiddleware() gin.HandlerFunc {
 		tokenString = strings.TrimSpace(parts[1])
 
 		// Validate token
-		claims, err := j.ValidateToken(tokenString)
+		claims, err := j.ValidateToken(c.Request.Context(), tokenString)
 		if err != nil {
 			j.Logger.Errorf("Token validation failed: %v", err)
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
@@ -1141,6 +1485,27 @@ func (j *JWTManager) AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_roles", claims.Roles)
 		c.Set("user_context", claims.Context)
 		c.Set("jwt_claims", claims)
+		c.Request = c.Request.WithContext(requestctx.WithClaims(c.Request.Context(), claims))
+		// Requests may name the organization they act on, which selects the
+		// tenant tables in isolated tenancy mode. Users may only act on the
+		// organizations they belong to.
+		if orgID := c.GetHeader(OrganizationHeader); orgID != "" {
+			if !claims.BelongsTo(orgID) {
+				j.Logger.Warnf("User %s denied access to organization %s", claims.UserID, orgID)
+				c.JSON(http.StatusForbidden, models.APIResponse{
+					Status:  "error",
+					Code:    http.StatusForbidden,
+					Message: "Organization access denied",
+					Error: &models.APIError{
+						Type:    "AuthorizationError",
+						Details: "User is not a member of the requested organization",
+					},
+				})
+				c.Abort()
+				return
+			}
+			c.Request = c.Request.WithContext(requestctx.WithTenant(c.Request.Context(), orgID))
+		}
 
 		// Add intelligent permission detection for smart APIs
 		c.Set("auto_permission", j.detectAPIPermission(c))
@@ -1194,7 +1559,7 @@ func (j *JWTManager) handleLoginAuthentication(c *gin.Context) {
 	}
 
 	// Get user from database
-	users, err := j.UserRepo.GetUser(req.Email)
+	user, err := j.UserRepo.GetUserCredentials(c.Request.Context(), req.Email)
 	if err != nil {
 		j.Logger.Error("Failed to get user by email", fmt.Errorf("error: %v", err))
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
@@ -1209,22 +1574,6 @@ func (j *JWTManager) handleLoginAuthentication(c *gin.Context) {
 		return
 	}
 
-	if len(users) == 0 {
-		j.Logger.Error("User not found")
-		c.JSON(http.StatusUnauthorized, models.APIResponse{
-			Status:  "error",
-			Code:    http.StatusUnauthorized,
-			Message: "Invalid email or password",
-			Error: &models.APIError{
-				Type:    "AuthenticationError",
-				Details: "Invalid email or password",
-			},
-		})
-		return
-	}
-
-	user := users[0]
-
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		j.Logger.Error("Invalid password")
@@ -1488,7 +1837,7 @@ func (j *JWTManager) ValidateTokenEndpoint(c *gin.Context) {
 	}
 
 	// Validate token
-	claims, err := j.ValidateToken(tokenString)
+	claims, err := j.ValidateToken(c.Request.Context(), tokenString)
 	if err != nil {
 		j.Logger.Errorf("Token validation failed: %v", err)
 		c.JSON(http.StatusUnauthorized, models.APIResponse{
@@ -1596,7 +1945,7 @@ func (j *JWTManager) RequireAdvancedPermission(permission string, contextData ma
 		jwtClaims := claims.(*models.JWTClaims)
 
 		// Create context with timeout for advanced evaluation
-		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 100*time.Millisecond)
 		defer cancel()
 
 		// Use advanced permission evaluator with context
@@ -1625,7 +1974,7 @@ func (j *JWTManager) RequireAdvancedPermission(permission string, contextData ma
 func (j *JWTManager) GetAuthMetrics() map[string]int64 {
 	cacheHits, cacheMisses := j.permissionCache.GetStats()
 
-	return map[string]int64{
+	metrics := map[string]int64{
 		"auth_requests":  atomic.LoadInt64(&j.metrics.authRequests),
 		"auth_successes": atomic.LoadInt64(&j.metrics.authSuccesses),
 		"auth_failures":  atomic.LoadInt64(&j.metrics.authFailures),
@@ -1634,6 +1983,17 @@ func (j *JWTManager) GetAuthMetrics() map[string]int64 {
 		"cache_size":     atomic.LoadInt64(&j.metrics.cacheSize),
 		"evaluations":    atomic.LoadInt64(&j.evaluator.(*SmartPermissionEvaluator).evaluations),
 	}
+
+	if userCache, ok := j.UserRepo.(repository.UserCacheInterface); ok {
+		stats := userCache.CacheStats()
+		metrics["user_cache_hits"] = stats.Hits
+		metrics["user_cache_shared_hits"] = stats.SharedHits
+		metrics["user_cache_misses"] = stats.Misses
+		metrics["user_cache_evictions"] = stats.Evictions
+		metrics["user_cache_size"] = int64(stats.Size)
+	}
+
+	return metrics
 }
 
 // ClearPermissionCache clears the permission cache (useful when roles change)
@@ -1650,6 +2010,15 @@ func (j *JWTManager) ClearPermissionCache() {
 	j.Logger.Debug("Permission cache cleared")
 }
 
+// InvalidateUser drops the cached user record and clears the permission
+// cache so the next request re-reads the user's roles and status
+func (j *JWTManager) InvalidateUser(ctx context.Context, userID string) {
+	if userCache, ok := j.UserRepo.(repository.UserCacheInterface); ok {
+		userCache.InvalidateUser(ctx, userID)
+	}
+	j.ClearPermissionCache()
+}
+
 // RequireResourcePermission creates middleware for resource-specific permission checking with context validation
 func (j *JWTManager) RequireResourcePermission(resourceName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
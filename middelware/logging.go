@@ -4,9 +4,15 @@ import (
 	"time"
 
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// RequestIDHeader carries the request ID to and from clients
+const RequestIDHeader = "X-Request-ID"
+
 // LoggingMiddleware provides request logging
 type LoggingMiddleware struct {
 	logger logger.Logger
@@ -39,6 +45,24 @@ func (m *LoggingMiddleware) RequestLogger() gin.HandlerFunc {
 	})
 }
 
+// RequestID assigns every request an ID, reusing the client's X-Request-ID
+// when present, and stores it in the request context so it reaches the
+// service and repository layers
+func (m *LoggingMiddleware) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(requestctx.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
 // StructuredLogger provides structured logging for requests
 func (m *LoggingMiddleware) StructuredLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -65,6 +89,7 @@ func (m *LoggingMiddleware) StructuredLogger() gin.HandlerFunc {
 			"latency":    latency,
 			"ip":         c.ClientIP(),
 			"user_agent": c.Request.UserAgent(),
+			"request_id": requestctx.RequestID(c.Request.Context()),
 		}
 
 		if userID != nil {
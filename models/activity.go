@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+type ActivityType string
+
+const (
+	ActivityJobCreated        ActivityType = "job_created"
+	ActivityJobUpdated        ActivityType = "job_updated"        // Fields changed, see Changes
+	ActivityStatusChanged     ActivityType = "status_changed"     // See StatusFrom and StatusTo
+	ActivityAssignmentChanged ActivityType = "assignment_changed" // Users or vehicles added or removed
+	ActivityChecklistUpdated  ActivityType = "checklist_updated"  // A checklist item was completed or reopened
+	ActivityAttachmentAdded   ActivityType = "attachment_added"
+	ActivityAttachmentRemoved ActivityType = "attachment_removed"
+	ActivityComment           ActivityType = "comment"
+	ActivityGeofenceFlagged   ActivityType = "geofence_flagged"   // The job was started or completed away from its site
+	ActivityGeofenceOverride  ActivityType = "geofence_override"  // A supervisor allowed the job to start away from its site
+	ActivitySLAAtRisk         ActivityType = "sla_at_risk"        // An SLA target of the job is due soon
+	ActivitySLABreached       ActivityType = "sla_breached"       // An SLA target of the job was missed
+	ActivityDependencyAdded   ActivityType = "dependency_added"   // The job now waits for another job
+	ActivityDependencyRemoved ActivityType = "dependency_removed" // The job no longer waits for another job
+	ActivitySignOffRecorded   ActivityType = "sign_off_recorded"  // The customer signed off the completed job
+	ActivitySummarySent       ActivityType = "summary_sent"       // The completion summary was emailed
+)
+
+// FieldChange is the old and new value of one job field. Values are
+// rendered as text; lists and objects as JSON.
+type FieldChange struct {
+	Field string `json:"field" dynamodbav:"field"`
+	From  string `json:"from,omitempty" dynamodbav:"from,omitempty"`
+	To    string `json:"to,omitempty" dynamodbav:"to,omitempty"`
+}
+
+// Activity is one entry of a job's activity feed: a change made to the job
+// or a comment posted on it
+type Activity struct {
+	ActivityID string       `json:"activityID" dynamodbav:"activityID"`
+	JobID      string       `json:"jobID" dynamodbav:"jobID"`
+	OrgID      string       `json:"orgID" dynamodbav:"orgID"`
+	Sequence   string       `json:"-" dynamodbav:"sequence"` // Orders the feed: creation time followed by the ID
+	Type       ActivityType `json:"type" dynamodbav:"type"`
+	ActorID    string       `json:"actorID" dynamodbav:"actorID"`
+	CreatedAt  time.Time    `json:"createdAt" dynamodbav:"createdAt"`
+	Summary    string       `json:"summary" dynamodbav:"summary"`
+
+	Changes         []FieldChange `json:"changes,omitempty" dynamodbav:"changes,omitempty"`
+	StatusFrom      JobStatus     `json:"statusFrom,omitempty" dynamodbav:"statusFrom,omitempty"`
+	StatusTo        JobStatus     `json:"statusTo,omitempty" dynamodbav:"statusTo,omitempty"`
+	Reason          string        `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	UsersAdded      []string      `json:"usersAdded,omitempty" dynamodbav:"usersAdded,omitempty"`
+	UsersRemoved    []string      `json:"usersRemoved,omitempty" dynamodbav:"usersRemoved,omitempty"`
+	VehiclesAdded   []string      `json:"vehiclesAdded,omitempty" dynamodbav:"vehiclesAdded,omitempty"`
+	VehiclesRemoved []string      `json:"vehiclesRemoved,omitempty" dynamodbav:"vehiclesRemoved,omitempty"`
+	ChecklistItemID string        `json:"checklistItemID,omitempty" dynamodbav:"checklistItemID,omitempty"`
+	AttachmentID    string        `json:"attachmentID,omitempty" dynamodbav:"attachmentID,omitempty"`
+
+	// Comments only
+	Body     string   `json:"body,omitempty" dynamodbav:"body,omitempty"`
+	ParentID string   `json:"parentID,omitempty" dynamodbav:"parentID,omitempty"` // Comment replied to
+	ThreadID string   `json:"threadID,omitempty" dynamodbav:"threadID,omitempty"` // First comment of the thread
+	Mentions []string `json:"mentions,omitempty" dynamodbav:"mentions,omitempty"` // IDs of the users mentioned with @username
+}
+
+// ActivityPage is one page of a job's activity feed, newest first.
+// NextCursor is empty on the last page.
+type ActivityPage struct {
+	Items      []*Activity `json:"items"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// CreateCommentRequest posts a comment on a job. Org users are mentioned
+// with @username; ParentID replies to another comment of the job.
+type CreateCommentRequest struct {
+	Body     string `json:"body" validate:"required,min=1,max=2000"`
+	ParentID string `json:"parentID,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type AttachmentCategory string
+
+const (
+	AttachmentCategoryBefore   AttachmentCategory = "before"   // Photo taken before the service
+	AttachmentCategoryAfter    AttachmentCategory = "after"    // Photo taken after the service
+	AttachmentCategoryDocument AttachmentCategory = "document" // Manual, report, permit and the like
+	AttachmentCategorySignOff  AttachmentCategory = "sign_off" // Customer signature or completion summary, kept for the life of the job
+)
+
+// Attachment is a photo or document uploaded to a job. The file itself lives
+// in the blob store; URL and ThumbnailURL are signed download links that are
+// generated on every read and expire at URLExpiresAt.
+type Attachment struct {
+	AttachmentID string             `json:"attachmentID" dynamodbav:"attachmentID"`
+	JobID        string             `json:"jobID" dynamodbav:"jobID"`
+	OrgID        string             `json:"orgID" dynamodbav:"orgID"`
+	Category     AttachmentCategory `json:"category" dynamodbav:"category"`
+	FileName     string             `json:"fileName" dynamodbav:"fileName"`
+	ContentType  string             `json:"contentType" dynamodbav:"contentType"` // Sniffed from the content, not taken from the client
+	Size         int64              `json:"size" dynamodbav:"size"`               // Bytes
+	BlobKey      string             `json:"-" dynamodbav:"blobKey"`
+	ThumbnailKey string             `json:"-" dynamodbav:"thumbnailKey,omitempty"` // Images only
+	UploadedBy   string             `json:"uploadedBy" dynamodbav:"uploadedBy"`
+	CreatedAt    time.Time          `json:"createdAt" dynamodbav:"createdAt"`
+	DeletedData  *DeletedData       `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt      int64              `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+
+	URL          string     `json:"url,omitempty" dynamodbav:"-"`
+	ThumbnailURL string     `json:"thumbnailURL,omitempty" dynamodbav:"-"`
+	URLExpiresAt *time.Time `json:"urlExpiresAt,omitempty" dynamodbav:"-"`
+}
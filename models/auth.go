@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -30,3 +32,26 @@ type JWTClaims struct {
 
 	jwt.RegisteredClaims
 }
+
+// BelongsTo reports whether the user is a member of the organization orgID:
+// it is their organization, or one of their active roles is scoped to it or
+// to every organization ("*")
+func (c *JWTClaims) BelongsTo(orgID string) bool {
+	if orgID == "" {
+		return false
+	}
+	if c.Context.OrganizationID == orgID {
+		return true
+	}
+
+	now := time.Now()
+	for _, role := range c.Roles {
+		if role.DeletedData != nil || (role.ExpiresAt != nil && role.ExpiresAt.Before(now)) {
+			continue
+		}
+		if roleOrgID := role.Context["organization_id"]; roleOrgID == orgID || roleOrgID == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJWTClaimsBelongsTo(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	claims := &JWTClaims{
+		Context: UserContext{OrganizationID: "org-1"},
+		Roles: []RoleAssignment{
+			{RoleName: "Dispatcher", Context: map[string]string{"organization_id": "org-2"}},
+			{RoleName: "Expired", Context: map[string]string{"organization_id": "org-3"}, ExpiresAt: &expired},
+			{RoleName: "Deleted", Context: map[string]string{"organization_id": "org-4"}, DeletedData: &DeletedData{}},
+		},
+	}
+
+	tests := []struct {
+		orgID string
+		want  bool
+	}{
+		{orgID: "org-1", want: true},
+		{orgID: "org-2", want: true},
+		{orgID: "org-3", want: false},
+		{orgID: "org-4", want: false},
+		{orgID: "org-5", want: false},
+		{orgID: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := claims.BelongsTo(tt.orgID); got != tt.want {
+			t.Errorf("BelongsTo(%q) = %v, want %v", tt.orgID, got, tt.want)
+		}
+	}
+
+	admin := &JWTClaims{Roles: []RoleAssignment{{RoleName: "Admin", Context: map[string]string{"organization_id": "*"}}}}
+	if !admin.BelongsTo("org-5") {
+		t.Errorf("BelongsTo() of a role scoped to every organization = false, want true")
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Assignment conflict types
+const (
+	ConflictUnknownUser         = "unknown_user"
+	ConflictInactiveUser        = "inactive_user"
+	ConflictOtherOrganization   = "other_organization"
+	ConflictDoubleBooked        = "double_booked"
+	ConflictOutsideWorkingHours = "outside_working_hours"
+	ConflictTimeOff             = "time_off"
+)
+
+// AssignmentConflict describes one problem with a technician or vehicle
+// assigned to a job
+type AssignmentConflict struct {
+	Type         string `json:"type"`
+	ResourceType string `json:"resourceType"` // "user" or "vehicle"
+	ResourceID   string `json:"resourceID"`
+	JobID        string `json:"jobID,omitempty"` // The overlapping job, for double bookings
+	Message      string `json:"message"`
+}
+
+// TechnicianAvailability holds when a technician can be scheduled. A
+// technician without working hours can be scheduled at any time outside
+// their time off.
+type TechnicianAvailability struct {
+	UserID       string         `json:"userID" dynamodbav:"userID"`
+	Timezone     string         `json:"timezone" dynamodbav:"timezone"` // Timezone the working hours are in
+	WorkingHours []WorkingHours `json:"workingHours" dynamodbav:"workingHours"`
+	TimeOff      []TimeOff      `json:"timeOff" dynamodbav:"timeOff"`
+	UpdatedAt    time.Time      `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy    string         `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+
+	// StartLocation is where the technician's day starts, such as their home
+	// or the depot. Routes are planned from it.
+	StartLocation *JobSite `json:"startLocation,omitempty" dynamodbav:"startLocation,omitempty"`
+}
+
+// WorkingHours is a weekly working period, e.g. monday 08:00 to 16:30
+type WorkingHours struct {
+	Day   string `json:"day" dynamodbav:"day" validate:"required,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	Start string `json:"start" dynamodbav:"start" validate:"required,datetime=15:04"`
+	End   string `json:"end" dynamodbav:"end" validate:"required,datetime=15:04"`
+}
+
+// TimeOff is a period a technician cannot be scheduled in
+type TimeOff struct {
+	Start  time.Time `json:"start" dynamodbav:"start" validate:"required"`
+	End    time.Time `json:"end" dynamodbav:"end" validate:"required"`
+	Reason string    `json:"reason,omitempty" dynamodbav:"reason,omitempty" validate:"omitempty,max=200"`
+}
+
+// Location returns the timezone of the working hours, falling back to UTC
+func (a *TechnicianAvailability) Location() *time.Location {
+	if a.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetAvailabilityRequest replaces the availability of a technician
+type SetAvailabilityRequest struct {
+	Timezone     string         `json:"timezone" validate:"required,timezone" example:"America/New_York"`
+	WorkingHours []WorkingHours `json:"workingHours,omitempty" validate:"omitempty,dive"`
+	TimeOff      []TimeOff      `json:"timeOff,omitempty" validate:"omitempty,dive"`
+
+	// StartLocation is where the technician's day starts
+	StartLocation *JobSiteInput `json:"startLocation,omitempty"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+type ChecklistValueType string
+
+const (
+	ChecklistValueNone    ChecklistValueType = "none"    // Ticked off without a value
+	ChecklistValueReading ChecklistValueType = "reading" // A numeric reading such as a pressure
+	ChecklistValueYesNo   ChecklistValueType = "yes_no"  // "yes" or "no"
+	ChecklistValuePhoto   ChecklistValueType = "photo"   // The URL of a photo
+)
+
+// ChecklistTemplate is a standard procedure of an organization. Its items are
+// copied onto every job of JobType created afterwards, or onto every job when
+// JobType is empty.
+type ChecklistTemplate struct {
+	TemplateID  string                  `json:"templateID" dynamodbav:"templateID"`
+	OrgID       string                  `json:"orgID" dynamodbav:"orgID"`
+	JobType     JobType                 `json:"jobType,omitempty" dynamodbav:"jobType,omitempty"`
+	Name        string                  `json:"name" dynamodbav:"name"`
+	Items       []ChecklistTemplateItem `json:"items" dynamodbav:"items"`
+	CreatedAt   time.Time               `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData CreatedData             `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt   time.Time               `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy   string                  `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	DeletedData *DeletedData            `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt     int64                   `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+}
+
+// ChecklistTemplateItem is one step of a checklist template
+type ChecklistTemplateItem struct {
+	ItemID    string             `json:"itemID" dynamodbav:"itemID"`
+	Label     string             `json:"label" dynamodbav:"label"`
+	Required  bool               `json:"required" dynamodbav:"required"` // Must be completed before the job is
+	ValueType ChecklistValueType `json:"valueType" dynamodbav:"valueType"`
+}
+
+// ChecklistItem is a checklist step on a job together with its completion
+type ChecklistItem struct {
+	ItemID      string             `json:"itemID" dynamodbav:"itemID"`
+	TemplateID  string             `json:"templateID" dynamodbav:"templateID"`
+	Label       string             `json:"label" dynamodbav:"label"`
+	Required    bool               `json:"required" dynamodbav:"required"`
+	ValueType   ChecklistValueType `json:"valueType" dynamodbav:"valueType"`
+	Completed   bool               `json:"completed" dynamodbav:"completed"`
+	CompletedBy string             `json:"completedBy,omitempty" dynamodbav:"completedBy,omitempty"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty" dynamodbav:"completedAt,omitempty"`
+	Value       string             `json:"value,omitempty" dynamodbav:"value,omitempty"`
+	Notes       string             `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+}
+
+// ChecklistTemplateItemInput is an item of a checklist template request. A
+// missing ValueType means none.
+type ChecklistTemplateItemInput struct {
+	Label     string             `json:"label" validate:"required,min=2,max=200"`
+	Required  bool               `json:"required,omitempty"`
+	ValueType ChecklistValueType `json:"valueType,omitempty" validate:"omitempty,oneof=none reading yes_no photo"`
+}
+
+// CreateChecklistTemplateRequest creates a checklist template
+type CreateChecklistTemplateRequest struct {
+	OrgID   string                       `json:"orgID" validate:"required"`
+	JobType JobType                      `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Name    string                       `json:"name" validate:"required,min=2,max=200"`
+	Items   []ChecklistTemplateItemInput `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// UpdateChecklistTemplateRequest changes a checklist template. Items, when
+// given, replaces the items. Jobs created before keep their checklist.
+type UpdateChecklistTemplateRequest struct {
+	JobType JobType                      `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Name    string                       `json:"name,omitempty" validate:"omitempty,min=2,max=200"`
+	Items   []ChecklistTemplateItemInput `json:"items,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// UpdateChecklistItemRequest completes or reopens a checklist item of a job.
+// Value is required for readings ("12.5"), yes/no questions ("yes" or "no")
+// and photos (the photo's URL).
+type UpdateChecklistItemRequest struct {
+	Completed bool   `json:"completed"`
+	Value     string `json:"value,omitempty" validate:"omitempty,max=1000"`
+	Notes     string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
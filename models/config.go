@@ -21,6 +21,12 @@ type Config struct {
 	StrictRoleValidation          bool `mapstructure:"strict_role_validation"`
 	LogPermissionChanges          bool `mapstructure:"log_permission_changes"`
 
+	// Repository cache
+	RepositoryCacheEnabled bool `mapstructure:"repository_cache_enabled"`
+	RepositoryCacheSize    int  `mapstructure:"repository_cache_size"`
+	UserCacheTTLSeconds    int  `mapstructure:"user_cache_ttl_seconds"`
+	RoleCacheTTLSeconds    int  `mapstructure:"role_cache_ttl_seconds"`
+
 	// AWS
 	AWSRegion           string `mapstructure:"aws_region"`
 	AWSAccessKeyID      string `mapstructure:"aws_access_key_id"`
@@ -28,6 +34,17 @@ type Config struct {
 	DynamoDBEndpoint    string `mapstructure:"dynamodb_endpoint"`
 	DynamoDBTablePrefix string `mapstructure:"dynamodb_table_prefix"`
 
+	// DAL resilience
+	DALOperationTimeoutMs        int `mapstructure:"dal_operation_timeout_ms"`
+	DALScanTimeoutMs             int `mapstructure:"dal_scan_timeout_ms"`
+	DALMaxRetries                int `mapstructure:"dal_max_retries"`
+	DALRetryBaseDelayMs          int `mapstructure:"dal_retry_base_delay_ms"`
+	DALRetryMaxDelayMs           int `mapstructure:"dal_retry_max_delay_ms"`
+	DALBreakerFailurePercent     int `mapstructure:"dal_breaker_failure_percent"`
+	DALBreakerMinRequests        int `mapstructure:"dal_breaker_min_requests"`
+	DALBreakerWindowSeconds      int `mapstructure:"dal_breaker_window_seconds"`
+	DALBreakerOpenTimeoutSeconds int `mapstructure:"dal_breaker_open_timeout_seconds"`
+
 	// Telnyx
 	TelnyxAPIKey    string `mapstructure:"telnyx_api_key"`
 	TelnyxAppID     string `mapstructure:"telnyx_app_id"`
@@ -43,8 +60,109 @@ type Config struct {
 	// Rate Limiting
 	RateLimitRequestsPerMinute int `mapstructure:"rate_limit_requests_per_minute"`
 
+	// Data retention
+	SoftDeleteRetentionDays int    `mapstructure:"soft_delete_retention_days"`
+	PurgeCronSchedule       string `mapstructure:"purge_cron_schedule"`
+
+	// Multi-tenancy
+	TenancyMode             string `mapstructure:"tenancy_mode"`
+	TenantProvisionSchedule string `mapstructure:"tenant_provision_schedule"`
+
+	// Recurring jobs
+	RecurrenceSchedule    string `mapstructure:"recurrence_schedule"`
+	RecurrenceHorizonDays int    `mapstructure:"recurrence_horizon_days"`
+
+	// Job checklists
+	ChecklistRequiredToComplete bool `mapstructure:"checklist_required_to_complete"`
+
+	// Blob storage for job attachments
+	BlobBackend           string `mapstructure:"blob_backend"`
+	BlobLocalPath         string `mapstructure:"blob_local_path"`
+	BlobBaseURL           string `mapstructure:"blob_base_url"`
+	BlobSigningSecret     string `mapstructure:"blob_signing_secret"`
+	BlobS3Endpoint        string `mapstructure:"blob_s3_endpoint"`
+	BlobS3Bucket          string `mapstructure:"blob_s3_bucket"`
+	BlobS3Region          string `mapstructure:"blob_s3_region"`
+	BlobS3PathStyle       bool   `mapstructure:"blob_s3_path_style"`
+	BlobS3AccessKeyID     string `mapstructure:"blob_s3_access_key_id"`
+	BlobS3SecretAccessKey string `mapstructure:"blob_s3_secret_access_key"`
+	BlobURLTTLSeconds     int    `mapstructure:"blob_url_ttl_seconds"`
+	AttachmentMaxSizeMB   int    `mapstructure:"attachment_max_size_mb"`
+
+	// Geofence checks on job start and completion
+	GeofenceRadiusMeters int    `mapstructure:"geofence_radius_m"` // Used by organizations without their own radius
+	GeocoderURL          string `mapstructure:"geocoder_url"`
+	GeocoderUserAgent    string `mapstructure:"geocoder_user_agent"`
+	GeocoderTimeoutMs    int    `mapstructure:"geocoder_timeout_ms"`
+
+	// Route optimization
+	RoutingProvider        string  `mapstructure:"routing_provider"`
+	RoutingAverageSpeedKmh float64 `mapstructure:"routing_average_speed_kmh"`
+	RoutingDetourFactor    float64 `mapstructure:"routing_detour_factor"`
+
+	// Job SLAs
+	SLASchedule      string `mapstructure:"sla_schedule"`
+	SLAAtRiskPercent int    `mapstructure:"sla_at_risk_percent"` // Share of a target's time left at which a job is at risk
+
+	// Notifications
+	NotificationProvider   string `mapstructure:"notification_provider"`
+	NotificationWebhookURL string `mapstructure:"notification_webhook_url"`
+	NotificationTimeoutMs  int    `mapstructure:"notification_timeout_ms"`
+
+	// Job import
+	JobImportMaxRows     int `mapstructure:"job_import_max_rows"`
+	JobImportMaxUploadMB int `mapstructure:"job_import_max_upload_mb"`
+
+	// Job search
+	SearchProvider        string `mapstructure:"search_provider"`
+	SearchReindexSchedule string `mapstructure:"search_reindex_schedule"` // Rebuilds the index to pick up writes of other instances
+	SearchMaxPageSize     int    `mapstructure:"search_max_page_size"`
+
+	// Change data capture
+	StreamConsumerEnabled bool `mapstructure:"stream_consumer_enabled"`
+	StreamPollIntervalMs  int  `mapstructure:"stream_poll_interval_ms"`
+	StreamBatchSize       int  `mapstructure:"stream_batch_size"`
+
 	// Base Path
 	BasePath string `mapstructure:"basePath"`
 
 	Tables []string `mapstructure:"tables"`
 }
+
+// Tenancy modes
+const (
+	// TenancyShared keeps every organization's data in the same tables
+	TenancyShared = "shared"
+	// TenancyIsolated gives every organization its own tenant-scoped tables
+	TenancyIsolated = "isolated"
+)
+
+// Blob storage backends
+const (
+	// BlobBackendLocal keeps attachments on the local filesystem
+	BlobBackendLocal = "local"
+	// BlobBackendS3 keeps attachments in an S3-compatible bucket
+	BlobBackendS3 = "s3"
+)
+
+// Distance matrix providers for route optimization
+const (
+	// RoutingProviderGreatCircle estimates travel from straight-line
+	// distances and works offline
+	RoutingProviderGreatCircle = "great_circle"
+)
+
+// Notification providers
+const (
+	// NotificationProviderLog writes notifications to the log
+	NotificationProviderLog = "log"
+	// NotificationProviderWebhook posts notifications to a webhook
+	NotificationProviderWebhook = "webhook"
+)
+
+// Search index providers
+const (
+	// SearchProviderMemory keeps the search index in the memory of each
+	// instance
+	SearchProviderMemory = "memory"
+)
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// OptimizeRoutesRequest asks for the best order of the jobs each technician
+// is scheduled for on Date, a day in the organization's timezone
+type OptimizeRoutesRequest struct {
+	OrgID       string   `json:"orgID" validate:"required"`
+	Date        string   `json:"date" validate:"required,datetime=2006-01-02"`
+	Technicians []string `json:"technicians" validate:"required,min=1,max=50,dive,required"`
+
+	// StartTime is when the routes start, HH:MM in the organization's
+	// timezone. Without it each technician starts at the beginning of their
+	// working hours, or at 08:00.
+	StartTime string `json:"startTime,omitempty" validate:"omitempty,datetime=15:04"`
+
+	// Apply reschedules the routed jobs to the planned times
+	Apply bool `json:"apply,omitempty"`
+}
+
+// RouteOptimization holds one route per requested technician
+type RouteOptimization struct {
+	OrgID    string            `json:"orgID"`
+	Date     string            `json:"date"`
+	Timezone string            `json:"timezone"`
+	Applied  bool              `json:"applied"`
+	Routes   []TechnicianRoute `json:"routes"`
+}
+
+// TechnicianRoute is the planned order of a technician's jobs for the day.
+// Without a start location the route starts at the first stop.
+type TechnicianRoute struct {
+	UserID        string        `json:"userID"`
+	StartLocation *JobSite      `json:"startLocation,omitempty"`
+	StartTime     time.Time     `json:"startTime"`
+	EndTime       time.Time     `json:"endTime"` // When the last job is done
+	TravelMeters  float64       `json:"travelMeters"`
+	TravelMinutes int           `json:"travelMinutes"`
+	LateStops     int           `json:"lateStops"` // Stops reached after their arrival window
+	Stops         []RouteStop   `json:"stops"`
+	Unrouted      []UnroutedJob `json:"unrouted,omitempty"`
+}
+
+// RouteStop is one job of a route. The technician arrives at ArriveAt,
+// waits for the arrival window to open if needed, and works from StartAt to
+// EndAt.
+type RouteStop struct {
+	Sequence       int            `json:"sequence"`
+	JobID          string         `json:"jobID"`
+	JobsName       string         `json:"jobsName"`
+	Site           *JobSite       `json:"site"`
+	ArrivalWindow  *ArrivalWindow `json:"arrivalWindow,omitempty"`
+	TravelMeters   float64        `json:"travelMeters"` // From the previous stop or the start location
+	TravelMinutes  int            `json:"travelMinutes"`
+	ArriveAt       time.Time      `json:"arriveAt"`
+	WaitMinutes    int            `json:"waitMinutes,omitempty"`
+	StartAt        time.Time      `json:"startAt"`
+	EndAt          time.Time      `json:"endAt"`
+	LateMinutes    int            `json:"lateMinutes,omitempty"`    // Arrival after the end of the arrival window
+	ScheduledStart *time.Time     `json:"scheduledStart,omitempty"` // Start the job was scheduled for before optimization
+}
+
+// UnroutedJob is a scheduled job of a technician that could not be routed
+type UnroutedJob struct {
+	JobID    string `json:"jobID"`
+	JobsName string `json:"jobsName"`
+	Reason   string `json:"reason"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Geofence policies of an organization
+const (
+	// GeofencePolicyFlag lets technicians start jobs away from the site and
+	// flags the start check
+	GeofencePolicyFlag = "flag"
+	// GeofencePolicyOverride refuses to start jobs away from the site until
+	// a supervisor overrides the geofence
+	GeofencePolicyOverride = "override"
+)
+
+type GeofenceResult string
+
+const (
+	GeofenceInside     GeofenceResult = "inside"
+	GeofenceOutside    GeofenceResult = "outside"
+	GeofenceUnverified GeofenceResult = "unverified" // The device or the site location is unknown, see Note
+)
+
+// JobSite is where a job is carried out. Without explicit coordinates they
+// are looked up from Address when a geocoder is configured.
+type JobSite struct {
+	Address   string   `json:"address,omitempty" dynamodbav:"address,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty" dynamodbav:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty" dynamodbav:"longitude,omitempty"`
+	Geocoded  bool     `json:"geocoded,omitempty" dynamodbav:"geocoded,omitempty"` // Coordinates were looked up from Address
+}
+
+// HasCoordinates reports whether the site's latitude and longitude are known
+func (s *JobSite) HasCoordinates() bool {
+	return s != nil && s.Latitude != nil && s.Longitude != nil
+}
+
+// JobSiteInput sets the site of a job in create and update requests. Give
+// an address, coordinates, or both.
+type JobSiteInput struct {
+	Address   string   `json:"address,omitempty" validate:"omitempty,max=300"`
+	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+}
+
+// DeviceLocation is the position reported by a technician's device when a
+// job is started or completed
+type DeviceLocation struct {
+	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	Accuracy  float64  `json:"accuracy,omitempty" validate:"omitempty,min=0"` // Meters
+}
+
+// GeofenceCheck records how far from the job site a job was started or
+// completed. A device counts as inside when the site lies within the
+// geofence radius plus the reported accuracy, the latter capped at the
+// radius.
+type GeofenceCheck struct {
+	Result         GeofenceResult    `json:"result" dynamodbav:"result"`
+	Latitude       *float64          `json:"latitude,omitempty" dynamodbav:"latitude,omitempty"`
+	Longitude      *float64          `json:"longitude,omitempty" dynamodbav:"longitude,omitempty"`
+	Accuracy       float64           `json:"accuracy,omitempty" dynamodbav:"accuracy,omitempty"`             // Meters
+	DistanceMeters *float64          `json:"distanceMeters,omitempty" dynamodbav:"distanceMeters,omitempty"` // From the job site
+	RadiusMeters   int               `json:"radiusMeters" dynamodbav:"radiusMeters"`
+	Note           string            `json:"note,omitempty" dynamodbav:"note,omitempty"`
+	UID            string            `json:"uID" dynamodbav:"uID"`
+	CheckedAt      time.Time         `json:"checkedAt" dynamodbav:"checkedAt"`
+	Override       *GeofenceOverride `json:"override,omitempty" dynamodbav:"override,omitempty"` // Override the job was started with
+}
+
+// GeofenceOverride allows the next start of a job outside its geofence
+// under the override policy
+type GeofenceOverride struct {
+	UID       string    `json:"uID" dynamodbav:"uID"`
+	Reason    string    `json:"reason" dynamodbav:"reason"`
+	GrantedAt time.Time `json:"grantedAt" dynamodbav:"grantedAt"`
+}
+
+// GeofenceOverrideRequest explains why a job may start outside its geofence
+type GeofenceOverrideRequest struct {
+	Reason string `json:"reason" validate:"required,min=2,max=500"`
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
 
 type JobStatus string
 
@@ -13,6 +18,32 @@ const (
 	JobStatusOnHold     JobStatus = "on_hold"
 )
 
+// jobTransitions lists the statuses a job may move to from each status.
+// Completed and cancelled jobs are final.
+var jobTransitions = map[JobStatus][]JobStatus{
+	JobStatusPending:    {JobStatusActive, JobStatusInProgress, JobStatusOnHold, JobStatusCancelled},
+	JobStatusActive:     {JobStatusInProgress, JobStatusOnHold, JobStatusCancelled},
+	JobStatusInProgress: {JobStatusCompleted, JobStatusOnHold, JobStatusCancelled},
+	JobStatusOnHold:     {JobStatusPending, JobStatusActive, JobStatusInProgress, JobStatusCancelled},
+	JobStatusCompleted:  {},
+	JobStatusCancelled:  {},
+}
+
+// CanTransitionTo reports whether a job in status s may move to next
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	for _, allowed := range jobTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresReason reports whether moving a job to s must be explained
+func (s JobStatus) RequiresReason() bool {
+	return s == JobStatusOnHold || s == JobStatusCancelled
+}
+
 type JobType string
 
 const (
@@ -36,6 +67,8 @@ type StartedData struct {
 	StartedAt  time.Time `json:"startedAt,omitempty" dynamodbav:"startedAt,omitempty"`
 }
 
+// DeletedData records who soft-deleted an item, when and why. Items carrying
+// it are hidden from reads until restored or purged.
 type DeletedData struct {
 	UID        string    `json:"uID,omitempty" dynamodbav:"uID,omitempty"`
 	UserName   string    `json:"userName,omitempty" dynamodbav:"userName,omitempty"`
@@ -44,59 +77,227 @@ type DeletedData struct {
 	Reason     string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
 }
 
+type CancelledData struct {
+	UID         string    `json:"uID,omitempty" dynamodbav:"uID,omitempty"`
+	UserName    string    `json:"userName,omitempty" dynamodbav:"userName,omitempty"`
+	UserStatus  string    `json:"userStatus,omitempty" dynamodbav:"userStatus,omitempty"`
+	CancelledAt time.Time `json:"cancelledAt,omitempty" dynamodbav:"cancelledAt,omitempty"`
+	Reason      string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+}
+
+// StatusChange records one status transition of a job
+type StatusChange struct {
+	From      JobStatus `json:"from,omitempty" dynamodbav:"from,omitempty"`
+	To        JobStatus `json:"to" dynamodbav:"to"`
+	UID       string    `json:"uID" dynamodbav:"uID"`
+	ChangedAt time.Time `json:"changedAt" dynamodbav:"changedAt"`
+	Reason    string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+}
+
+// ArrivalWindow is the period in which the technician is expected on site
+type ArrivalWindow struct {
+	Start time.Time `json:"start" dynamodbav:"start"`
+	End   time.Time `json:"end" dynamodbav:"end"`
+}
+
 type QBInfoOnJob struct {
-	CustomerID  string `json:"customerID,omitempty" dynamodbav:"customerID,omitempty"`
-	InvoiceID   string `json:"invoiceID,omitempty" dynamodbav:"invoiceID,omitempty"`
-	LineItemID  string `json:"lineItemID,omitempty" dynamodbav:"lineItemID,omitempty"`
-	PaymentID   string `json:"paymentID,omitempty" dynamodbav:"paymentID,omitempty"`
-	ScheduledAt string `json:"scheduledAt,omitempty" dynamodbav:"scheduledAt,omitempty"`
+	CustomerID   string `json:"customerID,omitempty" dynamodbav:"customerID,omitempty"`
+	InvoiceID    string `json:"invoiceID,omitempty" dynamodbav:"invoiceID,omitempty"`
+	LineItemID   string `json:"lineItemID,omitempty" dynamodbav:"lineItemID,omitempty"`
+	PaymentID    string `json:"paymentID,omitempty" dynamodbav:"paymentID,omitempty"`
+	ScheduledAt  string `json:"scheduledAt,omitempty" dynamodbav:"scheduledAt,omitempty"`
 	ServiceNotes string `json:"serviceNotes,omitempty" dynamodbav:"serviceNotes,omitempty"`
 }
 
 type Job struct {
-	JobID                   string      `json:"jobID" dynamodbav:"jobID" validate:"omitempty,uuid4"`
-	ClientID                string      `json:"clientID" dynamodbav:"clientID" validate:"required"`
-	CreatedAt               time.Time   `json:"createdAt" dynamodbav:"createdAt"`
-	CreatedData             CreatedData `json:"createdData" dynamodbav:"createdData" validate:"required"`
-	DeletedData             *DeletedData `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
-	InvID                   string      `json:"invID,omitempty" dynamodbav:"invID,omitempty"`
-	JobEndedAt              *time.Time  `json:"jobEndedAt,omitempty" dynamodbav:"jobEndedAt,omitempty"`
-	JobImagesAfterService   []string    `json:"jobImagesAfterService" dynamodbav:"jobImagesAfterService"`
-	JobsName                string      `json:"jobsName" dynamodbav:"jobsName" validate:"required,min=2,max=200"`
-	JobStartedAt            *time.Time  `json:"jobStartedAt,omitempty" dynamodbav:"jobStartedAt,omitempty"`
-	JobStatus               JobStatus   `json:"jobStatus" dynamodbav:"jobStatus" validate:"required,oneof=pending active in_progress completed cancelled on_hold"`
-	JobType                 JobType     `json:"jobType" dynamodbav:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
-	Notes                   string      `json:"notes,omitempty" dynamodbav:"notes,omitempty" validate:"omitempty,max=1000"`
-	OrgID                   string      `json:"orgID" dynamodbav:"orgID" validate:"required"`
-	PaymentID               string      `json:"paymentID,omitempty" dynamodbav:"paymentID,omitempty"`
-	QBInfoOnJob             *QBInfoOnJob `json:"qbInfoOnJob,omitempty" dynamodbav:"qbInfoOnJob,omitempty"`
-	StartedData             *StartedData `json:"startedData,omitempty" dynamodbav:"startedData,omitempty"`
-	UsersAssignedToJob      []string    `json:"usersAssignedToJob" dynamodbav:"usersAssignedToJob"`
-	VehiclesAssignedToJob   []string    `json:"vehiclesAssignedToJob" dynamodbav:"vehiclesAssignedToJob"`
-	UpdatedAt               time.Time   `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
-	UpdatedBy               string      `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	JobID                 string          `json:"jobID" dynamodbav:"jobID" validate:"omitempty,uuid4"`
+	ClientID              string          `json:"clientID" dynamodbav:"clientID" validate:"required"`
+	CreatedAt             time.Time       `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData           CreatedData     `json:"createdData" dynamodbav:"createdData" validate:"required"`
+	CancelledData         *CancelledData  `json:"cancelledData,omitempty" dynamodbav:"cancelledData,omitempty"`
+	DeletedData           *DeletedData    `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	InvID                 string          `json:"invID,omitempty" dynamodbav:"invID,omitempty"`
+	JobEndedAt            *time.Time      `json:"jobEndedAt,omitempty" dynamodbav:"jobEndedAt,omitempty"`
+	JobImagesAfterService []string        `json:"jobImagesAfterService" dynamodbav:"jobImagesAfterService"`
+	JobsName              string          `json:"jobsName" dynamodbav:"jobsName" validate:"required,min=2,max=200"`
+	JobStartedAt          *time.Time      `json:"jobStartedAt,omitempty" dynamodbav:"jobStartedAt,omitempty"`
+	JobStatus             JobStatus       `json:"jobStatus" dynamodbav:"jobStatus" validate:"required,oneof=pending active in_progress completed cancelled on_hold"`
+	JobType               JobType         `json:"jobType" dynamodbav:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
+	Notes                 string          `json:"notes,omitempty" dynamodbav:"notes,omitempty" validate:"omitempty,max=1000"`
+	OrgID                 string          `json:"orgID" dynamodbav:"orgID" validate:"required"`
+	PaymentID             string          `json:"paymentID,omitempty" dynamodbav:"paymentID,omitempty"`
+	QBInfoOnJob           *QBInfoOnJob    `json:"qbInfoOnJob,omitempty" dynamodbav:"qbInfoOnJob,omitempty"`
+	StartedData           *StartedData    `json:"startedData,omitempty" dynamodbav:"startedData,omitempty"`
+	StatusHistory         []StatusChange  `json:"statusHistory,omitempty" dynamodbav:"statusHistory,omitempty"`
+	ScheduledStart        *time.Time      `json:"scheduledStart,omitempty" dynamodbav:"scheduledStart,omitempty"`
+	ScheduledEnd          *time.Time      `json:"scheduledEnd,omitempty" dynamodbav:"scheduledEnd,omitempty"`
+	ArrivalWindow         *ArrivalWindow  `json:"arrivalWindow,omitempty" dynamodbav:"arrivalWindow,omitempty"`
+	EstimatedDuration     int             `json:"estimatedDuration,omitempty" dynamodbav:"estimatedDuration,omitempty"` // Minutes
+	Timezone              string          `json:"timezone,omitempty" dynamodbav:"timezone,omitempty"`                   // Timezone the schedule was entered in
+	RecurringJobID        string          `json:"recurringJobID,omitempty" dynamodbav:"recurringJobID,omitempty"`       // Template the job was materialised from
+	OccurrenceDate        string          `json:"occurrenceDate,omitempty" dynamodbav:"occurrenceDate,omitempty"`       // YYYY-MM-DD in the template's timezone
+	UsersAssignedToJob    []string        `json:"usersAssignedToJob" dynamodbav:"usersAssignedToJob"`
+	VehiclesAssignedToJob []string        `json:"vehiclesAssignedToJob" dynamodbav:"vehiclesAssignedToJob"`
+	Checklist             []ChecklistItem `json:"checklist,omitempty" dynamodbav:"checklist,omitempty"` // Copied from the matching checklist templates on creation
+	UpdatedAt             time.Time       `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy             string          `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	PurgeAt               int64           `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+
+	// Site is where the job is carried out. StartCheck and CompletionCheck
+	// compare it with the technician's location on start and completion.
+	Site             *JobSite          `json:"site,omitempty" dynamodbav:"site,omitempty"`
+	StartCheck       *GeofenceCheck    `json:"startCheck,omitempty" dynamodbav:"startCheck,omitempty"`
+	CompletionCheck  *GeofenceCheck    `json:"completionCheck,omitempty" dynamodbav:"completionCheck,omitempty"`
+	GeofenceOverride *GeofenceOverride `json:"geofenceOverride,omitempty" dynamodbav:"geofenceOverride,omitempty"` // Granted for the next start, cleared once used
+
+	// Priority sets the SLA target of the job. SLA tracks the job against it
+	// and is unset when no SLA policy of the organization applies.
+	Priority JobPriority `json:"priority,omitempty" dynamodbav:"priority,omitempty"`
+	SLA      *JobSLA     `json:"sla,omitempty" dynamodbav:"sla,omitempty"`
+
+	// JobTemplateID and ClonedFromJobID record what the job was created from
+	JobTemplateID   string `json:"jobTemplateID,omitempty" dynamodbav:"jobTemplateID,omitempty"`
+	ClonedFromJobID string `json:"clonedFromJobID,omitempty" dynamodbav:"clonedFromJobID,omitempty"`
+
+	// ProjectID is the project the job belongs to. BlockedBy lists the jobs
+	// that must be completed before the job can start and Blocks the jobs
+	// waiting for it in turn.
+	ProjectID string   `json:"projectID,omitempty" dynamodbav:"projectID,omitempty"`
+	BlockedBy []string `json:"blockedBy,omitempty" dynamodbav:"blockedBy,omitempty"`
+	Blocks    []string `json:"blocks,omitempty" dynamodbav:"blocks,omitempty"`
+
+	// SignOff is the customer's acceptance of the completed job. It is
+	// recorded on completion and never changed afterwards.
+	SignOff *CompletionSignOff `json:"signOff,omitempty" dynamodbav:"signOff,omitempty"`
+
+	// AssignmentWarnings lists the conflicts a forced assignment overrode.
+	// They are returned with the job but not stored.
+	AssignmentWarnings []AssignmentConflict `json:"assignmentWarnings,omitempty" dynamodbav:"-"`
+}
+
+// SortableTimeLayout formats times so that their strings sort in time order:
+// always in UTC and always with nine fractional digits. time.RFC3339Nano
+// trims trailing zeros and keeps the offset, so its strings do not.
+const SortableTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// SortableTime formats t in SortableTimeLayout
+func SortableTime(t time.Time) string {
+	return t.UTC().Format(SortableTimeLayout)
+}
+
+// MarshalDynamoDBAttributeValue stores the job with createdAt in
+// SortableTimeLayout, as it is the range key of orgID-createdAt-index.
+// Reading it back needs no counterpart since the layout is valid RFC 3339.
+func (j Job) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	// storedJob drops the methods of Job so marshalling does not recurse
+	type storedJob Job
+	av, err := attributevalue.Marshal(storedJob(j))
+	if err != nil {
+		return nil, err
+	}
+	if item, ok := av.(*types.AttributeValueMemberM); ok {
+		item.Value["createdAt"] = &types.AttributeValueMemberS{Value: SortableTime(j.CreatedAt)}
+	}
+	return av, nil
+}
+
+// OpenRequiredChecklistItems returns the required checklist items that are
+// not completed yet
+func (j *Job) OpenRequiredChecklistItems() []ChecklistItem {
+	var open []ChecklistItem
+	for _, item := range j.Checklist {
+		if item.Required && !item.Completed {
+			open = append(open, item)
+		}
+	}
+	return open
 }
 
 type CreateJobRequest struct {
-	ClientID              string      `json:"clientID" validate:"required"`
-	JobsName              string      `json:"jobsName" validate:"required,min=2,max=200"`
-	JobType               JobType     `json:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
-	Notes                 string      `json:"notes,omitempty" validate:"omitempty,max=1000"`
-	OrgID                 string      `json:"orgID" validate:"required"`
-	UsersAssignedToJob    []string    `json:"usersAssignedToJob,omitempty"`
-	VehiclesAssignedToJob []string    `json:"vehiclesAssignedToJob,omitempty"`
+	ClientID              string       `json:"clientID" validate:"required"`
+	JobsName              string       `json:"jobsName" validate:"required,min=2,max=200"`
+	JobType               JobType      `json:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
+	Notes                 string       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	OrgID                 string       `json:"orgID" validate:"required"`
+	UsersAssignedToJob    []string     `json:"usersAssignedToJob,omitempty"`
+	VehiclesAssignedToJob []string     `json:"vehiclesAssignedToJob,omitempty"`
 	QBInfoOnJob           *QBInfoOnJob `json:"qbInfoOnJob,omitempty"`
+
+	// Force saves the job despite scheduling conflicts of its assignees
+	Force bool `json:"force,omitempty"`
+
+	// Site is where the job is carried out
+	Site *JobSiteInput `json:"site,omitempty"`
+
+	// Priority sets the SLA target of the job, normal when unset
+	Priority JobPriority `json:"priority,omitempty" validate:"omitempty,oneof=low normal high emergency"`
+
+	// ProjectID adds the job to a project of its organization
+	ProjectID string `json:"projectID,omitempty"`
+
+	JobScheduleInput
 }
 
 type UpdateJobRequest struct {
-	JobsName              string      `json:"jobsName,omitempty" validate:"omitempty,min=2,max=200"`
-	JobStatus             JobStatus   `json:"jobStatus,omitempty" validate:"omitempty,oneof=pending active in_progress completed cancelled on_hold"`
-	JobType               JobType     `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
-	Notes                 string      `json:"notes,omitempty" validate:"omitempty,max=1000"`
-	UsersAssignedToJob    []string    `json:"usersAssignedToJob,omitempty"`
-	VehiclesAssignedToJob []string    `json:"vehiclesAssignedToJob,omitempty"`
+	JobsName              string       `json:"jobsName,omitempty" validate:"omitempty,min=2,max=200"`
+	JobStatus             JobStatus    `json:"jobStatus,omitempty" validate:"omitempty,oneof=pending active in_progress completed cancelled on_hold"`
+	JobType               JobType      `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Notes                 string       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	UsersAssignedToJob    []string     `json:"usersAssignedToJob,omitempty"`
+	VehiclesAssignedToJob []string     `json:"vehiclesAssignedToJob,omitempty"`
 	QBInfoOnJob           *QBInfoOnJob `json:"qbInfoOnJob,omitempty"`
-	JobImagesAfterService []string    `json:"jobImagesAfterService,omitempty"`
+	ClearQBInfoOnJob      bool         `json:"clearQBInfoOnJob,omitempty"`
+	JobImagesAfterService []string     `json:"jobImagesAfterService,omitempty"`
+
+	// StatusReason explains a change of JobStatus to on_hold or cancelled
+	StatusReason string `json:"statusReason,omitempty" validate:"omitempty,max=500"`
+
+	// Force saves the job despite scheduling conflicts of its assignees
+	Force bool `json:"force,omitempty"`
+
+	// Site replaces the site of the job
+	Site      *JobSiteInput `json:"site,omitempty"`
+	ClearSite bool          `json:"clearSite,omitempty"`
+
+	// Priority changes the priority of the job. Its SLA due dates are
+	// recalculated from the creation of the job.
+	Priority JobPriority `json:"priority,omitempty" validate:"omitempty,oneof=low normal high emergency"`
+
+	// ProjectID moves the job to another project, ClearProject takes it out
+	// of its project
+	ProjectID    string `json:"projectID,omitempty"`
+	ClearProject bool   `json:"clearProject,omitempty"`
+
+	JobScheduleInput
+	ClearSchedule bool `json:"clearSchedule,omitempty"`
+}
+
+// JobScheduleInput carries the schedule of a job in create and update
+// requests. Times are RFC 3339, or local times such as 2025-03-14T09:00 that
+// are read in the organization's timezone. A missing scheduledEnd is derived
+// from scheduledStart and estimatedDuration.
+type JobScheduleInput struct {
+	ScheduledStart    string              `json:"scheduledStart,omitempty"`
+	ScheduledEnd      string              `json:"scheduledEnd,omitempty"`
+	ArrivalWindow     *ArrivalWindowInput `json:"arrivalWindow,omitempty"`
+	EstimatedDuration int                 `json:"estimatedDuration,omitempty" validate:"omitempty,min=1,max=10080"` // Minutes
+}
+
+// ArrivalWindowInput is the arrival window of a JobScheduleInput
+type ArrivalWindowInput struct {
+	Start string `json:"start" validate:"required"`
+	End   string `json:"end" validate:"required"`
+}
+
+// IsEmpty reports whether the input sets no schedule field
+func (in JobScheduleInput) IsEmpty() bool {
+	return in.ScheduledStart == "" && in.ScheduledEnd == "" && in.ArrivalWindow == nil && in.EstimatedDuration == 0
+}
+
+// JobStatusRequest carries the reason for holding, resuming or cancelling a
+// job
+type JobStatusRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=500"`
 }
 
 type JobFilter struct {
@@ -107,4 +308,33 @@ type JobFilter struct {
 	CreatedBy string    `json:"createdBy,omitempty"`
 	FromDate  time.Time `json:"fromDate,omitempty"`
 	ToDate    time.Time `json:"toDate,omitempty"`
-}
\ No newline at end of file
+
+	// IncludeDeleted returns soft-deleted jobs as well
+	IncludeDeleted bool `json:"includeDeleted,omitempty"`
+
+	Priority  JobPriority `json:"priority,omitempty"`
+	SLAStatus SLAStatus   `json:"slaStatus,omitempty"`
+}
+
+// JobCalendar lists the scheduled jobs of an organization between From and
+// To, grouped by technician and by day in the organization's timezone
+type JobCalendar struct {
+	OrgID       string               `json:"orgID"`
+	Timezone    string               `json:"timezone"`
+	From        time.Time            `json:"from"`
+	To          time.Time            `json:"to"`
+	Technicians []TechnicianSchedule `json:"technicians"`
+}
+
+// TechnicianSchedule holds the jobs of one technician by day. Jobs without
+// an assignee are listed under an empty UserID.
+type TechnicianSchedule struct {
+	UserID string        `json:"userID"`
+	Days   []CalendarDay `json:"days"`
+}
+
+// CalendarDay holds the jobs scheduled to start on Date, ordered by start
+type CalendarDay struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Jobs []*Job `json:"jobs"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+type JobImportStatus string
+
+const (
+	JobImportStatusRunning   JobImportStatus = "running"
+	JobImportStatusCompleted JobImportStatus = "completed" // Every row was processed, some may have failed
+	JobImportStatusFailed    JobImportStatus = "failed"    // The import stopped before the last row
+)
+
+// Job fields an import can read from a CSV column. List fields hold IDs
+// separated by semicolons; times are RFC 3339 or local times in the
+// organization's timezone.
+const (
+	JobImportFieldClientID              = "clientID"
+	JobImportFieldJobsName              = "jobsName"
+	JobImportFieldJobType               = "jobType"
+	JobImportFieldJobStatus             = "jobStatus" // pending, completed or cancelled; pending when empty
+	JobImportFieldNotes                 = "notes"
+	JobImportFieldPriority              = "priority"
+	JobImportFieldUsersAssignedToJob    = "usersAssignedToJob"
+	JobImportFieldVehiclesAssignedToJob = "vehiclesAssignedToJob"
+	JobImportFieldScheduledStart        = "scheduledStart"
+	JobImportFieldScheduledEnd          = "scheduledEnd"
+	JobImportFieldEstimatedDuration     = "estimatedDuration" // Minutes
+	JobImportFieldSiteAddress           = "siteAddress"
+	JobImportFieldQBCustomerID          = "qbCustomerID"
+	JobImportFieldQBLineItemID          = "qbLineItemID"
+)
+
+// JobImportFields lists the fields an import can read, in the order jobs
+// are exported in
+var JobImportFields = []string{
+	JobImportFieldClientID,
+	JobImportFieldJobsName,
+	JobImportFieldJobType,
+	JobImportFieldJobStatus,
+	JobImportFieldNotes,
+	JobImportFieldPriority,
+	JobImportFieldUsersAssignedToJob,
+	JobImportFieldVehiclesAssignedToJob,
+	JobImportFieldScheduledStart,
+	JobImportFieldScheduledEnd,
+	JobImportFieldEstimatedDuration,
+	JobImportFieldSiteAddress,
+	JobImportFieldQBCustomerID,
+	JobImportFieldQBLineItemID,
+}
+
+// JobImportMapping tells an import where to find the fields of a job in a
+// CSV file. Columns maps a field to the header of its column; fields not in
+// Columns are read from the column headed like the field, so files exported
+// by GET /jobs/export import without a mapping. Defaults holds the values of
+// fields whose column is missing or empty.
+type JobImportMapping struct {
+	Columns  map[string]string `json:"columns,omitempty" example:"jobsName:Job Title,clientID:Customer"`
+	Defaults map[string]string `json:"defaults,omitempty" example:"jobType:maintenance"`
+}
+
+// JobImportRowError is a problem with one row of an import. Row counts the
+// lines of the file, so the header is row 1.
+type JobImportRowError struct {
+	Row     int    `json:"row" dynamodbav:"row"`
+	Field   string `json:"field,omitempty" dynamodbav:"field,omitempty"`
+	Message string `json:"message" dynamodbav:"message"`
+}
+
+// JobImport tracks an import running in the background
+type JobImport struct {
+	ImportID      string              `json:"importID" dynamodbav:"importID"`
+	OrgID         string              `json:"orgID" dynamodbav:"orgID"`
+	FileName      string              `json:"fileName,omitempty" dynamodbav:"fileName,omitempty"`
+	Status        JobImportStatus     `json:"status" dynamodbav:"status"`
+	TotalRows     int                 `json:"totalRows" dynamodbav:"totalRows"`
+	ProcessedRows int                 `json:"processedRows" dynamodbav:"processedRows"`
+	CreatedJobs   int                 `json:"createdJobs" dynamodbav:"createdJobs"`
+	FailedRows    int                 `json:"failedRows" dynamodbav:"failedRows"`
+	Errors        []JobImportRowError `json:"errors,omitempty" dynamodbav:"errors,omitempty"` // The first failures only
+	Error         string              `json:"error,omitempty" dynamodbav:"error,omitempty"`   // Why a failed import stopped
+	CreatedAt     time.Time           `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData   CreatedData         `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt     time.Time           `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	CompletedAt   *time.Time          `json:"completedAt,omitempty" dynamodbav:"completedAt,omitempty"`
+}
+
+// JobImportResult is the outcome of checking an import. Import is set once
+// the jobs of a valid, non dry-run import are being created.
+type JobImportResult struct {
+	DryRun    bool                `json:"dryRun"`
+	TotalRows int                 `json:"totalRows"`
+	ValidRows int                 `json:"validRows"`
+	Errors    []JobImportRowError `json:"errors"`
+	Import    *JobImport          `json:"import,omitempty"`
+}
+
+type JobExportFormat string
+
+const (
+	JobExportFormatCSV  JobExportFormat = "csv"
+	JobExportFormatXLSX JobExportFormat = "xlsx"
+)
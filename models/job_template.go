@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// JobTemplate is a job an organization creates over and over. Jobs created
+// from it copy its fields, which the request may override, and get its
+// checklist after the items of the matching checklist templates.
+type JobTemplate struct {
+	TemplateID            string                  `json:"templateID" dynamodbav:"templateID"`
+	OrgID                 string                  `json:"orgID" dynamodbav:"orgID"`
+	Name                  string                  `json:"name" dynamodbav:"name"`
+	JobsName              string                  `json:"jobsName" dynamodbav:"jobsName"`
+	JobType               JobType                 `json:"jobType" dynamodbav:"jobType"`
+	Notes                 string                  `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+	Priority              JobPriority             `json:"priority,omitempty" dynamodbav:"priority,omitempty"`
+	EstimatedDuration     int                     `json:"estimatedDuration,omitempty" dynamodbav:"estimatedDuration,omitempty"` // Minutes
+	VehiclesAssignedToJob []string                `json:"vehiclesAssignedToJob" dynamodbav:"vehiclesAssignedToJob"`
+	QBInfoOnJob           *QBInfoOnJob            `json:"qbInfoOnJob,omitempty" dynamodbav:"qbInfoOnJob,omitempty"`
+	Checklist             []ChecklistTemplateItem `json:"checklist,omitempty" dynamodbav:"checklist,omitempty"`
+	CreatedAt             time.Time               `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData           CreatedData             `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt             time.Time               `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy             string                  `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	DeletedData           *DeletedData            `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt               int64                   `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+}
+
+// CreateJobTemplateRequest creates a job template. Name identifies the
+// template, JobsName is the name of the jobs created from it.
+type CreateJobTemplateRequest struct {
+	OrgID                 string                       `json:"orgID" validate:"required"`
+	Name                  string                       `json:"name" validate:"required,min=2,max=200"`
+	JobsName              string                       `json:"jobsName" validate:"required,min=2,max=200"`
+	JobType               JobType                      `json:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
+	Notes                 string                       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Priority              JobPriority                  `json:"priority,omitempty" validate:"omitempty,oneof=low normal high emergency"`
+	EstimatedDuration     int                          `json:"estimatedDuration,omitempty" validate:"omitempty,min=1,max=10080"`
+	VehiclesAssignedToJob []string                     `json:"vehiclesAssignedToJob,omitempty"`
+	QBInfoOnJob           *QBInfoOnJob                 `json:"qbInfoOnJob,omitempty"`
+	Checklist             []ChecklistTemplateItemInput `json:"checklist,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// UpdateJobTemplateRequest changes a job template. VehiclesAssignedToJob and
+// Checklist, when given, replace the stored ones. Jobs created before keep
+// their fields.
+type UpdateJobTemplateRequest struct {
+	Name                  string                       `json:"name,omitempty" validate:"omitempty,min=2,max=200"`
+	JobsName              string                       `json:"jobsName,omitempty" validate:"omitempty,min=2,max=200"`
+	JobType               JobType                      `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Notes                 string                       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Priority              JobPriority                  `json:"priority,omitempty" validate:"omitempty,oneof=low normal high emergency"`
+	EstimatedDuration     int                          `json:"estimatedDuration,omitempty" validate:"omitempty,min=1,max=10080"`
+	VehiclesAssignedToJob []string                     `json:"vehiclesAssignedToJob,omitempty"`
+	QBInfoOnJob           *QBInfoOnJob                 `json:"qbInfoOnJob,omitempty"`
+	ClearQBInfoOnJob      bool                         `json:"clearQBInfoOnJob,omitempty"`
+	Checklist             []ChecklistTemplateItemInput `json:"checklist,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// JobOverrides replaces fields a job would otherwise copy from a template or
+// from the job it is cloned from. Unset fields are copied.
+type JobOverrides struct {
+	JobsName              string       `json:"jobsName,omitempty" validate:"omitempty,min=2,max=200"`
+	JobType               JobType      `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Notes                 string       `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Priority              JobPriority  `json:"priority,omitempty" validate:"omitempty,oneof=low normal high emergency"`
+	UsersAssignedToJob    []string     `json:"usersAssignedToJob,omitempty"`
+	VehiclesAssignedToJob []string     `json:"vehiclesAssignedToJob,omitempty"`
+	QBInfoOnJob           *QBInfoOnJob `json:"qbInfoOnJob,omitempty"`
+
+	// Force saves the job despite scheduling conflicts of its assignees
+	Force bool `json:"force,omitempty"`
+
+	// Site is where the job is carried out
+	Site *JobSiteInput `json:"site,omitempty"`
+
+	JobScheduleInput
+}
+
+// CreateJobFromTemplateRequest creates a job for ClientID from a job template
+type CreateJobFromTemplateRequest struct {
+	ClientID string `json:"clientID" validate:"required"`
+
+	JobOverrides
+}
+
+// CloneJobRequest creates a pending job from a completed one. The clone is
+// for the same client unless ClientID is given and is unscheduled unless a
+// schedule is given.
+type CloneJobRequest struct {
+	ClientID string `json:"clientID,omitempty"`
+
+	JobOverrides
+}
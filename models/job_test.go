@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestJobStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from JobStatus
+		to   JobStatus
+		want bool
+	}{
+		{JobStatusPending, JobStatusActive, true},
+		{JobStatusPending, JobStatusInProgress, true},
+		{JobStatusPending, JobStatusCompleted, false},
+		{JobStatusActive, JobStatusPending, false},
+		{JobStatusInProgress, JobStatusCompleted, true},
+		{JobStatusInProgress, JobStatusPending, false},
+		{JobStatusInProgress, JobStatusOnHold, true},
+		{JobStatusOnHold, JobStatusInProgress, true},
+		{JobStatusOnHold, JobStatusCompleted, false},
+		{JobStatusCompleted, JobStatusInProgress, false},
+		{JobStatusCompleted, JobStatusCancelled, false},
+		{JobStatusCancelled, JobStatusPending, false},
+		{JobStatusPending, JobStatusPending, false},
+		{JobStatus("unknown"), JobStatusActive, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// TestJobStoredCreatedAtSorts stores jobs whose createdAt strings would sort
+// out of time order in RFC 3339: a trimmed fraction sorts after a longer one
+// and an offset after UTC
+func TestJobStoredCreatedAtSorts(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	times := []time.Time{
+		time.Date(2026, 3, 1, 9, 30, 0, 0, berlin),
+		time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 1, 9, 0, 0, 500000000, time.UTC),
+		time.Date(2026, 3, 1, 9, 0, 1, 0, time.UTC),
+	}
+
+	var previous string
+	for i, createdAt := range times {
+		item, err := attributevalue.MarshalMap(&Job{JobID: "job-1", CreatedAt: createdAt})
+		if err != nil {
+			t.Fatalf("MarshalMap() error = %v", err)
+		}
+		stored, ok := item["createdAt"].(*types.AttributeValueMemberS)
+		if !ok {
+			t.Fatalf("createdAt stored as %T, want a string", item["createdAt"])
+		}
+		if len(stored.Value) != len(SortableTimeLayout) {
+			t.Errorf("createdAt %q is not in %s", stored.Value, SortableTimeLayout)
+		}
+		if i > 0 && stored.Value <= previous {
+			t.Errorf("createdAt %q sorts before %q", stored.Value, previous)
+		}
+		previous = stored.Value
+
+		var job Job
+		if err := attributevalue.UnmarshalMap(item, &job); err != nil {
+			t.Fatalf("UnmarshalMap() error = %v", err)
+		}
+		if !job.CreatedAt.Equal(createdAt) || job.JobID != "job-1" {
+			t.Errorf("round trip = %s %v, want job-1 %v", job.JobID, job.CreatedAt, createdAt)
+		}
+	}
+}
@@ -29,4 +29,23 @@ type Organization struct {
 	Country     string             `json:"country,omitempty" dynamodbav:"country,omitempty" validate:"omitempty,min=2,max=50"`
 	PostalCode  string             `json:"postal_code,omitempty" dynamodbav:"postal_code,omitempty" validate:"omitempty,min=3,max=20"` // Business details
 	Industry    string             `json:"industry,omitempty" dynamodbav:"industry,omitempty" validate:"omitempty,min=2,max=50"`
+	Timezone    string             `json:"timezone,omitempty" dynamodbav:"timezone,omitempty" validate:"omitempty,timezone"` // IANA name, UTC when unset
+	DeletedData *DeletedData       `json:"deleted_data,omitempty" dynamodbav:"deletedData,omitempty" validate:"omitempty"`
+	PurgeAt     int64              `json:"-" dynamodbav:"purgeAt,omitempty" validate:"omitempty"` // TTL (epoch seconds) set on soft delete
+
+	// Geofence checks on job start and completion
+	GeofencePolicy  string `json:"geofence_policy,omitempty" dynamodbav:"geofence_policy,omitempty" validate:"omitempty,oneof=flag override"`  // flag when unset
+	GeofenceRadiusM int    `json:"geofence_radius_m,omitempty" dynamodbav:"geofence_radius_m,omitempty" validate:"omitempty,min=10,max=10000"` // Configured default when unset
+}
+
+// Location returns the organization's timezone, or UTC when it has none
+func (o *Organization) Location() *time.Location {
+	if o.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(o.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
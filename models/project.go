@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// ProjectStatus is rolled up from the statuses of a project's jobs
+type ProjectStatus string
+
+const (
+	ProjectStatusPlanned    ProjectStatus = "planned"     // No job has moved past pending yet
+	ProjectStatusInProgress ProjectStatus = "in_progress" // Some jobs are underway or done, others open
+	ProjectStatusCompleted  ProjectStatus = "completed"   // Every job is closed and at least one completed
+	ProjectStatusCancelled  ProjectStatus = "cancelled"   // Every job was cancelled
+)
+
+// Project groups the jobs of a multi-visit piece of work, such as a site
+// survey, the installation and its inspection. Jobs join a project through
+// their ProjectID.
+type Project struct {
+	ProjectID   string       `json:"projectID" dynamodbav:"projectID"`
+	OrgID       string       `json:"orgID" dynamodbav:"orgID"`
+	ClientID    string       `json:"clientID,omitempty" dynamodbav:"clientID,omitempty"`
+	Name        string       `json:"name" dynamodbav:"name"`
+	Description string       `json:"description,omitempty" dynamodbav:"description,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData CreatedData  `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt   time.Time    `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy   string       `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	DeletedData *DeletedData `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt     int64        `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+
+	// Progress and Jobs are rolled up from the project's jobs when it is
+	// read. They are returned with the project but not stored.
+	Progress *ProjectProgress `json:"progress,omitempty" dynamodbav:"-"`
+	Jobs     []*Job           `json:"jobs,omitempty" dynamodbav:"-"`
+}
+
+// ProjectProgress sums up the jobs of a project. PercentComplete is the
+// share of completed jobs among those not cancelled.
+type ProjectProgress struct {
+	Status          ProjectStatus     `json:"status"`
+	TotalJobs       int               `json:"totalJobs"`
+	CompletedJobs   int               `json:"completedJobs"`
+	CancelledJobs   int               `json:"cancelledJobs"`
+	JobsByStatus    map[JobStatus]int `json:"jobsByStatus"`
+	PercentComplete int               `json:"percentComplete"`
+}
+
+type CreateProjectRequest struct {
+	OrgID       string `json:"orgID" validate:"required"`
+	ClientID    string `json:"clientID,omitempty"`
+	Name        string `json:"name" validate:"required,min=2,max=200"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+type UpdateProjectRequest struct {
+	ClientID    string `json:"clientID,omitempty"`
+	Name        string `json:"name,omitempty" validate:"omitempty,min=2,max=200"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+// JobDependencyRequest makes a job wait for BlockedBy, another job of its
+// organization, to be completed before it can start
+type JobDependencyRequest struct {
+	BlockedBy string `json:"blockedBy" validate:"required"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+type RecurringJobStatus string
+
+const (
+	RecurringJobStatusActive RecurringJobStatus = "active"
+	RecurringJobStatusPaused RecurringJobStatus = "paused"
+)
+
+// RecurringJob is the template of a recurring job or maintenance plan. Job
+// instances are materialised from it a configured number of days ahead.
+type RecurringJob struct {
+	RecurringJobID        string             `json:"recurringJobID" dynamodbav:"recurringJobID"`
+	OrgID                 string             `json:"orgID" dynamodbav:"orgID"`
+	ClientID              string             `json:"clientID" dynamodbav:"clientID"`
+	JobsName              string             `json:"jobsName" dynamodbav:"jobsName"`
+	JobType               JobType            `json:"jobType" dynamodbav:"jobType"`
+	Notes                 string             `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+	UsersAssignedToJob    []string           `json:"usersAssignedToJob" dynamodbav:"usersAssignedToJob"`
+	VehiclesAssignedToJob []string           `json:"vehiclesAssignedToJob" dynamodbav:"vehiclesAssignedToJob"`
+	EstimatedDuration     int                `json:"estimatedDuration,omitempty" dynamodbav:"estimatedDuration,omitempty"` // Minutes
+	RRule                 string             `json:"rrule" dynamodbav:"rrule"`                                             // RFC 5545 recurrence rule
+	StartAt               time.Time          `json:"startAt" dynamodbav:"startAt"`                                         // First possible occurrence
+	Timezone              string             `json:"timezone" dynamodbav:"timezone"`                                       // Timezone occurrences are computed in
+	ExceptionDates        []string           `json:"exceptionDates,omitempty" dynamodbav:"exceptionDates,omitempty"`       // YYYY-MM-DD dates that are skipped
+	Status                RecurringJobStatus `json:"status" dynamodbav:"status"`
+	Revision              int                `json:"revision" dynamodbav:"revision"`                                           // Bumped whenever the schedule changes
+	MaterializedThrough   *time.Time         `json:"materializedThrough,omitempty" dynamodbav:"materializedThrough,omitempty"` // End of the period instances exist for
+	CreatedAt             time.Time          `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData           CreatedData        `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt             time.Time          `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy             string             `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	DeletedData           *DeletedData       `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt               int64              `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+}
+
+// Location returns the timezone occurrences are computed in, falling back
+// to UTC
+func (r *RecurringJob) Location() *time.Location {
+	if r.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsException reports whether the occurrence on date is skipped
+func (r *RecurringJob) IsException(date string) bool {
+	for _, exception := range r.ExceptionDates {
+		if exception == date {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateRecurringJobRequest creates a recurring job. StartAt is RFC 3339 or a
+// local time in the organization's timezone and sets the time of day of
+// every occurrence. End conditions are given in the rule as COUNT or UNTIL.
+type CreateRecurringJobRequest struct {
+	ClientID              string   `json:"clientID" validate:"required"`
+	JobsName              string   `json:"jobsName" validate:"required,min=2,max=200"`
+	JobType               JobType  `json:"jobType" validate:"required,oneof=service maintenance installation repair inspection"`
+	Notes                 string   `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	OrgID                 string   `json:"orgID" validate:"required"`
+	UsersAssignedToJob    []string `json:"usersAssignedToJob,omitempty"`
+	VehiclesAssignedToJob []string `json:"vehiclesAssignedToJob,omitempty"`
+	EstimatedDuration     int      `json:"estimatedDuration,omitempty" validate:"omitempty,min=1,max=10080"`
+	RRule                 string   `json:"rrule" validate:"required" example:"FREQ=MONTHLY;BYMONTHDAY=1;COUNT=12"`
+	StartAt               string   `json:"startAt" validate:"required" example:"2025-01-01T09:00"`
+	ExceptionDates        []string `json:"exceptionDates,omitempty" validate:"omitempty,dive,datetime=2006-01-02"`
+}
+
+// UpdateRecurringJobRequest changes a recurring job. ExceptionDates, when
+// given, replaces the skipped dates. ApplyToFuture also brings the pending
+// instances scheduled after now in line with the template; otherwise only
+// instances materialised from now on are affected.
+type UpdateRecurringJobRequest struct {
+	JobsName              string             `json:"jobsName,omitempty" validate:"omitempty,min=2,max=200"`
+	JobType               JobType            `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Notes                 string             `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	UsersAssignedToJob    []string           `json:"usersAssignedToJob,omitempty"`
+	VehiclesAssignedToJob []string           `json:"vehiclesAssignedToJob,omitempty"`
+	EstimatedDuration     int                `json:"estimatedDuration,omitempty" validate:"omitempty,min=1,max=10080"`
+	RRule                 string             `json:"rrule,omitempty"`
+	StartAt               string             `json:"startAt,omitempty"`
+	ExceptionDates        []string           `json:"exceptionDates,omitempty" validate:"omitempty,dive,datetime=2006-01-02"`
+	Status                RecurringJobStatus `json:"status,omitempty" validate:"omitempty,oneof=active paused"`
+	ApplyToFuture         bool               `json:"applyToFuture,omitempty"`
+}
+
+// RecurringJobOccurrence is one computed occurrence of a recurring job
+type RecurringJobOccurrence struct {
+	Date           string    `json:"date"` // YYYY-MM-DD in the template's timezone
+	ScheduledStart time.Time `json:"scheduledStart"`
+	Skipped        bool      `json:"skipped,omitempty"`   // An exception date
+	JobID          string    `json:"jobID,omitempty"`     // Materialised instance, if any
+	JobStatus      JobStatus `json:"jobStatus,omitempty"` // Status of the instance
+}
@@ -11,6 +11,8 @@ type RoleAssignment struct {
 	Context     map[string]string `json:"context,omitempty" dynamodbav:"context,omitempty"`
 	AssignedAt  time.Time         `json:"assigned_at,omitempty" dynamodbav:"assigned_at" validate:"omitempty"`
 	ExpiresAt   *time.Time        `json:"expires_at,omitempty" dynamodbav:"expires_at,omitempty" validate:"omitempty"`
+	DeletedData *DeletedData      `json:"deleted_data,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt     int64             `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
 }
 
 // RoleStatus represents the status of a role
@@ -35,6 +37,8 @@ type Role struct {
 	CreatedBy   string                 `json:"created_by,omitempty" dynamodbav:"created_by" validate:"omitempty"`
 	UpdatedBy   string                 `json:"updated_by,omitempty" dynamodbav:"updated_by" validate:"omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
+	DeletedData *DeletedData           `json:"deleted_data,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt     int64                  `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
 }
 
 // UpdateRoleRequest represents the request structure for updating a role
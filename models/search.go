@@ -0,0 +1,56 @@
+package models
+
+// JobSearchDate names a period relative to today, in the organization's
+// timezone, that search results are counted and filtered by. Jobs fall into
+// periods by their scheduled start or, when unscheduled, their creation.
+type JobSearchDate string
+
+const (
+	JobSearchDateEarlier   JobSearchDate = "earlier"     // Before the last 7 days
+	JobSearchDateLast7Days JobSearchDate = "last_7_days" // The 7 days before today
+	JobSearchDateToday     JobSearchDate = "today"
+	JobSearchDateNext7Days JobSearchDate = "next_7_days" // The 7 days after today
+	JobSearchDateLater     JobSearchDate = "later"       // After the next 7 days
+)
+
+// JobSearchRequest searches the jobs of an organization by name, notes,
+// client and address. Jobs must match one of the values given for each of
+// Statuses, Types and Assignees, and fall within Date when it is set.
+type JobSearchRequest struct {
+	OrgID     string
+	Query     string
+	Statuses  []JobStatus
+	Types     []JobType
+	Assignees []string
+	Date      JobSearchDate
+	Page      int
+	Limit     int
+}
+
+// JobSearchFacet is the number of matching jobs having a value
+type JobSearchFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// JobSearchFacets counts the jobs matching a search, before pagination, by
+// status, type, assignee and date period
+type JobSearchFacets struct {
+	Status   []JobSearchFacet `json:"status"`
+	Type     []JobSearchFacet `json:"type"`
+	Assignee []JobSearchFacet `json:"assignee"`
+	Date     []JobSearchFacet `json:"date"`
+}
+
+// JobSearchResult is a page of the jobs matching a search, best matches
+// first. Total and the facets are counted by the search index, so until it
+// syncs they may include jobs that were just deleted.
+type JobSearchResult struct {
+	Query      string          `json:"query"`
+	Jobs       []*Job          `json:"jobs"`
+	Facets     JobSearchFacets `json:"facets"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	Total      int             `json:"total"`
+	TotalPages int             `json:"totalPages"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CompletionSignOff records that a customer accepted a completed job: who
+// signed, when, and the signature they gave. The signature and the
+// completion summary are sign-off attachments of the job, which cannot be
+// deleted; SignatureSHA256 proves the stored signature unchanged.
+type CompletionSignOff struct {
+	SignerName            string    `json:"signerName" dynamodbav:"signerName"`
+	SignerEmail           string    `json:"signerEmail,omitempty" dynamodbav:"signerEmail,omitempty"`
+	SignedAt              time.Time `json:"signedAt" dynamodbav:"signedAt"`
+	SignatureAttachmentID string    `json:"signatureAttachmentID" dynamodbav:"signatureAttachmentID"`
+	SignatureContentType  string    `json:"signatureContentType" dynamodbav:"signatureContentType"` // image/png or image/svg+xml
+	SignatureSHA256       string    `json:"signatureSHA256" dynamodbav:"signatureSHA256"`           // Hex digest of the stored signature
+	Rating                int       `json:"rating,omitempty" dynamodbav:"rating,omitempty"`         // Satisfaction from 1 to 5
+	Feedback              string    `json:"feedback,omitempty" dynamodbav:"feedback,omitempty"`
+	CollectedBy           string    `json:"collectedBy" dynamodbav:"collectedBy"`                 // User who completed the job
+	SummaryAttachmentID   string    `json:"summaryAttachmentID" dynamodbav:"summaryAttachmentID"` // Completion summary PDF
+}
+
+// SignOffInput is the customer sign-off given when completing a job.
+// Signature is a PNG or SVG image, base64 encoded or as a data URL.
+type SignOffInput struct {
+	SignerName  string `json:"signerName" validate:"required,min=2,max=200"`
+	SignerEmail string `json:"signerEmail,omitempty" validate:"omitempty,email,max=254"`
+	Signature   string `json:"signature" validate:"required"`
+	Rating      int    `json:"rating,omitempty" validate:"omitempty,min=1,max=5"`
+	Feedback    string `json:"feedback,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CompleteJobRequest completes a job. The device location is compared with
+// the job site; without it the geofence check is unverified. With SignOff
+// the customer's acceptance is recorded and, when the signer gives an
+// email, the completion summary is sent to them.
+type CompleteJobRequest struct {
+	DeviceLocation
+	SignOff *SignOffInput `json:"signOff,omitempty"`
+}
+
+// SendCompletionSummaryRequest sends the completion summary of a job again,
+// to Email or else to the signer
+type SendCompletionSummaryRequest struct {
+	Email string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// JobPriority is the urgency of a job. Jobs without a priority are normal.
+type JobPriority string
+
+const (
+	JobPriorityLow       JobPriority = "low"
+	JobPriorityNormal    JobPriority = "normal"
+	JobPriorityHigh      JobPriority = "high"
+	JobPriorityEmergency JobPriority = "emergency"
+)
+
+// OrDefault returns p, or normal when p is empty
+func (p JobPriority) OrDefault() JobPriority {
+	if p == "" {
+		return JobPriorityNormal
+	}
+	return p
+}
+
+// SLAStatus is how a job is doing against its SLA targets
+type SLAStatus string
+
+const (
+	SLAStatusOnTrack  SLAStatus = "on_track"
+	SLAStatusAtRisk   SLAStatus = "at_risk"  // A target is due soon
+	SLAStatusBreached SLAStatus = "breached" // A target was missed
+	SLAStatusMet      SLAStatus = "met"      // Completed within every target
+)
+
+// SLAPolicy sets the response and resolution times an organization promises
+// per priority for jobs of JobType, or for every job type without a policy
+// of its own when JobType is empty
+type SLAPolicy struct {
+	PolicyID    string       `json:"policyID" dynamodbav:"policyID"`
+	OrgID       string       `json:"orgID" dynamodbav:"orgID"`
+	JobType     JobType      `json:"jobType,omitempty" dynamodbav:"jobType,omitempty"`
+	Name        string       `json:"name" dynamodbav:"name"`
+	Targets     []SLATarget  `json:"targets" dynamodbav:"targets"`
+	CreatedAt   time.Time    `json:"createdAt" dynamodbav:"createdAt"`
+	CreatedData CreatedData  `json:"createdData" dynamodbav:"createdData"`
+	UpdatedAt   time.Time    `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy   string       `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+	DeletedData *DeletedData `json:"deletedData,omitempty" dynamodbav:"deletedData,omitempty"`
+	PurgeAt     int64        `json:"-" dynamodbav:"purgeAt,omitempty"` // TTL (epoch seconds) set on soft delete
+}
+
+// Target returns the target of the policy for priority, or nil when jobs of
+// that priority have none
+func (p *SLAPolicy) Target(priority JobPriority) *SLATarget {
+	for i := range p.Targets {
+		if p.Targets[i].Priority == priority {
+			return &p.Targets[i]
+		}
+	}
+	return nil
+}
+
+// SLATarget is the time allowed for jobs of one priority. Response is met
+// when the job is started, resolution when it is completed; both count
+// from the creation of the job.
+type SLATarget struct {
+	Priority          JobPriority `json:"priority" dynamodbav:"priority"`
+	ResponseMinutes   int         `json:"responseMinutes" dynamodbav:"responseMinutes"`
+	ResolutionMinutes int         `json:"resolutionMinutes" dynamodbav:"resolutionMinutes"`
+}
+
+// JobSLA tracks a job against the target of the policy it was created
+// under. The clocks keep running while the job is on hold.
+type JobSLA struct {
+	PolicyID           string     `json:"policyID" dynamodbav:"policyID"`
+	Status             SLAStatus  `json:"status" dynamodbav:"status"`
+	StartedAt          time.Time  `json:"startedAt" dynamodbav:"startedAt"` // When the clocks started
+	ResponseDueAt      time.Time  `json:"responseDueAt" dynamodbav:"responseDueAt"`
+	ResolutionDueAt    time.Time  `json:"resolutionDueAt" dynamodbav:"resolutionDueAt"`
+	RespondedAt        *time.Time `json:"respondedAt,omitempty" dynamodbav:"respondedAt,omitempty"`
+	ResolvedAt         *time.Time `json:"resolvedAt,omitempty" dynamodbav:"resolvedAt,omitempty"`
+	ResponseBreached   bool       `json:"responseBreached,omitempty" dynamodbav:"responseBreached,omitempty"`
+	ResolutionBreached bool       `json:"resolutionBreached,omitempty" dynamodbav:"resolutionBreached,omitempty"`
+}
+
+// SLATargetInput is a target of an SLA policy request
+type SLATargetInput struct {
+	Priority          JobPriority `json:"priority" validate:"required,oneof=low normal high emergency"`
+	ResponseMinutes   int         `json:"responseMinutes" validate:"required,min=1,max=525600"`
+	ResolutionMinutes int         `json:"resolutionMinutes" validate:"required,min=1,max=525600"`
+}
+
+// CreateSLAPolicyRequest creates an SLA policy
+type CreateSLAPolicyRequest struct {
+	OrgID   string           `json:"orgID" validate:"required"`
+	JobType JobType          `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	Name    string           `json:"name" validate:"required,min=2,max=200"`
+	Targets []SLATargetInput `json:"targets" validate:"required,min=1,max=4,dive"`
+}
+
+// UpdateSLAPolicyRequest changes an SLA policy. Targets, when given,
+// replaces the targets. Jobs created before keep their due dates until
+// their priority or job type changes.
+type UpdateSLAPolicyRequest struct {
+	JobType    JobType          `json:"jobType,omitempty" validate:"omitempty,oneof=service maintenance installation repair inspection"`
+	AnyJobType bool             `json:"anyJobType,omitempty"` // Applies the policy to every job type
+	Name       string           `json:"name,omitempty" validate:"omitempty,min=2,max=200"`
+	Targets    []SLATargetInput `json:"targets,omitempty" validate:"omitempty,max=4,dive"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ChangeType is the kind of modification a stream record describes
+type ChangeType string
+
+const (
+	ChangeInsert ChangeType = "INSERT"
+	ChangeModify ChangeType = "MODIFY"
+	ChangeRemove ChangeType = "REMOVE"
+)
+
+// ChangeEvent is a decoded DynamoDB Streams record for an item of type T.
+// OldImage is nil for inserts and NewImage is nil for removals.
+type ChangeEvent[T any] struct {
+	EventID        string     `json:"event_id"`
+	Type           ChangeType `json:"type"`
+	Table          string     `json:"table"`
+	SequenceNumber string     `json:"sequence_number"`
+	CreatedAt      time.Time  `json:"created_at"`
+	OldImage       *T         `json:"old_image,omitempty"`
+	NewImage       *T         `json:"new_image,omitempty"`
+}
+
+// Item returns the new image, or the old image for removals
+func (e ChangeEvent[T]) Item() *T {
+	if e.NewImage != nil {
+		return e.NewImage
+	}
+	return e.OldImage
+}
+
+// JobChangeEvent is a change to a job
+type JobChangeEvent = ChangeEvent[Job]
+
+// UserChangeEvent is a change to a user
+type UserChangeEvent = ChangeEvent[User]
+
+// OrganizationChangeEvent is a change to an organization
+type OrganizationChangeEvent = ChangeEvent[Organization]
+
+// StreamCheckpoint records the last stream record processed on a shard
+type StreamCheckpoint struct {
+	ShardKey       string    `json:"shard_key" dynamodbav:"shard_key"`
+	StreamArn      string    `json:"stream_arn" dynamodbav:"stream_arn"`
+	ShardID        string    `json:"shard_id" dynamodbav:"shard_id"`
+	SequenceNumber string    `json:"sequence_number" dynamodbav:"sequence_number"`
+	UpdatedAt      time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
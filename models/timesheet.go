@@ -0,0 +1,122 @@
+package models
+
+import "time"
+
+type TimeEntryCategory string
+
+const (
+	TimeEntryLabor  TimeEntryCategory = "labor"  // Time on site, billed as labour
+	TimeEntryTravel TimeEntryCategory = "travel" // Time on the way to or between sites
+)
+
+// TimeEntryEndReason tells why a time entry was closed
+type TimeEntryEndReason string
+
+const (
+	TimeEntryPaused     TimeEntryEndReason = "pause"
+	TimeEntryClockedOut TimeEntryEndReason = "clock_out"
+	TimeEntrySwitched   TimeEntryEndReason = "switch" // Closed by clocking in to the other category
+)
+
+type TimesheetStatus string
+
+const (
+	TimesheetOpen     TimesheetStatus = "open"
+	TimesheetApproved TimesheetStatus = "approved" // Entries of the week are locked
+)
+
+// TimeEntry is one continuous stretch of time a technician spent on a job.
+// An entry without EndedAt is running; a technician has at most one running
+// entry. Entries belong to the week, in the technician's timezone, they
+// started in.
+type TimeEntry struct {
+	EntryID    string             `json:"entryID" dynamodbav:"entryID"`
+	JobID      string             `json:"jobID" dynamodbav:"jobID"`
+	OrgID      string             `json:"orgID" dynamodbav:"orgID"`
+	UserID     string             `json:"userID" dynamodbav:"userID"`
+	Category   TimeEntryCategory  `json:"category" dynamodbav:"category"`
+	StartedAt  time.Time          `json:"startedAt" dynamodbav:"startedAt"`
+	EndedAt    *time.Time         `json:"endedAt,omitempty" dynamodbav:"endedAt,omitempty"`
+	EndReason  TimeEntryEndReason `json:"endReason,omitempty" dynamodbav:"endReason,omitempty"`
+	Minutes    int                `json:"minutes" dynamodbav:"minutes"`     // Set when the entry ends
+	WeekStart  string             `json:"weekStart" dynamodbav:"weekStart"` // YYYY-MM-DD of the Monday of the entry's week
+	Notes      string             `json:"notes,omitempty" dynamodbav:"notes,omitempty"`
+	OpenUserID string             `json:"-" dynamodbav:"openUserID,omitempty"` // UserID while running, indexes running entries
+	CreatedAt  time.Time          `json:"createdAt" dynamodbav:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt,omitempty" dynamodbav:"updatedAt,omitempty"`
+	UpdatedBy  string             `json:"updatedBy,omitempty" dynamodbav:"updatedBy,omitempty"`
+}
+
+// Running reports whether the entry has not ended yet
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Timesheet is the approval state of a technician's week. Weeks without a
+// stored timesheet are open.
+type Timesheet struct {
+	TimesheetID   string          `json:"-" dynamodbav:"timesheetID"` // UserID#WeekStart
+	UserID        string          `json:"userID" dynamodbav:"userID"`
+	WeekStart     string          `json:"weekStart" dynamodbav:"weekStart"`
+	Status        TimesheetStatus `json:"status" dynamodbav:"status"`
+	LaborMinutes  int             `json:"laborMinutes" dynamodbav:"laborMinutes"` // Totals as approved
+	TravelMinutes int             `json:"travelMinutes" dynamodbav:"travelMinutes"`
+	ApprovedBy    string          `json:"approvedBy,omitempty" dynamodbav:"approvedBy,omitempty"`
+	ApprovedAt    *time.Time      `json:"approvedAt,omitempty" dynamodbav:"approvedAt,omitempty"`
+	ReopenedBy    string          `json:"reopenedBy,omitempty" dynamodbav:"reopenedBy,omitempty"`
+	ReopenedAt    *time.Time      `json:"reopenedAt,omitempty" dynamodbav:"reopenedAt,omitempty"`
+	ReopenReason  string          `json:"reopenReason,omitempty" dynamodbav:"reopenReason,omitempty"`
+}
+
+// TimesheetDay totals the entries started on one day of a timesheet week
+type TimesheetDay struct {
+	Date          string `json:"date"` // YYYY-MM-DD
+	LaborMinutes  int    `json:"laborMinutes"`
+	TravelMinutes int    `json:"travelMinutes"`
+}
+
+// TimesheetJob totals the entries of one job in a timesheet week
+type TimesheetJob struct {
+	JobID         string `json:"jobID"`
+	LaborMinutes  int    `json:"laborMinutes"`
+	TravelMinutes int    `json:"travelMinutes"`
+}
+
+// TimesheetSummary aggregates the time entries of a technician's week.
+// Running entries are listed but not counted.
+type TimesheetSummary struct {
+	Timesheet
+	WeekEnd        string         `json:"weekEnd"` // YYYY-MM-DD of the Sunday
+	Timezone       string         `json:"timezone"`
+	TotalMinutes   int            `json:"totalMinutes"`
+	RunningEntries int            `json:"runningEntries"`
+	Days           []TimesheetDay `json:"days"`
+	Jobs           []TimesheetJob `json:"jobs"`
+	Entries        []*TimeEntry   `json:"entries"`
+}
+
+// ClockInRequest starts a time entry on a job. A missing Category means
+// labor; clocking in to the other category while running switches over.
+type ClockInRequest struct {
+	Category TimeEntryCategory `json:"category,omitempty" validate:"omitempty,oneof=labor travel"`
+	Notes    string            `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// ClockOutRequest ends the running time entry on a job
+type ClockOutRequest struct {
+	Notes string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateTimeEntryRequest corrects a time entry, e.g. one a technician forgot
+// to clock out of. Times are RFC 3339.
+type UpdateTimeEntryRequest struct {
+	Category  TimeEntryCategory `json:"category,omitempty" validate:"omitempty,oneof=labor travel"`
+	StartedAt string            `json:"startedAt,omitempty"`
+	EndedAt   string            `json:"endedAt,omitempty"`
+	Notes     string            `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// ReopenTimesheetRequest unlocks an approved week
+type ReopenTimesheetRequest struct {
+	Reason string `json:"reason" validate:"required,min=2,max=500"`
+}
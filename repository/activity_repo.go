@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrActivityNotFound is returned when an activity does not exist
+var ErrActivityNotFound = fmt.Errorf("activity %w", dal.ErrNotFound)
+
+// sequenceTimeFormat renders creation times at a fixed width so sequences
+// sort chronologically as strings
+const sequenceTimeFormat = "20060102T150405.000000000Z"
+
+// ActivityRepository implements ActivityRepositoryInterface
+type ActivityRepository struct {
+	activities *Repository[models.Activity]
+	logger     logger.Logger
+}
+
+func NewActivityRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *ActivityRepository {
+	return &ActivityRepository{
+		activities: NewTypedRepository[models.Activity](db, cfg, ActivityTable),
+		logger:     log,
+	}
+}
+
+// CreateActivity appends activity to the feed of its job
+func (r *ActivityRepository) CreateActivity(ctx context.Context, activity *models.Activity) (*models.Activity, error) {
+	if activity.JobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	now := time.Now().UTC()
+	activity.ActivityID = utils.GenerateUUID()
+	activity.CreatedAt = now
+	activity.Sequence = now.Format(sequenceTimeFormat) + "#" + activity.ActivityID
+
+	err := r.activities.Put(ctx, activity)
+	if err != nil {
+		r.logger.Errorf("Failed to create activity: %v", err)
+		return nil, err
+	}
+	return activity, nil
+}
+
+func (r *ActivityRepository) GetActivity(ctx context.Context, id string) (*models.Activity, error) {
+	if id == "" {
+		return nil, errors.New("activity ID is required")
+	}
+
+	activity, err := r.activities.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrActivityNotFound
+		}
+		r.logger.Errorf("Failed to get activity: %v", err)
+		return nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	if activity.ActivityID == "" {
+		return nil, ErrActivityNotFound
+	}
+	return activity, nil
+}
+
+// GetActivitiesByJob returns up to limit entries of the feed of jobID, newest
+// first, starting after the entry with sequence before when given
+func (r *ActivityRepository) GetActivitiesByJob(ctx context.Context, jobID string, before string, limit int) ([]*models.Activity, error) {
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	query, err := r.activities.NewQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.Index(ActivityTable.Indexes["jobID"]).
+		WhereHashKey("jobID", jobID).
+		Descending().
+		Limit(limit)
+	if before != "" {
+		query.SortLessThan("sequence", before)
+	}
+
+	activities, err := r.activities.Query(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Failed to get activities: %v", err)
+		return nil, err
+	}
+	return activities, nil
+}
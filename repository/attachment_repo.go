@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrAttachmentNotFound is returned when an attachment does not exist or
+// is deleted
+var ErrAttachmentNotFound = fmt.Errorf("attachment %w", dal.ErrNotFound)
+
+// AttachmentRepository implements AttachmentRepositoryInterface
+type AttachmentRepository struct {
+	attachments *Repository[models.Attachment]
+	logger      logger.Logger
+}
+
+func NewAttachmentRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *AttachmentRepository {
+	return &AttachmentRepository{
+		attachments: NewTypedRepository[models.Attachment](db, cfg, AttachmentsTable),
+		logger:      log,
+	}
+}
+
+// CreateAttachment stores attachment under the ID it was uploaded with
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	r.logger.Infof("Creating attachment %s of job %s", attachment.AttachmentID, attachment.JobID)
+
+	if attachment.AttachmentID == "" {
+		return nil, errors.New("attachment ID is required")
+	}
+
+	attachment.CreatedAt = time.Now().UTC()
+	err := r.attachments.Put(ctx, attachment)
+	if err != nil {
+		r.logger.Errorf("Failed to create attachment: %v", err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	if id == "" {
+		return nil, errors.New("attachment ID is required")
+	}
+
+	attachment, err := r.attachments.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrAttachmentNotFound
+		}
+		r.logger.Errorf("Failed to get attachment: %v", err)
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	if attachment.AttachmentID == "" || attachment.DeletedData != nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// GetAttachmentsByJob returns the attachments of jobID
+func (r *AttachmentRepository) GetAttachmentsByJob(ctx context.Context, jobID string) ([]*models.Attachment, error) {
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	attachments, err := r.attachments.ListByIndex(ctx, "jobID", jobID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get attachments: %v", err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment soft-deletes an attachment
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting attachment: %s", id)
+
+	if id == "" {
+		return errors.New("attachment ID is required")
+	}
+
+	err := r.attachments.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrAttachmentNotFound
+		}
+		r.logger.Errorf("Failed to delete attachment: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"time"
+)
+
+// AvailabilityRepository implements AvailabilityRepositoryInterface
+type AvailabilityRepository struct {
+	availability *Repository[models.TechnicianAvailability]
+	logger       logger.Logger
+}
+
+func NewAvailabilityRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		availability: NewTypedRepository[models.TechnicianAvailability](db, cfg, AvailabilityTable),
+		logger:       log,
+	}
+}
+
+// GetAvailability returns the availability of userID, or nil when none was
+// set
+func (r *AvailabilityRepository) GetAvailability(ctx context.Context, userID string) (*models.TechnicianAvailability, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	availability, err := r.availability.ByID(ctx, userID)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, nil
+		}
+		r.logger.Errorf("Failed to get availability of %s: %v", userID, err)
+		return nil, err
+	}
+	return availability, nil
+}
+
+// PutAvailability replaces the availability of availability.UserID
+func (r *AvailabilityRepository) PutAvailability(ctx context.Context, availability *models.TechnicianAvailability) (*models.TechnicianAvailability, error) {
+	r.logger.Infof("Setting availability of %s", availability.UserID)
+
+	if availability.UserID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	availability.UpdatedAt = time.Now().UTC()
+	if err := r.availability.Put(ctx, availability); err != nil {
+		r.logger.Errorf("Failed to set availability: %v", err)
+		return nil, err
+	}
+	return availability, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/cache"
+	"slices"
+)
+
+// CachedRoleRepository is a read-through cache in front of a role
+// repository. Roles are cached under their ID and name and role assignments
+// under their ID; writes drop the affected entries.
+type CachedRoleRepository struct {
+	next        RoleRepositoryInterface
+	roles       *cache.Cache[models.Role]
+	assignments *cache.Cache[models.RoleAssignment]
+}
+
+// NewCachedRoleRepository wraps next with the given caches
+func NewCachedRoleRepository(next RoleRepositoryInterface, roles *cache.Cache[models.Role], assignments *cache.Cache[models.RoleAssignment]) *CachedRoleRepository {
+	return &CachedRoleRepository{
+		next:        next,
+		roles:       roles,
+		assignments: assignments,
+	}
+}
+
+// CreateRoleAssignment creates a role assignment
+func (r *CachedRoleRepository) CreateRoleAssignment(ctx context.Context, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error) {
+	return r.next.CreateRoleAssignment(ctx, roleAssignment)
+}
+
+// GetRoleAssignments returns the role assignment by ID from the cache when
+// possible. Listing all assignments with an empty key is never cached.
+func (r *CachedRoleRepository) GetRoleAssignments(ctx context.Context, id string) ([]*models.RoleAssignment, error) {
+	if id == "" {
+		return r.next.GetRoleAssignments(ctx, id)
+	}
+
+	if assignment, ok := r.assignments.Get(ctx, id); ok {
+		return []*models.RoleAssignment{copyRoleAssignment(assignment)}, nil
+	}
+
+	assignments, err := r.next.GetRoleAssignments(ctx, id)
+	if err != nil || len(assignments) == 0 || assignments[0] == nil {
+		return assignments, err
+	}
+
+	r.assignments.Set(ctx, id, *copyRoleAssignment(*assignments[0]))
+	return assignments, nil
+}
+
+// GetRoles lists every role. Listings are never cached.
+func (r *CachedRoleRepository) GetRoles(ctx context.Context) ([]*models.Role, error) {
+	return r.next.GetRoles(ctx)
+}
+
+// GetRoleByID returns the role by ID from the cache when possible
+func (r *CachedRoleRepository) GetRoleByID(ctx context.Context, id string) (*models.Role, error) {
+	return r.getRole(ctx, roleIDKey(id), func() (*models.Role, error) {
+		return r.next.GetRoleByID(ctx, id)
+	})
+}
+
+// GetRoleByName returns the role by name from the cache when possible
+func (r *CachedRoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	return r.getRole(ctx, "name:"+name, func() (*models.Role, error) {
+		return r.next.GetRoleByName(ctx, name)
+	})
+}
+
+// getRole returns the role cached under key, or loads it and caches it
+// under all of its keys
+func (r *CachedRoleRepository) getRole(ctx context.Context, key string, load func() (*models.Role, error)) (*models.Role, error) {
+	if role, ok := r.roles.Get(ctx, key); ok {
+		return copyRole(role), nil
+	}
+
+	role, err := load()
+	if err != nil || role == nil {
+		return role, err
+	}
+
+	for _, alias := range roleCacheKeys(role) {
+		r.roles.Set(ctx, alias, *copyRole(*role))
+	}
+	return role, nil
+}
+
+// UpdateRole updates a role
+func (r *CachedRoleRepository) UpdateRole(ctx context.Context, id string, role *models.Role) (*models.Role, error) {
+	updated, err := r.next.UpdateRole(ctx, id, role)
+	r.invalidateRole(ctx, id, updated)
+	return updated, err
+}
+
+// DeleteRole soft-deletes a role
+func (r *CachedRoleRepository) DeleteRole(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	err := r.next.DeleteRole(ctx, id, deletedData)
+	r.invalidateRole(ctx, id, nil)
+	return err
+}
+
+// RestoreRole restores a soft-deleted role
+func (r *CachedRoleRepository) RestoreRole(ctx context.Context, id string) (*models.Role, error) {
+	restored, err := r.next.RestoreRole(ctx, id)
+	r.invalidateRole(ctx, id, restored)
+	return restored, err
+}
+
+// GetRoleAssignmentsByStatus returns role assignments by status
+func (r *CachedRoleRepository) GetRoleAssignmentsByStatus(ctx context.Context, status string) ([]*models.RoleAssignment, error) {
+	return r.next.GetRoleAssignmentsByStatus(ctx, status)
+}
+
+// UpdateRoleAssignment updates a role assignment
+func (r *CachedRoleRepository) UpdateRoleAssignment(ctx context.Context, id string, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error) {
+	updated, err := r.next.UpdateRoleAssignment(ctx, id, roleAssignment)
+	r.assignments.Delete(ctx, id)
+	return updated, err
+}
+
+// DeleteRoleAssignment soft-deletes a role assignment
+func (r *CachedRoleRepository) DeleteRoleAssignment(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	err := r.next.DeleteRoleAssignment(ctx, id, deletedData)
+	r.assignments.Delete(ctx, id)
+	return err
+}
+
+// RestoreRoleAssignment restores a soft-deleted role assignment
+func (r *CachedRoleRepository) RestoreRoleAssignment(ctx context.Context, id string) (*models.RoleAssignment, error) {
+	restored, err := r.next.RestoreRoleAssignment(ctx, id)
+	r.assignments.Delete(ctx, id)
+	return restored, err
+}
+
+// CacheStats returns the role and role assignment cache metrics
+func (r *CachedRoleRepository) CacheStats() []cache.Stats {
+	return []cache.Stats{r.roles.Stats(), r.assignments.Stats()}
+}
+
+// invalidateRole drops the keys of both the locally cached role and the role
+// returned by the write
+func (r *CachedRoleRepository) invalidateRole(ctx context.Context, id string, updated *models.Role) {
+	key := roleIDKey(id)
+	keys := []string{key}
+	if cached, ok := r.roles.Peek(key); ok {
+		keys = append(keys, roleCacheKeys(&cached)...)
+	}
+	if updated != nil {
+		keys = append(keys, roleCacheKeys(updated)...)
+	}
+	r.roles.Delete(ctx, compactKeys(keys)...)
+}
+
+// roleCacheKeys returns the cache keys of every lookup that finds the role
+func roleCacheKeys(role *models.Role) []string {
+	var keys []string
+	if role.ID != "" {
+		keys = append(keys, roleIDKey(role.ID))
+	}
+	if role.Name != "" {
+		keys = append(keys, "name:"+role.Name)
+	}
+	return keys
+}
+
+// roleIDKey returns the cache key of the lookup by ID
+func roleIDKey(id string) string {
+	return "id:" + id
+}
+
+// copyRole returns a copy of role that shares no slices with the cache
+func copyRole(role models.Role) *models.Role {
+	role.Permissions = slices.Clone(role.Permissions)
+	return &role
+}
+
+// copyRoleAssignment returns a copy of assignment that shares no slices with
+// the cache
+func copyRoleAssignment(assignment models.RoleAssignment) *models.RoleAssignment {
+	assignment.Permissions = slices.Clone(assignment.Permissions)
+	return &assignment
+}
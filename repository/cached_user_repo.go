@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/cache"
+	"slices"
+)
+
+// CachedUserRepository is a read-through cache in front of a user
+// repository. A user read by ID, email or username is cached under all
+// three lookups; every write drops all three. Password hashes are never
+// cached nor returned by the cached lookups, so they stay out of shared
+// cache backends; logins read them through GetUserCredentials.
+type CachedUserRepository struct {
+	next  UserRepositoryInterface
+	cache *cache.Cache[models.User]
+}
+
+// NewCachedUserRepository wraps next with c
+func NewCachedUserRepository(next UserRepositoryInterface, c *cache.Cache[models.User]) *CachedUserRepository {
+	return &CachedUserRepository{
+		next:  next,
+		cache: c,
+	}
+}
+
+// CreateUser creates a user. Misses are not cached, so there is nothing to
+// invalidate.
+func (r *CachedUserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	return r.next.CreateUser(ctx, user)
+}
+
+// GetUsers lists every user. Listings are never cached.
+func (r *CachedUserRepository) GetUsers(ctx context.Context) ([]*models.User, error) {
+	return r.next.GetUsers(ctx)
+}
+
+// GetUserByID returns the user by ID from the cache when possible
+func (r *CachedUserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	return r.get(ctx, userIDKey(id), func() (*models.User, error) {
+		return r.next.GetUserByID(ctx, id)
+	})
+}
+
+// GetUserByEmail returns the user by email from the cache when possible
+func (r *CachedUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.get(ctx, "email:"+email, func() (*models.User, error) {
+		return r.next.GetUserByEmail(ctx, email)
+	})
+}
+
+// GetUserByUsername returns the user by username from the cache when
+// possible
+func (r *CachedUserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.get(ctx, "username:"+username, func() (*models.User, error) {
+		return r.next.GetUserByUsername(ctx, username)
+	})
+}
+
+// GetUserCredentials reads the user with email, password hash included,
+// from the store
+func (r *CachedUserRepository) GetUserCredentials(ctx context.Context, email string) (*models.User, error) {
+	return r.next.GetUserCredentials(ctx, email)
+}
+
+// get returns the user cached under key, or loads it and caches it under
+// all of its keys, in both cases without its password hash
+func (r *CachedUserRepository) get(ctx context.Context, key string, load func() (*models.User, error)) (*models.User, error) {
+	if user, ok := r.cache.Get(ctx, key); ok {
+		return copyUser(user), nil
+	}
+
+	loaded, err := load()
+	if err != nil || loaded == nil {
+		return loaded, err
+	}
+
+	user := copyUser(*loaded)
+	for _, alias := range userCacheKeys(user) {
+		r.cache.Set(ctx, alias, *copyUser(*user))
+	}
+	return user, nil
+}
+
+// UpdateUser updates a user
+func (r *CachedUserRepository) UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	updated, err := r.next.UpdateUser(ctx, id, user)
+	r.invalidate(ctx, id, updated)
+	return updated, err
+}
+
+// AssignRoles replaces a user's roles
+func (r *CachedUserRepository) AssignRoles(ctx context.Context, userID string, roleAssignments []models.RoleAssignment) (*models.User, error) {
+	updated, err := r.next.AssignRoles(ctx, userID, roleAssignments)
+	r.invalidate(ctx, userID, updated)
+	return updated, err
+}
+
+// AddRoleToUser adds a role to a user
+func (r *CachedUserRepository) AddRoleToUser(ctx context.Context, userID string, roleAssignment models.RoleAssignment) (*models.User, error) {
+	updated, err := r.next.AddRoleToUser(ctx, userID, roleAssignment)
+	r.invalidate(ctx, userID, updated)
+	return updated, err
+}
+
+// AssignRoleToUser assigns an existing role to a user
+func (r *CachedUserRepository) AssignRoleToUser(ctx context.Context, userID, roleID string) (*models.User, error) {
+	updated, err := r.next.AssignRoleToUser(ctx, userID, roleID)
+	r.invalidate(ctx, userID, updated)
+	return updated, err
+}
+
+// RemoveRoleFromUser removes a role from a user
+func (r *CachedUserRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID string) (*models.User, error) {
+	updated, err := r.next.RemoveRoleFromUser(ctx, userID, roleID)
+	r.invalidate(ctx, userID, updated)
+	return updated, err
+}
+
+// InvalidateUser drops every cached entry for the user
+func (r *CachedUserRepository) InvalidateUser(ctx context.Context, userID string) {
+	r.invalidate(ctx, userID, nil)
+}
+
+// CacheStats returns the user cache metrics
+func (r *CachedUserRepository) CacheStats() cache.Stats {
+	return r.cache.Stats()
+}
+
+// invalidate drops the keys of both the locally cached user and the user
+// returned by the write. It runs even when the write failed, as the item may
+// have changed anyway.
+func (r *CachedUserRepository) invalidate(ctx context.Context, userID string, updated *models.User) {
+	key := userIDKey(userID)
+	keys := []string{key}
+	if cached, ok := r.cache.Peek(key); ok {
+		keys = append(keys, userCacheKeys(&cached)...)
+	}
+	if updated != nil {
+		keys = append(keys, userCacheKeys(updated)...)
+	}
+	r.cache.Delete(ctx, compactKeys(keys)...)
+}
+
+// userCacheKeys returns the cache keys of every lookup that finds the user
+func userCacheKeys(user *models.User) []string {
+	var keys []string
+	if user.ID != "" {
+		keys = append(keys, userIDKey(user.ID))
+	}
+	if user.Email != "" {
+		keys = append(keys, "email:"+user.Email)
+	}
+	if user.Username != "" {
+		keys = append(keys, "username:"+user.Username)
+	}
+	return keys
+}
+
+// userIDKey returns the cache key of the lookup by ID
+func userIDKey(id string) string {
+	return "id:" + id
+}
+
+// copyUser returns a copy of user without its password hash that shares no
+// slices with the cache
+func copyUser(user models.User) *models.User {
+	user.Password = ""
+	user.Roles = slices.Clone(user.Roles)
+	return &user
+}
+
+// compactKeys drops empty and duplicate keys
+func compactKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := keys[:0]
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, key)
+	}
+	return result
+}
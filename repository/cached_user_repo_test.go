@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/cache"
+	"fieldfuze-backend/utils/logger"
+	"testing"
+	"time"
+)
+
+// fakeUserStore answers the user lookups from users and counts the reads
+type fakeUserStore struct {
+	UserRepositoryInterface
+	users []*models.User
+	reads int
+}
+
+func (f *fakeUserStore) find(match func(*models.User) bool) (*models.User, error) {
+	f.reads++
+	for _, user := range f.users {
+		if match(user) {
+			stored := *user
+			return &stored, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (f *fakeUserStore) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	return f.find(func(user *models.User) bool { return user.ID == id })
+}
+
+func (f *fakeUserStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	return f.find(func(user *models.User) bool { return user.Username == username })
+}
+
+func (f *fakeUserStore) GetUserCredentials(ctx context.Context, email string) (*models.User, error) {
+	return f.find(func(user *models.User) bool { return user.Email == email })
+}
+
+func (f *fakeUserStore) UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	for _, stored := range f.users {
+		if stored.ID == id {
+			stored.Username = user.Username
+			updated := *stored
+			return &updated, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func newTestCachedUserRepository(store *fakeUserStore) *CachedUserRepository {
+	return NewCachedUserRepository(store, cache.New[models.User]("users", 10, time.Minute, nil, logger.NewLogger("error", "json")))
+}
+
+// TestCachedUserRepositoryLookups keeps the lookups apart: a username that
+// equals another user's ID must not be answered from that user's entry
+func TestCachedUserRepositoryLookups(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeUserStore{users: []*models.User{
+		{ID: "u-1", Username: "alice"},
+		{ID: "u-2", Username: "u-1"},
+	}}
+	repo := newTestCachedUserRepository(store)
+
+	if user, err := repo.GetUserByID(ctx, "u-1"); err != nil || user.Username != "alice" {
+		t.Fatalf("GetUserByID() = %+v, %v, want alice", user, err)
+	}
+	if user, err := repo.GetUserByUsername(ctx, "u-1"); err != nil || user.ID != "u-2" {
+		t.Fatalf("GetUserByUsername() = %+v, %v, want u-2", user, err)
+	}
+
+	// Both users are cached now under every lookup
+	reads := store.reads
+	if user, err := repo.GetUserByUsername(ctx, "alice"); err != nil || user.ID != "u-1" {
+		t.Fatalf("GetUserByUsername() = %+v, %v, want u-1", user, err)
+	}
+	if user, err := repo.GetUserByID(ctx, "u-2"); err != nil || user.Username != "u-1" {
+		t.Fatalf("GetUserByID() = %+v, %v, want u-1", user, err)
+	}
+	if store.reads != reads {
+		t.Fatalf("cached lookups read the store %d times", store.reads-reads)
+	}
+}
+
+// TestCachedUserRepositoryUpdate drops the entries of the old and the new
+// username
+func TestCachedUserRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeUserStore{users: []*models.User{{ID: "u-1", Username: "alice"}}}
+	repo := newTestCachedUserRepository(store)
+
+	if _, err := repo.GetUserByUsername(ctx, "alice"); err != nil {
+		t.Fatalf("GetUserByUsername() error = %v", err)
+	}
+	if _, err := repo.UpdateUser(ctx, "u-1", &models.User{Username: "alicia"}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	if _, err := repo.GetUserByUsername(ctx, "alice"); err == nil {
+		t.Fatalf("GetUserByUsername() found the old username")
+	}
+	if user, err := repo.GetUserByID(ctx, "u-1"); err != nil || user.Username != "alicia" {
+		t.Fatalf("GetUserByID() = %+v, %v, want alicia", user, err)
+	}
+}
+
+// TestCachedUserRepositoryPassword keeps password hashes out of the cache
+// and the cached lookups but not out of logins
+func TestCachedUserRepositoryPassword(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeUserStore{users: []*models.User{{ID: "u-1", Email: "alice@example.com", Password: "$2a$10$hash"}}}
+	repo := newTestCachedUserRepository(store)
+
+	if user, err := repo.GetUserByID(ctx, "u-1"); err != nil || user.Password != "" {
+		t.Fatalf("GetUserByID() = %+v, %v, want no password", user, err)
+	}
+	if cached, ok := repo.cache.Peek("email:alice@example.com"); !ok || cached.Password != "" {
+		t.Fatalf("cached user = %+v, %t, want no password", cached, ok)
+	}
+	if user, err := repo.GetUserByEmail(ctx, "alice@example.com"); err != nil || user.Password != "" {
+		t.Fatalf("GetUserByEmail() = %+v, %v, want no password", user, err)
+	}
+	if user, err := repo.GetUserCredentials(ctx, "alice@example.com"); err != nil || user.Password != "$2a$10$hash" {
+		t.Fatalf("GetUserCredentials() = %+v, %v, want the password", user, err)
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrChecklistTemplateNotFound is returned when a checklist template does
+// not exist or is deleted
+var ErrChecklistTemplateNotFound = fmt.Errorf("checklist template %w", dal.ErrNotFound)
+
+// ChecklistRepository implements ChecklistRepositoryInterface
+type ChecklistRepository struct {
+	templates *Repository[models.ChecklistTemplate]
+	logger    logger.Logger
+}
+
+func NewChecklistRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *ChecklistRepository {
+	return &ChecklistRepository{
+		templates: NewTypedRepository[models.ChecklistTemplate](db, cfg, ChecklistTemplatesTable),
+		logger:    log,
+	}
+}
+
+func (r *ChecklistRepository) CreateChecklistTemplate(ctx context.Context, template *models.ChecklistTemplate) (*models.ChecklistTemplate, error) {
+	r.logger.Infof("Creating checklist template: %s", template.Name)
+
+	now := time.Now().UTC()
+	template.TemplateID = utils.GenerateUUID()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	err := r.templates.Put(ctx, template)
+	if err != nil {
+		r.logger.Errorf("Failed to create checklist template: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Checklist template created successfully: %s", template.TemplateID)
+	return template, nil
+}
+
+func (r *ChecklistRepository) GetChecklistTemplate(ctx context.Context, id string) (*models.ChecklistTemplate, error) {
+	if id == "" {
+		return nil, errors.New("checklist template ID is required")
+	}
+
+	template, err := r.templates.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrChecklistTemplateNotFound
+		}
+		r.logger.Errorf("Failed to get checklist template: %v", err)
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+
+	if template.TemplateID == "" || template.DeletedData != nil {
+		return nil, ErrChecklistTemplateNotFound
+	}
+	return template, nil
+}
+
+// GetChecklistTemplatesByOrganization returns the checklist templates of
+// orgID
+func (r *ChecklistRepository) GetChecklistTemplatesByOrganization(ctx context.Context, orgID string) ([]*models.ChecklistTemplate, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	templates, err := r.templates.ListByIndex(ctx, "orgID", orgID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get checklist templates: %v", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateChecklistTemplate replaces the stored template with template
+func (r *ChecklistRepository) UpdateChecklistTemplate(ctx context.Context, id string, template *models.ChecklistTemplate) (*models.ChecklistTemplate, error) {
+	r.logger.Infof("Updating checklist template: %s", id)
+
+	if id == "" {
+		return nil, errors.New("checklist template ID is required")
+	}
+
+	template.TemplateID = id
+	template.UpdatedAt = time.Now().UTC()
+
+	err := r.templates.Put(ctx, template)
+	if err != nil {
+		r.logger.Errorf("Failed to update checklist template: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Checklist template updated successfully: %s", id)
+	return template, nil
+}
+
+// DeleteChecklistTemplate soft-deletes a checklist template. Jobs keep the
+// items copied from it.
+func (r *ChecklistRepository) DeleteChecklistTemplate(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting checklist template: %s", id)
+
+	if id == "" {
+		return errors.New("checklist template ID is required")
+	}
+
+	err := r.templates.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrChecklistTemplateNotFound
+		}
+		r.logger.Errorf("Failed to delete checklist template: %v", err)
+		return err
+	}
+
+	r.logger.Infof("Checklist template deleted successfully: %s", id)
+	return nil
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fmt"
+)
+
+// Repository provides typed access to the items of type T stored in one
+// table. Lookups are explicit: ByID reads by partition key and ByIndex reads
+// through the index declared for an attribute. Every operation resolves the
+// table from its context, so tenant-scoped tables reach the organization's
+// own table in isolated tenancy mode.
+type Repository[T any] struct {
+	db     dal.DatabaseClientInterface
+	config *models.Config
+	table  TableDefinition
+}
+
+// NewTypedRepository creates a repository for table using the configured
+// table prefix
+func NewTypedRepository[T any](db dal.DatabaseClientInterface, cfg *models.Config, table TableDefinition) *Repository[T] {
+	return &Repository[T]{
+		db:     db,
+		config: cfg,
+		table:  table,
+	}
+}
+
+// TableName returns the shared table name. Tenant-scoped tables may resolve
+// to another table per request, see ResolveTableName.
+func (r *Repository[T]) TableName() string {
+	return r.table.FullName(r.config.DynamoDBTablePrefix)
+}
+
+// tableName resolves the table to use for ctx
+func (r *Repository[T]) tableName(ctx context.Context) (string, error) {
+	return ResolveTableName(ctx, r.config, r.table)
+}
+
+// KeyName returns the table's partition key attribute
+func (r *Repository[T]) KeyName() string {
+	return r.table.PartitionKey
+}
+
+// IndexName returns the index keyed on attribute
+func (r *Repository[T]) IndexName(attribute string) (string, error) {
+	return r.table.IndexFor(attribute)
+}
+
+// ByID returns the item whose partition key is id. A missing item is
+// reported as dal.ErrNotFound.
+func (r *Repository[T]) ByID(ctx context.Context, id string) (*T, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item := new(T)
+	err = r.db.GetItem(ctx, models.QueryConfig{
+		TableName: tableName,
+		KeyName:   r.table.PartitionKey,
+		KeyValue:  id,
+		KeyType:   models.StringType,
+	}, item)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ByIndex returns the first item whose attribute equals value, read through
+// the index declared for attribute. A missing item is reported as
+// dal.ErrNotFound.
+func (r *Repository[T]) ByIndex(ctx context.Context, attribute, value string) (*T, error) {
+	indexName, err := r.table.IndexFor(attribute)
+	if err != nil {
+		return nil, err
+	}
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item := new(T)
+	err = r.db.GetItem(ctx, models.QueryConfig{
+		TableName: tableName,
+		IndexName: indexName,
+		KeyName:   attribute,
+		KeyValue:  value,
+		KeyType:   models.StringType,
+	}, item)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ListByIndex returns every item whose attribute equals value that also
+// matches filters
+func (r *Repository[T]) ListByIndex(ctx context.Context, attribute, value string, filters ...dal.Condition) ([]*T, error) {
+	indexName, err := r.table.IndexFor(attribute)
+	if err != nil {
+		return nil, err
+	}
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := dal.NewQuery(tableName).
+		Index(indexName).
+		WhereHashKey(attribute, value).
+		Filter(filters...)
+
+	var items []*T
+	if err := r.db.Query(ctx, query, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Query runs a query started with NewQuery for the same context
+func (r *Repository[T]) Query(ctx context.Context, query *dal.QueryBuilder) ([]*T, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if query.TableName() != tableName {
+		return nil, fmt.Errorf("query targets %s instead of %s", query.TableName(), tableName)
+	}
+
+	var items []*T
+	if err := r.db.Query(ctx, query, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// NewQuery starts a query against the table resolved for ctx
+func (r *Repository[T]) NewQuery(ctx context.Context) (*dal.QueryBuilder, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dal.NewQuery(tableName), nil
+}
+
+// Scan returns every item matching filters, or the whole table when no
+// filter is given
+func (r *Repository[T]) Scan(ctx context.Context, filters ...dal.Condition) ([]*T, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*T
+	if len(filters) == 0 {
+		err = r.db.ScanTable(ctx, tableName, &items)
+	} else {
+		err = r.db.ScanWhere(ctx, tableName, dal.And(filters...), &items)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Put writes item, replacing any item with the same key
+func (r *Repository[T]) Put(ctx context.Context, item *T) error {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.PutItem(ctx, tableName, item)
+}
+
+// PutIfAbsent writes item unless an item with the same key exists, in which
+// case it returns a conflict error (see dal.IsConflict)
+func (r *Repository[T]) PutIfAbsent(ctx context.Context, item *T) error {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.PutItem(ctx, tableName, item, dal.AttributeNotExists(r.table.PartitionKey))
+}
+
+// TransactPut returns a transaction item that writes item, provided all
+// conditions hold. Run it with the DAL's TransactWrite.
+func (r *Repository[T]) TransactPut(ctx context.Context, item *T, conditions ...dal.Condition) (dal.TransactItem, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return dal.TransactItem{}, err
+	}
+	return dal.TransactPut(tableName, item, conditions...), nil
+}
+
+// TransactCheck returns a transaction item that fails the transaction unless
+// all conditions hold for the item identified by id
+func (r *Repository[T]) TransactCheck(ctx context.Context, id string, conditions ...dal.Condition) (dal.TransactItem, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return dal.TransactItem{}, err
+	}
+	return dal.TransactCheck(tableName, r.table.PartitionKey, id, conditions...), nil
+}
+
+// SetAttributes sets the given attributes on the item identified by id
+func (r *Repository[T]) SetAttributes(ctx context.Context, id string, updates map[string]interface{}) error {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return err
+	}
+	return r.db.UpdateItem(ctx, tableName, r.table.PartitionKey, id, updates)
+}
+
+// NewUpdate starts an update of the item identified by id. The table is
+// resolved when the update is applied.
+func (r *Repository[T]) NewUpdate(id string) *dal.UpdateBuilder {
+	return dal.NewUpdate(r.TableName(), r.table.PartitionKey, id)
+}
+
+// Update applies an update started with NewUpdate and returns the item as
+// stored afterwards
+func (r *Repository[T]) Update(ctx context.Context, update *dal.UpdateBuilder) (*T, error) {
+	if update.TableName() != r.TableName() {
+		return nil, fmt.Errorf("update targets %s instead of %s", update.TableName(), r.TableName())
+	}
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	update.ForTable(tableName)
+
+	item := new(T)
+	if err := r.db.Update(ctx, update, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SoftDelete marks the item identified by id as deleted and schedules it for
+// purge. It returns errSoftDeleteConditionFailed when the item does not
+// exist or is already deleted.
+func (r *Repository[T]) SoftDelete(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return err
+	}
+	return softDelete(ctx, r.db, r.config, tableName, r.table.PartitionKey, id, deletedData)
+}
+
+// Restore clears the deleted marker of the item identified by id and returns
+// the restored item. It returns errSoftDeleteConditionFailed when the item
+// does not exist or is not deleted.
+func (r *Repository[T]) Restore(ctx context.Context, id string) (*T, error) {
+	tableName, err := r.tableName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	item := new(T)
+	if err := restore(ctx, r.db, tableName, r.table.PartitionKey, id, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/search"
+	"strings"
+)
+
+// Fields and facets of job search documents
+const (
+	JobSearchFieldName    = "name"
+	JobSearchFieldNotes   = "notes"
+	JobSearchFieldClient  = "client"
+	JobSearchFieldAddress = "address"
+
+	JobSearchFacetStatus   = "status"
+	JobSearchFacetType     = "type"
+	JobSearchFacetAssignee = "assignee"
+)
+
+// IndexedJobRepository keeps a search index in sync with the jobs written
+// through a job repository. Reads pass straight through; every successful
+// write indexes the job as stored, or drops it once deleted. The database
+// stays authoritative, so indexing failures are logged rather than failing
+// the write.
+type IndexedJobRepository struct {
+	JobRepositoryInterface
+	index  search.Index
+	logger logger.Logger
+}
+
+// NewIndexedJobRepository wraps next so its writes update index
+func NewIndexedJobRepository(next JobRepositoryInterface, index search.Index, log logger.Logger) *IndexedJobRepository {
+	return &IndexedJobRepository{
+		JobRepositoryInterface: next,
+		index:                  index,
+		logger:                 log,
+	}
+}
+
+// CreateJob creates a job
+func (r *IndexedJobRepository) CreateJob(ctx context.Context, job *models.Job) (*models.Job, error) {
+	created, err := r.JobRepositoryInterface.CreateJob(ctx, job)
+	r.indexJob(ctx, created, err)
+	return created, err
+}
+
+// CreateJobInstance creates a recurring job instance unless it exists
+func (r *IndexedJobRepository) CreateJobInstance(ctx context.Context, job *models.Job) (bool, error) {
+	created, err := r.JobRepositoryInterface.CreateJobInstance(ctx, job)
+	if created {
+		r.indexJob(ctx, job, err)
+	}
+	return created, err
+}
+
+// UpdateJob updates a job
+func (r *IndexedJobRepository) UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateJob(ctx, id, job)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// UpdateJobStatus updates a job still in status from
+func (r *IndexedJobRepository) UpdateJobStatus(ctx context.Context, id string, job *models.Job, from models.JobStatus) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateJobStatus(ctx, id, job, from)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// UpdateChecklistItem updates a checklist item of a job
+func (r *IndexedJobRepository) UpdateChecklistItem(ctx context.Context, id string, index int, item *models.ChecklistItem, updatedBy string) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateChecklistItem(ctx, id, index, item, updatedBy)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// UpdateJobSLA updates the SLA of a job
+func (r *IndexedJobRepository) UpdateJobSLA(ctx context.Context, id string, sla *models.JobSLA, from *models.JobSLA) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateJobSLA(ctx, id, sla, from)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// UpdateJobBlockedBy updates the jobs a job waits for
+func (r *IndexedJobRepository) UpdateJobBlockedBy(ctx context.Context, id string, blockedBy []string, from []string) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateJobBlockedBy(ctx, id, blockedBy, from)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// UpdateJobBlocks updates the jobs waiting for a job
+func (r *IndexedJobRepository) UpdateJobBlocks(ctx context.Context, id string, blocks []string, from []string) (*models.Job, error) {
+	updated, err := r.JobRepositoryInterface.UpdateJobBlocks(ctx, id, blocks, from)
+	r.indexJob(ctx, updated, err)
+	return updated, err
+}
+
+// DeleteJob soft-deletes a job and drops it from the index
+func (r *IndexedJobRepository) DeleteJob(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	err := r.JobRepositoryInterface.DeleteJob(ctx, id, deletedData)
+	if err == nil {
+		if err := r.index.Delete(ctx, id); err != nil {
+			r.logger.Warnf("Failed to remove job %s from the search index: %v", id, err)
+		}
+	}
+	return err
+}
+
+// RestoreJob restores a soft-deleted job and indexes it again
+func (r *IndexedJobRepository) RestoreJob(ctx context.Context, id string) (*models.Job, error) {
+	restored, err := r.JobRepositoryInterface.RestoreJob(ctx, id)
+	r.indexJob(ctx, restored, err)
+	return restored, err
+}
+
+// indexJob indexes job unless writing it failed
+func (r *IndexedJobRepository) indexJob(ctx context.Context, job *models.Job, err error) {
+	if err != nil || job == nil {
+		return
+	}
+	if err := IndexJob(ctx, r.index, job); err != nil {
+		r.logger.Warnf("Failed to index job %s: %v", job.JobID, err)
+	}
+}
+
+// IndexJob adds job to index, or removes it when it is deleted
+func IndexJob(ctx context.Context, index search.Index, job *models.Job) error {
+	if job.DeletedData != nil {
+		return index.Delete(ctx, job.JobID)
+	}
+	return index.Index(ctx, JobDocument(job))
+}
+
+// JobDocument returns the search document of job. Jobs only carry the ID of
+// their client, so the client field matches client and QuickBooks customer
+// IDs. Jobs are dated by their scheduled start or else their creation.
+func JobDocument(job *models.Job) *search.Document {
+	client := []string{job.ClientID}
+	notes := []string{job.Notes}
+	if job.QBInfoOnJob != nil {
+		client = append(client, job.QBInfoOnJob.CustomerID)
+		notes = append(notes, job.QBInfoOnJob.ServiceNotes)
+	}
+
+	var address string
+	if job.Site != nil {
+		address = job.Site.Address
+	}
+
+	date := job.CreatedAt
+	if job.ScheduledStart != nil {
+		date = *job.ScheduledStart
+	}
+
+	return &search.Document{
+		ID:    job.JobID,
+		OrgID: job.OrgID,
+		Text: map[string]string{
+			JobSearchFieldName:    job.JobsName,
+			JobSearchFieldNotes:   strings.Join(notes, " "),
+			JobSearchFieldClient:  strings.Join(client, " "),
+			JobSearchFieldAddress: address,
+		},
+		Facets: map[string][]string{
+			JobSearchFacetStatus:   {string(job.JobStatus)},
+			JobSearchFacetType:     {string(job.JobType)},
+			JobSearchFacetAssignee: job.UsersAssignedToJob,
+		},
+		Date: date,
+	}
+}
@@ -3,29 +3,48 @@ package repository
 import (
 	"context"
 	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/cache"
+	"fieldfuze-backend/utils/search"
+	"time"
 )
 
 // UserRepositoryInterface defines the contract for user repository operations
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
-	GetUser(key string) ([]*models.User, error)
-	UpdateUser(id string, user *models.User) (*models.User, error)
+	GetUsers(ctx context.Context) ([]*models.User, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetUserCredentials reads the user with email together with the
+	// password hash, which the other lookups may leave out
+	GetUserCredentials(ctx context.Context, email string) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error)
 	AssignRoles(ctx context.Context, userID string, roleAssignments []models.RoleAssignment) (*models.User, error)
 	AddRoleToUser(ctx context.Context, userID string, roleAssignment models.RoleAssignment) (*models.User, error)
 	AssignRoleToUser(ctx context.Context, userID, roleID string) (*models.User, error)
 	RemoveRoleFromUser(ctx context.Context, userID, roleID string) (*models.User, error)
 }
 
+// UserCacheInterface is implemented by user repositories that cache lookups
+type UserCacheInterface interface {
+	InvalidateUser(ctx context.Context, userID string)
+	CacheStats() cache.Stats
+}
+
 // RoleRepositoryInterface defines the contract for role repository operations
 type RoleRepositoryInterface interface {
 	CreateRoleAssignment(ctx context.Context, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error)
-	GetRoleAssignments(id string) ([]*models.RoleAssignment, error)
-	GetRole(name string) ([]*models.Role, error)
-	UpdateRole(id string, role *models.Role) (*models.Role, error)
-	DeleteRole(id string) error
-	GetRoleAssignmentsByStatus(status string) ([]*models.RoleAssignment, error)
-	UpdateRoleAssignment(id string, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error)
-	DeleteRoleAssignment(id string) error
+	GetRoleAssignments(ctx context.Context, id string) ([]*models.RoleAssignment, error)
+	GetRoles(ctx context.Context) ([]*models.Role, error)
+	GetRoleByID(ctx context.Context, id string) (*models.Role, error)
+	GetRoleByName(ctx context.Context, name string) (*models.Role, error)
+	UpdateRole(ctx context.Context, id string, role *models.Role) (*models.Role, error)
+	DeleteRole(ctx context.Context, id string, deletedData *models.DeletedData) error
+	RestoreRole(ctx context.Context, id string) (*models.Role, error)
+	GetRoleAssignmentsByStatus(ctx context.Context, status string) ([]*models.RoleAssignment, error)
+	UpdateRoleAssignment(ctx context.Context, id string, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error)
+	DeleteRoleAssignment(ctx context.Context, id string, deletedData *models.DeletedData) error
+	RestoreRoleAssignment(ctx context.Context, id string) (*models.RoleAssignment, error)
 }
 
 // RepositoryContainerInterface defines the contract for the repository container
@@ -34,21 +53,148 @@ type RepositoryContainerInterface interface {
 	GetRoleRepository() RoleRepositoryInterface
 	GetOrganizationRepository() OrganizationRepositoryInterface
 	GetJobRepository() JobRepositoryInterface
+	GetRecurringJobRepository() RecurringJobRepositoryInterface
+	GetAvailabilityRepository() AvailabilityRepositoryInterface
+	GetChecklistRepository() ChecklistRepositoryInterface
+	GetAttachmentRepository() AttachmentRepositoryInterface
+	GetActivityRepository() ActivityRepositoryInterface
+	GetTimeEntryRepository() TimeEntryRepositoryInterface
+	GetTimesheetRepository() TimesheetRepositoryInterface
+	GetSLAPolicyRepository() SLAPolicyRepositoryInterface
+	GetJobTemplateRepository() JobTemplateRepositoryInterface
+	GetJobImportRepository() JobImportRepositoryInterface
+	GetProjectRepository() ProjectRepositoryInterface
+	GetJobSearchIndex() search.Index
 }
 
 // OrganizationRepositoryInterface defines the contract for the organization repository
 type OrganizationRepositoryInterface interface {
 	CreateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error)
-	GetOrganization(name string) ([]*models.Organization, error)
-	UpdateOrganization(id string, organization *models.Organization) (*models.Organization, error)
-	DeleteOrganization(id string) error
+	GetOrganizations(ctx context.Context) ([]*models.Organization, error)
+	GetOrganizationByID(ctx context.Context, id string) (*models.Organization, error)
+	GetOrganizationByName(ctx context.Context, name string) (*models.Organization, error)
+	UpdateOrganization(ctx context.Context, id string, organization *models.Organization) (*models.Organization, error)
+	DeleteOrganization(ctx context.Context, id string, deletedData *models.DeletedData) error
+	RestoreOrganization(ctx context.Context, id string) (*models.Organization, error)
 }
 
 // JobRepositoryInterface defines the contract for job repository operations
 type JobRepositoryInterface interface {
 	CreateJob(ctx context.Context, job *models.Job) (*models.Job, error)
-	GetJob(key string) ([]*models.Job, error)
-	GetJobsByFilter(filter *models.JobFilter) ([]*models.Job, error)
-	UpdateJob(id string, job *models.Job) (*models.Job, error)
-	DeleteJob(id string) error
+	CreateJobInstance(ctx context.Context, job *models.Job) (bool, error)
+	GetJob(ctx context.Context, id string) ([]*models.Job, error)
+	GetJobsByFilter(ctx context.Context, filter *models.JobFilter) ([]*models.Job, error)
+	GetJobsByRecurringJob(ctx context.Context, recurringJobID string) ([]*models.Job, error)
+	GetJobsByProject(ctx context.Context, projectID string) ([]*models.Job, error)
+	GetScheduledJobs(ctx context.Context, orgID string, from, to time.Time, assignee string) ([]*models.Job, error)
+	UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error)
+	UpdateJobStatus(ctx context.Context, id string, job *models.Job, from models.JobStatus) (*models.Job, error)
+	UpdateChecklistItem(ctx context.Context, id string, index int, item *models.ChecklistItem, updatedBy string) (*models.Job, error)
+	GetOpenSLAJobs(ctx context.Context, orgID string) ([]*models.Job, error)
+	UpdateJobSLA(ctx context.Context, id string, sla *models.JobSLA, from *models.JobSLA) (*models.Job, error)
+	UpdateJobBlockedBy(ctx context.Context, id string, blockedBy []string, from []string) (*models.Job, error)
+	UpdateJobBlocks(ctx context.Context, id string, blocks []string, from []string) (*models.Job, error)
+	DeleteJob(ctx context.Context, id string, deletedData *models.DeletedData) error
+	RestoreJob(ctx context.Context, id string) (*models.Job, error)
+}
+
+// RecurringJobRepositoryInterface defines the contract for recurring job
+// template operations
+type RecurringJobRepositoryInterface interface {
+	CreateRecurringJob(ctx context.Context, recurringJob *models.RecurringJob) (*models.RecurringJob, error)
+	GetRecurringJob(ctx context.Context, id string) (*models.RecurringJob, error)
+	GetRecurringJobsByOrganization(ctx context.Context, orgID string) ([]*models.RecurringJob, error)
+	GetActiveRecurringJobs(ctx context.Context, orgID string) ([]*models.RecurringJob, error)
+	UpdateRecurringJob(ctx context.Context, id string, recurringJob *models.RecurringJob) (*models.RecurringJob, error)
+	SetMaterializedThrough(ctx context.Context, id string, through time.Time) error
+	DeleteRecurringJob(ctx context.Context, id string, deletedData *models.DeletedData) error
+}
+
+// AvailabilityRepositoryInterface defines the contract for technician
+// availability operations
+type AvailabilityRepositoryInterface interface {
+	GetAvailability(ctx context.Context, userID string) (*models.TechnicianAvailability, error)
+	PutAvailability(ctx context.Context, availability *models.TechnicianAvailability) (*models.TechnicianAvailability, error)
+}
+
+// ChecklistRepositoryInterface defines the contract for checklist template
+// operations
+type ChecklistRepositoryInterface interface {
+	CreateChecklistTemplate(ctx context.Context, template *models.ChecklistTemplate) (*models.ChecklistTemplate, error)
+	GetChecklistTemplate(ctx context.Context, id string) (*models.ChecklistTemplate, error)
+	GetChecklistTemplatesByOrganization(ctx context.Context, orgID string) ([]*models.ChecklistTemplate, error)
+	UpdateChecklistTemplate(ctx context.Context, id string, template *models.ChecklistTemplate) (*models.ChecklistTemplate, error)
+	DeleteChecklistTemplate(ctx context.Context, id string, deletedData *models.DeletedData) error
+}
+
+// AttachmentRepositoryInterface defines the contract for job attachment
+// operations
+type AttachmentRepositoryInterface interface {
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error)
+	GetAttachment(ctx context.Context, id string) (*models.Attachment, error)
+	GetAttachmentsByJob(ctx context.Context, jobID string) ([]*models.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string, deletedData *models.DeletedData) error
+}
+
+// ActivityRepositoryInterface defines the contract for job activity feed
+// operations
+type ActivityRepositoryInterface interface {
+	CreateActivity(ctx context.Context, activity *models.Activity) (*models.Activity, error)
+	GetActivity(ctx context.Context, id string) (*models.Activity, error)
+	GetActivitiesByJob(ctx context.Context, jobID string, before string, limit int) ([]*models.Activity, error)
+}
+
+// TimeEntryRepositoryInterface defines the contract for technician time
+// entry operations
+type TimeEntryRepositoryInterface interface {
+	CreateTimeEntry(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error)
+	GetTimeEntry(ctx context.Context, id string) (*models.TimeEntry, error)
+	GetRunningTimeEntry(ctx context.Context, userID string) (*models.TimeEntry, error)
+	GetTimeEntriesByJob(ctx context.Context, jobID string) ([]*models.TimeEntry, error)
+	GetTimeEntriesByWeek(ctx context.Context, userID, weekStart string) ([]*models.TimeEntry, error)
+	EndTimeEntry(ctx context.Context, entry *models.TimeEntry, updatedBy string) (*models.TimeEntry, error)
+	UpdateTimeEntry(ctx context.Context, entry *models.TimeEntry, previousWeek string, updatedBy string) (*models.TimeEntry, error)
+}
+
+// TimesheetRepositoryInterface defines the contract for timesheet approval
+// operations
+type TimesheetRepositoryInterface interface {
+	GetTimesheet(ctx context.Context, userID, weekStart string) (*models.Timesheet, error)
+	PutTimesheet(ctx context.Context, timesheet *models.Timesheet) (*models.Timesheet, error)
+}
+
+// SLAPolicyRepositoryInterface defines the contract for SLA policy
+// operations
+type SLAPolicyRepositoryInterface interface {
+	CreateSLAPolicy(ctx context.Context, policy *models.SLAPolicy) (*models.SLAPolicy, error)
+	GetSLAPolicy(ctx context.Context, id string) (*models.SLAPolicy, error)
+	GetSLAPoliciesByOrganization(ctx context.Context, orgID string) ([]*models.SLAPolicy, error)
+	UpdateSLAPolicy(ctx context.Context, id string, policy *models.SLAPolicy) (*models.SLAPolicy, error)
+	DeleteSLAPolicy(ctx context.Context, id string, deletedData *models.DeletedData) error
+}
+
+// JobTemplateRepositoryInterface defines the contract for job template
+// operations
+type JobTemplateRepositoryInterface interface {
+	CreateJobTemplate(ctx context.Context, template *models.JobTemplate) (*models.JobTemplate, error)
+	GetJobTemplate(ctx context.Context, id string) (*models.JobTemplate, error)
+	GetJobTemplatesByOrganization(ctx context.Context, orgID string) ([]*models.JobTemplate, error)
+	UpdateJobTemplate(ctx context.Context, id string, template *models.JobTemplate) (*models.JobTemplate, error)
+	DeleteJobTemplate(ctx context.Context, id string, deletedData *models.DeletedData) error
+}
+
+// JobImportRepositoryInterface defines the contract for job import tracking
+type JobImportRepositoryInterface interface {
+	CreateJobImport(ctx context.Context, jobImport *models.JobImport) (*models.JobImport, error)
+	GetJobImport(ctx context.Context, id string) (*models.JobImport, error)
+	UpdateJobImport(ctx context.Context, jobImport *models.JobImport) (*models.JobImport, error)
+}
+
+// ProjectRepositoryInterface defines the contract for project operations
+type ProjectRepositoryInterface interface {
+	CreateProject(ctx context.Context, project *models.Project) (*models.Project, error)
+	GetProject(ctx context.Context, id string) (*models.Project, error)
+	GetProjectsByOrganization(ctx context.Context, orgID string) ([]*models.Project, error)
+	UpdateProject(ctx context.Context, id string, project *models.Project) (*models.Project, error)
+	DeleteProject(ctx context.Context, id string, deletedData *models.DeletedData) error
 }
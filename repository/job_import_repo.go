@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrJobImportNotFound is returned when a job import does not exist
+var ErrJobImportNotFound = fmt.Errorf("job import %w", dal.ErrNotFound)
+
+// JobImportRepository implements JobImportRepositoryInterface
+type JobImportRepository struct {
+	imports *Repository[models.JobImport]
+	logger  logger.Logger
+}
+
+func NewJobImportRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *JobImportRepository {
+	return &JobImportRepository{
+		imports: NewTypedRepository[models.JobImport](db, cfg, JobImportsTable),
+		logger:  log,
+	}
+}
+
+func (r *JobImportRepository) CreateJobImport(ctx context.Context, jobImport *models.JobImport) (*models.JobImport, error) {
+	r.logger.Infof("Creating job import of %d rows for organization %s", jobImport.TotalRows, jobImport.OrgID)
+
+	now := time.Now().UTC()
+	jobImport.ImportID = utils.GenerateUUID()
+	jobImport.CreatedAt = now
+	jobImport.UpdatedAt = now
+
+	err := r.imports.Put(ctx, jobImport)
+	if err != nil {
+		r.logger.Errorf("Failed to create job import: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Job import created successfully: %s", jobImport.ImportID)
+	return jobImport, nil
+}
+
+func (r *JobImportRepository) GetJobImport(ctx context.Context, id string) (*models.JobImport, error) {
+	if id == "" {
+		return nil, errors.New("job import ID is required")
+	}
+
+	jobImport, err := r.imports.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrJobImportNotFound
+		}
+		r.logger.Errorf("Failed to get job import: %v", err)
+		return nil, fmt.Errorf("failed to get job import: %w", err)
+	}
+
+	if jobImport.ImportID == "" {
+		return nil, ErrJobImportNotFound
+	}
+	return jobImport, nil
+}
+
+// UpdateJobImport stores the progress of jobImport
+func (r *JobImportRepository) UpdateJobImport(ctx context.Context, jobImport *models.JobImport) (*models.JobImport, error) {
+	if jobImport.ImportID == "" {
+		return nil, errors.New("job import ID is required")
+	}
+
+	jobImport.UpdatedAt = time.Now().UTC()
+
+	err := r.imports.Put(ctx, jobImport)
+	if err != nil {
+		r.logger.Errorf("Failed to update job import %s: %v", jobImport.ImportID, err)
+		return nil, err
+	}
+	return jobImport, nil
+}
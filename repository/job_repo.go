@@ -8,21 +8,43 @@ import (
 	"fieldfuze-backend/utils"
 	"fieldfuze-backend/utils/logger"
 	"fmt"
-	"regexp"
-	"strings"
+	"sort"
 	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 )
 
+// ErrJobNotFound is returned when a job does not exist or is deleted
+var ErrJobNotFound = fmt.Errorf("job %w", dal.ErrNotFound)
+
+// ErrDeletedJobNotFound is returned by RestoreJob when there is no deleted
+// job to restore
+var ErrDeletedJobNotFound = fmt.Errorf("deleted job %w", dal.ErrNotFound)
+
+// ErrJobStatusChanged is returned when a job's status changed between
+// reading the job and writing it
+var ErrJobStatusChanged = errors.New("job status changed concurrently")
+
+// ErrChecklistItemChanged is returned by UpdateChecklistItem when the item is
+// no longer at the given position or the job was closed in the meantime
+var ErrChecklistItemChanged = errors.New("checklist item changed concurrently")
+
+// ErrJobSLAChanged is returned by UpdateJobSLA when the SLA of the job
+// changed or the job was closed in the meantime
+var ErrJobSLAChanged = errors.New("job SLA changed concurrently")
+
+// ErrJobDependenciesChanged is returned by UpdateJobBlockedBy and
+// UpdateJobBlocks when the dependencies of the job changed in the meantime
+var ErrJobDependenciesChanged = errors.New("job dependencies changed concurrently")
+
 type JobRepository struct {
-	db     dal.DatabaseClientInterface
-	config *models.Config
+	jobs   *Repository[models.Job]
 	logger logger.Logger
 }
 
 func NewJobRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *JobRepository {
 	return &JobRepository{
-		db:     db,
-		config: cfg,
+		jobs:   NewTypedRepository[models.Job](db, cfg, JobsTable),
 		logger: log,
 	}
 }
@@ -30,15 +52,18 @@ func NewJobRepository(db dal.DatabaseClientInterface, cfg *models.Config, log lo
 func (r *JobRepository) CreateJob(ctx context.Context, job *models.Job) (*models.Job, error) {
 	r.logger.Infof("Creating job: %s", job.JobsName)
 
-	now := time.Now()
+	now := time.Now().UTC()
 	job.JobID = utils.GenerateUUID()
 	job.CreatedAt = now
 	job.UpdatedAt = now
-	job.JobStatus = models.JobStatusPending
+	// Jobs start out pending unless they are imported history
+	if job.JobStatus == "" {
+		job.JobStatus = models.JobStatusPending
+	}
 
 	fmt.Println("job ::::", dal.PrintPrettyJSON(job))
 
-	err := r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_jobs", job)
+	err := r.jobs.Put(ctx, job)
 	if err != nil {
 		r.logger.Errorf("Failed to create job: %v", err)
 		return nil, err
@@ -48,89 +73,77 @@ func (r *JobRepository) CreateJob(ctx context.Context, job *models.Job) (*models
 	return job, nil
 }
 
-func (r *JobRepository) GetJob(key string) ([]*models.Job, error) {
-	ctx := context.Background()
-
-	if key == "" {
-		return nil, errors.New("job key is required")
+// CreateJobInstance stores job under the ID it already carries unless a job
+// with that ID exists, and reports whether it was created. Recurring job
+// instances use it so an occurrence is only materialised once.
+func (r *JobRepository) CreateJobInstance(ctx context.Context, job *models.Job) (bool, error) {
+	if job.JobID == "" {
+		return false, errors.New("job ID is required")
 	}
 
-	r.logger.Infof("Job checking for: %s", key)
+	now := time.Now().UTC()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	job.JobStatus = models.JobStatusPending
+
+	err := r.jobs.PutIfAbsent(ctx, job)
+	if err != nil {
+		if dal.IsConflict(err) {
+			return false, nil
+		}
+		r.logger.Errorf("Failed to create job instance: %v", err)
+		return false, err
+	}
 
-	job := models.Job{}
-	keyType, indexName, keyName := r.determineKeyType(key)
+	r.logger.Infof("Job instance created: %s", job.JobID)
+	return true, nil
+}
 
-	var config models.QueryConfig
+// GetJob reads a job by ID
+func (r *JobRepository) GetJob(ctx context.Context, id string) ([]*models.Job, error) {
 
-	if keyType == "id" {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_jobs",
-			KeyName:   "jobID",
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
-	} else {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_jobs",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
+	if id == "" {
+		return nil, errors.New("job ID is required")
 	}
 
-	r.logger.Infof("Querying %s table with %s: %s", r.config.DynamoDBTablePrefix, keyName, key)
+	r.logger.Infof("Job checking for: %s", id)
 
-	err := r.db.GetItem(ctx, config, &job)
+	job, err := r.jobs.ByID(ctx, id)
 	if err != nil {
-		r.logger.Errorf("Failed to get job by %s: %v", keyName, err)
-		return nil, fmt.Errorf("failed to get job by %s: %w", keyName, err)
+		r.logger.Errorf("Failed to get job by ID: %v", err)
+		return nil, fmt.Errorf("failed to get job by ID: %w", err)
 	}
 
-	if job.JobID == "" {
-		return nil, errors.New("job not found")
+	if job.JobID == "" || job.DeletedData != nil {
+		return nil, ErrJobNotFound
 	}
 
 	r.logger.Infof("Job found: %s", job.JobID)
-	return []*models.Job{&job}, nil
+	return []*models.Job{job}, nil
 }
 
-func (r *JobRepository) GetJobsByFilter(filter *models.JobFilter) ([]*models.Job, error) {
-	ctx := context.Background()
+func (r *JobRepository) GetJobsByFilter(ctx context.Context, filter *models.JobFilter) ([]*models.Job, error) {
 	r.logger.Infof("Getting jobs with filter")
 
+	query, err := r.buildFilterQuery(ctx, filter)
+	if err != nil {
+		r.logger.Errorf("Failed to get jobs: %v", err)
+		return nil, err
+	}
+
 	var jobs []*models.Job
-	var err error
-
-	// Query by organization 
-	if filter.OrgID != "" {
-		// Use simple orgID index (date filtering will be done post-query)
-		err = r.db.QueryByIndex(ctx, 
-			r.config.DynamoDBTablePrefix+"_jobs", 
-			"orgID-index", 
-			"orgID", filter.OrgID, 
-			&jobs)
-	} else if filter.ClientID != "" {
-		err = r.db.QueryByIndex(ctx, 
-			r.config.DynamoDBTablePrefix+"_jobs", 
-			"clientID-index", 
-			"clientID", filter.ClientID, 
-			&jobs)
-	} else if filter.JobStatus != "" {
-		err = r.db.QueryByIndex(ctx, 
-			r.config.DynamoDBTablePrefix+"_jobs", 
-			"jobStatus-index", 
-			"jobStatus", string(filter.JobStatus), 
-			&jobs)
-	} else if filter.JobType != "" {
-		err = r.db.QueryByIndex(ctx, 
-			r.config.DynamoDBTablePrefix+"_jobs", 
-			"jobType-index", 
-			"jobType", string(filter.JobType), 
-			&jobs)
+	if query != nil {
+		jobs, err = r.jobs.Query(ctx, query)
 	} else {
 		// Scan all jobs (use with caution in production)
-		err = r.db.ScanTable(ctx, r.config.DynamoDBTablePrefix+"_jobs", &jobs)
+		if filter.IncludeDeleted {
+			jobs, err = r.jobs.Scan(ctx)
+		} else {
+			jobs, err = r.jobs.Scan(ctx, notDeleted())
+		}
+		if err == nil {
+			jobs = r.applyAdditionalFilters(jobs, filter)
+		}
 	}
 
 	if err != nil {
@@ -138,62 +151,428 @@ func (r *JobRepository) GetJobsByFilter(filter *models.JobFilter) ([]*models.Job
 		return nil, err
 	}
 
-	// Apply additional filtering if needed
-	filteredJobs := r.applyAdditionalFilters(jobs, filter)
+	r.logger.Infof("Found %d jobs", len(jobs))
+	return jobs, nil
+}
+
+// buildFilterQuery picks the most selective index for the filter and pushes
+// the remaining criteria into filter expressions. It returns nil when no
+// index applies and the table has to be scanned.
+func (r *JobRepository) buildFilterQuery(ctx context.Context, filter *models.JobFilter) (*dal.QueryBuilder, error) {
+	query, err := r.jobs.NewQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case filter.OrgID != "" && (!filter.FromDate.IsZero() || !filter.ToDate.IsZero()):
+		// Date ranges within an organization use the createdAt range key,
+		// which is compared as a string in models.SortableTimeLayout
+		query.Index(jobsByOrgCreatedAtIndex).WhereHashKey("orgID", filter.OrgID)
+		switch {
+		case !filter.FromDate.IsZero() && !filter.ToDate.IsZero():
+			query.SortBetween("createdAt", models.SortableTime(filter.FromDate), models.SortableTime(filter.ToDate))
+		case !filter.FromDate.IsZero():
+			query.SortGreaterOrEqual("createdAt", models.SortableTime(filter.FromDate))
+		default:
+			query.SortLessOrEqual("createdAt", models.SortableTime(filter.ToDate))
+		}
+	case filter.OrgID != "":
+		query.Index(JobsTable.Indexes["orgID"]).WhereHashKey("orgID", filter.OrgID)
+	case filter.ClientID != "":
+		query.Index(JobsTable.Indexes["clientID"]).WhereHashKey("clientID", filter.ClientID)
+	case filter.JobStatus != "":
+		query.Index(JobsTable.Indexes["jobStatus"]).WhereHashKey("jobStatus", string(filter.JobStatus))
+	case filter.JobType != "":
+		query.Index(JobsTable.Indexes["jobType"]).WhereHashKey("jobType", string(filter.JobType))
+	default:
+		return nil, nil
+	}
+
+	if filter.OrgID != "" && filter.ClientID != "" {
+		query.Filter(dal.Equal("clientID", filter.ClientID))
+	}
+	if filter.JobStatus != "" && (filter.OrgID != "" || filter.ClientID != "") {
+		query.Filter(dal.Equal("jobStatus", string(filter.JobStatus)))
+	}
+	if filter.JobType != "" && (filter.OrgID != "" || filter.ClientID != "" || filter.JobStatus != "") {
+		query.Filter(dal.Equal("jobType", string(filter.JobType)))
+	}
+	if filter.CreatedBy != "" {
+		query.Filter(dal.Equal("createdData.uID", filter.CreatedBy))
+	}
+	if filter.Priority != "" {
+		query.Filter(priorityCondition(filter.Priority))
+	}
+	if filter.SLAStatus != "" {
+		query.Filter(dal.Equal("sla.status", string(filter.SLAStatus)))
+	}
+	if !filter.IncludeDeleted {
+		query.Filter(notDeleted())
+	}
+	if filter.OrgID == "" {
+		// Date bounds only map onto a range key within an organization
+		if !filter.FromDate.IsZero() {
+			query.Filter(dal.GreaterOrEqual("createdAt", models.SortableTime(filter.FromDate)))
+		}
+		if !filter.ToDate.IsZero() {
+			query.Filter(dal.LessOrEqual("createdAt", models.SortableTime(filter.ToDate)))
+		}
+	}
+
+	return query, nil
+}
+
+// GetScheduledJobs returns the jobs of orgID scheduled to start between from
+// and to inclusive, ordered by start. Deleted and cancelled jobs are left
+// out; a non-empty assignee keeps only the jobs assigned to that user.
+func (r *JobRepository) GetScheduledJobs(ctx context.Context, orgID string, from, to time.Time, assignee string) ([]*models.Job, error) {
+	r.logger.Infof("Getting jobs of organization %s scheduled between %s and %s", orgID, from, to)
+
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	query, err := r.jobs.NewQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.Index(jobsByOrgScheduleIndex).
+		WhereHashKey("orgID", orgID).
+		SortBetween("scheduledStart", from.UTC(), to.UTC()).
+		Filter(notDeleted(), dal.NotEqual("jobStatus", string(models.JobStatusCancelled)))
+	if assignee != "" {
+		query.Filter(dal.Contains("usersAssignedToJob", assignee))
+	}
+
+	jobs, err := r.jobs.Query(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Failed to get scheduled jobs: %v", err)
+		return nil, err
+	}
 
-	r.logger.Infof("Found %d jobs", len(filteredJobs))
-	return filteredJobs, nil
+	r.logger.Infof("Found %d scheduled jobs", len(jobs))
+	return jobs, nil
 }
 
-func (r *JobRepository) UpdateJob(id string, job *models.Job) (*models.Job, error) {
-	ctx := context.Background()
-	r.logger.Infof("Updating job: %s", id)
+// priorityCondition matches the jobs of priority. Jobs stored without a
+// priority are normal.
+func priorityCondition(priority models.JobPriority) dal.Condition {
+	if priority == models.JobPriorityNormal {
+		return dal.Or(dal.Equal("priority", string(priority)), dal.AttributeNotExists("priority"))
+	}
+	return dal.Equal("priority", string(priority))
+}
 
-	if id == "" {
-		return nil, errors.New("job ID is required")
+// GetOpenSLAJobs returns the jobs of orgID tracked against an SLA that are
+// neither completed nor cancelled, breached ones included
+func (r *JobRepository) GetOpenSLAJobs(ctx context.Context, orgID string) ([]*models.Job, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
 	}
 
-	existing, err := r.GetJob(id)
+	jobs, err := r.jobs.ListByIndex(ctx, "orgID", orgID,
+		notDeleted(),
+		dal.AttributeExists("sla"),
+		dal.Not(dal.In("jobStatus", string(models.JobStatusCompleted), string(models.JobStatusCancelled))))
 	if err != nil {
-		return nil, fmt.Errorf("job not found: %w", err)
+		r.logger.Errorf("Failed to get open SLA jobs of organization %s: %v", orgID, err)
+		return nil, err
 	}
-	if len(existing) == 0 {
-		return nil, errors.New("job not found")
+	return jobs, nil
+}
+
+// GetJobsByRecurringJob returns the non-deleted instances of a recurring job
+func (r *JobRepository) GetJobsByRecurringJob(ctx context.Context, recurringJobID string) ([]*models.Job, error) {
+	if recurringJobID == "" {
+		return nil, errors.New("recurring job ID is required")
 	}
 
-	now := time.Now()
-	job.JobID = id
-	job.CreatedAt = existing[0].CreatedAt
-	job.UpdatedAt = now
+	jobs, err := r.jobs.ListByIndex(ctx, "recurringJobID", recurringJobID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get instances of recurring job %s: %v", recurringJobID, err)
+		return nil, err
+	}
+	return jobs, nil
+}
 
-	// Handle job state transitions
-	if job.JobStatus == models.JobStatusInProgress && existing[0].JobStatus != models.JobStatusInProgress {
-		job.JobStartedAt = &now
+// GetJobsByProject returns the non-deleted jobs of a project
+func (r *JobRepository) GetJobsByProject(ctx context.Context, projectID string) ([]*models.Job, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
 	}
-	if job.JobStatus == models.JobStatusCompleted && existing[0].JobStatus != models.JobStatusCompleted {
-		job.JobEndedAt = &now
+
+	jobs, err := r.jobs.ListByIndex(ctx, "projectID", projectID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get jobs of project %s: %v", projectID, err)
+		return nil, err
 	}
+	return jobs, nil
+}
 
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_jobs", job)
+// immutableJobAttributes are never rewritten by UpdateJob. The soft delete
+// attributes are owned by DeleteJob and RestoreJob, the checklist by
+// UpdateChecklistItem, the dependencies by UpdateJobBlockedBy and
+// UpdateJobBlocks. The sign-off is only ever set once.
+var immutableJobAttributes = map[string]bool{
+	"jobID":              true,
+	"createdAt":          true,
+	"createdData":        true,
+	"orgID":              true,
+	"recurringJobID":     true,
+	"occurrenceDate":     true,
+	"checklist":          true,
+	"blockedBy":          true,
+	"blocks":             true,
+	"signOff":            true,
+	deletedDataAttribute: true,
+	PurgeAtAttribute:     true,
+}
+
+// optionalJobAttributes are removed from the item when unset on the job
+var optionalJobAttributes = []string{
+	"cancelledData",
+	"invID",
+	"jobEndedAt",
+	"jobStartedAt",
+	"notes",
+	"paymentID",
+	"qbInfoOnJob",
+	"startedData",
+	"scheduledStart",
+	"scheduledEnd",
+	"arrivalWindow",
+	"estimatedDuration",
+	"timezone",
+	"site",
+	"startCheck",
+	"completionCheck",
+	"geofenceOverride",
+	"priority",
+	"sla",
+	"projectID",
+}
+
+// UpdateJob writes the mutable attributes of job without changing its
+// status, provided the stored job is still in the status of job. Otherwise
+// it returns ErrJobStatusChanged, so edits made from a job read before a
+// concurrent transition cannot revert the transition.
+func (r *JobRepository) UpdateJob(ctx context.Context, id string, job *models.Job) (*models.Job, error) {
+	r.logger.Infof("Updating job: %s", id)
+
+	updatedJob, err := r.updateJob(ctx, id, job, dal.Equal("jobStatus", string(job.JobStatus)))
 	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrJobStatusChanged
+		}
 		r.logger.Errorf("Failed to update job: %v", err)
 		return nil, err
 	}
 
 	r.logger.Infof("Job updated successfully: %s", id)
+	return updatedJob, nil
+}
+
+// UpdateJobStatus writes job like UpdateJob, but only while the stored job
+// is still in status from. Otherwise it returns ErrJobStatusChanged.
+func (r *JobRepository) UpdateJobStatus(ctx context.Context, id string, job *models.Job, from models.JobStatus) (*models.Job, error) {
+	r.logger.Infof("Moving job %s from %s to %s", id, from, job.JobStatus)
+
+	updatedJob, err := r.updateJob(ctx, id, job, dal.Equal("jobStatus", string(from)))
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrJobStatusChanged
+		}
+		r.logger.Errorf("Failed to update job status: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Job %s is now %s", id, updatedJob.JobStatus)
+	return updatedJob, nil
+}
+
+// UpdateChecklistItem replaces the checklist item at index of job id with
+// item. Only that item is written, so technicians can work through the
+// checklist of a job at the same time. The item must still be at index and
+// the job must not be completed or cancelled, otherwise
+// ErrChecklistItemChanged is returned.
+func (r *JobRepository) UpdateChecklistItem(ctx context.Context, id string, index int, item *models.ChecklistItem, updatedBy string) (*models.Job, error) {
+	r.logger.Infof("Updating checklist item %s of job %s", item.ItemID, id)
+
+	if id == "" {
+		return nil, errors.New("job ID is required")
+	}
+	if index < 0 {
+		return nil, errors.New("checklist item index must not be negative")
+	}
+
+	path := fmt.Sprintf("checklist[%d]", index)
+	update := r.jobs.NewUpdate(id).
+		Condition(dal.AttributeExists("jobID"), notDeleted()).
+		Condition(dal.Equal(path+".itemID", item.ItemID)).
+		Condition(dal.Not(dal.In("jobStatus", string(models.JobStatusCompleted), string(models.JobStatusCancelled)))).
+		Set(path, item).
+		Set("updatedAt", time.Now().UTC()).
+		Set("updatedBy", updatedBy)
+
+	job, err := r.jobs.Update(ctx, update)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrChecklistItemChanged
+		}
+		r.logger.Errorf("Failed to update checklist item: %v", err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateJobSLA replaces the SLA of job id with sla, provided the stored
+// SLA still equals from and the job is neither completed nor cancelled.
+// Otherwise it returns ErrJobSLAChanged. Only the SLA is written, so
+// evaluating SLAs does not overwrite edits made in the meantime.
+func (r *JobRepository) UpdateJobSLA(ctx context.Context, id string, sla *models.JobSLA, from *models.JobSLA) (*models.Job, error) {
+	if id == "" {
+		return nil, errors.New("job ID is required")
+	}
+	if sla == nil || from == nil {
+		return nil, errors.New("SLA is required")
+	}
+
+	update := r.jobs.NewUpdate(id).
+		Condition(dal.AttributeExists("jobID"), notDeleted()).
+		Condition(dal.Equal("sla", from)).
+		Condition(dal.Not(dal.In("jobStatus", string(models.JobStatusCompleted), string(models.JobStatusCancelled)))).
+		Set("sla", sla)
+
+	job, err := r.jobs.Update(ctx, update)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrJobSLAChanged
+		}
+		r.logger.Errorf("Failed to update SLA of job %s: %v", id, err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateJobBlockedBy replaces the jobs job id waits for with blockedBy,
+// provided the stored list still equals from. Otherwise it returns
+// ErrJobDependenciesChanged.
+func (r *JobRepository) UpdateJobBlockedBy(ctx context.Context, id string, blockedBy []string, from []string) (*models.Job, error) {
+	return r.updateDependencies(ctx, id, "blockedBy", blockedBy, from)
+}
+
+// UpdateJobBlocks replaces the jobs waiting for job id with blocks, provided
+// the stored list still equals from. Otherwise it returns
+// ErrJobDependenciesChanged.
+func (r *JobRepository) UpdateJobBlocks(ctx context.Context, id string, blocks []string, from []string) (*models.Job, error) {
+	return r.updateDependencies(ctx, id, "blocks", blocks, from)
+}
+
+// updateDependencies writes only the dependency list attribute of job id,
+// removing it when ids is empty
+func (r *JobRepository) updateDependencies(ctx context.Context, id string, attribute string, ids []string, from []string) (*models.Job, error) {
+	if id == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	update := r.jobs.NewUpdate(id).
+		Condition(dal.AttributeExists("jobID"), notDeleted()).
+		Set("updatedAt", time.Now().UTC())
+	// Empty lists are never stored
+	if len(from) == 0 {
+		update.Condition(dal.AttributeNotExists(attribute))
+	} else {
+		update.Condition(dal.Equal(attribute, from))
+	}
+	if len(ids) == 0 {
+		update.Remove(attribute)
+	} else {
+		update.Set(attribute, ids)
+	}
+
+	job, err := r.jobs.Update(ctx, update)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrJobDependenciesChanged
+		}
+		r.logger.Errorf("Failed to update %s of job %s: %v", attribute, id, err)
+		return nil, err
+	}
 	return job, nil
 }
 
-func (r *JobRepository) DeleteJob(id string) error {
-	ctx := context.Background()
+// updateJob writes the mutable attributes of job to the existing,
+// non-deleted job id, provided conditions hold
+func (r *JobRepository) updateJob(ctx context.Context, id string, job *models.Job, conditions ...dal.Condition) (*models.Job, error) {
+	if id == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	now := time.Now().UTC()
+	job.JobID = id
+	job.UpdatedAt = now
+
+	item, err := attributevalue.MarshalMap(job)
+	if err != nil {
+		r.logger.Errorf("Failed to marshal job: %v", err)
+		return nil, err
+	}
+
+	update := r.jobs.NewUpdate(id).
+		Condition(dal.AttributeExists("jobID"), notDeleted()).
+		Condition(conditions...)
+
+	attributes := make([]string, 0, len(item))
+	for attribute := range item {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	for _, attribute := range attributes {
+		if immutableJobAttributes[attribute] {
+			continue
+		}
+		update.Set(attribute, item[attribute])
+	}
+
+	// Record state transition timestamps only the first time they happen
+	transitions := make(map[string]bool)
+	if job.JobStatus == models.JobStatusInProgress && job.JobStartedAt == nil {
+		update.SetIfNotExists("jobStartedAt", now)
+		transitions["jobStartedAt"] = true
+	}
+	if job.JobStatus == models.JobStatusCompleted && job.JobEndedAt == nil {
+		update.SetIfNotExists("jobEndedAt", now)
+		transitions["jobEndedAt"] = true
+	}
+	if signOff, ok := item["signOff"]; ok {
+		update.SetIfNotExists("signOff", signOff)
+	}
+
+	for _, attribute := range optionalJobAttributes {
+		if _, ok := item[attribute]; !ok && !transitions[attribute] {
+			update.Remove(attribute)
+		}
+	}
+
+	return r.jobs.Update(ctx, update)
+}
+
+// DeleteJob soft-deletes a job. It stays hidden from reads until it is
+// restored or purged after the retention period.
+func (r *JobRepository) DeleteJob(ctx context.Context, id string, deletedData *models.DeletedData) error {
 	r.logger.Infof("Deleting job: %s", id)
 
 	if id == "" {
 		return errors.New("job ID is required")
 	}
 
-	err := r.db.DeleteItem(ctx, r.config.DynamoDBTablePrefix+"_jobs", "jobID", id)
+	err := r.jobs.SoftDelete(ctx, id, deletedData)
 	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrJobNotFound
+		}
 		r.logger.Errorf("Failed to delete job: %v", err)
 		return err
 	}
@@ -202,18 +581,28 @@ func (r *JobRepository) DeleteJob(id string) error {
 	return nil
 }
 
-func (r *JobRepository) determineKeyType(key string) (keyType, indexName, keyName string) {
-	uuidPattern := `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
-	isUUID, _ := regexp.MatchString(uuidPattern, strings.ToLower(key))
+// RestoreJob brings back a soft-deleted job
+func (r *JobRepository) RestoreJob(ctx context.Context, id string) (*models.Job, error) {
+	r.logger.Infof("Restoring job: %s", id)
 
-	if isUUID {
-		return "id", "", "jobID"
-	} else {
-		// Assume it's a client ID or organization ID
-		return "clientID", "clientID-index", "clientID"
+	if id == "" {
+		return nil, errors.New("job ID is required")
 	}
+
+	job, err := r.jobs.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return nil, ErrDeletedJobNotFound
+		}
+		r.logger.Errorf("Failed to restore job: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Job restored successfully: %s", id)
+	return job, nil
 }
 
+// applyAdditionalFilters filters scanned jobs in memory when no index applies
 func (r *JobRepository) applyAdditionalFilters(jobs []*models.Job, filter *models.JobFilter) []*models.Job {
 	if filter == nil {
 		return jobs
@@ -244,8 +633,15 @@ func (r *JobRepository) applyAdditionalFilters(jobs []*models.Job, filter *model
 			continue
 		}
 
+		if filter.Priority != "" && job.Priority.OrDefault() != filter.Priority {
+			continue
+		}
+		if filter.SLAStatus != "" && (job.SLA == nil || job.SLA.Status != filter.SLAStatus) {
+			continue
+		}
+
 		filtered = append(filtered, job)
 	}
 
 	return filtered
-}
\ No newline at end of file
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var equalCondition = regexp.MustCompile(`(#\w+) = (:\w+)`)
+
+// fakeJobStore holds the status of a single job and fails updates whose
+// jobStatus condition does not match it, like DynamoDB would
+type fakeJobStore struct {
+	dal.DatabaseClientInterface
+	status models.JobStatus
+	writes int
+}
+
+func (f *fakeJobStore) Update(ctx context.Context, update *dal.UpdateBuilder, result interface{}) error {
+	input, err := update.Build()
+	if err != nil {
+		return err
+	}
+	if input.ConditionExpression != nil {
+		for _, match := range equalCondition.FindAllStringSubmatch(*input.ConditionExpression, -1) {
+			if input.ExpressionAttributeNames[match[1]] != "jobStatus" {
+				continue
+			}
+			value, ok := input.ExpressionAttributeValues[match[2]].(*types.AttributeValueMemberS)
+			if !ok || value.Value != string(f.status) {
+				return &types.ConditionalCheckFailedException{}
+			}
+		}
+	}
+
+	f.writes++
+	for _, operation := range update.Operations() {
+		if operation.Path == "jobStatus" {
+			if status, ok := operation.Value.(*types.AttributeValueMemberS); ok {
+				f.status = models.JobStatus(status.Value)
+			}
+		}
+	}
+	job := result.(*models.Job)
+	job.JobID = input.Key["jobID"].(*types.AttributeValueMemberS).Value
+	job.JobStatus = f.status
+	return nil
+}
+
+func newTestJobRepository(store *fakeJobStore) *JobRepository {
+	cfg := &models.Config{DynamoDBTablePrefix: "ff", TenancyMode: models.TenancyShared}
+	return NewJobRepository(store, cfg, logger.NewLogger("error", "json"))
+}
+
+// TestUpdateJobKeepsConcurrentTransition edits a job read before it was put
+// on hold; the edit must not move the job back out of hold
+func TestUpdateJobKeepsConcurrentTransition(t *testing.T) {
+	store := &fakeJobStore{status: models.JobStatusOnHold}
+	repo := newTestJobRepository(store)
+
+	stale := &models.Job{JobID: "job-1", OrgID: "org-1", JobStatus: models.JobStatusInProgress, JobsName: "Renamed"}
+	if _, err := repo.UpdateJob(context.Background(), "job-1", stale); !errors.Is(err, ErrJobStatusChanged) {
+		t.Fatalf("UpdateJob() of a stale job error = %v, want ErrJobStatusChanged", err)
+	}
+	if store.status != models.JobStatusOnHold || store.writes != 0 {
+		t.Fatalf("stored status = %s after %d writes, want on_hold after none", store.status, store.writes)
+	}
+
+	current := &models.Job{JobID: "job-1", OrgID: "org-1", JobStatus: models.JobStatusOnHold, JobsName: "Renamed"}
+	job, err := repo.UpdateJob(context.Background(), "job-1", current)
+	if err != nil {
+		t.Fatalf("UpdateJob() error = %v", err)
+	}
+	if job.JobStatus != models.JobStatusOnHold {
+		t.Fatalf("UpdateJob() status = %s, want on_hold", job.JobStatus)
+	}
+}
+
+func TestUpdateJobStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  models.JobStatus
+		from    models.JobStatus
+		to      models.JobStatus
+		wantErr error
+	}{
+		{name: "expected status", stored: models.JobStatusInProgress, from: models.JobStatusInProgress, to: models.JobStatusCompleted},
+		{name: "completed concurrently", stored: models.JobStatusCompleted, from: models.JobStatusInProgress, to: models.JobStatusOnHold, wantErr: ErrJobStatusChanged},
+		{name: "put on hold concurrently", stored: models.JobStatusOnHold, from: models.JobStatusInProgress, to: models.JobStatusCompleted, wantErr: ErrJobStatusChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeJobStore{status: tt.stored}
+			repo := newTestJobRepository(store)
+
+			job := &models.Job{JobID: "job-1", OrgID: "org-1", JobStatus: tt.to}
+			_, err := repo.UpdateJobStatus(context.Background(), "job-1", job, tt.from)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateJobStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.stored
+			}
+			if store.status != want {
+				t.Fatalf("stored status = %s, want %s", store.status, want)
+			}
+		})
+	}
+}
+
+// TestBuildFilterQueryCreatedAtRange compares the createdAt range key in the
+// layout jobs store it in, whatever the offset of the bounds
+func TestBuildFilterQueryCreatedAtRange(t *testing.T) {
+	repo := newTestJobRepository(&fakeJobStore{})
+	berlin := time.FixedZone("CET", 3600)
+	filter := &models.JobFilter{
+		OrgID:    "org-1",
+		FromDate: time.Date(2026, 3, 1, 1, 0, 0, 0, berlin),
+		ToDate:   time.Date(2026, 3, 31, 23, 59, 59, 500000000, time.UTC),
+	}
+
+	query, err := repo.buildFilterQuery(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("buildFilterQuery() error = %v", err)
+	}
+	input, err := query.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	bounds := map[string]bool{}
+	for _, value := range input.ExpressionAttributeValues {
+		if s, ok := value.(*types.AttributeValueMemberS); ok {
+			bounds[s.Value] = true
+		}
+	}
+	for _, want := range []string{"2026-03-01T00:00:00.000000000Z", "2026-03-31T23:59:59.500000000Z"} {
+		if !bounds[want] {
+			t.Errorf("query bounds %v do not include %s", bounds, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrJobTemplateNotFound is returned when a job template does not exist or
+// is deleted
+var ErrJobTemplateNotFound = fmt.Errorf("job template %w", dal.ErrNotFound)
+
+// JobTemplateRepository implements JobTemplateRepositoryInterface
+type JobTemplateRepository struct {
+	templates *Repository[models.JobTemplate]
+	logger    logger.Logger
+}
+
+func NewJobTemplateRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *JobTemplateRepository {
+	return &JobTemplateRepository{
+		templates: NewTypedRepository[models.JobTemplate](db, cfg, JobTemplatesTable),
+		logger:    log,
+	}
+}
+
+func (r *JobTemplateRepository) CreateJobTemplate(ctx context.Context, template *models.JobTemplate) (*models.JobTemplate, error) {
+	r.logger.Infof("Creating job template: %s", template.Name)
+
+	now := time.Now().UTC()
+	template.TemplateID = utils.GenerateUUID()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	err := r.templates.Put(ctx, template)
+	if err != nil {
+		r.logger.Errorf("Failed to create job template: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Job template created successfully: %s", template.TemplateID)
+	return template, nil
+}
+
+func (r *JobTemplateRepository) GetJobTemplate(ctx context.Context, id string) (*models.JobTemplate, error) {
+	if id == "" {
+		return nil, errors.New("job template ID is required")
+	}
+
+	template, err := r.templates.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrJobTemplateNotFound
+		}
+		r.logger.Errorf("Failed to get job template: %v", err)
+		return nil, fmt.Errorf("failed to get job template: %w", err)
+	}
+
+	if template.TemplateID == "" || template.DeletedData != nil {
+		return nil, ErrJobTemplateNotFound
+	}
+	return template, nil
+}
+
+// GetJobTemplatesByOrganization returns the job templates of orgID
+func (r *JobTemplateRepository) GetJobTemplatesByOrganization(ctx context.Context, orgID string) ([]*models.JobTemplate, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	templates, err := r.templates.ListByIndex(ctx, "orgID", orgID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get job templates: %v", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+// UpdateJobTemplate replaces the stored template with template
+func (r *JobTemplateRepository) UpdateJobTemplate(ctx context.Context, id string, template *models.JobTemplate) (*models.JobTemplate, error) {
+	r.logger.Infof("Updating job template: %s", id)
+
+	if id == "" {
+		return nil, errors.New("job template ID is required")
+	}
+
+	template.TemplateID = id
+	template.UpdatedAt = time.Now().UTC()
+
+	err := r.templates.Put(ctx, template)
+	if err != nil {
+		r.logger.Errorf("Failed to update job template: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Job template updated successfully: %s", id)
+	return template, nil
+}
+
+// DeleteJobTemplate soft-deletes a job template. Jobs created from it are
+// kept.
+func (r *JobTemplateRepository) DeleteJobTemplate(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting job template: %s", id)
+
+	if id == "" {
+		return errors.New("job template ID is required")
+	}
+
+	err := r.templates.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrJobTemplateNotFound
+		}
+		r.logger.Errorf("Failed to delete job template: %v", err)
+		return err
+	}
+
+	r.logger.Infof("Job template deleted successfully: %s", id)
+	return nil
+}
@@ -8,33 +8,40 @@ import (
 	"fieldfuze-backend/utils"
 	"fieldfuze-backend/utils/logger"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 )
 
+// ErrOrganizationNotFound is returned when an organization does not exist
+// or is deleted
+var ErrOrganizationNotFound = fmt.Errorf("organization %w", dal.ErrNotFound)
+
+// ErrDeletedOrganizationNotFound is returned by RestoreOrganization when
+// there is no deleted organization to restore
+var ErrDeletedOrganizationNotFound = fmt.Errorf("deleted organization %w", dal.ErrNotFound)
+
+// ErrOrganizationExists is returned when creating an organization with the
+// name of another organization
+var ErrOrganizationExists = errors.New("organization with this name already exists")
+
 // OrganizationRepository implements OrganizationRepositoryInterface
 type OrganizationRepository struct {
-	db     dal.DatabaseClientInterface
-	config *models.Config
-	logger logger.Logger
+	organizations *Repository[models.Organization]
+	logger        logger.Logger
 }
 
 func NewOrganizationRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *OrganizationRepository {
 	return &OrganizationRepository{
-		db:     db,
-		config: cfg,
-		logger: log,
+		organizations: NewTypedRepository[models.Organization](db, cfg, OrganizationsTable),
+		logger:        log,
 	}
 }
 
 func (r *OrganizationRepository) CreateOrganization(ctx context.Context, organization *models.Organization) (*models.Organization, error) {
 	r.logger.Infof("Creating organization: %s", organization.Name)
 
-	existingOrg := &models.Organization{}
-	err := r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_organization", "name-index", "name", organization.Name, &[]*models.Organization{existingOrg})
+	existingOrg, err := r.organizations.ByIndex(ctx, "name", organization.Name)
 	if err == nil && existingOrg.ID != "" {
-		return nil, errors.New("organization with this name already exists")
+		return nil, ErrOrganizationExists
 	}
 
 	now := time.Now()
@@ -43,7 +50,7 @@ func (r *OrganizationRepository) CreateOrganization(ctx context.Context, organiz
 
 	fmt.Println("organization ::::", dal.PrintPrettyJSON(organization))
 
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_organization", organization)
+	err = r.organizations.Put(ctx, organization)
 	if err != nil {
 		r.logger.Errorf("Failed to create organization: %v", err)
 		return nil, err
@@ -53,75 +60,70 @@ func (r *OrganizationRepository) CreateOrganization(ctx context.Context, organiz
 	return organization, nil
 }
 
-func (r *OrganizationRepository) GetOrganization(key string) ([]*models.Organization, error) {
-	ctx := context.Background()
+// GetOrganizations returns every organization that is not deleted
+func (r *OrganizationRepository) GetOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	organizations, err := r.organizations.Scan(ctx, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to scan organizations table: %v", err)
+		return nil, fmt.Errorf("failed to get all organizations: %w", err)
+	}
+	return organizations, nil
+}
 
-	if key == "" {
+// GetOrganizationByID reads an organization by ID
+func (r *OrganizationRepository) GetOrganizationByID(ctx context.Context, id string) (*models.Organization, error) {
+	if id == "" {
 		return nil, errors.New("organization ID is required")
 	}
 
-	r.logger.Infof("Organization checking for: %s", key)
-
-	organization := models.Organization{}
-	keyType, indexName, keyName := r.determineKeyType(key)
-
-	var config models.QueryConfig
+	organization, err := r.organizations.ByID(ctx, id)
+	return r.found(organization, r.organizations.KeyName(), err)
+}
 
-	if keyType == "id" {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_organization",
-			KeyName:   "id",
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
-	} else {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_organization",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
+// GetOrganizationByName reads an organization through the name index
+func (r *OrganizationRepository) GetOrganizationByName(ctx context.Context, name string) (*models.Organization, error) {
+	if name == "" {
+		return nil, errors.New("organization name is required")
 	}
 
-	r.logger.Infof("Querying %s table with %s: %s", r.config.DynamoDBTablePrefix, keyName, key)
+	organization, err := r.organizations.ByIndex(ctx, "name", name)
+	return r.found(organization, "name", err)
+}
 
-	err := r.db.GetItem(ctx, config, &organization)
+// found returns the organization read by keyName, or an error if the read
+// failed or matched no live organization
+func (r *OrganizationRepository) found(organization *models.Organization, keyName string, err error) (*models.Organization, error) {
 	if err != nil {
 		r.logger.Errorf("Failed to get organization by %s: %v", keyName, err)
 		return nil, fmt.Errorf("failed to get organization by %s: %w", keyName, err)
 	}
 
-	if organization.ID == "" {
-		return nil, errors.New("organization not found")
+	if organization.ID == "" || organization.DeletedData != nil {
+		return nil, ErrOrganizationNotFound
 	}
 
 	r.logger.Infof("Organization found: %s", organization.ID)
-	return []*models.Organization{&organization}, nil
+	return organization, nil
 }
 
-func (r *OrganizationRepository) UpdateOrganization(id string, organization *models.Organization) (*models.Organization, error) {
-	ctx := context.Background()
+func (r *OrganizationRepository) UpdateOrganization(ctx context.Context, id string, organization *models.Organization) (*models.Organization, error) {
 	r.logger.Infof("Updating organization: %s", id)
 
 	if id == "" {
 		return nil, errors.New("organization ID is required")
 	}
 
-	existing, err := r.GetOrganization(id)
+	existing, err := r.GetOrganizationByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("organization not found: %w", err)
 	}
-	if len(existing) == 0 {
-		return nil, errors.New("organization not found")
-	}
 
 	now := time.Now()
 	organization.ID = id
-	organization.CreatedAt = existing[0].CreatedAt
+	organization.CreatedAt = existing.CreatedAt
 	organization.UpdatedAt = now
 
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_organization", organization)
+	err = r.organizations.Put(ctx, organization)
 	if err != nil {
 		r.logger.Errorf("Failed to update organization: %v", err)
 		return nil, err
@@ -131,16 +133,19 @@ func (r *OrganizationRepository) UpdateOrganization(id string, organization *mod
 	return organization, nil
 }
 
-func (r *OrganizationRepository) DeleteOrganization(id string) error {
-	ctx := context.Background()
+// DeleteOrganization soft-deletes an organization
+func (r *OrganizationRepository) DeleteOrganization(ctx context.Context, id string, deletedData *models.DeletedData) error {
 	r.logger.Infof("Deleting organization: %s", id)
 
 	if id == "" {
 		return errors.New("organization ID is required")
 	}
 
-	err := r.db.DeleteItem(ctx, r.config.DynamoDBTablePrefix+"_organization", "id", id)
+	err := r.organizations.SoftDelete(ctx, id, deletedData)
 	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrOrganizationNotFound
+		}
 		r.logger.Errorf("Failed to delete organization: %v", err)
 		return err
 	}
@@ -149,13 +154,23 @@ func (r *OrganizationRepository) DeleteOrganization(id string) error {
 	return nil
 }
 
-func (r *OrganizationRepository) determineKeyType(key string) (keyType, indexName, keyName string) {
-	uuidPattern := `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
-	isUUID, _ := regexp.MatchString(uuidPattern, strings.ToLower(key))
+// RestoreOrganization brings back a soft-deleted organization
+func (r *OrganizationRepository) RestoreOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	r.logger.Infof("Restoring organization: %s", id)
+
+	if id == "" {
+		return nil, errors.New("organization ID is required")
+	}
 
-	if isUUID {
-		return "id", "", "id"
-	} else {
-		return "name", "name-index", "name"
+	organization, err := r.organizations.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return nil, ErrDeletedOrganizationNotFound
+		}
+		r.logger.Errorf("Failed to restore organization: %v", err)
+		return nil, err
 	}
+
+	r.logger.Infof("Organization restored successfully: %s", id)
+	return organization, nil
 }
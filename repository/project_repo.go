@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrProjectNotFound is returned when a project does not exist or is deleted
+var ErrProjectNotFound = fmt.Errorf("project %w", dal.ErrNotFound)
+
+// ProjectRepository implements ProjectRepositoryInterface
+type ProjectRepository struct {
+	projects *Repository[models.Project]
+	logger   logger.Logger
+}
+
+func NewProjectRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *ProjectRepository {
+	return &ProjectRepository{
+		projects: NewTypedRepository[models.Project](db, cfg, ProjectsTable),
+		logger:   log,
+	}
+}
+
+func (r *ProjectRepository) CreateProject(ctx context.Context, project *models.Project) (*models.Project, error) {
+	r.logger.Infof("Creating project: %s", project.Name)
+
+	now := time.Now().UTC()
+	project.ProjectID = utils.GenerateUUID()
+	project.CreatedAt = now
+	project.UpdatedAt = now
+
+	err := r.projects.Put(ctx, project)
+	if err != nil {
+		r.logger.Errorf("Failed to create project: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Project created successfully: %s", project.ProjectID)
+	return project, nil
+}
+
+func (r *ProjectRepository) GetProject(ctx context.Context, id string) (*models.Project, error) {
+	if id == "" {
+		return nil, errors.New("project ID is required")
+	}
+
+	project, err := r.projects.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrProjectNotFound
+		}
+		r.logger.Errorf("Failed to get project: %v", err)
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if project.ProjectID == "" || project.DeletedData != nil {
+		return nil, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// GetProjectsByOrganization returns the projects of orgID
+func (r *ProjectRepository) GetProjectsByOrganization(ctx context.Context, orgID string) ([]*models.Project, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	projects, err := r.projects.ListByIndex(ctx, "orgID", orgID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get projects: %v", err)
+		return nil, err
+	}
+	return projects, nil
+}
+
+// UpdateProject replaces the stored project with project
+func (r *ProjectRepository) UpdateProject(ctx context.Context, id string, project *models.Project) (*models.Project, error) {
+	r.logger.Infof("Updating project: %s", id)
+
+	if id == "" {
+		return nil, errors.New("project ID is required")
+	}
+
+	project.ProjectID = id
+	project.UpdatedAt = time.Now().UTC()
+
+	err := r.projects.Put(ctx, project)
+	if err != nil {
+		r.logger.Errorf("Failed to update project: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Project updated successfully: %s", id)
+	return project, nil
+}
+
+// DeleteProject soft-deletes a project. Its jobs are kept.
+func (r *ProjectRepository) DeleteProject(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting project: %s", id)
+
+	if id == "" {
+		return errors.New("project ID is required")
+	}
+
+	err := r.projects.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrProjectNotFound
+		}
+		r.logger.Errorf("Failed to delete project: %v", err)
+		return err
+	}
+
+	r.logger.Infof("Project deleted successfully: %s", id)
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrRecurringJobNotFound is returned when a recurring job does not exist
+// or is deleted
+var ErrRecurringJobNotFound = fmt.Errorf("recurring job %w", dal.ErrNotFound)
+
+// RecurringJobRepository implements RecurringJobRepositoryInterface
+type RecurringJobRepository struct {
+	recurringJobs *Repository[models.RecurringJob]
+	logger        logger.Logger
+}
+
+func NewRecurringJobRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *RecurringJobRepository {
+	return &RecurringJobRepository{
+		recurringJobs: NewTypedRepository[models.RecurringJob](db, cfg, RecurringJobsTable),
+		logger:        log,
+	}
+}
+
+func (r *RecurringJobRepository) CreateRecurringJob(ctx context.Context, recurringJob *models.RecurringJob) (*models.RecurringJob, error) {
+	r.logger.Infof("Creating recurring job: %s", recurringJob.JobsName)
+
+	now := time.Now().UTC()
+	recurringJob.RecurringJobID = utils.GenerateUUID()
+	recurringJob.CreatedAt = now
+	recurringJob.UpdatedAt = now
+
+	err := r.recurringJobs.Put(ctx, recurringJob)
+	if err != nil {
+		r.logger.Errorf("Failed to create recurring job: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Recurring job created successfully: %s", recurringJob.RecurringJobID)
+	return recurringJob, nil
+}
+
+func (r *RecurringJobRepository) GetRecurringJob(ctx context.Context, id string) (*models.RecurringJob, error) {
+	if id == "" {
+		return nil, errors.New("recurring job ID is required")
+	}
+
+	recurringJob, err := r.recurringJobs.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrRecurringJobNotFound
+		}
+		r.logger.Errorf("Failed to get recurring job: %v", err)
+		return nil, fmt.Errorf("failed to get recurring job: %w", err)
+	}
+
+	if recurringJob.RecurringJobID == "" || recurringJob.DeletedData != nil {
+		return nil, ErrRecurringJobNotFound
+	}
+	return recurringJob, nil
+}
+
+// GetRecurringJobsByOrganization returns the recurring jobs of orgID
+func (r *RecurringJobRepository) GetRecurringJobsByOrganization(ctx context.Context, orgID string) ([]*models.RecurringJob, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	recurringJobs, err := r.recurringJobs.ListByIndex(ctx, "orgID", orgID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get recurring jobs: %v", err)
+		return nil, err
+	}
+	return recurringJobs, nil
+}
+
+// GetActiveRecurringJobs returns the recurring jobs of orgID that still
+// materialise instances
+func (r *RecurringJobRepository) GetActiveRecurringJobs(ctx context.Context, orgID string) ([]*models.RecurringJob, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	recurringJobs, err := r.recurringJobs.ListByIndex(ctx, "orgID", orgID,
+		notDeleted(),
+		dal.Equal("status", string(models.RecurringJobStatusActive)))
+	if err != nil {
+		r.logger.Errorf("Failed to get active recurring jobs of organization %s: %v", orgID, err)
+		return nil, err
+	}
+	return recurringJobs, nil
+}
+
+// UpdateRecurringJob replaces the stored template with recurringJob
+func (r *RecurringJobRepository) UpdateRecurringJob(ctx context.Context, id string, recurringJob *models.RecurringJob) (*models.RecurringJob, error) {
+	r.logger.Infof("Updating recurring job: %s", id)
+
+	if id == "" {
+		return nil, errors.New("recurring job ID is required")
+	}
+
+	recurringJob.RecurringJobID = id
+	recurringJob.UpdatedAt = time.Now().UTC()
+
+	err := r.recurringJobs.Put(ctx, recurringJob)
+	if err != nil {
+		r.logger.Errorf("Failed to update recurring job: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Recurring job updated successfully: %s", id)
+	return recurringJob, nil
+}
+
+// SetMaterializedThrough records that the instances of recurring job id
+// exist up to through
+func (r *RecurringJobRepository) SetMaterializedThrough(ctx context.Context, id string, through time.Time) error {
+	return r.recurringJobs.SetAttributes(ctx, id, map[string]interface{}{
+		"materializedThrough": through.UTC(),
+	})
+}
+
+// DeleteRecurringJob soft-deletes a recurring job
+func (r *RecurringJobRepository) DeleteRecurringJob(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting recurring job: %s", id)
+
+	if id == "" {
+		return errors.New("recurring job ID is required")
+	}
+
+	err := r.recurringJobs.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrRecurringJobNotFound
+		}
+		r.logger.Errorf("Failed to delete recurring job: %v", err)
+		return err
+	}
+
+	r.logger.Infof("Recurring job deleted successfully: %s", id)
+	return nil
+}
@@ -3,45 +3,163 @@ package repository
 import (
 	"fieldfuze-backend/dal"
 	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/cache"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/search"
+	"time"
 )
 
-// Repository implements RepositoryContainerInterface
-type Repository struct {
+// Container implements RepositoryContainerInterface
+type Container struct {
 	userRepository         UserRepositoryInterface
 	roleRepository         RoleRepositoryInterface
 	organizationRepository OrganizationRepositoryInterface
 	jobRepository          JobRepositoryInterface
+	recurringJobRepository RecurringJobRepositoryInterface
+	availabilityRepository AvailabilityRepositoryInterface
+	checklistRepository    ChecklistRepositoryInterface
+	attachmentRepository   AttachmentRepositoryInterface
+	activityRepository     ActivityRepositoryInterface
+	timeEntryRepository    TimeEntryRepositoryInterface
+	timesheetRepository    TimesheetRepositoryInterface
+	slaPolicyRepository    SLAPolicyRepositoryInterface
+	jobTemplateRepository  JobTemplateRepositoryInterface
+	jobImportRepository    JobImportRepositoryInterface
+	projectRepository      ProjectRepositoryInterface
+
+	// jobSearchIndex is kept in sync by the job repository
+	jobSearchIndex search.Index
+}
+
+// NewRepository creates a new repository container with all dependencies
+// injected. Jobs written through it are indexed in jobIndex, which may be
+// nil.
+func NewRepository(dalContainer dal.DALContainerInterface, cfg *models.Config, log logger.Logger, jobIndex search.Index) RepositoryContainerInterface {
+	return NewRepositoryWithSharedCache(dalContainer, cfg, log, nil, jobIndex)
 }
 
-// NewRepository creates a new repository container with all dependencies injected
-func NewRepository(dalContainer dal.DALContainerInterface, cfg *models.Config, log logger.Logger) RepositoryContainerInterface {
+// NewRepositoryWithSharedCache creates a repository container whose caches
+// use shared as their second tier. shared and jobIndex may be nil.
+func NewRepositoryWithSharedCache(dalContainer dal.DALContainerInterface, cfg *models.Config, log logger.Logger, shared cache.Store, jobIndex search.Index) RepositoryContainerInterface {
 	dbClient := dalContainer.GetDatabaseClient()
-	
-	return &Repository{
-		userRepository:         NewUserRepository(dbClient, cfg, log),
-		roleRepository:         NewRoleRepository(dbClient, cfg, log),
+
+	var jobRepository JobRepositoryInterface = NewJobRepository(dbClient, cfg, log)
+	if jobIndex != nil {
+		jobRepository = NewIndexedJobRepository(jobRepository, jobIndex, log)
+	}
+
+	var userRepository UserRepositoryInterface = NewUserRepository(dbClient, cfg, log)
+	var roleRepository RoleRepositoryInterface = NewRoleRepository(dbClient, cfg, log)
+
+	if cfg.RepositoryCacheEnabled {
+		userTTL := time.Duration(cfg.UserCacheTTLSeconds) * time.Second
+		roleTTL := time.Duration(cfg.RoleCacheTTLSeconds) * time.Second
+
+		userRepository = NewCachedUserRepository(userRepository,
+			cache.New[models.User]("users", cfg.RepositoryCacheSize, userTTL, shared, log))
+		roleRepository = NewCachedRoleRepository(roleRepository,
+			cache.New[models.Role]("roles", cfg.RepositoryCacheSize, roleTTL, shared, log),
+			cache.New[models.RoleAssignment]("role_assignments", cfg.RepositoryCacheSize, roleTTL, shared, log))
+	}
+
+	return &Container{
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
 		organizationRepository: NewOrganizationRepository(dbClient, cfg, log),
-		jobRepository:          NewJobRepository(dbClient, cfg, log),
+		jobRepository:          jobRepository,
+		recurringJobRepository: NewRecurringJobRepository(dbClient, cfg, log),
+		availabilityRepository: NewAvailabilityRepository(dbClient, cfg, log),
+		checklistRepository:    NewChecklistRepository(dbClient, cfg, log),
+		attachmentRepository:   NewAttachmentRepository(dbClient, cfg, log),
+		activityRepository:     NewActivityRepository(dbClient, cfg, log),
+		timeEntryRepository:    NewTimeEntryRepository(dbClient, cfg, log),
+		timesheetRepository:    NewTimesheetRepository(dbClient, cfg, log),
+		slaPolicyRepository:    NewSLAPolicyRepository(dbClient, cfg, log),
+		jobTemplateRepository:  NewJobTemplateRepository(dbClient, cfg, log),
+		jobImportRepository:    NewJobImportRepository(dbClient, cfg, log),
+		projectRepository:      NewProjectRepository(dbClient, cfg, log),
+		jobSearchIndex:         jobIndex,
 	}
 }
 
 // GetUserRepository returns the user repository interface
-func (r *Repository) GetUserRepository() UserRepositoryInterface {
+func (r *Container) GetUserRepository() UserRepositoryInterface {
 	return r.userRepository
 }
 
 // GetRoleRepository returns the role repository interface
-func (r *Repository) GetRoleRepository() RoleRepositoryInterface {
+func (r *Container) GetRoleRepository() RoleRepositoryInterface {
 	return r.roleRepository
 }
 
 // GetOrganizationRepository returns the organization repository interface
-func (r *Repository) GetOrganizationRepository() OrganizationRepositoryInterface {
+func (r *Container) GetOrganizationRepository() OrganizationRepositoryInterface {
 	return r.organizationRepository
 }
 
 // GetJobRepository returns the job repository interface
-func (r *Repository) GetJobRepository() JobRepositoryInterface {
+func (r *Container) GetJobRepository() JobRepositoryInterface {
 	return r.jobRepository
 }
+
+// GetRecurringJobRepository returns the recurring job repository interface
+func (r *Container) GetRecurringJobRepository() RecurringJobRepositoryInterface {
+	return r.recurringJobRepository
+}
+
+// GetAvailabilityRepository returns the technician availability repository
+// interface
+func (r *Container) GetAvailabilityRepository() AvailabilityRepositoryInterface {
+	return r.availabilityRepository
+}
+
+// GetChecklistRepository returns the checklist template repository interface
+func (r *Container) GetChecklistRepository() ChecklistRepositoryInterface {
+	return r.checklistRepository
+}
+
+// GetAttachmentRepository returns the job attachment repository interface
+func (r *Container) GetAttachmentRepository() AttachmentRepositoryInterface {
+	return r.attachmentRepository
+}
+
+// GetActivityRepository returns the job activity repository interface
+func (r *Container) GetActivityRepository() ActivityRepositoryInterface {
+	return r.activityRepository
+}
+
+// GetTimeEntryRepository returns the time entry repository interface
+func (r *Container) GetTimeEntryRepository() TimeEntryRepositoryInterface {
+	return r.timeEntryRepository
+}
+
+// GetTimesheetRepository returns the timesheet repository interface
+func (r *Container) GetTimesheetRepository() TimesheetRepositoryInterface {
+	return r.timesheetRepository
+}
+
+// GetSLAPolicyRepository returns the SLA policy repository interface
+func (r *Container) GetSLAPolicyRepository() SLAPolicyRepositoryInterface {
+	return r.slaPolicyRepository
+}
+
+// GetJobTemplateRepository returns the job template repository interface
+func (r *Container) GetJobTemplateRepository() JobTemplateRepositoryInterface {
+	return r.jobTemplateRepository
+}
+
+// GetJobImportRepository returns the job import repository interface
+func (r *Container) GetJobImportRepository() JobImportRepositoryInterface {
+	return r.jobImportRepository
+}
+
+// GetProjectRepository returns the project repository interface
+func (r *Container) GetProjectRepository() ProjectRepositoryInterface {
+	return r.projectRepository
+}
+
+// GetJobSearchIndex returns the index of the jobs written through the job
+// repository, or nil when jobs are not indexed
+func (r *Container) GetJobSearchIndex() search.Index {
+	return r.jobSearchIndex
+}
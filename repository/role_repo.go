@@ -7,35 +7,49 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/utils"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 
 	"fieldfuze-backend/utils/logger"
 )
 
+// ErrRoleNotFound is returned when a role does not exist or is deleted
+var ErrRoleNotFound = fmt.Errorf("role %w", dal.ErrNotFound)
+
+// ErrDeletedRoleNotFound is returned by RestoreRole and
+// RestoreRoleAssignment when there is nothing deleted to restore
+var ErrDeletedRoleNotFound = fmt.Errorf("deleted role %w", dal.ErrNotFound)
+
+// ErrRoleAssignmentNotFound is returned when a role assignment does not
+// exist
+var ErrRoleAssignmentNotFound = fmt.Errorf("role assignment %w", dal.ErrNotFound)
+
+// ErrRoleExists is returned when a role is created or renamed with the name
+// of another role
+var ErrRoleExists = errors.New("role with this name already exists")
+
 // RoleRepository implements RoleRepositoryInterface
 type RoleRepository struct {
-	db     dal.DatabaseClientInterface
-	config *models.Config
-	logger logger.Logger
+	assignments *Repository[models.RoleAssignment]
+	roles       *Repository[models.Role]
+	logger      logger.Logger
 }
 
 func NewRoleRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *RoleRepository {
 	return &RoleRepository{
-		db:     db,
-		config: cfg,
-		logger: log,
+		assignments: NewTypedRepository[models.RoleAssignment](db, cfg, RoleAssignmentsTable),
+		roles:       NewTypedRepository[models.Role](db, cfg, RolesTable),
+		logger:      log,
 	}
 }
 
 func (r *RoleRepository) CreateRoleAssignment(ctx context.Context, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error) {
 	r.logger.Infof("Creating role assignment: %s", roleAssignment.RoleName)
 
-	existingRole := &models.RoleAssignment{}
-	err := r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_role", "name-index", "role_name", roleAssignment.RoleName, &[]*models.RoleAssignment{existingRole})
-	if err == nil && existingRole.RoleID != "" {
-		return nil, errors.New("role with this name already exists")
+	// Assignments store their name as role_name, which name-index does not
+	// cover, so the duplicate check has to scan
+	existing, err := r.assignments.Scan(ctx, dal.Equal("role_name", roleAssignment.RoleName), notDeleted())
+	if err == nil && len(existing) > 0 {
+		return nil, ErrRoleExists
 	}
 
 	now := time.Now()
@@ -44,7 +58,7 @@ func (r *RoleRepository) CreateRoleAssignment(ctx context.Context, roleAssignmen
 
 	fmt.Println("roles ::::", dal.PrintPrettyJSON(roleAssignment))
 
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_role", roleAssignment)
+	err = r.assignments.Put(ctx, roleAssignment)
 	if err != nil {
 		r.logger.Errorf("Failed to create role assignment: %v", err)
 		return nil, err
@@ -54,16 +68,12 @@ func (r *RoleRepository) CreateRoleAssignment(ctx context.Context, roleAssignmen
 	return roleAssignment, nil
 }
 
-func (r *RoleRepository) GetRoleAssignments(key string) ([]*models.RoleAssignment, error) {
-	ctx := context.Background()
+func (r *RoleRepository) GetRoleAssignments(ctx context.Context, key string) ([]*models.RoleAssignment, error) {
 
 	if key == "" {
-		var roleAssignments []*models.RoleAssignment
-		tableName := r.config.DynamoDBTablePrefix + "_role"
-
-		r.logger.Infof("Scanning %s table for all role assignments", tableName)
+		r.logger.Infof("Scanning %s table for all role assignments", r.assignments.TableName())
 
-		err := r.db.ScanTable(ctx, tableName, &roleAssignments)
+		roleAssignments, err := r.assignments.Scan(ctx, notDeleted())
 		if err != nil {
 			r.logger.Errorf("Failed to scan role assignments table: %v", err)
 			return nil, fmt.Errorf("failed to get all role assignments: %w", err)
@@ -73,34 +83,26 @@ func (r *RoleRepository) GetRoleAssignments(key string) ([]*models.RoleAssignmen
 		return roleAssignments, nil
 	}
 
-	roleAssignment := models.RoleAssignment{}
-	config := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_role",
-		KeyName:   "role_id",
-		KeyValue:  key,
-		KeyType:   models.StringType,
-	}
-
-	r.logger.Infof("Querying %s table with role_id: %s", r.config.DynamoDBTablePrefix+"_role", key)
+	r.logger.Infof("Querying %s table with role_id: %s", r.assignments.TableName(), key)
 
-	err := r.db.GetItem(ctx, config, &roleAssignment)
+	roleAssignment, err := r.assignments.ByID(ctx, key)
 	if err != nil {
 		r.logger.Errorf("Failed to get role assignment by ID: %v", err)
 		return nil, fmt.Errorf("failed to get role assignment by ID: %w", err)
 	}
 
-	return []*models.RoleAssignment{&roleAssignment}, nil
-}
+	if roleAssignment.RoleID == "" || roleAssignment.DeletedData != nil {
+		return []*models.RoleAssignment{}, nil
+	}
 
-func (r *RoleRepository) GetRoleAssignmentsByStatus(status string) ([]*models.RoleAssignment, error) {
-	ctx := context.Background()
+	return []*models.RoleAssignment{roleAssignment}, nil
+}
 
-	var roleAssignments []*models.RoleAssignment
-	tableName := r.config.DynamoDBTablePrefix + "_role"
+func (r *RoleRepository) GetRoleAssignmentsByStatus(ctx context.Context, status string) ([]*models.RoleAssignment, error) {
 
-	r.logger.Infof("Scanning %s table for role assignments with status: %s", tableName, status)
+	r.logger.Infof("Scanning %s table for role assignments with status: %s", r.assignments.TableName(), status)
 
-	err := r.db.ScanTable(ctx, tableName, &roleAssignments)
+	roleAssignments, err := r.assignments.Scan(ctx, notDeleted())
 	if err != nil {
 		r.logger.Errorf("Failed to get role assignments by status: %v", err)
 		return nil, fmt.Errorf("failed to get role assignments by status: %w", err)
@@ -112,19 +114,18 @@ func (r *RoleRepository) GetRoleAssignmentsByStatus(status string) ([]*models.Ro
 	return roleAssignments, nil
 }
 
-func (r *RoleRepository) UpdateRoleAssignment(id string, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error) {
-	ctx := context.Background()
+func (r *RoleRepository) UpdateRoleAssignment(ctx context.Context, id string, roleAssignment *models.RoleAssignment) (*models.RoleAssignment, error) {
 	r.logger.Infof("Updating role assignment: %s", id)
 
 	// Check if role assignment exists
-	existing, err := r.GetRoleAssignments(id)
+	existing, err := r.GetRoleAssignments(ctx, id)
 	if err != nil || len(existing) == 0 {
-		return nil, errors.New("role assignment not found")
+		return nil, ErrRoleAssignmentNotFound
 	}
 
 	// Update the role assignment
 	roleAssignment.RoleID = id
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_role", roleAssignment)
+	err = r.assignments.Put(ctx, roleAssignment)
 	if err != nil {
 		r.logger.Errorf("Failed to update role assignment: %v", err)
 		return nil, err
@@ -134,19 +135,15 @@ func (r *RoleRepository) UpdateRoleAssignment(id string, roleAssignment *models.
 	return roleAssignment, nil
 }
 
-func (r *RoleRepository) DeleteRoleAssignment(id string) error {
-	ctx := context.Background()
+// DeleteRoleAssignment soft-deletes a role assignment
+func (r *RoleRepository) DeleteRoleAssignment(ctx context.Context, id string, deletedData *models.DeletedData) error {
 	r.logger.Infof("Deleting role assignment: %s", id)
 
-	// Check if role assignment exists
-	existing, err := r.GetRoleAssignments(id)
-	if err != nil || len(existing) == 0 {
-		return errors.New("role assignment not found")
-	}
-
-	tableName := r.config.DynamoDBTablePrefix + "_role"
-	err = r.db.DeleteItem(ctx, tableName, "role_id", id)
+	err := r.assignments.SoftDelete(ctx, id, deletedData)
 	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrRoleNotFound
+		}
 		r.logger.Errorf("Failed to delete role assignment: %v", err)
 		return fmt.Errorf("failed to delete role assignment: %w", err)
 	}
@@ -155,116 +152,78 @@ func (r *RoleRepository) DeleteRoleAssignment(id string) error {
 	return nil
 }
 
-func (r *RoleRepository) GetRole(key string) ([]*models.Role, error) {
-	ctx := context.Background()
-
-	if key == "" {
-		var roles []*models.Role
-		tableName := r.config.DynamoDBTablePrefix + "_roles"
+// RestoreRoleAssignment brings back a soft-deleted role assignment
+func (r *RoleRepository) RestoreRoleAssignment(ctx context.Context, id string) (*models.RoleAssignment, error) {
+	r.logger.Infof("Restoring role assignment: %s", id)
 
-		r.logger.Infof("Scanning %s table for all roles", tableName)
-
-		err := r.db.ScanTable(ctx, tableName, &roles)
-		if err != nil {
-			r.logger.Errorf("Failed to scan roles table: %v", err)
-			return nil, fmt.Errorf("failed to get all roles: %w", err)
+	roleAssignment, err := r.assignments.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return nil, ErrDeletedRoleNotFound
 		}
-
-		r.logger.Infof("Found %d roles", len(roles))
-		return roles, nil
+		r.logger.Errorf("Failed to restore role assignment: %v", err)
+		return nil, err
 	}
 
-	role := models.Role{}
-	keyType, indexName, keyName := r.determineKeyType(key)
+	r.logger.Infof("Role assignment restored successfully: %s", id)
+	return roleAssignment, nil
+}
 
-	var config models.QueryConfig
+// GetRoles returns every role that is not deleted
+func (r *RoleRepository) GetRoles(ctx context.Context) ([]*models.Role, error) {
+	r.logger.Infof("Scanning %s table for all roles", r.roles.TableName())
 
-	if keyType == "id" {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			KeyName:   "id",
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
-	} else {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
+	roles, err := r.roles.Scan(ctx, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to scan roles table: %v", err)
+		return nil, fmt.Errorf("failed to get all roles: %w", err)
 	}
 
-	r.logger.Infof("Querying %s table with %s: %s", r.config.DynamoDBTablePrefix, keyName, key)
+	r.logger.Infof("Found %d roles", len(roles))
+	return roles, nil
+}
+
+// GetRoleByID reads a role by ID
+func (r *RoleRepository) GetRoleByID(ctx context.Context, id string) (*models.Role, error) {
+	role, err := r.roles.ByID(ctx, id)
+	return r.found(role, r.roles.KeyName(), err)
+}
+
+// GetRoleByName reads a role through the name index
+func (r *RoleRepository) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	role, err := r.roles.ByIndex(ctx, "name", name)
+	return r.found(role, "name", err)
+}
 
-	err := r.db.GetItem(ctx, config, &role)
+// found returns the role read by keyName, or an error if the read failed or
+// matched no live role
+func (r *RoleRepository) found(role *models.Role, keyName string, err error) (*models.Role, error) {
 	if err != nil {
 		r.logger.Errorf("Failed to get role by %s: %v", keyName, err)
 		return nil, fmt.Errorf("failed to get role by %s: %w", keyName, err)
 	}
 
-	if role.ID == "" {
-		return nil, errors.New("role not found")
+	if role.ID == "" || role.DeletedData != nil {
+		return nil, ErrRoleNotFound
 	}
 
 	r.logger.Infof("Role found: %s", role.ID)
-	return []*models.Role{&role}, nil
+	return role, nil
 }
 
-func (r *RoleRepository) determineKeyType(key string) (keyType, indexName, keyName string) {
-	uuidPattern := `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
-	isUUID, _ := regexp.MatchString(uuidPattern, strings.ToLower(key))
+func (r *RoleRepository) UpdateRole(ctx context.Context, id string, role *models.Role) (*models.Role, error) {
 
-	if isUUID {
-		return "id", "", "id"
-	} else {
-		return "name", "name-index", "name"
-	}
-}
-
-func (r *RoleRepository) UpdateRole(id string, role *models.Role) (*models.Role, error) {
-	ctx := context.Background()
-
-	existingRole := models.Role{}
-	keyType, indexName, keyName := r.determineKeyType(id)
-
-	var config models.QueryConfig
-
-	if keyType == "id" {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			KeyName:   "id",
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	} else {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	}
-
-	err := r.db.GetItem(ctx, config, &existingRole)
+	existingRole, err := r.GetRoleByID(ctx, id)
 	if err != nil {
-		r.logger.Errorf("Failed to get role by %s: %v", keyName, err)
-		return nil, fmt.Errorf("failed to get role by %s: %w", keyName, err)
-	}
-
-	if existingRole.ID == "" {
-		return nil, errors.New("role not found")
+		return nil, err
 	}
 
 	updates := make(map[string]interface{})
 
 	if role.Name != "" {
-		existingRoleWithName := &models.Role{}
-		err := r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_roles", "name-index", "name", role.Name, &[]*models.Role{existingRoleWithName})
+		existingRoleWithName, err := r.roles.ByIndex(ctx, "name", role.Name)
 		if err == nil && existingRoleWithName.ID != "" && existingRoleWithName.ID != existingRole.ID {
-			return nil, errors.New("role with this name already exists")
+			return nil, ErrRoleExists
 		}
 		updates["name"] = role.Name
 	}
@@ -288,7 +247,7 @@ func (r *RoleRepository) UpdateRole(id string, role *models.Role) (*models.Role,
 	}
 	updates["updated_at"] = time.Now()
 
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_roles", "id", existingRole.ID, updates)
+	err = r.roles.SetAttributes(ctx, existingRole.ID, updates)
 	if err != nil {
 		r.logger.Errorf("Failed to update role: %v", err)
 		return nil, err
@@ -318,46 +277,22 @@ func (r *RoleRepository) UpdateRole(id string, role *models.Role) (*models.Role,
 	existingRole.UpdatedAt = time.Now()
 
 	r.logger.Infof("Role updated successfully: %s", existingRole.ID)
-	return &existingRole, nil
+	return existingRole, nil
 }
 
-func (r *RoleRepository) DeleteRole(id string) error {
-	ctx := context.Background()
-
-	existingRole := models.Role{}
-	keyType, indexName, keyName := r.determineKeyType(id)
-
-	var config models.QueryConfig
+// DeleteRole soft-deletes a role
+func (r *RoleRepository) DeleteRole(ctx context.Context, id string, deletedData *models.DeletedData) error {
 
-	if keyType == "id" {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			KeyName:   "id",
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	} else {
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_roles",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	}
-
-	err := r.db.GetItem(ctx, config, &existingRole)
+	existingRole, err := r.GetRoleByID(ctx, id)
 	if err != nil {
-		r.logger.Errorf("Failed to get role by %s: %v", keyName, err)
-		return fmt.Errorf("failed to get role by %s: %w", keyName, err)
-	}
-
-	if existingRole.ID == "" {
-		return errors.New("role not found")
+		return err
 	}
 
-	err = r.db.DeleteItem(ctx, r.config.DynamoDBTablePrefix+"_roles", "id", existingRole.ID)
+	err = r.roles.SoftDelete(ctx, existingRole.ID, deletedData)
 	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrRoleNotFound
+		}
 		r.logger.Errorf("Failed to delete role: %v", err)
 		return err
 	}
@@ -366,11 +301,24 @@ func (r *RoleRepository) DeleteRole(id string) error {
 	return nil
 }
 
-func (r *RoleRepository) GetRolesByStatus(status models.RoleStatus) ([]*models.Role, error) {
-	ctx := context.Background()
-	var roles []*models.Role
+// RestoreRole brings back a soft-deleted role by ID
+func (r *RoleRepository) RestoreRole(ctx context.Context, id string) (*models.Role, error) {
+
+	role, err := r.roles.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return nil, ErrDeletedRoleNotFound
+		}
+		r.logger.Errorf("Failed to restore role: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Role restored successfully: %s", role.ID)
+	return role, nil
+}
 
-	err := r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_roles", "status-index", "status", string(status), &roles)
+func (r *RoleRepository) GetRolesByStatus(ctx context.Context, status models.RoleStatus) ([]*models.Role, error) {
+	roles, err := r.roles.ListByIndex(ctx, "status", string(status), notDeleted())
 	if err != nil {
 		r.logger.Errorf("Failed to get roles by status: %v", err)
 		return nil, err
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+)
+
+// ErrSLAPolicyNotFound is returned when an SLA policy does not exist or is
+// deleted
+var ErrSLAPolicyNotFound = fmt.Errorf("SLA policy %w", dal.ErrNotFound)
+
+// ErrSLAPolicyChanged is returned when an SLA policy was changed or deleted
+// between reading and updating it
+var ErrSLAPolicyChanged = errors.New("SLA policy changed concurrently")
+
+// SLAPolicyRepository implements SLAPolicyRepositoryInterface
+type SLAPolicyRepository struct {
+	policies *Repository[models.SLAPolicy]
+	logger   logger.Logger
+}
+
+func NewSLAPolicyRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *SLAPolicyRepository {
+	return &SLAPolicyRepository{
+		policies: NewTypedRepository[models.SLAPolicy](db, cfg, SLAPoliciesTable),
+		logger:   log,
+	}
+}
+
+func (r *SLAPolicyRepository) CreateSLAPolicy(ctx context.Context, policy *models.SLAPolicy) (*models.SLAPolicy, error) {
+	r.logger.Infof("Creating SLA policy: %s", policy.Name)
+
+	now := time.Now().UTC()
+	policy.PolicyID = utils.GenerateUUID()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	err := r.policies.Put(ctx, policy)
+	if err != nil {
+		r.logger.Errorf("Failed to create SLA policy: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("SLA policy created successfully: %s", policy.PolicyID)
+	return policy, nil
+}
+
+func (r *SLAPolicyRepository) GetSLAPolicy(ctx context.Context, id string) (*models.SLAPolicy, error) {
+	if id == "" {
+		return nil, errors.New("SLA policy ID is required")
+	}
+
+	policy, err := r.policies.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrSLAPolicyNotFound
+		}
+		r.logger.Errorf("Failed to get SLA policy: %v", err)
+		return nil, fmt.Errorf("failed to get SLA policy: %w", err)
+	}
+
+	if policy.PolicyID == "" || policy.DeletedData != nil {
+		return nil, ErrSLAPolicyNotFound
+	}
+	return policy, nil
+}
+
+// GetSLAPoliciesByOrganization returns the SLA policies of orgID
+func (r *SLAPolicyRepository) GetSLAPoliciesByOrganization(ctx context.Context, orgID string) ([]*models.SLAPolicy, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	policies, err := r.policies.ListByIndex(ctx, "orgID", orgID, notDeleted())
+	if err != nil {
+		r.logger.Errorf("Failed to get SLA policies: %v", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// UpdateSLAPolicy writes the name, job type and targets of policy, provided
+// the stored policy was not changed since policy was read, as told by its
+// updatedAt. Otherwise it returns ErrSLAPolicyChanged, so concurrent edits
+// cannot overwrite each other.
+func (r *SLAPolicyRepository) UpdateSLAPolicy(ctx context.Context, id string, policy *models.SLAPolicy) (*models.SLAPolicy, error) {
+	r.logger.Infof("Updating SLA policy: %s", id)
+
+	if id == "" {
+		return nil, errors.New("SLA policy ID is required")
+	}
+
+	update := r.policies.NewUpdate(id).
+		Condition(dal.AttributeExists("policyID"), notDeleted()).
+		Condition(dal.Equal("updatedAt", policy.UpdatedAt)).
+		Set("name", policy.Name).
+		Set("targets", policy.Targets).
+		Set("updatedAt", time.Now().UTC()).
+		Set("updatedBy", policy.UpdatedBy)
+	if policy.JobType == "" {
+		update.Remove("jobType")
+	} else {
+		update.Set("jobType", policy.JobType)
+	}
+
+	updated, err := r.policies.Update(ctx, update)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrSLAPolicyChanged
+		}
+		r.logger.Errorf("Failed to update SLA policy: %v", err)
+		return nil, err
+	}
+
+	r.logger.Infof("SLA policy updated successfully: %s", id)
+	return updated, nil
+}
+
+// DeleteSLAPolicy soft-deletes an SLA policy. Jobs keep the due dates
+// calculated from it.
+func (r *SLAPolicyRepository) DeleteSLAPolicy(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.logger.Infof("Deleting SLA policy: %s", id)
+
+	if id == "" {
+		return errors.New("SLA policy ID is required")
+	}
+
+	err := r.policies.SoftDelete(ctx, id, deletedData)
+	if err != nil {
+		if errors.Is(err, errSoftDeleteConditionFailed) {
+			return ErrSLAPolicyNotFound
+		}
+		r.logger.Errorf("Failed to delete SLA policy: %v", err)
+		return err
+	}
+
+	r.logger.Infof("SLA policy deleted successfully: %s", id)
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"time"
+)
+
+const (
+	// deletedDataAttribute marks an item as soft-deleted
+	deletedDataAttribute = "deletedData"
+
+	// PurgeAtAttribute is the DynamoDB TTL attribute (epoch seconds) after
+	// which a soft-deleted item is removed permanently
+	PurgeAtAttribute = "purgeAt"
+)
+
+// errSoftDeleteConditionFailed is returned when the item does not exist or
+// is not in the expected deleted/not-deleted state
+var errSoftDeleteConditionFailed = errors.New("soft delete condition failed")
+
+// notDeleted filters out soft-deleted items
+func notDeleted() dal.Condition {
+	return dal.AttributeNotExists(deletedDataAttribute)
+}
+
+// purgeAt returns the TTL for an item deleted now, or 0 when retention is
+// disabled and the item should be kept until restored
+func purgeAt(retentionDays int, deletedAt time.Time) int64 {
+	if retentionDays <= 0 {
+		return 0
+	}
+	return deletedAt.AddDate(0, 0, retentionDays).Unix()
+}
+
+// softDelete marks the item identified by keyName = id as deleted and
+// schedules it for purge after the configured retention period
+func softDelete(ctx context.Context, db dal.DatabaseClientInterface, cfg *models.Config, tableName, keyName, id string, deletedData *models.DeletedData) error {
+	if deletedData.DeletedAt.IsZero() {
+		deletedData.DeletedAt = time.Now()
+	}
+
+	update := dal.NewUpdate(tableName, keyName, id).
+		Set(deletedDataAttribute, deletedData).
+		Condition(dal.AttributeExists(keyName), notDeleted())
+
+	if ttl := purgeAt(cfg.SoftDeleteRetentionDays, deletedData.DeletedAt); ttl > 0 {
+		update.Set(PurgeAtAttribute, ttl)
+	}
+
+	err := db.Update(ctx, update, nil)
+	if isConditionalCheckFailed(err) {
+		return errSoftDeleteConditionFailed
+	}
+	return err
+}
+
+// restore clears the deleted marker and pending purge from a soft-deleted
+// item and unmarshals the restored item into result
+func restore(ctx context.Context, db dal.DatabaseClientInterface, tableName, keyName, id string, result interface{}) error {
+	update := dal.NewUpdate(tableName, keyName, id).
+		Remove(deletedDataAttribute).
+		Remove(PurgeAtAttribute).
+		Condition(dal.AttributeExists(keyName), dal.AttributeExists(deletedDataAttribute))
+
+	err := db.Update(ctx, update, result)
+	if isConditionalCheckFailed(err) {
+		return errSoftDeleteConditionFailed
+	}
+	return err
+}
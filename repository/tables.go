@@ -0,0 +1,245 @@
+package repository
+
+import "fmt"
+
+// TableDefinition declares a DynamoDB table together with its partition key
+// and global secondary indexes. Name is the table's key in
+// infrastructure/table_schema.json; the deployed table is prefixed per
+// environment.
+type TableDefinition struct {
+	Name         string
+	PartitionKey string
+	// Indexes maps an attribute to the global secondary index keyed on it
+	Indexes map[string]string
+	// SoftDelete marks tables whose items are soft-deleted and later purged
+	SoftDelete bool
+	// TenantScoped marks tables every organization gets its own copy of in
+	// isolated tenancy mode
+	TenantScoped bool
+}
+
+var (
+	// UsersTable holds user accounts
+	UsersTable = TableDefinition{
+		Name:         "users1",
+		PartitionKey: "id",
+		Indexes: map[string]string{
+			"email":    "email-index",
+			"username": "username-index",
+		},
+	}
+
+	// RoleAssignmentsTable holds the role assignment templates users are
+	// given roles from
+	RoleAssignmentsTable = TableDefinition{
+		Name:         "role",
+		PartitionKey: "role_id",
+		Indexes: map[string]string{
+			"name":   "name-index",
+			"status": "status-index",
+		},
+		SoftDelete: true,
+	}
+
+	// RolesTable holds role definitions
+	RolesTable = TableDefinition{
+		Name:         "roles",
+		PartitionKey: "id",
+		Indexes: map[string]string{
+			"name":   "name-index",
+			"status": "status-index",
+		},
+		SoftDelete: true,
+	}
+
+	// OrganizationsTable holds organizations
+	OrganizationsTable = TableDefinition{
+		Name:         "organization",
+		PartitionKey: "id",
+		Indexes: map[string]string{
+			"name":       "name-index",
+			"status":     "status-index",
+			"created_by": "created-by-index",
+			"email":      "email-index",
+		},
+		SoftDelete: true,
+	}
+
+	// JobsTable holds jobs. orgID-createdAt-index additionally sorts an
+	// organization's jobs by creation time and orgID-scheduledStart-index
+	// sorts its scheduled jobs by start.
+	JobsTable = TableDefinition{
+		Name:         "jobs",
+		PartitionKey: "jobID",
+		Indexes: map[string]string{
+			"orgID":          "orgID-index",
+			"clientID":       "clientID-index",
+			"jobStatus":      "jobStatus-index",
+			"jobType":        "jobType-index",
+			"recurringJobID": "recurringJobID-index",
+			"projectID":      "projectID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// RecurringJobsTable holds the templates recurring jobs are materialised
+	// from
+	RecurringJobsTable = TableDefinition{
+		Name:         "recurring",
+		PartitionKey: "recurringJobID",
+		Indexes: map[string]string{
+			"orgID":  "orgID-index",
+			"status": "status-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// AvailabilityTable holds the working hours and time off of technicians
+	AvailabilityTable = TableDefinition{
+		Name:         "availability",
+		PartitionKey: "userID",
+		TenantScoped: true,
+	}
+
+	// ChecklistTemplatesTable holds the checklist templates of organizations
+	ChecklistTemplatesTable = TableDefinition{
+		Name:         "checklists",
+		PartitionKey: "templateID",
+		Indexes: map[string]string{
+			"orgID": "orgID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// AttachmentsTable holds the photos and documents uploaded to jobs. The
+	// files themselves are in the blob store.
+	AttachmentsTable = TableDefinition{
+		Name:         "attachments",
+		PartitionKey: "attachmentID",
+		Indexes: map[string]string{
+			"jobID": "jobID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// ActivityTable holds the activity feeds of jobs. jobID-sequence-index
+	// sorts a job's entries by sequence, which starts with the creation time.
+	ActivityTable = TableDefinition{
+		Name:         "activity",
+		PartitionKey: "activityID",
+		Indexes: map[string]string{
+			"jobID": "jobID-sequence-index",
+		},
+		TenantScoped: true,
+	}
+
+	// TimeEntriesTable holds the time technicians spend on jobs.
+	// userID-weekStart-index groups a technician's entries by week and the
+	// sparse openUserID-index holds running entries only.
+	TimeEntriesTable = TableDefinition{
+		Name:         "timeentries",
+		PartitionKey: "entryID",
+		Indexes: map[string]string{
+			"jobID":      "jobID-index",
+			"userID":     "userID-weekStart-index",
+			"openUserID": "openUserID-index",
+		},
+		TenantScoped: true,
+	}
+
+	// TimesheetsTable holds the approval state of technicians' weeks
+	TimesheetsTable = TableDefinition{
+		Name:         "timesheets",
+		PartitionKey: "timesheetID",
+		TenantScoped: true,
+	}
+
+	// SLAPoliciesTable holds the response and resolution times organizations
+	// promise per job type and priority
+	SLAPoliciesTable = TableDefinition{
+		Name:         "slapolicies",
+		PartitionKey: "policyID",
+		Indexes: map[string]string{
+			"orgID": "orgID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// JobTemplatesTable holds the jobs organizations create over and over
+	JobTemplatesTable = TableDefinition{
+		Name:         "jobtemplates",
+		PartitionKey: "templateID",
+		Indexes: map[string]string{
+			"orgID": "orgID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// JobImportsTable holds the progress and outcome of job imports
+	JobImportsTable = TableDefinition{
+		Name:         "jobimports",
+		PartitionKey: "importID",
+		TenantScoped: true,
+	}
+
+	// ProjectsTable holds the projects jobs are grouped in
+	ProjectsTable = TableDefinition{
+		Name:         "projects",
+		PartitionKey: "projectID",
+		Indexes: map[string]string{
+			"orgID": "orgID-index",
+		},
+		SoftDelete:   true,
+		TenantScoped: true,
+	}
+
+	// CheckpointsTable holds the stream consumer's per-shard progress
+	CheckpointsTable = TableDefinition{
+		Name:         "checkpoints",
+		PartitionKey: "shard_key",
+	}
+)
+
+// jobsByOrgCreatedAtIndex is the JobsTable index with orgID as hash key and
+// createdAt as range key
+const jobsByOrgCreatedAtIndex = "orgID-createdAt-index"
+
+// jobsByOrgScheduleIndex is the JobsTable index with orgID as hash key and
+// scheduledStart as range key. Unscheduled jobs are not in it.
+const jobsByOrgScheduleIndex = "orgID-scheduledStart-index"
+
+// Tables returns every table the application uses
+func Tables() []TableDefinition {
+	return []TableDefinition{UsersTable, RoleAssignmentsTable, RolesTable, OrganizationsTable, JobsTable, RecurringJobsTable, AvailabilityTable, ChecklistTemplatesTable, AttachmentsTable, ActivityTable, TimeEntriesTable, TimesheetsTable, SLAPoliciesTable, JobTemplatesTable, JobImportsTable, ProjectsTable, CheckpointsTable}
+}
+
+// SoftDeleteTables returns the tables whose items are soft-deleted
+func SoftDeleteTables() []TableDefinition {
+	var tables []TableDefinition
+	for _, table := range Tables() {
+		if table.SoftDelete {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// FullName returns the deployed table name for the environment prefix
+func (t TableDefinition) FullName(prefix string) string {
+	return prefix + "_" + t.Name
+}
+
+// IndexFor returns the index keyed on attribute
+func (t TableDefinition) IndexFor(attribute string) (string, error) {
+	index, ok := t.Indexes[attribute]
+	if !ok {
+		return "", fmt.Errorf("table %s has no index on %s", t.Name, attribute)
+	}
+	return index, nil
+}
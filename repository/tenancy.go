@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/requestctx"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ErrTenantRequired is returned when tenant-scoped data is accessed in
+// isolated tenancy mode without an organization in the request context
+var ErrTenantRequired = errors.New("organization is required to access tenant data")
+
+// ErrInvalidTenant is returned when the request context names an
+// organization that cannot own tables
+var ErrInvalidTenant = errors.New("invalid organization ID")
+
+// tenantIDPattern matches the organization IDs generated by
+// utils.GenerateUUID, the only IDs allowed in table names
+var tenantIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// TenantTablePrefix returns the table prefix of an organization's own tables
+func TenantTablePrefix(cfg *models.Config, orgID string) string {
+	return cfg.DynamoDBTablePrefix + "-" + orgID
+}
+
+// TenantTables returns the tables every organization gets its own copy of
+// in isolated tenancy mode
+func TenantTables() []TableDefinition {
+	var tables []TableDefinition
+	for _, table := range Tables() {
+		if table.TenantScoped {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// ResolveTableName returns the deployed name of table for the request. In
+// isolated mode tenant-scoped tables resolve to the tables of the
+// organization ctx is scoped to; everything else uses the shared tables.
+func ResolveTableName(ctx context.Context, cfg *models.Config, table TableDefinition) (string, error) {
+	if !table.TenantScoped || cfg.TenancyMode != models.TenancyIsolated {
+		return table.FullName(cfg.DynamoDBTablePrefix), nil
+	}
+
+	orgID := requestctx.TenantID(ctx)
+	if orgID == "" {
+		return "", ErrTenantRequired
+	}
+	// Organization IDs become part of table names, so only generated IDs
+	// are accepted
+	if !tenantIDPattern.MatchString(orgID) {
+		return "", fmt.Errorf("%w %q", ErrInvalidTenant, orgID)
+	}
+	return table.FullName(TenantTablePrefix(cfg, orgID)), nil
+}
+
+// TenantIDs returns the organizations that have their own tables in
+// isolated mode, which is every organization that is not deleted
+func TenantIDs(ctx context.Context, db dal.DatabaseClientInterface, cfg *models.Config) ([]string, error) {
+	organizations, err := NewTypedRepository[models.Organization](db, cfg, OrganizationsTable).Scan(ctx, notDeleted())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	orgIDs := make([]string, 0, len(organizations))
+	for _, organization := range organizations {
+		if tenantIDPattern.MatchString(organization.ID) {
+			orgIDs = append(orgIDs, organization.ID)
+		}
+	}
+	sort.Strings(orgIDs)
+	return orgIDs, nil
+}
+
+// DeployedTableNames returns every deployed copy of table: the shared table
+// and, for tenant-scoped tables in isolated mode, each organization's table
+func DeployedTableNames(ctx context.Context, db dal.DatabaseClientInterface, cfg *models.Config, table TableDefinition) ([]string, error) {
+	names := []string{table.FullName(cfg.DynamoDBTablePrefix)}
+	if !table.TenantScoped || cfg.TenancyMode != models.TenancyIsolated {
+		return names, nil
+	}
+
+	orgIDs, err := TenantIDs(ctx, db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, orgID := range orgIDs {
+		names = append(names, table.FullName(TenantTablePrefix(cfg, orgID)))
+	}
+	return names, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/requestctx"
+	"testing"
+)
+
+func TestResolveTableName(t *testing.T) {
+	const orgID = "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f"
+	cfg := &models.Config{DynamoDBTablePrefix: "ff", TenancyMode: models.TenancyIsolated}
+	tenantCtx := requestctx.WithTenant(context.Background(), orgID)
+
+	// Everything stored per job or per organization follows the jobs into
+	// the tenant's tables
+	jobOwned := []TableDefinition{
+		JobsTable, RecurringJobsTable, AvailabilityTable, ChecklistTemplatesTable,
+		AttachmentsTable, ActivityTable, TimeEntriesTable, TimesheetsTable,
+		SLAPoliciesTable, JobTemplatesTable, JobImportsTable, ProjectsTable,
+	}
+	for _, table := range jobOwned {
+		got, err := ResolveTableName(tenantCtx, cfg, table)
+		if err != nil {
+			t.Fatalf("ResolveTableName(%s) error = %v", table.Name, err)
+		}
+		if want := "ff-" + orgID + "_" + table.Name; got != want {
+			t.Errorf("ResolveTableName(%s) = %q, want %q", table.Name, got, want)
+		}
+
+		if _, err := ResolveTableName(context.Background(), cfg, table); !errors.Is(err, ErrTenantRequired) {
+			t.Errorf("ResolveTableName(%s) without a tenant error = %v, want ErrTenantRequired", table.Name, err)
+		}
+	}
+
+	for _, table := range []TableDefinition{UsersTable, RolesTable, OrganizationsTable, CheckpointsTable} {
+		got, err := ResolveTableName(tenantCtx, cfg, table)
+		if err != nil {
+			t.Fatalf("ResolveTableName(%s) error = %v", table.Name, err)
+		}
+		if want := "ff_" + table.Name; got != want {
+			t.Errorf("ResolveTableName(%s) = %q, want shared %q", table.Name, got, want)
+		}
+	}
+
+	shared := &models.Config{DynamoDBTablePrefix: "ff", TenancyMode: models.TenancyShared}
+	if got, err := ResolveTableName(context.Background(), shared, ProjectsTable); err != nil || got != "ff_projects" {
+		t.Errorf("ResolveTableName() in shared mode = %q, %v, want ff_projects", got, err)
+	}
+
+	if _, err := ResolveTableName(requestctx.WithTenant(context.Background(), "../users1"), cfg, JobsTable); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("ResolveTableName() of a malformed tenant error = %v, want ErrInvalidTenant", err)
+	}
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrTimeEntryNotFound is returned when a time entry does not exist
+var ErrTimeEntryNotFound = fmt.Errorf("time entry %w", dal.ErrNotFound)
+
+// ErrTimeEntryChanged is returned by EndTimeEntry when the entry was ended
+// by another request in the meantime
+var ErrTimeEntryChanged = errors.New("time entry changed concurrently")
+
+// ErrTimesheetLocked is returned by CreateTimeEntry and UpdateTimeEntry when
+// the entry falls in a week whose timesheet was approved
+var ErrTimesheetLocked = errors.New("timesheet is approved and locked")
+
+// TimeEntryRepository implements TimeEntryRepositoryInterface
+type TimeEntryRepository struct {
+	db         dal.DatabaseClientInterface
+	entries    *Repository[models.TimeEntry]
+	timesheets *Repository[models.Timesheet]
+	logger     logger.Logger
+}
+
+func NewTimeEntryRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *TimeEntryRepository {
+	return &TimeEntryRepository{
+		db:         db,
+		entries:    NewTypedRepository[models.TimeEntry](db, cfg, TimeEntriesTable),
+		timesheets: NewTypedRepository[models.Timesheet](db, cfg, TimesheetsTable),
+		logger:     log,
+	}
+}
+
+// CreateTimeEntry stores a new time entry. Running entries are added to the
+// index of running entries. It returns ErrTimesheetLocked when the week of
+// the entry was approved.
+func (r *TimeEntryRepository) CreateTimeEntry(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error) {
+	r.logger.Infof("Creating %s time entry of %s on job %s", entry.Category, entry.UserID, entry.JobID)
+
+	entry.EntryID = utils.GenerateUUID()
+	entry.CreatedAt = time.Now().UTC()
+	entry.OpenUserID = ""
+	if entry.Running() {
+		entry.OpenUserID = entry.UserID
+	}
+
+	if err := r.putUnlocked(ctx, entry, entry.WeekStart); err != nil {
+		if !errors.Is(err, ErrTimesheetLocked) {
+			r.logger.Errorf("Failed to create time entry: %v", err)
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *TimeEntryRepository) GetTimeEntry(ctx context.Context, id string) (*models.TimeEntry, error) {
+	if id == "" {
+		return nil, errors.New("time entry ID is required")
+	}
+
+	entry, err := r.entries.ByID(ctx, id)
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, ErrTimeEntryNotFound
+		}
+		r.logger.Errorf("Failed to get time entry: %v", err)
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+
+	if entry.EntryID == "" {
+		return nil, ErrTimeEntryNotFound
+	}
+	return entry, nil
+}
+
+// GetRunningTimeEntry returns the running entry of userID, or nil when the
+// user is not clocked in
+func (r *TimeEntryRepository) GetRunningTimeEntry(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	entries, err := r.entries.ListByIndex(ctx, "openUserID", userID)
+	if err != nil {
+		r.logger.Errorf("Failed to get running time entry of %s: %v", userID, err)
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	// Only one entry runs at a time; should a race leave more, the latest
+	// one is current
+	sortTimeEntries(entries)
+	return entries[len(entries)-1], nil
+}
+
+// GetTimeEntriesByJob returns the entries of jobID in start order
+func (r *TimeEntryRepository) GetTimeEntriesByJob(ctx context.Context, jobID string) ([]*models.TimeEntry, error) {
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+
+	entries, err := r.entries.ListByIndex(ctx, "jobID", jobID)
+	if err != nil {
+		r.logger.Errorf("Failed to get time entries of job %s: %v", jobID, err)
+		return nil, err
+	}
+	sortTimeEntries(entries)
+	return entries, nil
+}
+
+// GetTimeEntriesByWeek returns the entries userID started in the week
+// beginning on weekStart, in start order
+func (r *TimeEntryRepository) GetTimeEntriesByWeek(ctx context.Context, userID, weekStart string) ([]*models.TimeEntry, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	query, err := r.entries.NewQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.Index(TimeEntriesTable.Indexes["userID"]).
+		WhereHashKey("userID", userID).
+		SortEqual("weekStart", weekStart)
+
+	entries, err := r.entries.Query(ctx, query)
+	if err != nil {
+		r.logger.Errorf("Failed to get time entries of %s for week %s: %v", userID, weekStart, err)
+		return nil, err
+	}
+	sortTimeEntries(entries)
+	return entries, nil
+}
+
+// EndTimeEntry stores the end of a running entry and removes it from the
+// index of running entries. It returns ErrTimeEntryChanged when the entry
+// is no longer running.
+func (r *TimeEntryRepository) EndTimeEntry(ctx context.Context, entry *models.TimeEntry, updatedBy string) (*models.TimeEntry, error) {
+	r.logger.Infof("Ending time entry %s of %s", entry.EntryID, entry.UserID)
+
+	if entry.EndedAt == nil {
+		return nil, errors.New("end time is required")
+	}
+
+	update := r.entries.NewUpdate(entry.EntryID).
+		Condition(dal.AttributeExists("openUserID")).
+		Set("endedAt", entry.EndedAt.UTC()).
+		Set("endReason", entry.EndReason).
+		Set("minutes", entry.Minutes).
+		Set("updatedAt", time.Now().UTC()).
+		Set("updatedBy", updatedBy).
+		Remove("openUserID")
+	if entry.Notes != "" {
+		update.Set("notes", entry.Notes)
+	}
+
+	ended, err := r.entries.Update(ctx, update)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, ErrTimeEntryChanged
+		}
+		r.logger.Errorf("Failed to end time entry: %v", err)
+		return nil, err
+	}
+	return ended, nil
+}
+
+// UpdateTimeEntry replaces a corrected entry, keeping the index of running
+// entries in step with its end. It returns ErrTimesheetLocked when the week
+// the entry moves from, previousWeek, or its new week was approved.
+func (r *TimeEntryRepository) UpdateTimeEntry(ctx context.Context, entry *models.TimeEntry, previousWeek string, updatedBy string) (*models.TimeEntry, error) {
+	r.logger.Infof("Updating time entry %s", entry.EntryID)
+
+	if entry.EntryID == "" {
+		return nil, errors.New("time entry ID is required")
+	}
+
+	entry.UpdatedAt = time.Now().UTC()
+	entry.UpdatedBy = updatedBy
+	entry.OpenUserID = ""
+	if entry.Running() {
+		entry.OpenUserID = entry.UserID
+	}
+
+	weeks := []string{entry.WeekStart}
+	if previousWeek != "" && previousWeek != entry.WeekStart {
+		weeks = append(weeks, previousWeek)
+	}
+	if err := r.putUnlocked(ctx, entry, weeks...); err != nil {
+		if !errors.Is(err, ErrTimesheetLocked) {
+			r.logger.Errorf("Failed to update time entry: %v", err)
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// putUnlocked writes entry in one transaction with checks that none of the
+// timesheets of its technician for weeks was approved, so an approval cannot
+// slip in between the check and the write
+func (r *TimeEntryRepository) putUnlocked(ctx context.Context, entry *models.TimeEntry, weeks ...string) error {
+	put, err := r.entries.TransactPut(ctx, entry)
+	if err != nil {
+		return err
+	}
+	items := []dal.TransactItem{put}
+	for _, week := range weeks {
+		check, err := r.timesheets.TransactCheck(ctx, timesheetID(entry.UserID, week), dal.Or(
+			dal.AttributeNotExists("timesheetID"),
+			dal.NotEqual("status", models.TimesheetApproved),
+		))
+		if err != nil {
+			return err
+		}
+		items = append(items, check)
+	}
+
+	err = r.db.TransactWrite(ctx, items...)
+	for _, i := range dal.FailedConditions(err) {
+		if i > 0 && i <= len(weeks) {
+			return fmt.Errorf("%w: the week of %s was approved", ErrTimesheetLocked, weeks[i-1])
+		}
+	}
+	return err
+}
+
+// sortTimeEntries orders entries by start
+func sortTimeEntries(entries []*models.TimeEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.Before(entries[j].StartedAt)
+	})
+}
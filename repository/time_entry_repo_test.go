@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeTimesheetStore cancels transactions that check an approved timesheet,
+// like DynamoDB would, and otherwise counts the entries put
+type fakeTimesheetStore struct {
+	dal.DatabaseClientInterface
+	approved map[string]bool
+	// reasonsInMessage lists the cancellation reasons only in the error
+	// message, as some SDK responses do
+	reasonsInMessage bool
+	puts             int
+}
+
+func (f *fakeTimesheetStore) TransactWrite(ctx context.Context, items ...dal.TransactItem) error {
+	cancelled, codes := false, make([]string, len(items))
+	for i, item := range items {
+		codes[i] = "None"
+		if item.Item == nil && f.approved[item.KeyValue.(string)] {
+			cancelled, codes[i] = true, "ConditionalCheckFailed"
+		}
+	}
+	if !cancelled {
+		f.puts++
+		return nil
+	}
+
+	message := "Transaction cancelled, please refer cancellation reasons for specific reasons [" + strings.Join(codes, ", ") + "]"
+	if f.reasonsInMessage {
+		return &types.TransactionCanceledException{Message: aws.String(message)}
+	}
+	reasons := make([]types.CancellationReason, len(codes))
+	for i, code := range codes {
+		reasons[i] = types.CancellationReason{Code: aws.String(code)}
+	}
+	return &types.TransactionCanceledException{Message: aws.String(message), CancellationReasons: reasons}
+}
+
+func TestTimeEntryWritesOfApprovedWeeks(t *testing.T) {
+	tests := []struct {
+		name             string
+		week             string
+		previousWeek     string
+		reasonsInMessage bool
+		wantLocked       string
+	}{
+		{name: "open week", week: "2026-03-09"},
+		{name: "approved week", week: "2026-03-02", wantLocked: "2026-03-02"},
+		{name: "approved week, reasons in message", week: "2026-03-02", reasonsInMessage: true, wantLocked: "2026-03-02"},
+		{name: "moved out of approved week", week: "2026-03-09", previousWeek: "2026-03-02", wantLocked: "2026-03-02"},
+		{name: "moved into approved week", week: "2026-03-02", previousWeek: "2026-03-09", wantLocked: "2026-03-02"},
+		{name: "moved between open weeks", week: "2026-03-09", previousWeek: "2026-03-16"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeTimesheetStore{
+				approved:         map[string]bool{timesheetID("tech-1", "2026-03-02"): true},
+				reasonsInMessage: tt.reasonsInMessage,
+			}
+			cfg := &models.Config{DynamoDBTablePrefix: "ff", TenancyMode: models.TenancyShared}
+			repo := NewTimeEntryRepository(store, cfg, logger.NewLogger("error", "json"))
+
+			entry := &models.TimeEntry{EntryID: "entry-1", UserID: "tech-1", StartedAt: time.Now().Add(-time.Hour), WeekStart: tt.week}
+			var err error
+			if tt.previousWeek == "" {
+				_, err = repo.CreateTimeEntry(context.Background(), entry)
+			} else {
+				_, err = repo.UpdateTimeEntry(context.Background(), entry, tt.previousWeek, "manager")
+			}
+
+			if tt.wantLocked == "" {
+				if err != nil || store.puts != 1 {
+					t.Fatalf("write error = %v after %d puts, want one put", err, store.puts)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTimesheetLocked) || !strings.Contains(err.Error(), tt.wantLocked) {
+				t.Fatalf("write error = %v, want ErrTimesheetLocked for the week of %s", err, tt.wantLocked)
+			}
+			if store.puts != 0 {
+				t.Fatalf("%d entries were put into an approved week", store.puts)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+)
+
+// TimesheetRepository implements TimesheetRepositoryInterface
+type TimesheetRepository struct {
+	timesheets *Repository[models.Timesheet]
+	logger     logger.Logger
+}
+
+func NewTimesheetRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *TimesheetRepository {
+	return &TimesheetRepository{
+		timesheets: NewTypedRepository[models.Timesheet](db, cfg, TimesheetsTable),
+		logger:     log,
+	}
+}
+
+// timesheetID returns the key of the timesheet of userID for weekStart
+func timesheetID(userID, weekStart string) string {
+	return userID + "#" + weekStart
+}
+
+// GetTimesheet returns the timesheet of userID for the week beginning on
+// weekStart, or nil when none was stored
+func (r *TimesheetRepository) GetTimesheet(ctx context.Context, userID, weekStart string) (*models.Timesheet, error) {
+	if userID == "" || weekStart == "" {
+		return nil, errors.New("user ID and week are required")
+	}
+
+	timesheet, err := r.timesheets.ByID(ctx, timesheetID(userID, weekStart))
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return nil, nil
+		}
+		r.logger.Errorf("Failed to get timesheet of %s for week %s: %v", userID, weekStart, err)
+		return nil, err
+	}
+	if timesheet.TimesheetID == "" {
+		return nil, nil
+	}
+	return timesheet, nil
+}
+
+// PutTimesheet stores the timesheet of timesheet.UserID for its week
+func (r *TimesheetRepository) PutTimesheet(ctx context.Context, timesheet *models.Timesheet) (*models.Timesheet, error) {
+	r.logger.Infof("Setting timesheet of %s for week %s to %s", timesheet.UserID, timesheet.WeekStart, timesheet.Status)
+
+	if timesheet.UserID == "" || timesheet.WeekStart == "" {
+		return nil, errors.New("user ID and week are required")
+	}
+
+	timesheet.TimesheetID = timesheetID(timesheet.UserID, timesheet.WeekStart)
+	if err := r.timesheets.Put(ctx, timesheet); err != nil {
+		r.logger.Errorf("Failed to set timesheet: %v", err)
+		return nil, err
+	}
+	return timesheet, nil
+}
@@ -7,42 +7,59 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/utils"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 
 	"fieldfuze-backend/utils/logger"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// ErrUserNotFound is returned when a user does not exist
+var ErrUserNotFound = fmt.Errorf("user %w", dal.ErrNotFound)
+
+// ErrUserRoleNotFound is returned by RemoveRoleFromUser when the user does
+// not have the role
+var ErrUserRoleNotFound = fmt.Errorf("role %w for user", dal.ErrNotFound)
+
+// ErrUserEmailExists is returned when creating a user with the email of
+// another user
+var ErrUserEmailExists = errors.New("user with this email already exists")
+
+// ErrUsernameExists is returned when creating a user with the username of
+// another user
+var ErrUsernameExists = errors.New("user with this username already exists")
+
+// ErrUserHasRole is returned by AssignRoleToUser when the user already has
+// the role
+var ErrUserHasRole = errors.New("user already has this role")
+
 // UserRepository implements UserRepositoryInterface
 type UserRepository struct {
-	db     dal.DatabaseClientInterface
-	config *models.Config
+	users  *Repository[models.User]
+	roles  *Repository[models.RoleAssignment]
 	logger logger.Logger
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db dal.DatabaseClientInterface, cfg *models.Config, log logger.Logger) *UserRepository {
 	return &UserRepository{
-		db:     db,
-		config: cfg,
+		users:  NewTypedRepository[models.User](db, cfg, UsersTable),
+		roles:  NewTypedRepository[models.RoleAssignment](db, cfg, RoleAssignmentsTable),
 		logger: log,
 	}
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	fmt.Println("Creating user:", utils.PrintPrettyJSON(user))
-	existingUser := &models.User{}
-	err := r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_users", "email-index", "email", user.Email, &[]*models.User{existingUser})
+	existingUser, err := r.users.ByIndex(ctx, "email", user.Email)
 	if err == nil && existingUser.ID != "" {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrUserEmailExists
 	}
 
 	// Check if username already exists
-	existingUser = &models.User{}
-	err = r.db.QueryByIndex(ctx, r.config.DynamoDBTablePrefix+"_users", "username-index", "username", user.Username, &[]*models.User{existingUser})
+	existingUser, err = r.users.ByIndex(ctx, "username", user.Username)
 	if err == nil && existingUser.ID != "" {
-		return nil, errors.New("user with this username already exists")
+		return nil, ErrUsernameExists
 	}
 
 	// Set timestamps
@@ -61,7 +78,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (*mo
 	user.Password = hashedPassword
 
 	// Save to database
-	err = r.db.PutItem(ctx, r.config.DynamoDBTablePrefix+"_users", user)
+	err = r.users.Put(ctx, user)
 	if err != nil {
 		r.logger.Errorf("Failed to create user: %v", err)
 		return nil, err
@@ -71,125 +88,63 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (*mo
 	return user, nil
 }
 
-// GetUser retrieves users by ID, email, username, or returns all users if key is empty
-func (r *UserRepository) GetUser(key string) ([]*models.User, error) {
-	ctx := context.Background()
-
-	// If key is empty, return all users
-	if key == "" {
-		var users []*models.User
-		tableName := r.config.DynamoDBTablePrefix + "_users"
-
-		fmt.Printf("Scanning %s table for all users\n", tableName)
-
-		err := r.db.ScanTable(ctx, tableName, &users)
-		if err != nil {
-			r.logger.Errorf("Failed to scan users table: %v", err)
-			return nil, fmt.Errorf("failed to get all users: %w", err)
-		}
+// GetUsers returns every user
+func (r *UserRepository) GetUsers(ctx context.Context) ([]*models.User, error) {
+	fmt.Printf("Scanning %s table for all users\n", r.users.TableName())
 
-		fmt.Printf("Found %d users\n", len(users))
-		return users, nil
+	users, err := r.users.Scan(ctx)
+	if err != nil {
+		r.logger.Errorf("Failed to scan users table: %v", err)
+		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
 
-	// Single user lookup
-	user := models.User{}
+	fmt.Printf("Found %d users\n", len(users))
+	return users, nil
+}
 
-	// Determine the key type and set up query config accordingly
-	keyType, indexName, keyName := r.determineKeyType(key)
+// GetUserByID reads a user by ID
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := r.users.ByID(ctx, id)
+	return r.found(user, r.users.KeyName(), err)
+}
 
-	var config models.QueryConfig
+// GetUserByEmail reads a user through the email index
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := r.users.ByIndex(ctx, "email", email)
+	return r.found(user, "email", err)
+}
 
-	if keyType == "id" {
-		// For ID, use direct GetItem without index
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_users",
-			KeyName:   "id",
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
-	} else {
-		// For email/username, use secondary index
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_users",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  key,
-			KeyType:   models.StringType,
-		}
-	}
+// GetUserCredentials reads a user, password hash included, through the
+// email index
+func (r *UserRepository) GetUserCredentials(ctx context.Context, email string) (*models.User, error) {
+	return r.GetUserByEmail(ctx, email)
+}
 
-	fmt.Printf("Querying %s table with %s: %s\n", r.config.DynamoDBTablePrefix, keyName, key)
+// GetUserByUsername reads a user through the username index
+func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := r.users.ByIndex(ctx, "username", username)
+	return r.found(user, "username", err)
+}
 
-	err := r.db.GetItem(ctx, config, &user)
+// found returns the user read by keyName, or an error if the read failed or
+// matched nothing
+func (r *UserRepository) found(user *models.User, keyName string, err error) (*models.User, error) {
 	if err != nil {
 		r.logger.Errorf("Failed to get user by %s: %v", keyName, err)
 		return nil, fmt.Errorf("failed to get user by %s: %w", keyName, err)
 	}
 
 	if user.ID == "" {
-		return nil, errors.New("user not found")
-	}
-
-	fmt.Println("User found:", utils.PrintPrettyJSON(user))
-	return []*models.User{&user}, nil
-}
-
-// determineKeyType determines if the key is an ID, email, or username
-func (r *UserRepository) determineKeyType(key string) (keyType, indexName, keyName string) {
-	// UUID pattern for ID (format: 8-4-4-4-12 characters)
-	uuidPattern := `^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`
-	isUUID, _ := regexp.MatchString(uuidPattern, strings.ToLower(key))
-
-	if isUUID {
-		return "id", "", "id"
-	} else if strings.Contains(key, "@") {
-		// Contains @ symbol, likely an email
-		return "email", "email-index", "email"
-	} else {
-		// Assume it's a username
-		return "username", "username-index", "username"
+		return nil, ErrUserNotFound
 	}
+	return user, nil
 }
 
-func (r *UserRepository) UpdateUser(id string, user *models.User) (*models.User, error) {
-	ctx := context.Background()
-
-	// Fetch existing user using the same logic as GetUser
-	existingUser := models.User{}
-
-	// Determine the key type and set up query config accordingly
-	keyType, indexName, keyName := r.determineKeyType(id)
-
-	var config models.QueryConfig
-
-	if keyType == "id" {
-		// For ID, use direct GetItem without index
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_users",
-			KeyName:   "id",
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	} else {
-		// For email/username, use secondary index
-		config = models.QueryConfig{
-			TableName: r.config.DynamoDBTablePrefix + "_users",
-			IndexName: indexName,
-			KeyName:   keyName,
-			KeyValue:  id,
-			KeyType:   models.StringType,
-		}
-	}
+func (r *UserRepository) UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error) {
 
-	err := r.db.GetItem(ctx, config, &existingUser)
+	existingUser, err := r.GetUserByID(ctx, id)
 	if err != nil {
-		r.logger.Errorf("Failed to get user by %s: %v", keyName, err)
-		return nil, fmt.Errorf("failed to get user by %s: %w", keyName, err)
-	}
-
-	if existingUser.ID == "" {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Prepare update fields
@@ -221,7 +176,7 @@ func (r *UserRepository) UpdateUser(id string, user *models.User) (*models.User,
 	updates["updated_at"] = time.Now()
 
 	// Save updates
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", existingUser.ID, updates)
+	err = r.users.SetAttributes(ctx, existingUser.ID, updates)
 	if err != nil {
 		r.logger.Errorf("Failed to update user: %v", err)
 		return nil, err
@@ -247,28 +202,15 @@ func (r *UserRepository) UpdateUser(id string, user *models.User) (*models.User,
 	existingUser.UpdatedAt = time.Now()
 
 	r.logger.Infof("User updated successfully: %s", existingUser.ID)
-	return &existingUser, nil
+	return existingUser, nil
 }
 
 // AssignRoles assigns roles to a user
 func (r *UserRepository) AssignRoles(ctx context.Context, userID string, roleAssignments []models.RoleAssignment) (*models.User, error) {
 	// Get existing user
-	user := models.User{}
-	config := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_users",
-		KeyName:   "id",
-		KeyValue:  userID,
-		KeyType:   models.StringType,
-	}
-
-	err := r.db.GetItem(ctx, config, &user)
+	user, err := r.GetUserByID(ctx, userID)
 	if err != nil {
-		r.logger.Errorf("Failed to get user by ID: %v", err)
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
-	}
-
-	if user.ID == "" {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Set assigned timestamp for new roles
@@ -283,7 +225,7 @@ func (r *UserRepository) AssignRoles(ctx context.Context, userID string, roleAss
 		"updated_at": now,
 	}
 
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", userID, updates)
+	err = r.users.SetAttributes(ctx, userID, updates)
 	if err != nil {
 		r.logger.Errorf("Failed to assign roles to user: %v", err)
 		return nil, err
@@ -294,202 +236,142 @@ func (r *UserRepository) AssignRoles(ctx context.Context, userID string, roleAss
 	user.UpdatedAt = now
 
 	r.logger.Infof("Roles assigned successfully to user: %s", userID)
-	return &user, nil
+	return user, nil
 }
 
 // AddRoleToUser adds a single role to a user's existing roles
 func (r *UserRepository) AddRoleToUser(ctx context.Context, userID string, roleAssignment models.RoleAssignment) (*models.User, error) {
 	// Get existing user
-	user := models.User{}
-	config := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_users",
-		KeyName:   "id",
-		KeyValue:  userID,
-		KeyType:   models.StringType,
-	}
-
-	err := r.db.GetItem(ctx, config, &user)
+	user, err := r.GetUserByID(ctx, userID)
 	if err != nil {
-		r.logger.Errorf("Failed to get user by ID: %v", err)
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
-	}
-
-	if user.ID == "" {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
-	// Initialize roles if nil
-	if user.Roles == nil {
-		user.Roles = []models.RoleAssignment{}
-	}
+	roleAssignment.AssignedAt = time.Now()
 
-	// Check if role already exists
+	// Replace the role in place if the user already has it; the condition
+	// guards against the list having been reordered since it was read
 	for i, existingRole := range user.Roles {
 		if existingRole.RoleID == roleAssignment.RoleID {
-			// Update existing role
-			roleAssignment.AssignedAt = time.Now()
-			user.Roles[i] = roleAssignment
+			path := fmt.Sprintf("roles[%d]", i)
+			update := r.users.NewUpdate(userID).
+				Set(path, roleAssignment, dal.Equal(path+".role_id", roleAssignment.RoleID)).
+				Set("updated_at", time.Now())
 
-			updates := map[string]interface{}{
-				"roles":      user.Roles,
-				"updated_at": time.Now(),
-			}
-
-			err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", userID, updates)
+			updatedUser, err := r.users.Update(ctx, update)
 			if err != nil {
+				if isConditionalCheckFailed(err) {
+					return nil, errors.New("user roles were modified concurrently, please retry")
+				}
 				r.logger.Errorf("Failed to update role for user: %v", err)
 				return nil, err
 			}
 
-			user.UpdatedAt = time.Now()
 			r.logger.Infof("Role updated successfully for user: %s", userID)
-			return &user, nil
+			return updatedUser, nil
 		}
 	}
 
 	// Add new role
-	roleAssignment.AssignedAt = time.Now()
-	user.Roles = append(user.Roles, roleAssignment)
-
-	updates := map[string]interface{}{
-		"roles":      user.Roles,
-		"updated_at": time.Now(),
-	}
+	update := r.users.NewUpdate(userID).
+		Append("roles", []models.RoleAssignment{roleAssignment}).
+		Set("updated_at", time.Now())
 
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", userID, updates)
+	updatedUser, err := r.users.Update(ctx, update)
 	if err != nil {
 		r.logger.Errorf("Failed to add role to user: %v", err)
 		return nil, err
 	}
 
-	user.UpdatedAt = time.Now()
 	r.logger.Infof("Role added successfully to user: %s", userID)
-	return &user, nil
+	return updatedUser, nil
 }
 
 // AssignRoleToUser assigns an existing role by ID to a user
 func (r *UserRepository) AssignRoleToUser(ctx context.Context, userID, roleID string) (*models.User, error) {
 	// Get existing user
-	user := models.User{}
-	config := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_users",
-		KeyName:   "id",
-		KeyValue:  userID,
-		KeyType:   models.StringType,
-	}
-
-	err := r.db.GetItem(ctx, config, &user)
+	user, err := r.GetUserByID(ctx, userID)
 	if err != nil {
-		r.logger.Errorf("Failed to get user by ID: %v", err)
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
-	}
-
-	if user.ID == "" {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Get role details from role repository
-	role := models.RoleAssignment{}
-	roleConfig := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_role",
-		KeyName:   "role_id",
-		KeyValue:  roleID,
-		KeyType:   models.StringType,
-	}
-
-	err = r.db.GetItem(ctx, roleConfig, &role)
+	role, err := r.roles.ByID(ctx, roleID)
 	if err != nil {
 		r.logger.Errorf("Failed to get role by ID: %v", err)
-		return nil, errors.New("role not found")
+		return nil, ErrRoleNotFound
 	}
 
-	if role.RoleID == "" {
-		return nil, errors.New("role not found")
-	}
-
-	// Initialize roles if nil
-	if user.Roles == nil {
-		user.Roles = []models.RoleAssignment{}
+	if role.RoleID == "" || role.DeletedData != nil {
+		return nil, ErrRoleNotFound
 	}
 
 	// Check if user already has this role
 	for _, existingRole := range user.Roles {
 		if existingRole.RoleID == roleID {
-			return nil, errors.New("user already has this role")
+			return nil, ErrUserHasRole
 		}
 	}
 
-	// Add role to user with assigned timestamp
+	// Append role to user with assigned timestamp
 	role.AssignedAt = time.Now()
-	user.Roles = append(user.Roles, role)
+	update := r.users.NewUpdate(userID).
+		Append("roles", []models.RoleAssignment{*role}).
+		Set("updated_at", time.Now())
 
-	updates := map[string]interface{}{
-		"roles":      user.Roles,
-		"updated_at": time.Now(),
-	}
-
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", userID, updates)
+	updatedUser, err := r.users.Update(ctx, update)
 	if err != nil {
 		r.logger.Errorf("Failed to assign role to user: %v", err)
 		return nil, err
 	}
 
-	user.UpdatedAt = time.Now()
 	r.logger.Infof("Role %s assigned successfully to user: %s", roleID, userID)
-	return &user, nil
+	return updatedUser, nil
 }
 
 // RemoveRoleFromUser removes a role from a user
 func (r *UserRepository) RemoveRoleFromUser(ctx context.Context, userID, roleID string) (*models.User, error) {
 	// Get existing user
-	user := models.User{}
-	config := models.QueryConfig{
-		TableName: r.config.DynamoDBTablePrefix + "_users",
-		KeyName:   "id",
-		KeyValue:  userID,
-		KeyType:   models.StringType,
-	}
-
-	err := r.db.GetItem(ctx, config, &user)
+	user, err := r.GetUserByID(ctx, userID)
 	if err != nil {
-		r.logger.Errorf("Failed to get user by ID: %v", err)
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
-	}
-
-	if user.ID == "" {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
-	// Find and remove the role
-	updatedRoles := []models.RoleAssignment{}
-	roleFound := false
-
-	for _, role := range user.Roles {
-		if role.RoleID != roleID {
-			updatedRoles = append(updatedRoles, role)
-		} else {
-			roleFound = true
+	// Find the role's position in the list
+	roleIndex := -1
+	for i, role := range user.Roles {
+		if role.RoleID == roleID {
+			roleIndex = i
+			break
 		}
 	}
 
-	if !roleFound {
-		return nil, errors.New("role not found for user")
+	if roleIndex < 0 {
+		return nil, ErrUserRoleNotFound
 	}
 
-	user.Roles = updatedRoles
-
-	updates := map[string]interface{}{
-		"roles":      user.Roles,
-		"updated_at": time.Now(),
-	}
+	// Remove the element by index, conditioned on it still being the same role
+	path := fmt.Sprintf("roles[%d]", roleIndex)
+	update := r.users.NewUpdate(userID).
+		Remove(path, dal.Equal(path+".role_id", roleID)).
+		Set("updated_at", time.Now())
 
-	err = r.db.UpdateItem(ctx, r.config.DynamoDBTablePrefix+"_users", "id", userID, updates)
+	updatedUser, err := r.users.Update(ctx, update)
 	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, errors.New("user roles were modified concurrently, please retry")
+		}
 		r.logger.Errorf("Failed to remove role from user: %v", err)
 		return nil, err
 	}
 
-	user.UpdatedAt = time.Now()
 	r.logger.Infof("Role removed successfully from user: %s", userID)
-	return &user, nil
+	return updatedUser, nil
+}
+
+// isConditionalCheckFailed reports whether err was caused by a failed
+// condition expression on a conditional write
+func isConditionalCheckFailed(err error) bool {
+	var conditionalErr *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionalErr)
 }
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned for activity feed cursors the service did not
+// hand out
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidComment is returned for comments without a body, with a body
+// that is too long or with too many mentions
+var ErrInvalidComment = errors.New("invalid comment")
+
+// ErrParentCommentNotFound is returned when replying to a comment that does
+// not exist
+var ErrParentCommentNotFound = fmt.Errorf("parent comment %w", dal.ErrNotFound)
+
+const (
+	defaultActivityPageSize = 50
+	maxActivityPageSize     = 100
+	maxCommentMentions      = 20
+)
+
+// mentionPattern matches @username mentions. The @ must not follow a word
+// character so email addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9][A-Za-z0-9._-]{0,63})`)
+
+type ActivityService struct {
+	activityRepo repository.ActivityRepositoryInterface
+	jobRepo      repository.JobRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	logger       logger.Logger
+}
+
+func NewActivityService(activityRepo repository.ActivityRepositoryInterface, jobRepo repository.JobRepositoryInterface, userRepo repository.UserRepositoryInterface, logger logger.Logger) *ActivityService {
+	return &ActivityService{
+		activityRepo: activityRepo,
+		jobRepo:      jobRepo,
+		userRepo:     userRepo,
+		logger:       logger,
+	}
+}
+
+// GetActivity returns a page of the activity feed of job jobID, newest
+// first. cursor is the NextCursor of the previous page, empty for the first
+// page; limit defaults to 50 and is capped at 100.
+func (s *ActivityService) GetActivity(ctx context.Context, jobID string, cursor string, limit int) (*models.ActivityPage, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultActivityPageSize
+	}
+	if limit > maxActivityPageSize {
+		limit = maxActivityPageSize
+	}
+
+	before := ""
+	if cursor != "" {
+		if before, err = decodeActivityCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// One extra entry tells whether another page follows
+	activities, err := s.activityRepo.GetActivitiesByJob(ctx, job.JobID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ActivityPage{Items: activities}
+	if len(activities) > limit {
+		page.Items = activities[:limit]
+		page.NextCursor = encodeActivityCursor(page.Items[limit-1].Sequence)
+	}
+	if page.Items == nil {
+		page.Items = []*models.Activity{}
+	}
+	return page, nil
+}
+
+// CreateComment posts a comment on job jobID. Mentioned users are resolved
+// by username and kept when they belong to the job's organization; a reply
+// joins the thread of the comment it answers.
+func (s *ActivityService) CreateComment(ctx context.Context, jobID string, req *models.CreateCommentRequest, authorID string) (*models.Activity, error) {
+	if req == nil {
+		return nil, errors.New("comment request is required")
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: comment body is required", ErrInvalidComment)
+	}
+	if len(body) > 2000 {
+		return nil, fmt.Errorf("%w: comment body must be less than 2000 characters", ErrInvalidComment)
+	}
+
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Activity{
+		JobID:   job.JobID,
+		OrgID:   job.OrgID,
+		Type:    models.ActivityComment,
+		ActorID: authorID,
+		Body:    body,
+		Summary: "Commented",
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.activityRepo.GetActivity(ctx, req.ParentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrActivityNotFound) {
+				return nil, ErrParentCommentNotFound
+			}
+			return nil, err
+		}
+		if parent.JobID != job.JobID || parent.Type != models.ActivityComment {
+			return nil, ErrParentCommentNotFound
+		}
+
+		comment.ParentID = parent.ActivityID
+		comment.ThreadID = parent.ThreadID
+		if comment.ThreadID == "" {
+			comment.ThreadID = parent.ActivityID
+		}
+		comment.Summary = "Replied to a comment"
+	}
+
+	if comment.Mentions, err = s.resolveMentions(ctx, body, job.OrgID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.activityRepo.CreateActivity(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Comment %s posted on job %s by %s mentioning %d users", created.ActivityID, job.JobID, authorID, len(created.Mentions))
+	return created, nil
+}
+
+// resolveMentions returns the IDs of the active users of orgID mentioned in
+// body. Unknown usernames are ignored.
+func (s *ActivityService) resolveMentions(ctx context.Context, body string, orgID string) ([]string, error) {
+	seen := make(map[string]bool)
+	var mentions []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		if len(seen) > maxCommentMentions {
+			return nil, fmt.Errorf("%w: a comment may mention at most %d users", ErrInvalidComment, maxCommentMentions)
+		}
+
+		user, err := s.userRepo.GetUserByUsername(ctx, username)
+		if err != nil && !dal.IsNotFound(err) {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+
+		if user.Status != models.UserStatusActive || !belongsToOrganization(user, orgID) {
+			continue
+		}
+		mentions = append(mentions, user.ID)
+	}
+	return mentions, nil
+}
+
+// getJob returns job jobID of the organization in ctx
+func (s *ActivityService) getJob(ctx context.Context, jobID string) (*models.Job, error) {
+	jobs, err := s.jobRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, repository.ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// encodeActivityCursor turns the sequence of the last entry of a page into
+// an opaque cursor
+func encodeActivityCursor(sequence string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(sequence))
+}
+
+func decodeActivityCursor(cursor string) (string, error) {
+	sequence, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.Contains(string(sequence), "#") {
+		return "", ErrInvalidCursor
+	}
+	return string(sequence), nil
+}
+
+// recordActivity appends activity to its job's feed. The change it records
+// has already been stored, so failures are logged instead of returned.
+func recordActivity(ctx context.Context, activityRepo repository.ActivityRepositoryInterface, logger logger.Logger, activity *models.Activity) {
+	if _, err := activityRepo.CreateActivity(ctx, activity); err != nil {
+		logger.Warnf("Failed to record %s activity of job %s: %v", activity.Type, activity.JobID, err)
+	}
+}
+
+// trackedJobFields are the job fields whose changes are recorded in the
+// activity feed, with their value rendered as text. Status, assignees and
+// checklist items have entries of their own.
+var trackedJobFields = []struct {
+	name  string
+	value func(job *models.Job) string
+}{
+	{"jobsName", func(job *models.Job) string { return job.JobsName }},
+	{"jobType", func(job *models.Job) string { return string(job.JobType) }},
+	{"priority", func(job *models.Job) string { return string(job.Priority) }},
+	{"notes", func(job *models.Job) string { return job.Notes }},
+	{"qbInfoOnJob", func(job *models.Job) string {
+		if job.QBInfoOnJob == nil {
+			return ""
+		}
+		return jsonValue(job.QBInfoOnJob)
+	}},
+	{"jobImagesAfterService", func(job *models.Job) string {
+		if len(job.JobImagesAfterService) == 0 {
+			return ""
+		}
+		return jsonValue(job.JobImagesAfterService)
+	}},
+	{"scheduledStart", func(job *models.Job) string { return timeValue(job.ScheduledStart) }},
+	{"scheduledEnd", func(job *models.Job) string { return timeValue(job.ScheduledEnd) }},
+	{"arrivalWindow", func(job *models.Job) string {
+		if job.ArrivalWindow == nil {
+			return ""
+		}
+		return jsonValue(job.ArrivalWindow)
+	}},
+	{"estimatedDuration", func(job *models.Job) string {
+		if job.EstimatedDuration == 0 {
+			return ""
+		}
+		return strconv.Itoa(job.EstimatedDuration)
+	}},
+	{"timezone", func(job *models.Job) string { return job.Timezone }},
+	{"site", func(job *models.Job) string {
+		if job.Site == nil {
+			return ""
+		}
+		return jsonValue(job.Site)
+	}},
+	{"projectID", func(job *models.Job) string { return job.ProjectID }},
+}
+
+// jobChanges returns the tracked fields that differ between before and after
+func jobChanges(before, after *models.Job) []models.FieldChange {
+	var changes []models.FieldChange
+	for _, field := range trackedJobFields {
+		from, to := field.value(before), field.value(after)
+		if from != to {
+			changes = append(changes, models.FieldChange{Field: field.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// jobUpdateActivities returns the feed entries for the update of before into
+// after by actor: one for changed fields, one for changed assignees and one
+// for a status change
+func jobUpdateActivities(before, after *models.Job, actor string) []*models.Activity {
+	var activities []*models.Activity
+
+	if changes := jobChanges(before, after); len(changes) > 0 {
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+		activities = append(activities, &models.Activity{
+			JobID:   after.JobID,
+			OrgID:   after.OrgID,
+			Type:    models.ActivityJobUpdated,
+			ActorID: actor,
+			Summary: "Updated " + strings.Join(fields, ", "),
+			Changes: changes,
+		})
+	}
+
+	if assignment := assignmentActivity(before, after, actor); assignment != nil {
+		activities = append(activities, assignment)
+	}
+
+	if before.JobStatus != after.JobStatus {
+		activities = append(activities, statusActivity(after, before.JobStatus, actor))
+	}
+	return activities
+}
+
+// assignmentActivity returns the feed entry for the assignees added to and
+// removed from a job, or nil when they did not change. A nil before records
+// the assignees of a new job.
+func assignmentActivity(before, after *models.Job, actor string) *models.Activity {
+	var usersBefore, vehiclesBefore []string
+	if before != nil {
+		usersBefore, vehiclesBefore = before.UsersAssignedToJob, before.VehiclesAssignedToJob
+	}
+
+	activity := &models.Activity{
+		JobID:           after.JobID,
+		OrgID:           after.OrgID,
+		Type:            models.ActivityAssignmentChanged,
+		ActorID:         actor,
+		UsersAdded:      missingFrom(after.UsersAssignedToJob, usersBefore),
+		UsersRemoved:    missingFrom(usersBefore, after.UsersAssignedToJob),
+		VehiclesAdded:   missingFrom(after.VehiclesAssignedToJob, vehiclesBefore),
+		VehiclesRemoved: missingFrom(vehiclesBefore, after.VehiclesAssignedToJob),
+	}
+
+	var parts []string
+	if n := len(activity.UsersAdded); n > 0 {
+		parts = append(parts, fmt.Sprintf("assigned %d users", n))
+	}
+	if n := len(activity.UsersRemoved); n > 0 {
+		parts = append(parts, fmt.Sprintf("unassigned %d users", n))
+	}
+	if n := len(activity.VehiclesAdded); n > 0 {
+		parts = append(parts, fmt.Sprintf("assigned %d vehicles", n))
+	}
+	if n := len(activity.VehiclesRemoved); n > 0 {
+		parts = append(parts, fmt.Sprintf("unassigned %d vehicles", n))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	summary := strings.Join(parts, ", ")
+	activity.Summary = strings.ToUpper(summary[:1]) + summary[1:]
+	return activity
+}
+
+// statusActivity returns the feed entry for job moving from status from to
+// its current status. The reason is taken from the latest status change.
+func statusActivity(job *models.Job, from models.JobStatus, actor string) *models.Activity {
+	activity := &models.Activity{
+		JobID:      job.JobID,
+		OrgID:      job.OrgID,
+		Type:       models.ActivityStatusChanged,
+		ActorID:    actor,
+		Summary:    fmt.Sprintf("Status changed from %s to %s", from, job.JobStatus),
+		StatusFrom: from,
+		StatusTo:   job.JobStatus,
+	}
+	if n := len(job.StatusHistory); n > 0 && job.StatusHistory[n-1].To == job.JobStatus {
+		activity.Reason = job.StatusHistory[n-1].Reason
+	}
+	return activity
+}
+
+// missingFrom returns the values of a that are not in b
+func missingFrom(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, value := range b {
+		present[value] = true
+	}
+
+	var missing []string
+	for _, value := range a {
+		if !present[value] {
+			missing = append(missing, value)
+			present[value] = true
+		}
+	}
+	return missing
+}
+
+func jsonValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func timeValue(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
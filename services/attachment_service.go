@@ -0,0 +1,316 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/thumbnail"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrAttachmentTooLarge is returned for uploads over the configured size
+// limit
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// ErrUnsupportedAttachment is returned for files whose content is not an
+// accepted type for their category
+var ErrUnsupportedAttachment = errors.New("unsupported attachment type")
+
+// ErrAttachmentEmpty is returned for uploads without content
+var ErrAttachmentEmpty = errors.New("attachment is empty")
+
+// ErrAttachmentLocked is returned when deleting the signature or completion
+// summary of a signed-off job
+var ErrAttachmentLocked = errors.New("attachment is locked")
+
+// thumbnailSize is the longest side of generated thumbnails in pixels
+const thumbnailSize = 320
+
+// photoTypes are the content types accepted as before and after photos,
+// with the extension they are stored under
+var photoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// documentTypes are the content types accepted as documents besides photos
+var documentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepositoryInterface
+	jobRepo        repository.JobRepositoryInterface
+	activityRepo   repository.ActivityRepositoryInterface
+	store          blob.BlobStore
+	maxSize        int64
+	urlTTL         time.Duration
+	logger         logger.Logger
+}
+
+// NewAttachmentService creates an attachment service that keeps files of up
+// to maxSize bytes in store and hands out download links valid for urlTTL
+func NewAttachmentService(attachmentRepo repository.AttachmentRepositoryInterface, jobRepo repository.JobRepositoryInterface, activityRepo repository.ActivityRepositoryInterface, store blob.BlobStore, maxSize int64, urlTTL time.Duration, logger logger.Logger) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		jobRepo:        jobRepo,
+		activityRepo:   activityRepo,
+		store:          store,
+		maxSize:        maxSize,
+		urlTTL:         urlTTL,
+		logger:         logger,
+	}
+}
+
+// MaxUploadSize returns the largest accepted attachment in bytes
+func (s *AttachmentService) MaxUploadSize() int64 {
+	return s.maxSize
+}
+
+// UploadAttachment stores the file read from body as an attachment of job
+// jobID. The content type is sniffed from the content: before and after
+// photos must be images, documents may also be PDFs or plain text. Images
+// the standard decoders can read get a thumbnail.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, jobID string, category models.AttachmentCategory, fileName string, body io.Reader, uploadedBy string) (*models.Attachment, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: files may be at most %d MB", ErrAttachmentTooLarge, s.maxSize>>20)
+	}
+	if len(data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+
+	contentType, ext, err := sniffAttachment(category, data)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentID := utils.GenerateUUID()
+	prefix := fmt.Sprintf("orgs/%s/jobs/%s/%s", job.OrgID, job.JobID, attachmentID)
+	attachment := &models.Attachment{
+		AttachmentID: attachmentID,
+		JobID:        job.JobID,
+		OrgID:        job.OrgID,
+		Category:     category,
+		FileName:     cleanFileName(fileName, ext),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		BlobKey:      prefix + ext,
+		UploadedBy:   uploadedBy,
+	}
+
+	if err := s.store.Put(ctx, attachment.BlobKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		// A missing thumbnail only costs the client a preview
+		thumb, err := thumbnail.Generate(bytes.NewReader(data), thumbnailSize)
+		switch {
+		case err != nil:
+			s.logger.Warnf("No thumbnail for attachment %s: %v", attachmentID, err)
+		default:
+			key := prefix + "_thumb.jpg"
+			if err := s.store.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), thumbnail.ContentType); err != nil {
+				s.logger.Warnf("Failed to store thumbnail of attachment %s: %v", attachmentID, err)
+			} else {
+				attachment.ThumbnailKey = key
+			}
+		}
+	}
+
+	created, err := s.attachmentRepo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.removeBlobs(ctx, attachment)
+		return nil, err
+	}
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:        job.JobID,
+		OrgID:        job.OrgID,
+		Type:         models.ActivityAttachmentAdded,
+		ActorID:      uploadedBy,
+		Summary:      fmt.Sprintf("Uploaded %s (%s)", created.FileName, category),
+		AttachmentID: created.AttachmentID,
+	})
+
+	s.logger.Infof("Attachment %s (%s, %d bytes) uploaded to job %s by %s", attachmentID, contentType, attachment.Size, job.JobID, uploadedBy)
+	return s.withURLs(ctx, created)
+}
+
+// GetAttachments returns the attachments of job jobID with fresh download
+// links
+func (s *AttachmentService) GetAttachments(ctx context.Context, jobID string) ([]*models.Attachment, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByJob(ctx, job.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		if _, err := s.withURLs(ctx, attachment); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// GetAttachment returns an attachment of job jobID with fresh download links
+func (s *AttachmentService) GetAttachment(ctx context.Context, jobID, attachmentID string) (*models.Attachment, error) {
+	attachment, err := s.getAttachment(ctx, jobID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withURLs(ctx, attachment)
+}
+
+// DeleteAttachment soft-deletes an attachment and removes its files from
+// the blob store. Sign-off attachments are kept for the life of the job.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, jobID, attachmentID string, deletedBy string, reason string) error {
+	attachment, err := s.getAttachment(ctx, jobID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.Category == models.AttachmentCategorySignOff {
+		return fmt.Errorf("%w: sign-off attachments cannot be deleted", ErrAttachmentLocked)
+	}
+
+	if err := s.attachmentRepo.DeleteAttachment(ctx, attachment.AttachmentID, newDeletedData(deletedBy, reason)); err != nil {
+		return err
+	}
+	s.removeBlobs(ctx, attachment)
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:        attachment.JobID,
+		OrgID:        attachment.OrgID,
+		Type:         models.ActivityAttachmentRemoved,
+		ActorID:      deletedBy,
+		Summary:      fmt.Sprintf("Removed %s", attachment.FileName),
+		Reason:       strings.TrimSpace(reason),
+		AttachmentID: attachment.AttachmentID,
+	})
+	return nil
+}
+
+// getJob returns job jobID of the organization in ctx
+func (s *AttachmentService) getJob(ctx context.Context, jobID string) (*models.Job, error) {
+	jobs, err := s.jobRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, repository.ErrJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// getAttachment returns attachmentID provided it belongs to job jobID
+func (s *AttachmentService) getAttachment(ctx context.Context, jobID, attachmentID string) (*models.Attachment, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.JobID != job.JobID {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// withURLs sets the signed download links of attachment
+func (s *AttachmentService) withURLs(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	expiresAt := time.Now().Add(s.urlTTL).UTC()
+
+	url, err := s.store.SignedURL(ctx, attachment.BlobKey, s.urlTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign attachment URL: %w", err)
+	}
+	attachment.URL = url
+	attachment.URLExpiresAt = &expiresAt
+
+	if attachment.ThumbnailKey != "" {
+		thumbnailURL, err := s.store.SignedURL(ctx, attachment.ThumbnailKey, s.urlTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign thumbnail URL: %w", err)
+		}
+		attachment.ThumbnailURL = thumbnailURL
+	}
+	return attachment, nil
+}
+
+// removeBlobs deletes the files of attachment, logging failures
+func (s *AttachmentService) removeBlobs(ctx context.Context, attachment *models.Attachment) {
+	for _, key := range []string{attachment.BlobKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			s.logger.Warnf("Failed to delete blob %s of attachment %s: %v", key, attachment.AttachmentID, err)
+		}
+	}
+}
+
+// sniffAttachment detects the content type of data and checks it is
+// accepted for category. It returns the type without parameters and the
+// extension to store the file under.
+func sniffAttachment(category models.AttachmentCategory, data []byte) (string, string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: content type cannot be detected", ErrUnsupportedAttachment)
+	}
+
+	if ext, ok := photoTypes[contentType]; ok {
+		return contentType, ext, nil
+	}
+	if ext, ok := documentTypes[contentType]; ok && category == models.AttachmentCategoryDocument {
+		return contentType, ext, nil
+	}
+
+	if category == models.AttachmentCategoryDocument {
+		return "", "", fmt.Errorf("%w: %s is not an image, PDF or text file", ErrUnsupportedAttachment, contentType)
+	}
+	return "", "", fmt.Errorf("%w: %s photos must be images, got %s", ErrUnsupportedAttachment, category, contentType)
+}
+
+// cleanFileName strips any directory from a client file name, falling back
+// to a generic name with ext
+func cleanFileName(name, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment" + ext
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecklistIncomplete is returned when a job is completed while required
+// checklist items are open
+var ErrChecklistIncomplete = errors.New("checklist incomplete")
+
+// ErrInvalidChecklistValue is returned when a checklist item is completed
+// without the value its type asks for
+var ErrInvalidChecklistValue = errors.New("invalid checklist value")
+
+// ErrChecklistItemNotFound is returned when a job has no checklist item
+// with the given ID
+var ErrChecklistItemNotFound = fmt.Errorf("checklist item %w", dal.ErrNotFound)
+
+type ChecklistService struct {
+	checklistRepo repository.ChecklistRepositoryInterface
+	logger        logger.Logger
+}
+
+func NewChecklistService(checklistRepo repository.ChecklistRepositoryInterface, logger logger.Logger) *ChecklistService {
+	return &ChecklistService{
+		checklistRepo: checklistRepo,
+		logger:        logger,
+	}
+}
+
+func (s *ChecklistService) CreateChecklistTemplate(ctx context.Context, req *models.CreateChecklistTemplateRequest, createdBy string) (*models.ChecklistTemplate, error) {
+	if req == nil {
+		return nil, errors.New("checklist template request is required")
+	}
+
+	if strings.TrimSpace(req.OrgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("checklist template name is required")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, errors.New("checklist template needs at least one item")
+	}
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.ChecklistTemplate{
+		OrgID:   req.OrgID,
+		JobType: req.JobType,
+		Name:    strings.TrimSpace(req.Name),
+		Items:   newChecklistTemplateItems(req.Items),
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	}
+	return s.checklistRepo.CreateChecklistTemplate(ctx, template)
+}
+
+func (s *ChecklistService) GetChecklistTemplate(ctx context.Context, id string) (*models.ChecklistTemplate, error) {
+	return s.checklistRepo.GetChecklistTemplate(ctx, id)
+}
+
+func (s *ChecklistService) GetChecklistTemplates(ctx context.Context, orgID string) ([]*models.ChecklistTemplate, error) {
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.GetChecklistTemplatesByOrganization(ctx, orgID)
+}
+
+// UpdateChecklistTemplate changes a checklist template. Only jobs created
+// afterwards get the new items.
+func (s *ChecklistService) UpdateChecklistTemplate(ctx context.Context, id string, req *models.UpdateChecklistTemplateRequest, updatedBy string) (*models.ChecklistTemplate, error) {
+	if req == nil {
+		return nil, errors.New("checklist template request is required")
+	}
+
+	existing, err := s.checklistRepo.GetChecklistTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = strings.TrimSpace(req.Name)
+	}
+	if req.JobType != "" {
+		updated.JobType = req.JobType
+	}
+	if req.Items != nil {
+		if len(req.Items) == 0 {
+			return nil, errors.New("checklist template needs at least one item")
+		}
+		updated.Items = newChecklistTemplateItems(req.Items)
+	}
+	updated.UpdatedBy = updatedBy
+
+	return s.checklistRepo.UpdateChecklistTemplate(ctx, id, &updated)
+}
+
+func (s *ChecklistService) DeleteChecklistTemplate(ctx context.Context, id string, deletedBy string, reason string) error {
+	return s.checklistRepo.DeleteChecklistTemplate(ctx, id, newDeletedData(deletedBy, reason))
+}
+
+// newChecklistTemplateItems gives every item of a template request an ID
+func newChecklistTemplateItems(inputs []models.ChecklistTemplateItemInput) []models.ChecklistTemplateItem {
+	items := make([]models.ChecklistTemplateItem, 0, len(inputs))
+	for _, input := range inputs {
+		valueType := input.ValueType
+		if valueType == "" {
+			valueType = models.ChecklistValueNone
+		}
+		items = append(items, models.ChecklistTemplateItem{
+			ItemID:    utils.GenerateUUID(),
+			Label:     strings.TrimSpace(input.Label),
+			Required:  input.Required,
+			ValueType: valueType,
+		})
+	}
+	return items
+}
+
+// newJobChecklist copies the items of the checklist templates of orgID that
+// apply to jobType, oldest template first. Templates without a job type
+// apply to every job.
+func newJobChecklist(ctx context.Context, checklistRepo repository.ChecklistRepositoryInterface, orgID string, jobType models.JobType) ([]models.ChecklistItem, error) {
+	templates, err := checklistRepo.GetChecklistTemplatesByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist templates: %w", err)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+	})
+
+	var checklist []models.ChecklistItem
+	for _, template := range templates {
+		if template.JobType != "" && template.JobType != jobType {
+			continue
+		}
+		for _, item := range template.Items {
+			checklist = append(checklist, models.ChecklistItem{
+				ItemID:     item.ItemID,
+				TemplateID: template.TemplateID,
+				Label:      item.Label,
+				Required:   item.Required,
+				ValueType:  item.ValueType,
+			})
+		}
+	}
+	return checklist, nil
+}
+
+// UpdateChecklistItem completes or reopens the checklist item itemID of job
+// id. A completed item records who completed it and when; readings must be
+// numbers, yes/no items "yes" or "no" and photo items carry the photo URL.
+func (s *JobService) UpdateChecklistItem(ctx context.Context, id, itemID string, req *models.UpdateChecklistItemRequest, updatedBy string) (*models.Job, error) {
+	if req == nil {
+		return nil, errors.New("checklist item request is required")
+	}
+
+	job, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.JobStatus == models.JobStatusCompleted || job.JobStatus == models.JobStatusCancelled {
+		return nil, fmt.Errorf("%w: the checklist of a %s job cannot change", ErrInvalidJobTransition, job.JobStatus)
+	}
+
+	index := -1
+	for i, item := range job.Checklist {
+		if item.ItemID == itemID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrChecklistItemNotFound
+	}
+
+	item := job.Checklist[index]
+	item.Notes = strings.TrimSpace(req.Notes)
+	if req.Completed {
+		value, err := checklistValue(item.ValueType, req.Value)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC()
+		item.Completed = true
+		item.CompletedBy = updatedBy
+		item.CompletedAt = &now
+		item.Value = value
+	} else {
+		item.Completed = false
+		item.CompletedBy = ""
+		item.CompletedAt = nil
+		item.Value = ""
+	}
+
+	updated, err := s.jobRepo.UpdateChecklistItem(ctx, job.JobID, index, &item, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("Reopened checklist item %q", item.Label)
+	if item.Completed {
+		summary = fmt.Sprintf("Completed checklist item %q", item.Label)
+	}
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:           job.JobID,
+		OrgID:           job.OrgID,
+		Type:            models.ActivityChecklistUpdated,
+		ActorID:         updatedBy,
+		Summary:         summary,
+		ChecklistItemID: item.ItemID,
+		Changes: []models.FieldChange{{
+			Field: "value",
+			From:  job.Checklist[index].Value,
+			To:    item.Value,
+		}},
+	})
+
+	s.logger.Infof("Checklist item %s of job %s set to completed=%t by %s", itemID, job.JobID, item.Completed, updatedBy)
+	return updated, nil
+}
+
+// checklistValue checks value against the value type of a checklist item and
+// returns it normalised
+func checklistValue(valueType models.ChecklistValueType, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch valueType {
+	case models.ChecklistValueReading:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("%w: a reading must be a number", ErrInvalidChecklistValue)
+		}
+	case models.ChecklistValueYesNo:
+		value = strings.ToLower(value)
+		if value != "yes" && value != "no" {
+			return "", fmt.Errorf("%w: answer must be yes or no", ErrInvalidChecklistValue)
+		}
+	case models.ChecklistValuePhoto:
+		if value == "" {
+			return "", fmt.Errorf("%w: a photo is required", ErrInvalidChecklistValue)
+		}
+	}
+	return value, nil
+}
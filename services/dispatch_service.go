@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/geo"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// defaultStopDuration is planned for jobs without an estimated duration or
+// scheduled end
+const defaultStopDuration = time.Hour
+
+// defaultRouteStart is when routes start for technicians without working
+// hours on the day
+const defaultRouteStart = "08:00"
+
+type DispatchService struct {
+	jobRepo          repository.JobRepositoryInterface
+	orgRepo          repository.OrganizationRepositoryInterface
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	activityRepo     repository.ActivityRepositoryInterface
+	matrix           geo.DistanceMatrix
+	logger           logger.Logger
+}
+
+// NewDispatchService creates a dispatch service that estimates travel with
+// matrix
+func NewDispatchService(jobRepo repository.JobRepositoryInterface, orgRepo repository.OrganizationRepositoryInterface, availabilityRepo repository.AvailabilityRepositoryInterface, activityRepo repository.ActivityRepositoryInterface, matrix geo.DistanceMatrix, logger logger.Logger) *DispatchService {
+	return &DispatchService{
+		jobRepo:          jobRepo,
+		orgRepo:          orgRepo,
+		availabilityRepo: availabilityRepo,
+		activityRepo:     activityRepo,
+		matrix:           matrix,
+		logger:           logger,
+	}
+}
+
+// OptimizeRoutes orders the pending and active jobs each technician is
+// scheduled for on the requested day, from the technician's start location
+// and within the arrival windows of the jobs. Jobs assigned to several of
+// the technicians are routed for the first of them. With req.Apply the
+// routed jobs are rescheduled to their planned start and end.
+func (s *DispatchService) OptimizeRoutes(ctx context.Context, req *models.OptimizeRoutesRequest, requestedBy string) (*models.RouteOptimization, error) {
+	if req == nil {
+		return nil, errors.New("route optimization request is required")
+	}
+	if strings.TrimSpace(req.OrgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+	if len(req.Technicians) == 0 {
+		return nil, errors.New("at least one technician is required")
+	}
+
+	// Jobs are stored with the rest of their organization's jobs
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidSchedule)
+	}
+	if req.StartTime != "" {
+		if _, err := time.Parse("15:04", req.StartTime); err != nil {
+			return nil, fmt.Errorf("%w: start time must be HH:MM", ErrInvalidSchedule)
+		}
+	}
+
+	result := &models.RouteOptimization{
+		OrgID:    req.OrgID,
+		Date:     req.Date,
+		Timezone: loc.String(),
+		Routes:   make([]models.TechnicianRoute, 0, len(req.Technicians)),
+	}
+
+	routed := make(map[string]string)
+	jobs := make(map[string]*models.Job)
+	seen := make(map[string]bool)
+	for _, userID := range req.Technicians {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		route, err := s.planRoute(ctx, req.OrgID, userID, day, req.StartTime, routed, jobs)
+		if err != nil {
+			return nil, err
+		}
+		result.Routes = append(result.Routes, *route)
+	}
+
+	if req.Apply {
+		for _, route := range result.Routes {
+			if err := s.applyRoute(ctx, &route, jobs, requestedBy); err != nil {
+				return nil, err
+			}
+		}
+		result.Applied = true
+	}
+
+	s.logger.Infof("Optimized routes of %d technicians of organization %s for %s", len(result.Routes), req.OrgID, req.Date)
+	return result, nil
+}
+
+// planRoute plans the route of userID on day. routed maps the jobs already
+// routed to their technician and jobs collects the routed jobs by ID.
+func (s *DispatchService) planRoute(ctx context.Context, orgID, userID string, day time.Time, startTime string, routed map[string]string, jobs map[string]*models.Job) (*models.TechnicianRoute, error) {
+	// Stored starts are whole minutes, so the second before the next day
+	// excludes it
+	scheduled, err := s.jobRepo.GetScheduledJobs(ctx, orgID, day, day.AddDate(0, 0, 1).Add(-time.Second), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability, err := s.availabilityRepo.GetAvailability(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	route := &models.TechnicianRoute{
+		UserID:    userID,
+		StartTime: routeStart(day, startTime, availability),
+		Stops:     []models.RouteStop{},
+	}
+
+	var candidates []routeJob
+	for _, job := range scheduled {
+		reason := ""
+		switch {
+		case routed[job.JobID] != "":
+			reason = "routed for technician " + routed[job.JobID]
+		case job.JobStatus != models.JobStatusPending && job.JobStatus != models.JobStatusActive:
+			reason = fmt.Sprintf("job is %s", job.JobStatus)
+		case !job.Site.HasCoordinates():
+			reason = "job site location is unknown"
+		}
+		if reason != "" {
+			route.Unrouted = append(route.Unrouted, models.UnroutedJob{JobID: job.JobID, JobsName: job.JobsName, Reason: reason})
+			continue
+		}
+
+		routed[job.JobID] = userID
+		jobs[job.JobID] = job
+		candidates = append(candidates, routeJob{job: job, duration: stopDuration(job)})
+	}
+
+	points := make([]geo.Point, 0, len(candidates)+1)
+	hasOrigin := availability != nil && availability.StartLocation.HasCoordinates()
+	if hasOrigin {
+		route.StartLocation = availability.StartLocation
+		points = append(points, geo.Point{Latitude: *availability.StartLocation.Latitude, Longitude: *availability.StartLocation.Longitude})
+	}
+	for _, candidate := range candidates {
+		points = append(points, geo.Point{Latitude: *candidate.job.Site.Latitude, Longitude: *candidate.job.Site.Longitude})
+	}
+
+	legs, err := s.matrix.Matrix(ctx, points)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate travel for technician %s: %w", userID, err)
+	}
+
+	planner := &routePlanner{
+		start:     route.StartTime,
+		hasOrigin: hasOrigin,
+		legs:      legs,
+		jobs:      candidates,
+	}
+	cost, stops := planner.simulate(planner.plan(), true)
+
+	route.Stops = stops
+	route.EndTime = cost.finish
+	route.TravelMinutes = int(cost.travel / time.Minute)
+	for _, stop := range stops {
+		route.TravelMeters += stop.TravelMeters
+		if stop.LateMinutes > 0 {
+			route.LateStops++
+		}
+	}
+	route.TravelMeters = math.Round(route.TravelMeters)
+	return route, nil
+}
+
+// applyRoute reschedules the jobs of route to their planned start and end
+func (s *DispatchService) applyRoute(ctx context.Context, route *models.TechnicianRoute, jobs map[string]*models.Job, updatedBy string) error {
+	for _, stop := range route.Stops {
+		existing := jobs[stop.JobID]
+		start, end := stop.StartAt.UTC(), stop.EndAt.UTC()
+		if existing.ScheduledStart != nil && existing.ScheduledStart.Equal(start) &&
+			existing.ScheduledEnd != nil && existing.ScheduledEnd.Equal(end) {
+			continue
+		}
+
+		updatedJob := *existing
+		updatedJob.ScheduledStart = &start
+		updatedJob.ScheduledEnd = &end
+		updatedJob.UpdatedBy = updatedBy
+
+		job, err := s.jobRepo.UpdateJob(ctx, existing.JobID, &updatedJob)
+		if err != nil {
+			return fmt.Errorf("failed to reschedule job %s: %w", existing.JobID, err)
+		}
+		for _, activity := range jobUpdateActivities(existing, job, updatedBy) {
+			recordActivity(ctx, s.activityRepo, s.logger, activity)
+		}
+	}
+	return nil
+}
+
+// routeStart returns when a route on day starts: at startTime in the
+// organization's timezone if given, else at the start of the technician's
+// earliest working hours on that weekday, else at defaultRouteStart
+func routeStart(day time.Time, startTime string, availability *models.TechnicianAvailability) time.Time {
+	date := day.Format("2006-01-02")
+	if startTime != "" {
+		start, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+startTime, day.Location())
+		return start
+	}
+
+	if availability != nil {
+		weekday := strings.ToLower(day.Weekday().String())
+		earliest := ""
+		for _, hours := range availability.WorkingHours {
+			if hours.Day == weekday && (earliest == "" || hours.Start < earliest) {
+				earliest = hours.Start
+			}
+		}
+		if earliest != "" {
+			if start, err := time.ParseInLocation("2006-01-02 15:04", date+" "+earliest, availability.Location()); err == nil {
+				return start
+			}
+		}
+	}
+
+	start, _ := time.ParseInLocation("2006-01-02 15:04", date+" "+defaultRouteStart, day.Location())
+	return start
+}
+
+// stopDuration returns how long a job is planned to take
+func stopDuration(job *models.Job) time.Duration {
+	if job.EstimatedDuration > 0 {
+		return time.Duration(job.EstimatedDuration) * time.Minute
+	}
+	if job.ScheduledStart != nil && job.ScheduledEnd != nil && job.ScheduledEnd.After(*job.ScheduledStart) {
+		return job.ScheduledEnd.Sub(*job.ScheduledStart)
+	}
+	return defaultStopDuration
+}
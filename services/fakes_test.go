@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"slices"
+	"sync"
+	"time"
+)
+
+// fakeJobRepo keeps jobs in memory. Operations the tests do not need are
+// left to the embedded interface and panic when called.
+type fakeJobRepo struct {
+	repository.JobRepositoryInterface
+	mu   sync.Mutex
+	jobs map[string]*models.Job
+}
+
+func newFakeJobRepo(jobs ...*models.Job) *fakeJobRepo {
+	repo := &fakeJobRepo{jobs: make(map[string]*models.Job)}
+	for _, job := range jobs {
+		repo.jobs[job.JobID] = job
+	}
+	return repo
+}
+
+// job returns a copy of the stored job id
+func (r *fakeJobRepo) job(id string) *models.Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil
+	}
+	stored := *job
+	return &stored
+}
+
+func (r *fakeJobRepo) GetJob(ctx context.Context, id string) ([]*models.Job, error) {
+	job := r.job(id)
+	if job == nil {
+		return nil, repository.ErrJobNotFound
+	}
+	return []*models.Job{job}, nil
+}
+
+func (r *fakeJobRepo) GetScheduledJobs(ctx context.Context, orgID string, from, to time.Time, assignee string) ([]*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []*models.Job
+	for _, job := range r.jobs {
+		if job.OrgID == orgID && job.ScheduledStart != nil && !job.ScheduledStart.Before(from) && !job.ScheduledStart.After(to) {
+			stored := *job
+			jobs = append(jobs, &stored)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobRepo) UpdateJobBlockedBy(ctx context.Context, id string, blockedBy []string, from []string) (*models.Job, error) {
+	return r.updateDependencies(id, func(job *models.Job) *[]string { return &job.BlockedBy }, blockedBy, from)
+}
+
+func (r *fakeJobRepo) UpdateJobBlocks(ctx context.Context, id string, blocks []string, from []string) (*models.Job, error) {
+	return r.updateDependencies(id, func(job *models.Job) *[]string { return &job.Blocks }, blocks, from)
+}
+
+func (r *fakeJobRepo) updateDependencies(id string, list func(*models.Job) *[]string, ids []string, from []string) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok || !slices.Equal(*list(job), from) {
+		return nil, repository.ErrJobDependenciesChanged
+	}
+	*list(job) = slices.Clone(ids)
+	stored := *job
+	return &stored, nil
+}
+
+// fakeUserRepo answers GetUserByID from users
+type fakeUserRepo struct {
+	repository.UserRepositoryInterface
+	users map[string]*models.User
+}
+
+func (r *fakeUserRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// fakeAvailabilityRepo answers GetAvailability from availability
+type fakeAvailabilityRepo struct {
+	repository.AvailabilityRepositoryInterface
+	availability map[string]*models.TechnicianAvailability
+}
+
+func (r *fakeAvailabilityRepo) GetAvailability(ctx context.Context, userID string) (*models.TechnicianAvailability, error) {
+	return r.availability[userID], nil
+}
+
+// fakeOrgRepo answers GetOrganizationByID from organizations
+type fakeOrgRepo struct {
+	repository.OrganizationRepositoryInterface
+	organizations map[string]*models.Organization
+}
+
+func (r *fakeOrgRepo) GetOrganizationByID(ctx context.Context, id string) (*models.Organization, error) {
+	organization, ok := r.organizations[id]
+	if !ok {
+		return nil, repository.ErrOrganizationNotFound
+	}
+	return organization, nil
+}
+
+// fakeActivityRepo collects the recorded activities
+type fakeActivityRepo struct {
+	repository.ActivityRepositoryInterface
+	mu         sync.Mutex
+	activities []*models.Activity
+}
+
+func (r *fakeActivityRepo) CreateActivity(ctx context.Context, activity *models.Activity) (*models.Activity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.activities = append(r.activities, activity)
+	return activity, nil
+}
+
+func testLogger() logger.Logger {
+	return logger.NewLogger("error", "json")
+}
@@ -3,34 +3,37 @@ package services
 import (
 	"context"
 	"fieldfuze-backend/models"
+	"io"
 )
 
 // UserServiceInterface defines the contract for user service
 type UserServiceInterface interface {
-	CreateUser(user *models.User) (*models.User, error)
-	GetUsers() ([]*models.User, error)
-	GetUserByID(id string) (*models.User, error)
-	GetUserByEmail(email string) (*models.User, error)
-	GetUserByUsername(username string) (*models.User, error)
-	UpdateUser(id string, user *models.User) (*models.User, error)
-	AssignRolesToUser(userID string, roleAssignments []models.RoleAssignment) (*models.User, error)
-	AddRoleToUser(userID string, roleAssignment models.RoleAssignment) (*models.User, error)
-	AssignRoleToUser(userID, roleID string) (*models.User, error)
-	RemoveRoleFromUser(userID, roleID string) (*models.User, error)
-	GetUsersByStatus(status models.UserStatus) ([]*models.User, error)
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	GetUsers(ctx context.Context) ([]*models.User, error)
+	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error)
+	AssignRolesToUser(ctx context.Context, userID string, roleAssignments []models.RoleAssignment) (*models.User, error)
+	AddRoleToUser(ctx context.Context, userID string, roleAssignment models.RoleAssignment) (*models.User, error)
+	AssignRoleToUser(ctx context.Context, userID, roleID string) (*models.User, error)
+	RemoveRoleFromUser(ctx context.Context, userID, roleID string) (*models.User, error)
+	GetUsersByStatus(ctx context.Context, status models.UserStatus) ([]*models.User, error)
 }
 
 // RoleServiceInterface defines the contract for role service
 type RoleServiceInterface interface {
 	CreateRole(ctx context.Context, roleAssignment *models.RoleAssignment, createdBy string) (*models.RoleAssignment, error)
-	GetRoleAssignments() ([]*models.RoleAssignment, error)
-	GetRoleAssignmentByID(id string) (*models.RoleAssignment, error)
-	GetRoleByName(name string) (*models.Role, error)
-	UpdateRole(id string, req *models.UpdateRoleRequest, updatedBy string) (*models.Role, error)
-	DeleteRole(id string) error
-	GetRoleAssignmentsByStatus(status string) ([]*models.RoleAssignment, error)
-	UpdateRoleAssignment(id string, roleAssignment *models.RoleAssignment, updatedBy string) (*models.RoleAssignment, error)
-	DeleteRoleAssignment(id string) error
+	GetRoleAssignments(ctx context.Context) ([]*models.RoleAssignment, error)
+	GetRoleAssignmentByID(ctx context.Context, id string) (*models.RoleAssignment, error)
+	GetRoleByName(ctx context.Context, name string) (*models.Role, error)
+	UpdateRole(ctx context.Context, id string, req *models.UpdateRoleRequest, updatedBy string) (*models.Role, error)
+	DeleteRole(ctx context.Context, id string, deletedBy string, reason string) error
+	RestoreRole(ctx context.Context, id string) (*models.Role, error)
+	GetRoleAssignmentsByStatus(ctx context.Context, status string) ([]*models.RoleAssignment, error)
+	UpdateRoleAssignment(ctx context.Context, id string, roleAssignment *models.RoleAssignment, updatedBy string) (*models.RoleAssignment, error)
+	DeleteRoleAssignment(ctx context.Context, id string, deletedBy string, reason string) error
+	RestoreRoleAssignment(ctx context.Context, id string) (*models.RoleAssignment, error)
 }
 
 // InfrastructureServiceInterface defines the contract for infrastructure service
@@ -44,27 +47,101 @@ type InfrastructureServiceInterface interface {
 // OrganizationServiceInterface defines the contract for organization service
 type OrganizationServiceInterface interface {
 	CreateOrganization(ctx context.Context, organization *models.Organization, createdBy string) (*models.Organization, error)
-	GetOrganizations(key string) ([]*models.Organization, error)
-	GetOrganizationByID(id string) (*models.Organization, error)
-	UpdateOrganization(id string, req *models.Organization, updatedBy string) (*models.Organization, error)
-	DeleteOrganization(id string) error
-	GetOrganizationAssignmentsByStatus(status string) ([]*models.Organization, error)
-	UpdateOrganizationAssignment(id string, organizationAssignment *models.Organization, updatedBy string) (*models.Organization, error)
-	DeleteOrganizationAssignment(id string) error
+	GetOrganizations(ctx context.Context) ([]*models.Organization, error)
+	GetOrganizationByID(ctx context.Context, id string) (*models.Organization, error)
+	UpdateOrganization(ctx context.Context, id string, req *models.Organization, updatedBy string) (*models.Organization, error)
+	DeleteOrganization(ctx context.Context, id string, deletedBy string, reason string) error
+	RestoreOrganization(ctx context.Context, id string) (*models.Organization, error)
+	GetOrganizationAssignmentsByStatus(ctx context.Context, status string) ([]*models.Organization, error)
+	UpdateOrganizationAssignment(ctx context.Context, id string, organizationAssignment *models.Organization, updatedBy string) (*models.Organization, error)
+	DeleteOrganizationAssignment(ctx context.Context, id string, deletedBy string, reason string) error
 }
 
 // JobServiceInterface defines the contract for job service
 type JobServiceInterface interface {
 	CreateJob(ctx context.Context, req *models.CreateJobRequest, createdBy string) (*models.Job, error)
-	GetJobs(filter *models.JobFilter) ([]*models.Job, error)
-	GetJobByID(id string) (*models.Job, error)
+	CreateJobFromTemplate(ctx context.Context, templateID string, req *models.CreateJobFromTemplateRequest, createdBy string) (*models.Job, error)
+	CloneJob(ctx context.Context, id string, req *models.CloneJobRequest, createdBy string) (*models.Job, error)
+	ImportJobs(ctx context.Context, orgID string, fileName string, file io.Reader, mapping *models.JobImportMapping, dryRun bool, createdBy string) (*models.JobImportResult, error)
+	GetJobImport(ctx context.Context, id string) (*models.JobImport, error)
+	MaxImportSize() int64
+	ExportJobs(ctx context.Context, filter *models.JobFilter, format models.JobExportFormat, w io.Writer) error
+	SearchJobs(ctx context.Context, req *models.JobSearchRequest) (*models.JobSearchResult, error)
+	GetJobs(ctx context.Context, filter *models.JobFilter) ([]*models.Job, error)
+	GetJobByID(ctx context.Context, id string) (*models.Job, error)
 	UpdateJob(ctx context.Context, id string, req *models.UpdateJobRequest, updatedBy string) (*models.Job, error)
-	DeleteJob(id string) error
-	StartJob(ctx context.Context, id string, startedBy string) (*models.Job, error)
-	CompleteJob(ctx context.Context, id string, completedBy string) (*models.Job, error)
+	DeleteJob(ctx context.Context, id string, deletedBy string, reason string) error
+	RestoreJob(ctx context.Context, id string) (*models.Job, error)
+	StartJob(ctx context.Context, id string, startedBy string, location *models.DeviceLocation) (*models.Job, error)
+	CompleteJob(ctx context.Context, id string, completedBy string, req *models.CompleteJobRequest) (*models.Job, error)
+	SendCompletionSummary(ctx context.Context, id string, email string, sentBy string) error
+	GrantGeofenceOverride(ctx context.Context, id string, grantedBy string, reason string) (*models.Job, error)
+	AddJobDependency(ctx context.Context, id string, blockerID string, actor string) (*models.Job, error)
+	RemoveJobDependency(ctx context.Context, id string, blockerID string, actor string) (*models.Job, error)
 	CancelJob(ctx context.Context, id string, cancelledBy string, reason string) (*models.Job, error)
-	GetJobsByOrganization(orgID string, status models.JobStatus) ([]*models.Job, error)
-	GetJobsByClient(clientID string) ([]*models.Job, error)
+	HoldJob(ctx context.Context, id string, heldBy string, reason string) (*models.Job, error)
+	ResumeJob(ctx context.Context, id string, resumedBy string, reason string) (*models.Job, error)
+	GetJobsByOrganization(ctx context.Context, orgID string, status models.JobStatus) ([]*models.Job, error)
+	GetJobsByClient(ctx context.Context, clientID string) ([]*models.Job, error)
+	GetCalendar(ctx context.Context, orgID, from, to, assignee string) (*models.JobCalendar, error)
+	GetAvailability(ctx context.Context, userID string) (*models.TechnicianAvailability, error)
+	SetAvailability(ctx context.Context, userID string, req *models.SetAvailabilityRequest, updatedBy string) (*models.TechnicianAvailability, error)
+	UpdateChecklistItem(ctx context.Context, id, itemID string, req *models.UpdateChecklistItemRequest, updatedBy string) (*models.Job, error)
+}
+
+// ChecklistServiceInterface defines the contract for checklist template
+// service
+type ChecklistServiceInterface interface {
+	CreateChecklistTemplate(ctx context.Context, req *models.CreateChecklistTemplateRequest, createdBy string) (*models.ChecklistTemplate, error)
+	GetChecklistTemplate(ctx context.Context, id string) (*models.ChecklistTemplate, error)
+	GetChecklistTemplates(ctx context.Context, orgID string) ([]*models.ChecklistTemplate, error)
+	UpdateChecklistTemplate(ctx context.Context, id string, req *models.UpdateChecklistTemplateRequest, updatedBy string) (*models.ChecklistTemplate, error)
+	DeleteChecklistTemplate(ctx context.Context, id string, deletedBy string, reason string) error
+}
+
+// AttachmentServiceInterface defines the contract for job attachment service
+type AttachmentServiceInterface interface {
+	UploadAttachment(ctx context.Context, jobID string, category models.AttachmentCategory, fileName string, body io.Reader, uploadedBy string) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, jobID string) ([]*models.Attachment, error)
+	GetAttachment(ctx context.Context, jobID, attachmentID string) (*models.Attachment, error)
+	DeleteAttachment(ctx context.Context, jobID, attachmentID string, deletedBy string, reason string) error
+	MaxUploadSize() int64
+}
+
+// ActivityServiceInterface defines the contract for job activity feed service
+type ActivityServiceInterface interface {
+	GetActivity(ctx context.Context, jobID string, cursor string, limit int) (*models.ActivityPage, error)
+	CreateComment(ctx context.Context, jobID string, req *models.CreateCommentRequest, authorID string) (*models.Activity, error)
+}
+
+// TimesheetServiceInterface defines the contract for time tracking and
+// timesheet service
+type TimesheetServiceInterface interface {
+	ClockIn(ctx context.Context, jobID string, req *models.ClockInRequest, userID string) (*models.TimeEntry, error)
+	PauseTime(ctx context.Context, jobID string, userID string, notes string) (*models.TimeEntry, error)
+	ResumeTime(ctx context.Context, jobID string, userID string) (*models.TimeEntry, error)
+	ClockOut(ctx context.Context, jobID string, userID string, notes string) (*models.TimeEntry, error)
+	GetJobTimeEntries(ctx context.Context, jobID string) ([]*models.TimeEntry, error)
+	UpdateTimeEntry(ctx context.Context, entryID string, req *models.UpdateTimeEntryRequest, updatedBy string) (*models.TimeEntry, error)
+	GetTimesheet(ctx context.Context, userID string, week string) (*models.TimesheetSummary, error)
+	ApproveTimesheet(ctx context.Context, userID string, week string, approvedBy string) (*models.TimesheetSummary, error)
+	ReopenTimesheet(ctx context.Context, userID string, week string, req *models.ReopenTimesheetRequest, reopenedBy string) (*models.TimesheetSummary, error)
+}
+
+// DispatchServiceInterface defines the contract for dispatch service
+type DispatchServiceInterface interface {
+	OptimizeRoutes(ctx context.Context, req *models.OptimizeRoutesRequest, requestedBy string) (*models.RouteOptimization, error)
+}
+
+// RecurringJobServiceInterface defines the contract for recurring job service
+type RecurringJobServiceInterface interface {
+	CreateRecurringJob(ctx context.Context, req *models.CreateRecurringJobRequest, createdBy string) (*models.RecurringJob, error)
+	GetRecurringJob(ctx context.Context, id string) (*models.RecurringJob, error)
+	GetRecurringJobs(ctx context.Context, orgID string) ([]*models.RecurringJob, error)
+	UpdateRecurringJob(ctx context.Context, id string, req *models.UpdateRecurringJobRequest, updatedBy string) (*models.RecurringJob, error)
+	DeleteRecurringJob(ctx context.Context, id string, deletedBy string, reason string) error
+	PreviewOccurrences(ctx context.Context, id, from, to string) ([]models.RecurringJobOccurrence, error)
+	MaterializeDue(ctx context.Context, orgIDs []string) error
 }
 
 // ServiceContainer interface defines the main service container contract
@@ -74,4 +151,41 @@ type ServiceContainerInterface interface {
 	GetInfrastructureService() InfrastructureServiceInterface
 	GetOrganizationService() OrganizationServiceInterface
 	GetJobService() JobServiceInterface
+	GetRecurringJobService() RecurringJobServiceInterface
+	GetChecklistService() ChecklistServiceInterface
+	GetAttachmentService() AttachmentServiceInterface
+	GetActivityService() ActivityServiceInterface
+	GetTimesheetService() TimesheetServiceInterface
+	GetDispatchService() DispatchServiceInterface
+	GetSLAService() SLAServiceInterface
+	GetJobTemplateService() JobTemplateServiceInterface
+	GetProjectService() ProjectServiceInterface
+}
+
+// SLAServiceInterface defines the contract for SLA policy service
+type SLAServiceInterface interface {
+	CreateSLAPolicy(ctx context.Context, req *models.CreateSLAPolicyRequest, createdBy string) (*models.SLAPolicy, error)
+	GetSLAPolicy(ctx context.Context, id string) (*models.SLAPolicy, error)
+	GetSLAPolicies(ctx context.Context, orgID string) ([]*models.SLAPolicy, error)
+	UpdateSLAPolicy(ctx context.Context, id string, req *models.UpdateSLAPolicyRequest, updatedBy string) (*models.SLAPolicy, error)
+	DeleteSLAPolicy(ctx context.Context, id string, deletedBy string, reason string) error
+	CheckSLAs(ctx context.Context, orgIDs []string) error
+}
+
+// JobTemplateServiceInterface defines the contract for job template service
+type JobTemplateServiceInterface interface {
+	CreateJobTemplate(ctx context.Context, req *models.CreateJobTemplateRequest, createdBy string) (*models.JobTemplate, error)
+	GetJobTemplate(ctx context.Context, id string) (*models.JobTemplate, error)
+	GetJobTemplates(ctx context.Context, orgID string) ([]*models.JobTemplate, error)
+	UpdateJobTemplate(ctx context.Context, id string, req *models.UpdateJobTemplateRequest, updatedBy string) (*models.JobTemplate, error)
+	DeleteJobTemplate(ctx context.Context, id string, deletedBy string, reason string) error
+}
+
+// ProjectServiceInterface defines the contract for project service
+type ProjectServiceInterface interface {
+	CreateProject(ctx context.Context, req *models.CreateProjectRequest, createdBy string) (*models.Project, error)
+	GetProject(ctx context.Context, id string) (*models.Project, error)
+	GetProjects(ctx context.Context, orgID string) ([]*models.Project, error)
+	UpdateProject(ctx context.Context, id string, req *models.UpdateProjectRequest, updatedBy string) (*models.Project, error)
+	DeleteProject(ctx context.Context, id string, deletedBy string, reason string) error
 }
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAssignee is returned when a job is assigned to users that do
+// not exist, are not active or belong to another organization
+var ErrInvalidAssignee = errors.New("invalid assignee")
+
+// ErrAssignmentConflict is returned when an assignee is double-booked or
+// unavailable and the assignment was not forced
+var ErrAssignmentConflict = errors.New("assignment conflict")
+
+// assignmentLookback is how long before a job's start other jobs are looked
+// up that may still be running. It matches the longest estimated duration.
+const assignmentLookback = 7 * 24 * time.Hour
+
+// AssignmentError carries the conflicts behind ErrInvalidAssignee or
+// ErrAssignmentConflict
+type AssignmentError struct {
+	Kind      error
+	Conflicts []models.AssignmentConflict
+}
+
+// Error lists the conflicts
+func (e *AssignmentError) Error() string {
+	messages := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		messages = append(messages, conflict.Message)
+	}
+	return fmt.Sprintf("%v: %s", e.Kind, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the kind of the error
+func (e *AssignmentError) Unwrap() error {
+	return e.Kind
+}
+
+// checkAssignment checks the assignees of job. With validateUsers the
+// assigned users must exist, be active and belong to the job's
+// organization; this cannot be forced. For scheduled jobs it then looks for
+// assignees booked on overlapping jobs and technicians who are off or
+// outside their working hours. Those conflicts fail the check unless force
+// is set, in which case they are returned as warnings.
+func (s *JobService) checkAssignment(ctx context.Context, job *models.Job, validateUsers bool, force bool) ([]models.AssignmentConflict, error) {
+	ctx, err := tenantContext(ctx, job.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if validateUsers {
+		if conflicts, err := s.validateAssignees(ctx, job); err != nil {
+			return nil, err
+		} else if len(conflicts) > 0 {
+			return nil, &AssignmentError{Kind: ErrInvalidAssignee, Conflicts: conflicts}
+		}
+	}
+
+	if job.ScheduledStart == nil || (len(job.UsersAssignedToJob) == 0 && len(job.VehiclesAssignedToJob) == 0) {
+		return nil, nil
+	}
+
+	conflicts, err := s.findDoubleBookings(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range job.UsersAssignedToJob {
+		availability, err := s.availabilityRepo.GetAvailability(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if availability != nil {
+			conflicts = append(conflicts, availabilityConflicts(job, availability)...)
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+	if !force {
+		return nil, &AssignmentError{Kind: ErrAssignmentConflict, Conflicts: conflicts}
+	}
+
+	s.logger.Warnf("Assignment of job %s forced despite %d conflicts", job.JobID, len(conflicts))
+	return conflicts, nil
+}
+
+// validateAssignees reports the assigned users that cannot work on job
+func (s *JobService) validateAssignees(ctx context.Context, job *models.Job) ([]models.AssignmentConflict, error) {
+	var conflicts []models.AssignmentConflict
+	for _, userID := range job.UsersAssignedToJob {
+		if strings.TrimSpace(userID) == "" {
+			conflicts = append(conflicts, userConflict(models.ConflictUnknownUser, userID, "", "empty user ID assigned"))
+			continue
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil && !dal.IsNotFound(err) {
+			return nil, err
+		}
+		if err != nil {
+			conflicts = append(conflicts, userConflict(models.ConflictUnknownUser, userID, "", fmt.Sprintf("user %s does not exist", userID)))
+			continue
+		}
+
+		switch {
+		case user.Status != models.UserStatusActive:
+			conflicts = append(conflicts, userConflict(models.ConflictInactiveUser, userID, "", fmt.Sprintf("user %s is %s", userID, user.Status)))
+		case !belongsToOrganization(user, job.OrgID):
+			conflicts = append(conflicts, userConflict(models.ConflictOtherOrganization, userID, "", fmt.Sprintf("user %s is not a member of organization %s", userID, job.OrgID)))
+		}
+	}
+	return conflicts, nil
+}
+
+// belongsToOrganization reports whether user may work for orgID. Users are
+// scoped to organizations through the organization_id context of their
+// roles; users without any organization-scoped role are not restricted.
+func belongsToOrganization(user *models.User, orgID string) bool {
+	scoped := false
+	for _, role := range user.Roles {
+		if role.DeletedData != nil {
+			continue
+		}
+		if roleOrgID, ok := role.Context["organization_id"]; ok {
+			scoped = true
+			if roleOrgID == orgID || roleOrgID == "*" {
+				return true
+			}
+		}
+	}
+	return !scoped
+}
+
+// findDoubleBookings reports the assignees of job that are also assigned to
+// another open job overlapping it
+func (s *JobService) findDoubleBookings(ctx context.Context, job *models.Job) ([]models.AssignmentConflict, error) {
+	start, end := jobInterval(job)
+
+	others, err := s.jobRepo.GetScheduledJobs(ctx, job.OrgID, start.Add(-assignmentLookback), end, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.AssignmentConflict
+	for _, other := range others {
+		if other.JobID == job.JobID || other.JobStatus == models.JobStatusCompleted || other.JobStatus == models.JobStatusCancelled {
+			continue
+		}
+		otherStart, otherEnd := jobInterval(other)
+		if !start.Before(otherEnd) || !otherStart.Before(end) {
+			continue
+		}
+
+		for _, userID := range shared(job.UsersAssignedToJob, other.UsersAssignedToJob) {
+			conflicts = append(conflicts, userConflict(models.ConflictDoubleBooked, userID, other.JobID,
+				fmt.Sprintf("user %s is already assigned to job %s from %s to %s", userID, other.JobID, otherStart.Format(time.RFC3339), otherEnd.Format(time.RFC3339))))
+		}
+		for _, vehicleID := range shared(job.VehiclesAssignedToJob, other.VehiclesAssignedToJob) {
+			conflicts = append(conflicts, models.AssignmentConflict{
+				Type:         models.ConflictDoubleBooked,
+				ResourceType: "vehicle",
+				ResourceID:   vehicleID,
+				JobID:        other.JobID,
+				Message:      fmt.Sprintf("vehicle %s is already assigned to job %s from %s to %s", vehicleID, other.JobID, otherStart.Format(time.RFC3339), otherEnd.Format(time.RFC3339)),
+			})
+		}
+	}
+	return conflicts, nil
+}
+
+// availabilityConflicts reports when job falls outside the working hours or
+// into the time off of a technician
+func availabilityConflicts(job *models.Job, availability *models.TechnicianAvailability) []models.AssignmentConflict {
+	start, end := jobInterval(job)
+	userID := availability.UserID
+
+	var conflicts []models.AssignmentConflict
+	for _, off := range availability.TimeOff {
+		if start.Before(off.End) && off.Start.Before(end) {
+			message := fmt.Sprintf("user %s is off from %s to %s", userID, off.Start.Format(time.RFC3339), off.End.Format(time.RFC3339))
+			if off.Reason != "" {
+				message += " (" + off.Reason + ")"
+			}
+			conflicts = append(conflicts, userConflict(models.ConflictTimeOff, userID, "", message))
+		}
+	}
+
+	if len(availability.WorkingHours) > 0 && !withinWorkingHours(start, end, availability) {
+		loc := availability.Location()
+		conflicts = append(conflicts, userConflict(models.ConflictOutsideWorkingHours, userID, "",
+			fmt.Sprintf("job from %s to %s is outside the working hours of user %s", start.In(loc).Format("Mon 15:04"), end.In(loc).Format("Mon 15:04"), userID)))
+	}
+	return conflicts
+}
+
+// withinWorkingHours reports whether [start, end) fits in one working
+// period on the local day it starts
+func withinWorkingHours(start, end time.Time, availability *models.TechnicianAvailability) bool {
+	loc := availability.Location()
+	localStart := start.In(loc)
+	day := strings.ToLower(localStart.Weekday().String())
+
+	for _, hours := range availability.WorkingHours {
+		if hours.Day != day {
+			continue
+		}
+		from, err := time.ParseInLocation("2006-01-02 15:04", localStart.Format("2006-01-02")+" "+hours.Start, loc)
+		if err != nil {
+			continue
+		}
+		to, err := time.ParseInLocation("2006-01-02 15:04", localStart.Format("2006-01-02")+" "+hours.End, loc)
+		if err != nil {
+			continue
+		}
+		if !start.Before(from) && !end.After(to) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobInterval returns the period a scheduled job occupies. Jobs without an
+// end or duration occupy their start minute.
+func jobInterval(job *models.Job) (time.Time, time.Time) {
+	start := *job.ScheduledStart
+	switch {
+	case job.ScheduledEnd != nil && job.ScheduledEnd.After(start):
+		return start, *job.ScheduledEnd
+	case job.EstimatedDuration > 0:
+		return start, start.Add(time.Duration(job.EstimatedDuration) * time.Minute)
+	default:
+		return start, start.Add(time.Minute)
+	}
+}
+
+// shared returns the IDs in both a and b, sorted
+func shared(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, id := range b {
+		in[id] = true
+	}
+
+	var both []string
+	for _, id := range a {
+		if in[id] {
+			both = append(both, id)
+			delete(in, id)
+		}
+	}
+	sort.Strings(both)
+	return both
+}
+
+func userConflict(conflictType, userID, jobID, message string) models.AssignmentConflict {
+	return models.AssignmentConflict{
+		Type:         conflictType,
+		ResourceType: "user",
+		ResourceID:   userID,
+		JobID:        jobID,
+		Message:      message,
+	}
+}
+
+// GetAvailability returns the availability of a technician of the caller's
+// organization. Technicians without one get an empty availability in UTC.
+func (s *JobService) GetAvailability(ctx context.Context, userID string) (*models.TechnicianAvailability, error) {
+	ctx, err := userTenantContext(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability, err := s.availabilityRepo.GetAvailability(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if availability == nil {
+		availability = &models.TechnicianAvailability{
+			UserID:       userID,
+			Timezone:     time.UTC.String(),
+			WorkingHours: []models.WorkingHours{},
+			TimeOff:      []models.TimeOff{},
+		}
+	}
+	return availability, nil
+}
+
+// SetAvailability replaces the working hours and time off of a technician
+// of the caller's organization
+func (s *JobService) SetAvailability(ctx context.Context, userID string, req *models.SetAvailabilityRequest, updatedBy string) (*models.TechnicianAvailability, error) {
+	if req == nil {
+		return nil, errors.New("availability request is required")
+	}
+
+	ctx, err := userTenantContext(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, req.Timezone)
+	}
+	for _, hours := range req.WorkingHours {
+		if hours.End <= hours.Start {
+			return nil, fmt.Errorf("%w: working hours on %s must end after they start", ErrInvalidSchedule, hours.Day)
+		}
+	}
+	for _, off := range req.TimeOff {
+		if !off.End.After(off.Start) {
+			return nil, fmt.Errorf("%w: time off must end after it starts", ErrInvalidSchedule)
+		}
+	}
+
+	availability := &models.TechnicianAvailability{
+		UserID:       userID,
+		Timezone:     req.Timezone,
+		WorkingHours: req.WorkingHours,
+		TimeOff:      req.TimeOff,
+		UpdatedBy:    updatedBy,
+	}
+	if req.StartLocation != nil {
+		if availability.StartLocation, err = s.resolveSite(ctx, req.StartLocation); err != nil {
+			return nil, err
+		}
+	}
+	if availability.WorkingHours == nil {
+		availability.WorkingHours = []models.WorkingHours{}
+	}
+	if availability.TimeOff == nil {
+		availability.TimeOff = []models.TimeOff{}
+	}
+	return s.availabilityRepo.PutAvailability(ctx, availability)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/requestctx"
+	"testing"
+	"time"
+)
+
+// at returns 2026-03-02, a Monday, at hour:minute UTC
+func at(hour, minute int) *time.Time {
+	t := time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+func scheduledJob(id string, start, end *time.Time, users ...string) *models.Job {
+	return &models.Job{
+		JobID:              id,
+		OrgID:              "org-1",
+		JobsName:           id,
+		JobStatus:          models.JobStatusPending,
+		ScheduledStart:     start,
+		ScheduledEnd:       end,
+		UsersAssignedToJob: users,
+	}
+}
+
+func newAssignmentService(jobs []*models.Job, availability map[string]*models.TechnicianAvailability) *JobService {
+	member := []models.RoleAssignment{{RoleName: "technician", Context: map[string]string{"organization_id": "org-1"}}}
+	return &JobService{
+		jobRepo: newFakeJobRepo(jobs...),
+		userRepo: &fakeUserRepo{users: map[string]*models.User{
+			"tech-1":   {ID: "tech-1", Status: models.UserStatusActive, Roles: member},
+			"tech-2":   {ID: "tech-2", Status: models.UserStatusActive, Roles: member},
+			"inactive": {ID: "inactive", Status: models.UserStatusInactive, Roles: member},
+			"outsider": {ID: "outsider", Status: models.UserStatusActive, Roles: []models.RoleAssignment{{RoleName: "technician", Context: map[string]string{"organization_id": "org-2"}}}},
+		}},
+		availabilityRepo: &fakeAvailabilityRepo{availability: availability},
+		logger:           testLogger(),
+	}
+}
+
+func conflictTypes(conflicts []models.AssignmentConflict) []string {
+	types := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		types = append(types, conflict.Type)
+	}
+	return types
+}
+
+func TestCheckAssignmentConflicts(t *testing.T) {
+	weekdays := &models.TechnicianAvailability{
+		UserID:       "tech-1",
+		WorkingHours: []models.WorkingHours{{Day: "monday", Start: "08:00", End: "16:00"}},
+		TimeOff:      []models.TimeOff{{Start: *at(12, 0), End: *at(13, 0), Reason: "dentist"}},
+	}
+
+	tests := []struct {
+		name  string
+		other *models.Job
+		job   *models.Job
+		want  []string
+	}{
+		{
+			name:  "overlapping job of the same technician",
+			other: scheduledJob("other", at(9, 0), at(11, 0), "tech-1"),
+			job:   scheduledJob("job", at(10, 0), at(11, 30), "tech-1"),
+			want:  []string{models.ConflictDoubleBooked},
+		},
+		{
+			name:  "back to back jobs",
+			other: scheduledJob("other", at(9, 0), at(10, 0), "tech-1"),
+			job:   scheduledJob("job", at(10, 0), at(11, 0), "tech-1"),
+		},
+		{
+			name:  "overlapping job of another technician",
+			other: scheduledJob("other", at(9, 0), at(11, 0), "tech-2"),
+			job:   scheduledJob("job", at(10, 0), at(11, 0), "tech-1"),
+		},
+		{
+			name: "overlapping completed job",
+			other: func() *models.Job {
+				job := scheduledJob("other", at(9, 0), at(11, 0), "tech-1")
+				job.JobStatus = models.JobStatusCompleted
+				return job
+			}(),
+			job: scheduledJob("job", at(10, 0), at(11, 0), "tech-1"),
+		},
+		{
+			name: "shared vehicle",
+			other: func() *models.Job {
+				job := scheduledJob("other", at(9, 0), at(11, 0))
+				job.VehiclesAssignedToJob = []string{"van-1"}
+				return job
+			}(),
+			job: func() *models.Job {
+				job := scheduledJob("job", at(10, 0), at(11, 0))
+				job.VehiclesAssignedToJob = []string{"van-1"}
+				return job
+			}(),
+			want: []string{models.ConflictDoubleBooked},
+		},
+		{
+			name: "duration overlaps without an end",
+			other: func() *models.Job {
+				job := scheduledJob("other", at(9, 0), nil, "tech-1")
+				job.EstimatedDuration = 90
+				return job
+			}(),
+			job:  scheduledJob("job", at(10, 0), at(11, 0), "tech-1"),
+			want: []string{models.ConflictDoubleBooked},
+		},
+		{
+			name: "time off",
+			job:  scheduledJob("job", at(11, 30), at(12, 30), "tech-1"),
+			want: []string{models.ConflictTimeOff},
+		},
+		{
+			name: "outside working hours",
+			job:  scheduledJob("job", at(15, 0), at(17, 0), "tech-1"),
+			want: []string{models.ConflictOutsideWorkingHours},
+		},
+		{
+			name: "within working hours",
+			job:  scheduledJob("job", at(8, 0), at(12, 0), "tech-1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jobs []*models.Job
+			if tt.other != nil {
+				jobs = append(jobs, tt.other)
+			}
+			s := newAssignmentService(jobs, map[string]*models.TechnicianAvailability{"tech-1": weekdays})
+
+			_, err := s.checkAssignment(context.Background(), tt.job, true, false)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("checkAssignment() error = %v", err)
+				}
+				return
+			}
+
+			var assignmentErr *AssignmentError
+			if !errors.As(err, &assignmentErr) || !errors.Is(err, ErrAssignmentConflict) {
+				t.Fatalf("checkAssignment() error = %v, want ErrAssignmentConflict", err)
+			}
+			if got := conflictTypes(assignmentErr.Conflicts); !equalStrings(got, tt.want) {
+				t.Fatalf("conflicts = %v, want %v", got, tt.want)
+			}
+
+			// Forcing the assignment turns the conflicts into warnings
+			warnings, err := s.checkAssignment(context.Background(), tt.job, true, true)
+			if err != nil {
+				t.Fatalf("checkAssignment() forced error = %v", err)
+			}
+			if got := conflictTypes(warnings); !equalStrings(got, tt.want) {
+				t.Fatalf("warnings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAssignmentInvalidAssignees(t *testing.T) {
+	tests := []struct {
+		user string
+		want string
+	}{
+		{user: "nobody", want: models.ConflictUnknownUser},
+		{user: "inactive", want: models.ConflictInactiveUser},
+		{user: "outsider", want: models.ConflictOtherOrganization},
+	}
+
+	for _, tt := range tests {
+		s := newAssignmentService(nil, nil)
+		job := scheduledJob("job", at(10, 0), at(11, 0), "tech-1", tt.user)
+
+		// Assignees that cannot work on the job cannot be forced
+		_, err := s.checkAssignment(context.Background(), job, true, true)
+		var assignmentErr *AssignmentError
+		if !errors.As(err, &assignmentErr) || !errors.Is(err, ErrInvalidAssignee) {
+			t.Fatalf("checkAssignment(%s) error = %v, want ErrInvalidAssignee", tt.user, err)
+		}
+		if got := conflictTypes(assignmentErr.Conflicts); !equalStrings(got, []string{tt.want}) {
+			t.Fatalf("checkAssignment(%s) conflicts = %v, want [%s]", tt.user, got, tt.want)
+		}
+	}
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAvailabilityOfOtherOrganization(t *testing.T) {
+	s := newAssignmentService(nil, nil)
+	ctx := requestctx.WithClaims(context.Background(), &models.JWTClaims{
+		UserID:  "dispatcher",
+		Context: models.UserContext{OrganizationID: "org-1"},
+	})
+
+	if _, err := s.GetAvailability(ctx, "tech-1"); err != nil {
+		t.Fatalf("GetAvailability() of a member error = %v", err)
+	}
+	if _, err := s.GetAvailability(ctx, "outsider"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("GetAvailability() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+
+	req := &models.SetAvailabilityRequest{Timezone: "UTC"}
+	if _, err := s.SetAvailability(ctx, "outsider", req, "dispatcher"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("SetAvailability() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrJobNotCloneable is returned when a job that is not completed is cloned
+var ErrJobNotCloneable = errors.New("only completed jobs can be cloned")
+
+// CreateJobFromTemplate creates a pending job for the client of req from the
+// job template templateID. Fields set in req override the template.
+func (s *JobService) CreateJobFromTemplate(ctx context.Context, templateID string, req *models.CreateJobFromTemplateRequest, createdBy string) (*models.Job, error) {
+	if req == nil {
+		return nil, errors.New("job request is required")
+	}
+
+	if strings.TrimSpace(req.ClientID) == "" {
+		return nil, errors.New("client ID is required")
+	}
+
+	template, err := s.jobTemplateRepo.GetJobTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newPendingJob(createdBy)
+	job.ClientID = req.ClientID
+	job.OrgID = template.OrgID
+	job.JobsName = template.JobsName
+	job.JobType = template.JobType
+	job.Notes = template.Notes
+	job.Priority = template.Priority.OrDefault()
+	job.EstimatedDuration = template.EstimatedDuration
+	job.VehiclesAssignedToJob = append([]string(nil), template.VehiclesAssignedToJob...)
+	job.QBInfoOnJob = copyQBInfo(template.QBInfoOnJob)
+	job.JobTemplateID = template.TemplateID
+
+	if err := s.applyJobOverrides(ctx, job, &req.JobOverrides); err != nil {
+		return nil, err
+	}
+
+	// Standard procedures of the organization come first, then the steps
+	// particular to the template
+	checklist, err := newJobChecklist(ctx, s.checklistRepo, job.OrgID, job.JobType)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range template.Checklist {
+		checklist = append(checklist, models.ChecklistItem{
+			ItemID:     item.ItemID,
+			TemplateID: template.TemplateID,
+			Label:      item.Label,
+			Required:   item.Required,
+			ValueType:  item.ValueType,
+		})
+	}
+	job.Checklist = checklist
+
+	return s.insertJob(ctx, job, req.Force, createdBy, fmt.Sprintf("Job created from template %s", template.Name))
+}
+
+// CloneJob creates a pending job from the completed job id. The clone gets
+// the client, assignees, site, billing line item and reopened checklist of
+// the job; its status, schedule, timestamps and records of the work done
+// start afresh. Fields set in req override the copied ones.
+func (s *JobService) CloneJob(ctx context.Context, id string, req *models.CloneJobRequest, createdBy string) (*models.Job, error) {
+	if req == nil {
+		req = &models.CloneJobRequest{}
+	}
+
+	source, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.JobStatus != models.JobStatusCompleted {
+		return nil, fmt.Errorf("%w: job is %s", ErrJobNotCloneable, source.JobStatus)
+	}
+
+	job := newPendingJob(createdBy)
+	job.ClientID = source.ClientID
+	if req.ClientID != "" {
+		job.ClientID = req.ClientID
+	}
+	job.OrgID = source.OrgID
+	job.JobsName = source.JobsName
+	job.JobType = source.JobType
+	job.Notes = source.Notes
+	job.Priority = source.Priority.OrDefault()
+	job.EstimatedDuration = source.EstimatedDuration
+	job.UsersAssignedToJob = append([]string(nil), source.UsersAssignedToJob...)
+	job.VehiclesAssignedToJob = append([]string(nil), source.VehiclesAssignedToJob...)
+	job.QBInfoOnJob = copyQBInfo(source.QBInfoOnJob)
+	job.JobTemplateID = source.JobTemplateID
+	job.ClonedFromJobID = source.JobID
+	if source.Site != nil {
+		site := *source.Site
+		job.Site = &site
+	}
+
+	for _, item := range source.Checklist {
+		job.Checklist = append(job.Checklist, models.ChecklistItem{
+			ItemID:     item.ItemID,
+			TemplateID: item.TemplateID,
+			Label:      item.Label,
+			Required:   item.Required,
+			ValueType:  item.ValueType,
+		})
+	}
+
+	if err := s.applyJobOverrides(ctx, job, &req.JobOverrides); err != nil {
+		return nil, err
+	}
+
+	return s.insertJob(ctx, job, req.Force, createdBy, fmt.Sprintf("Job cloned from %s", source.JobID))
+}
+
+// newPendingJob returns an empty pending job created by createdBy
+func newPendingJob(createdBy string) *models.Job {
+	return &models.Job{
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+		JobImagesAfterService: []string{},
+		JobStatus:             models.JobStatusPending,
+		StatusHistory: []models.StatusChange{{
+			To:        models.JobStatusPending,
+			UID:       createdBy,
+			ChangedAt: time.Now().UTC(),
+		}},
+	}
+}
+
+// applyJobOverrides replaces the fields of job that overrides sets. A
+// schedule without a duration of its own ends after the duration job
+// already has.
+func (s *JobService) applyJobOverrides(ctx context.Context, job *models.Job, overrides *models.JobOverrides) error {
+	if overrides.JobsName != "" {
+		job.JobsName = overrides.JobsName
+	}
+	if overrides.JobType != "" {
+		job.JobType = overrides.JobType
+	}
+	if overrides.Notes != "" {
+		job.Notes = overrides.Notes
+	}
+	if overrides.Priority != "" {
+		job.Priority = overrides.Priority
+	}
+	if overrides.UsersAssignedToJob != nil {
+		job.UsersAssignedToJob = overrides.UsersAssignedToJob
+	}
+	if overrides.VehiclesAssignedToJob != nil {
+		job.VehiclesAssignedToJob = overrides.VehiclesAssignedToJob
+	}
+	if overrides.QBInfoOnJob != nil {
+		job.QBInfoOnJob = overrides.QBInfoOnJob
+	}
+
+	if len(job.JobsName) < 2 || len(job.JobsName) > 200 {
+		return errors.New("job name must be between 2 and 200 characters")
+	}
+
+	if len(job.Notes) > 1000 {
+		return errors.New("notes must be less than 1000 characters")
+	}
+
+	if overrides.Site != nil {
+		site, err := s.resolveSite(ctx, overrides.Site)
+		if err != nil {
+			return err
+		}
+		job.Site = site
+	}
+
+	if !overrides.JobScheduleInput.IsEmpty() {
+		schedule := overrides.JobScheduleInput
+		if schedule.EstimatedDuration == 0 {
+			schedule.EstimatedDuration = job.EstimatedDuration
+		}
+		loc, err := organizationLocation(ctx, s.orgRepo, job.OrgID)
+		if err != nil {
+			return err
+		}
+		if err := applySchedule(job, schedule, loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyQBInfo copies the QuickBooks customer and line item of info onto a new
+// job. The invoice and payment belong to the job they were issued for.
+func copyQBInfo(info *models.QBInfoOnJob) *models.QBInfoOnJob {
+	if info == nil {
+		return nil
+	}
+	return &models.QBInfoOnJob{
+		CustomerID:   info.CustomerID,
+		LineItemID:   info.LineItemID,
+		ServiceNotes: info.ServiceNotes,
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrJobBlocked is returned when a job is started while jobs it waits for
+// are not completed
+var ErrJobBlocked = errors.New("job is blocked")
+
+// ErrInvalidJobDependency is returned for dependencies that cannot be added,
+// such as a job blocking itself or a cycle of jobs waiting for each other
+var ErrInvalidJobDependency = errors.New("invalid job dependency")
+
+// ErrJobDependencyNotFound is returned when removing a dependency a job
+// does not have
+var ErrJobDependencyNotFound = fmt.Errorf("job dependency %w", dal.ErrNotFound)
+
+// dependencyRetries bounds how often a dependency list is re-read and written
+// again after a concurrent change
+const dependencyRetries = 3
+
+// AddJobDependency makes job id wait for job blockerID to be completed
+// before it can start. Both jobs must be of the same organization and job id
+// must not have started yet.
+func (s *JobService) AddJobDependency(ctx context.Context, id string, blockerID string, actor string) (*models.Job, error) {
+	blockerID = strings.TrimSpace(blockerID)
+	if blockerID == "" {
+		return nil, fmt.Errorf("%w: blockedBy is required", ErrInvalidJobDependency)
+	}
+	if blockerID == id {
+		return nil, fmt.Errorf("%w: a job cannot block itself", ErrInvalidJobDependency)
+	}
+
+	job, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := s.GetJobByID(ctx, blockerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, fmt.Errorf("%w: blocking job %s not found", ErrInvalidJobDependency, blockerID)
+		}
+		return nil, err
+	}
+
+	if blocker.OrgID != job.OrgID {
+		return nil, fmt.Errorf("%w: jobs of different organizations cannot depend on each other", ErrInvalidJobDependency)
+	}
+	if job.JobStartedAt != nil || job.JobStatus == models.JobStatusCompleted || job.JobStatus == models.JobStatusCancelled {
+		return nil, fmt.Errorf("%w: job has already started", ErrInvalidJobDependency)
+	}
+	if slices.Contains(job.BlockedBy, blockerID) {
+		return job, nil
+	}
+
+	cycle, err := s.waitsFor(ctx, blocker, job.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, fmt.Errorf("%w: %s already waits for this job", ErrInvalidJobDependency, blocker.JobsName)
+	}
+
+	updated, err := s.jobRepo.UpdateJobBlockedBy(ctx, id, withID(job.BlockedBy, blockerID), job.BlockedBy)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBlocks(ctx, blockerID, func(blocks []string) []string { return withID(blocks, id) })
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:   updated.JobID,
+		OrgID:   updated.OrgID,
+		Type:    models.ActivityDependencyAdded,
+		ActorID: actor,
+		Summary: "Blocked by " + blocker.JobsName,
+	})
+	return updated, nil
+}
+
+// RemoveJobDependency stops job id from waiting for job blockerID
+func (s *JobService) RemoveJobDependency(ctx context.Context, id string, blockerID string, actor string) (*models.Job, error) {
+	job, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(job.BlockedBy, blockerID) {
+		return nil, ErrJobDependencyNotFound
+	}
+
+	updated, err := s.jobRepo.UpdateJobBlockedBy(ctx, id, withoutID(job.BlockedBy, blockerID), job.BlockedBy)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBlocks(ctx, blockerID, func(blocks []string) []string { return withoutID(blocks, id) })
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:   updated.JobID,
+		OrgID:   updated.OrgID,
+		Type:    models.ActivityDependencyRemoved,
+		ActorID: actor,
+		Summary: "No longer blocked by job " + blockerID,
+	})
+	return updated, nil
+}
+
+// updateBlocks rewrites the list of jobs waiting for job id with change.
+// The list only mirrors the blockedBy lists of those jobs, which decide
+// whether they can start, so failures are logged rather than returned.
+func (s *JobService) updateBlocks(ctx context.Context, id string, change func([]string) []string) {
+	for attempt := 0; attempt < dependencyRetries; attempt++ {
+		job, err := s.GetJobByID(ctx, id)
+		if err != nil {
+			// Deleted jobs keep the list they had
+			if !errors.Is(err, repository.ErrJobNotFound) {
+				s.logger.Warnf("Failed to read job %s to update the jobs it blocks: %v", id, err)
+			}
+			return
+		}
+
+		_, err = s.jobRepo.UpdateJobBlocks(ctx, id, change(job.Blocks), job.Blocks)
+		if err == nil {
+			return
+		}
+		if !errors.Is(err, repository.ErrJobDependenciesChanged) {
+			s.logger.Warnf("Failed to update the jobs blocked by job %s: %v", id, err)
+			return
+		}
+	}
+	s.logger.Warnf("Gave up updating the jobs blocked by job %s after %d concurrent changes", id, dependencyRetries)
+}
+
+// waitsFor reports whether job waits for job target, directly or through
+// the jobs it waits for
+func (s *JobService) waitsFor(ctx context.Context, job *models.Job, target string) (bool, error) {
+	visited := map[string]bool{job.JobID: true}
+	pending := append([]string{}, job.BlockedBy...)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if id == target {
+			return true, nil
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		next, err := s.GetJobByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrJobNotFound) {
+				continue
+			}
+			return false, err
+		}
+		pending = append(pending, next.BlockedBy...)
+	}
+	return false, nil
+}
+
+// checkBlockers returns ErrJobBlocked when jobs job waits for are not
+// completed. Deleted blockers no longer block.
+func (s *JobService) checkBlockers(ctx context.Context, job *models.Job) error {
+	var open []string
+	for _, id := range job.BlockedBy {
+		blocker, err := s.GetJobByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrJobNotFound) {
+				continue
+			}
+			return err
+		}
+		if blocker.JobStatus != models.JobStatusCompleted {
+			open = append(open, fmt.Sprintf("%s (%s)", blocker.JobsName, blocker.JobStatus))
+		}
+	}
+
+	if len(open) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: waiting for %d jobs: %s", ErrJobBlocked, len(open), strings.Join(open, ", "))
+}
+
+// checkProject returns ErrInvalidProject unless projectID is a project of
+// orgID
+func (s *JobService) checkProject(ctx context.Context, projectID string, orgID string) error {
+	project, err := s.projectRepo.GetProject(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, repository.ErrProjectNotFound) {
+			return fmt.Errorf("%w: project %s not found", ErrInvalidProject, projectID)
+		}
+		return err
+	}
+	if project.OrgID != orgID {
+		return fmt.Errorf("%w: project %s belongs to another organization", ErrInvalidProject, projectID)
+	}
+	return nil
+}
+
+// withID returns a copy of ids with id appended unless it is already there
+func withID(ids []string, id string) []string {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(slices.Clone(ids), id)
+}
+
+// withoutID returns a copy of ids without id
+func withoutID(ids []string, id string) []string {
+	return slices.DeleteFunc(slices.Clone(ids), func(candidate string) bool { return candidate == id })
+}
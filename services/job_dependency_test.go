@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+func dependentJob(id string, blockedBy ...string) *models.Job {
+	return &models.Job{JobID: id, OrgID: "org-1", JobsName: id, JobStatus: models.JobStatusPending, BlockedBy: blockedBy}
+}
+
+func newDependencyService(jobs ...*models.Job) (*JobService, *fakeJobRepo) {
+	repo := newFakeJobRepo(jobs...)
+	return &JobService{jobRepo: repo, activityRepo: &fakeActivityRepo{}, logger: testLogger()}, repo
+}
+
+func TestAddJobDependency(t *testing.T) {
+	started := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		jobs      []*models.Job
+		id        string
+		blockerID string
+		wantErr   error
+	}{
+		{name: "independent jobs", jobs: []*models.Job{dependentJob("a"), dependentJob("b")}, id: "a", blockerID: "b"},
+		{name: "itself", jobs: []*models.Job{dependentJob("a")}, id: "a", blockerID: "a", wantErr: ErrInvalidJobDependency},
+		{name: "direct cycle", jobs: []*models.Job{dependentJob("a"), dependentJob("b", "a")}, id: "a", blockerID: "b", wantErr: ErrInvalidJobDependency},
+		{
+			name:      "indirect cycle",
+			jobs:      []*models.Job{dependentJob("a"), dependentJob("b", "c"), dependentJob("c", "d"), dependentJob("d", "a")},
+			id:        "a",
+			blockerID: "b",
+			wantErr:   ErrInvalidJobDependency,
+		},
+		{
+			name:      "shared blocker is no cycle",
+			jobs:      []*models.Job{dependentJob("a", "c"), dependentJob("b", "c"), dependentJob("c")},
+			id:        "a",
+			blockerID: "b",
+		},
+		{
+			name:      "deleted job in the chain",
+			jobs:      []*models.Job{dependentJob("a"), dependentJob("b", "gone")},
+			id:        "a",
+			blockerID: "b",
+		},
+		{name: "missing blocker", jobs: []*models.Job{dependentJob("a")}, id: "a", blockerID: "gone", wantErr: ErrInvalidJobDependency},
+		{
+			name:      "other organization",
+			jobs:      []*models.Job{dependentJob("a"), {JobID: "b", OrgID: "org-2", JobStatus: models.JobStatusPending}},
+			id:        "a",
+			blockerID: "b",
+			wantErr:   ErrInvalidJobDependency,
+		},
+		{
+			name:      "started job",
+			jobs:      []*models.Job{{JobID: "a", OrgID: "org-1", JobStatus: models.JobStatusInProgress, JobStartedAt: &started}, dependentJob("b")},
+			id:        "a",
+			blockerID: "b",
+			wantErr:   ErrInvalidJobDependency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newDependencyService(tt.jobs...)
+
+			job, err := s.AddJobDependency(context.Background(), tt.id, tt.blockerID, "user-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddJobDependency() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if stored := repo.job(tt.id); stored != nil && slices.Contains(stored.BlockedBy, tt.blockerID) {
+					t.Fatalf("rejected dependency was stored: %v", stored.BlockedBy)
+				}
+				return
+			}
+
+			if !slices.Contains(job.BlockedBy, tt.blockerID) {
+				t.Fatalf("BlockedBy = %v, want %s", job.BlockedBy, tt.blockerID)
+			}
+			if blocks := repo.job(tt.blockerID).Blocks; !slices.Contains(blocks, tt.id) {
+				t.Fatalf("Blocks of %s = %v, want %s", tt.blockerID, blocks, tt.id)
+			}
+		})
+	}
+}
+
+func TestRemoveJobDependency(t *testing.T) {
+	a := dependentJob("a", "b")
+	b := dependentJob("b")
+	b.Blocks = []string{"a"}
+	s, repo := newDependencyService(a, b)
+
+	job, err := s.RemoveJobDependency(context.Background(), "a", "b", "user-1")
+	if err != nil {
+		t.Fatalf("RemoveJobDependency() error = %v", err)
+	}
+	if len(job.BlockedBy) != 0 || len(repo.job("b").Blocks) != 0 {
+		t.Fatalf("dependency left behind: blockedBy %v, blocks %v", job.BlockedBy, repo.job("b").Blocks)
+	}
+
+	// The jobs no longer depend on each other, so the reverse is allowed
+	if _, err := s.AddJobDependency(context.Background(), "b", "a", "user-1"); err != nil {
+		t.Fatalf("AddJobDependency() after removal error = %v", err)
+	}
+}
+
+func TestCheckBlockers(t *testing.T) {
+	completed := dependentJob("done")
+	completed.JobStatus = models.JobStatusCompleted
+	s, _ := newDependencyService(completed, dependentJob("open"))
+
+	if err := s.checkBlockers(context.Background(), dependentJob("a", "done", "gone")); err != nil {
+		t.Fatalf("checkBlockers() with completed and deleted blockers error = %v", err)
+	}
+	if err := s.checkBlockers(context.Background(), dependentJob("a", "done", "open")); !errors.Is(err, ErrJobBlocked) {
+		t.Fatalf("checkBlockers() with an open blocker error = %v, want ErrJobBlocked", err)
+	}
+}
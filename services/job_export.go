@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/xlsx"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportJobs writes the jobs matching filter to w as CSV or XLSX. The
+// columns an import reads come first, headed by their field, so exports can
+// be imported without a mapping; the job ID, when the job was created,
+// started and ended and its SLA status follow.
+func (s *JobService) ExportJobs(ctx context.Context, filter *models.JobFilter, format models.JobExportFormat, w io.Writer) error {
+	if format != models.JobExportFormatCSV && format != models.JobExportFormatXLSX {
+		return fmt.Errorf("format must be %s or %s", models.JobExportFormatCSV, models.JobExportFormatXLSX)
+	}
+
+	jobs, err := s.GetJobs(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	header := append([]string{"jobID"}, models.JobImportFields...)
+	header = append(header, "createdAt", "jobStartedAt", "jobEndedAt", "slaStatus")
+
+	rows := make([][]string, 0, len(jobs)+1)
+	rows = append(rows, header)
+	for _, job := range jobs {
+		rows = append(rows, exportRow(job))
+	}
+
+	if format == models.JobExportFormatXLSX {
+		return xlsx.Write(w, "Jobs", rows)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write jobs: %w", err)
+	}
+	return nil
+}
+
+// exportRow returns the cells of job in the order of the export header
+func exportRow(job *models.Job) []string {
+	var site, customerID, lineItemID, slaStatus, duration string
+	if job.Site != nil {
+		site = job.Site.Address
+	}
+	if job.QBInfoOnJob != nil {
+		customerID = job.QBInfoOnJob.CustomerID
+		lineItemID = job.QBInfoOnJob.LineItemID
+	}
+	if job.SLA != nil {
+		slaStatus = string(job.SLA.Status)
+	}
+	if job.EstimatedDuration != 0 {
+		duration = strconv.Itoa(job.EstimatedDuration)
+	}
+
+	return escapeCells(
+		job.JobID,
+		job.ClientID,
+		job.JobsName,
+		string(job.JobType),
+		string(job.JobStatus),
+		job.Notes,
+		string(job.Priority),
+		strings.Join(job.UsersAssignedToJob, ";"),
+		strings.Join(job.VehiclesAssignedToJob, ";"),
+		exportTime(job.ScheduledStart),
+		exportTime(job.ScheduledEnd),
+		duration,
+		site,
+		customerID,
+		lineItemID,
+		exportTime(&job.CreatedAt),
+		exportTime(job.JobStartedAt),
+		exportTime(job.JobEndedAt),
+		slaStatus,
+	)
+}
+
+// escapeCells returns cells with a ' put before every cell that spreadsheet
+// programs would evaluate as a formula, so that names and notes entered by
+// users cannot run formulas when the export is opened
+func escapeCells(cells ...string) []string {
+	for i, cell := range cells {
+		if formulaCell(cell) {
+			cells[i] = "'" + cell
+		}
+	}
+	return cells
+}
+
+// formulaCell reports whether spreadsheet programs evaluate cell as a
+// formula
+func formulaCell(cell string) bool {
+	return cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0]))
+}
+
+// unescapeCell reverses escapeCells for a cell read back by an import
+func unescapeCell(cell string) string {
+	if rest, ok := strings.CutPrefix(cell, "'"); ok && formulaCell(rest) {
+		return rest
+	}
+	return cell
+}
+
+// exportTime formats t as RFC 3339, or returns an empty cell when t is unset
+func exportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
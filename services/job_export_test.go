@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fieldfuze-backend/models"
+	"testing"
+)
+
+func TestExportRowEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{cell: "Boiler service", want: "Boiler service"},
+		{cell: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{cell: "+1 555 0100", want: "'+1 555 0100"},
+		{cell: "-call first", want: "'-call first"},
+		{cell: "@SUM(A1)", want: "'@SUM(A1)"},
+		{cell: "\tindented", want: "'\tindented"},
+		{cell: "\rreturn", want: "'\rreturn"},
+		{cell: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		job := &models.Job{JobsName: tt.cell, Notes: tt.cell, ClientID: tt.cell, Site: &models.JobSite{Address: tt.cell}}
+		row := exportRow(job)
+		for _, i := range []int{1, 2, 5, 12} {
+			if row[i] != tt.want {
+				t.Errorf("exportRow() cell %d = %q, want %q", i, row[i], tt.want)
+			}
+		}
+		if got := unescapeCell(tt.want); got != tt.cell {
+			t.Errorf("unescapeCell(%q) = %q, want %q", tt.want, got, tt.cell)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/geo"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrInvalidJobSite is returned for job sites without an address or with
+// incomplete or unknown coordinates
+var ErrInvalidJobSite = errors.New("invalid job site")
+
+// ErrGeofenceOverrideRequired is returned when a job of an organization with
+// the override policy is started away from its site before a supervisor
+// granted an override
+var ErrGeofenceOverrideRequired = errors.New("geofence override required")
+
+// resolveSite builds a job site from in. Coordinates missing from in are
+// looked up from the address when a geocoder is configured; if the lookup
+// fails for reasons other than an unknown address, the site is kept without
+// coordinates and looked up again when the job is checked.
+func (s *JobService) resolveSite(ctx context.Context, in *models.JobSiteInput) (*models.JobSite, error) {
+	site := &models.JobSite{
+		Address:   strings.TrimSpace(in.Address),
+		Latitude:  in.Latitude,
+		Longitude: in.Longitude,
+	}
+
+	if (site.Latitude == nil) != (site.Longitude == nil) {
+		return nil, fmt.Errorf("%w: latitude and longitude must be given together", ErrInvalidJobSite)
+	}
+	if site.HasCoordinates() {
+		if !(geo.Point{Latitude: *site.Latitude, Longitude: *site.Longitude}).Valid() {
+			return nil, fmt.Errorf("%w: coordinates are out of range", ErrInvalidJobSite)
+		}
+		return site, nil
+	}
+	if site.Address == "" {
+		return nil, fmt.Errorf("%w: an address or coordinates are required", ErrInvalidJobSite)
+	}
+	if len(site.Address) > 300 {
+		return nil, fmt.Errorf("%w: address must be less than 300 characters", ErrInvalidJobSite)
+	}
+
+	if s.geocoder == nil {
+		return site, nil
+	}
+
+	point, err := s.geocode(ctx, site.Address)
+	switch {
+	case errors.Is(err, geo.ErrAddressNotFound):
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJobSite, err)
+	case err != nil:
+		return site, nil
+	}
+	site.Latitude = &point.Latitude
+	site.Longitude = &point.Longitude
+	site.Geocoded = true
+	return site, nil
+}
+
+// geocode looks up address with the configured geocoder, logging failures
+// other than an unknown address
+func (s *JobService) geocode(ctx context.Context, address string) (geo.Point, error) {
+	point, err := s.geocoder.Geocode(ctx, address)
+	if err != nil && !errors.Is(err, geo.ErrAddressNotFound) {
+		s.logger.Warnf("Failed to geocode job site %q: %v", address, err)
+	}
+	return point, err
+}
+
+// siteLocation returns the coordinates of the site of job, geocoding its
+// address if they are missing. ok is false when they are unknown.
+func (s *JobService) siteLocation(ctx context.Context, job *models.Job) (point geo.Point, ok bool) {
+	if job.Site == nil {
+		return geo.Point{}, false
+	}
+	if job.Site.HasCoordinates() {
+		return geo.Point{Latitude: *job.Site.Latitude, Longitude: *job.Site.Longitude}, true
+	}
+	if job.Site.Address == "" || s.geocoder == nil {
+		return geo.Point{}, false
+	}
+	point, err := s.geocode(ctx, job.Site.Address)
+	return point, err == nil
+}
+
+// geofenceCheck compares location, which may be nil, with the site of job
+// using the geofence radius of org. siteKnown is false when the check could
+// not be made for lack of site coordinates.
+func (s *JobService) geofenceCheck(ctx context.Context, job *models.Job, org *models.Organization, location *models.DeviceLocation, actor string) (check *models.GeofenceCheck, siteKnown bool) {
+	check = &models.GeofenceCheck{
+		Result:       models.GeofenceUnverified,
+		RadiusMeters: s.geofenceRadius,
+		UID:          actor,
+		CheckedAt:    time.Now().UTC(),
+	}
+	if org.GeofenceRadiusM > 0 {
+		check.RadiusMeters = org.GeofenceRadiusM
+	}
+
+	reported := location != nil && location.Latitude != nil && location.Longitude != nil
+	if reported {
+		check.Latitude = location.Latitude
+		check.Longitude = location.Longitude
+		check.Accuracy = location.Accuracy
+	}
+
+	site, ok := s.siteLocation(ctx, job)
+	switch {
+	case !ok:
+		check.Note = "job site location is unknown"
+		return check, false
+	case !reported:
+		check.Note = "device location was not reported"
+		return check, true
+	}
+
+	distance := math.Round(geo.Distance(site, geo.Point{Latitude: *location.Latitude, Longitude: *location.Longitude})*10) / 10
+	check.DistanceMeters = &distance
+
+	// An imprecise fix counts in the device's favour, but never for more
+	// than the radius itself
+	radius := float64(check.RadiusMeters)
+	if distance <= radius+math.Min(location.Accuracy, radius) {
+		check.Result = models.GeofenceInside
+	} else {
+		check.Result = models.GeofenceOutside
+	}
+	return check, true
+}
+
+// checkStart records the start check of job, which is about to start for
+// the first time, on updated. Under the override policy a job whose site is
+// known must be started inside the geofence or with an override granted
+// beforehand; the override is used up by the start.
+func (s *JobService) checkStart(ctx context.Context, job *models.Job, updated *models.Job, location *models.DeviceLocation, actor string) error {
+	org, err := s.organization(ctx, job.OrgID)
+	if err != nil {
+		return err
+	}
+
+	check, siteKnown := s.geofenceCheck(ctx, job, org, location, actor)
+	if org.GeofencePolicy == models.GeofencePolicyOverride && siteKnown && check.Result != models.GeofenceInside {
+		if job.GeofenceOverride == nil {
+			return fmt.Errorf("%w: %s", ErrGeofenceOverrideRequired, geofenceDetail(check))
+		}
+		check.Override = job.GeofenceOverride
+	}
+
+	updated.StartCheck = check
+	updated.GeofenceOverride = nil
+	return nil
+}
+
+// checkCompletion records the completion check of job on updated.
+// Completions away from the site are only flagged.
+func (s *JobService) checkCompletion(ctx context.Context, job *models.Job, updated *models.Job, location *models.DeviceLocation, actor string) error {
+	org, err := s.organization(ctx, job.OrgID)
+	if err != nil {
+		return err
+	}
+
+	updated.CompletionCheck, _ = s.geofenceCheck(ctx, job, org, location, actor)
+	return nil
+}
+
+// organization returns the organization orgID
+func (s *JobService) organization(ctx context.Context, orgID string) (*models.Organization, error) {
+	return s.orgRepo.GetOrganizationByID(ctx, orgID)
+}
+
+// GrantGeofenceOverride allows the next start of job id outside its
+// geofence. Jobs that have already started cannot be overridden.
+func (s *JobService) GrantGeofenceOverride(ctx context.Context, id string, grantedBy string, reason string) (*models.Job, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w to override the geofence", ErrStatusReasonRequired)
+	}
+	if len(reason) > 500 {
+		return nil, errors.New("reason must be less than 500 characters")
+	}
+
+	existing, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.JobStartedAt != nil {
+		return nil, fmt.Errorf("%w: job has already been started", ErrInvalidJobTransition)
+	}
+	if !existing.JobStatus.CanTransitionTo(models.JobStatusInProgress) {
+		return nil, fmt.Errorf("%w: job is %s and cannot be started", ErrInvalidJobTransition, existing.JobStatus)
+	}
+
+	updatedJob := *existing
+	updatedJob.UpdatedBy = grantedBy
+	updatedJob.GeofenceOverride = &models.GeofenceOverride{
+		UID:       grantedBy,
+		Reason:    reason,
+		GrantedAt: time.Now().UTC(),
+	}
+
+	job, err := s.jobRepo.UpdateJob(ctx, id, &updatedJob)
+	if err != nil {
+		return nil, err
+	}
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:   job.JobID,
+		OrgID:   job.OrgID,
+		Type:    models.ActivityGeofenceOverride,
+		ActorID: grantedBy,
+		Summary: "Geofence override granted for the next start",
+		Reason:  reason,
+	})
+
+	s.logger.Infof("Geofence override for job %s granted by %s", id, grantedBy)
+	return job, nil
+}
+
+// geofenceActivity returns the feed entry flagging a check made away from
+// the job site, or nil when check is not outside the geofence. action is
+// the past tense of what was checked, such as "Started".
+func geofenceActivity(job *models.Job, action string, check *models.GeofenceCheck) *models.Activity {
+	if check == nil || check.Result != models.GeofenceOutside {
+		return nil
+	}
+	activity := &models.Activity{
+		JobID:   job.JobID,
+		OrgID:   job.OrgID,
+		Type:    models.ActivityGeofenceFlagged,
+		ActorID: check.UID,
+		Summary: action + " " + geofenceDetail(check),
+	}
+	if check.Override != nil {
+		activity.Reason = check.Override.Reason
+	}
+	return activity
+}
+
+// geofenceDetail describes where check was made relative to the job site
+func geofenceDetail(check *models.GeofenceCheck) string {
+	if check.DistanceMeters == nil {
+		return check.Note
+	}
+	return fmt.Sprintf("%.0f m from the job site, outside the %d m geofence", *check.DistanceMeters, check.RadiusMeters)
+}
@@ -0,0 +1,413 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fieldfuze-backend/models"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidJobImport is returned for import files that cannot be read or
+// have invalid rows
+var ErrInvalidJobImport = errors.New("invalid job import")
+
+// maxJobImportErrors caps the row errors stored with an import
+const maxJobImportErrors = 100
+
+// jobImportProgressInterval is the number of rows after which the progress
+// of an import is stored
+const jobImportProgressInterval = 25
+
+// importRow is a valid row of an import, ready to be created
+type importRow struct {
+	line   int
+	req    *models.CreateJobRequest
+	status models.JobStatus
+}
+
+// ImportJobs reads the jobs of orgID from the CSV file and validates every
+// row like CreateJob does. A dry run stops there and reports the invalid
+// rows. Otherwise, provided every row is valid, the jobs are created in the
+// background and the returned result carries the import tracking them.
+func (s *JobService) ImportJobs(ctx context.Context, orgID string, fileName string, file io.Reader, mapping *models.JobImportMapping, dryRun bool, createdBy string) (*models.JobImportResult, error) {
+	if strings.TrimSpace(orgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+	if mapping == nil {
+		mapping = &models.JobImportMapping{}
+	}
+	if err := checkImportMapping(mapping); err != nil {
+		return nil, err
+	}
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, rowErrors, total, err := s.readImport(file, orgID, mapping, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.JobImportResult{
+		DryRun:    dryRun,
+		TotalRows: total,
+		ValidRows: len(rows),
+		Errors:    rowErrors,
+	}
+	if dryRun {
+		return result, nil
+	}
+	if len(rowErrors) > 0 {
+		return result, fmt.Errorf("%w: %d of %d rows are invalid", ErrInvalidJobImport, total-len(rows), total)
+	}
+	if total == 0 {
+		return result, fmt.Errorf("%w: the file has no rows", ErrInvalidJobImport)
+	}
+
+	jobImport, err := s.jobImportRepo.CreateJobImport(ctx, &models.JobImport{
+		OrgID:     orgID,
+		FileName:  fileName,
+		Status:    models.JobImportStatusRunning,
+		TotalRows: total,
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The import outlives the request but keeps its values
+	tracked := *jobImport
+	go s.runImport(context.WithoutCancel(ctx), &tracked, rows, createdBy)
+
+	result.Import = jobImport
+	return result, nil
+}
+
+// MaxImportSize returns the largest accepted import file in bytes
+func (s *JobService) MaxImportSize() int64 {
+	return s.importMaxSize
+}
+
+// GetJobImport returns the progress of an import
+func (s *JobService) GetJobImport(ctx context.Context, id string) (*models.JobImport, error) {
+	return s.jobImportRepo.GetJobImport(ctx, id)
+}
+
+// checkImportMapping rejects mappings of fields an import cannot read
+func checkImportMapping(mapping *models.JobImportMapping) error {
+	known := make(map[string]bool, len(models.JobImportFields))
+	for _, field := range models.JobImportFields {
+		known[field] = true
+	}
+	for field := range mapping.Columns {
+		if !known[field] {
+			return fmt.Errorf("%w: unknown field %q in columns", ErrInvalidJobImport, field)
+		}
+	}
+	for field := range mapping.Defaults {
+		if !known[field] {
+			return fmt.Errorf("%w: unknown field %q in defaults", ErrInvalidJobImport, field)
+		}
+	}
+	return nil
+}
+
+// readImport parses the CSV file and validates its rows. It returns the
+// valid rows, the errors of the invalid ones and the number of rows.
+func (s *JobService) readImport(file io.Reader, orgID string, mapping *models.JobImportMapping, loc *time.Location) ([]importRow, []models.JobImportRowError, int, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, 0, fmt.Errorf("%w: the file is empty", ErrInvalidJobImport)
+	}
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("%w: %v", ErrInvalidJobImport, err)
+	}
+
+	columns, err := importColumns(header, mapping)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var rows []importRow
+	rowErrors := []models.JobImportRowError{}
+	total := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("%w: %v", ErrInvalidJobImport, err)
+		}
+		// Quoted cells may span lines, so rows are numbered by where they start
+		line, _ := reader.FieldPos(0)
+		if blankRecord(record) {
+			continue
+		}
+
+		total++
+		if total > s.importMaxRows {
+			return nil, nil, 0, fmt.Errorf("%w: the file has more than %d rows", ErrInvalidJobImport, s.importMaxRows)
+		}
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				if v := strings.TrimSpace(unescapeCell(record[i])); v != "" {
+					return v
+				}
+			}
+			return strings.TrimSpace(mapping.Defaults[field])
+		}
+
+		row, rowErr := s.parseImportRow(line, orgID, value, loc)
+		if rowErr != nil {
+			rowErrors = append(rowErrors, *rowErr)
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, total, nil
+}
+
+// importColumns finds the column of every field in header. Headers are
+// matched ignoring case and surrounding space.
+func importColumns(header []string, mapping *models.JobImportMapping) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Byte order mark of spreadsheet exports
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := positions[name]; !ok {
+			positions[name] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for _, field := range models.JobImportFields {
+		name, mapped := mapping.Columns[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("%w: column %q mapped to %s is not in the file", ErrInvalidJobImport, name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+	return columns, nil
+}
+
+// blankRecord reports whether every cell of record is empty
+func blankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportRow builds the job request of the row on line from the values
+// of its fields and checks it with the rules of CreateJob
+func (s *JobService) parseImportRow(line int, orgID string, value func(string) string, loc *time.Location) (importRow, *models.JobImportRowError) {
+	fail := func(field, message string) (importRow, *models.JobImportRowError) {
+		return importRow{}, &models.JobImportRowError{Row: line, Field: field, Message: message}
+	}
+
+	req := &models.CreateJobRequest{
+		ClientID:              value(models.JobImportFieldClientID),
+		JobsName:              value(models.JobImportFieldJobsName),
+		JobType:               models.JobType(value(models.JobImportFieldJobType)),
+		Notes:                 value(models.JobImportFieldNotes),
+		OrgID:                 orgID,
+		UsersAssignedToJob:    splitImportList(value(models.JobImportFieldUsersAssignedToJob)),
+		VehiclesAssignedToJob: splitImportList(value(models.JobImportFieldVehiclesAssignedToJob)),
+		Priority:              models.JobPriority(value(models.JobImportFieldPriority)),
+		JobScheduleInput: models.JobScheduleInput{
+			ScheduledStart: value(models.JobImportFieldScheduledStart),
+			ScheduledEnd:   value(models.JobImportFieldScheduledEnd),
+		},
+	}
+	if err := s.validateCreateJob(req); err != nil {
+		return fail("", err.Error())
+	}
+
+	switch req.JobType {
+	case models.JobTypeService, models.JobTypeMaintenance, models.JobTypeInstallation, models.JobTypeRepair, models.JobTypeInspection:
+	default:
+		return fail(models.JobImportFieldJobType, "job type must be one of: service, maintenance, installation, repair, inspection")
+	}
+
+	switch req.Priority {
+	case "", models.JobPriorityLow, models.JobPriorityNormal, models.JobPriorityHigh, models.JobPriorityEmergency:
+	default:
+		return fail(models.JobImportFieldPriority, "priority must be one of: low, normal, high, emergency")
+	}
+
+	status := models.JobStatus(value(models.JobImportFieldJobStatus))
+	switch status {
+	case "":
+		status = models.JobStatusPending
+	case models.JobStatusPending, models.JobStatusCompleted, models.JobStatusCancelled:
+	default:
+		return fail(models.JobImportFieldJobStatus, "job status must be one of: pending, completed, cancelled")
+	}
+
+	if duration := value(models.JobImportFieldEstimatedDuration); duration != "" {
+		minutes, err := strconv.Atoi(duration)
+		if err != nil || minutes < 1 || minutes > 10080 {
+			return fail(models.JobImportFieldEstimatedDuration, "estimated duration must be a number of minutes between 1 and 10080")
+		}
+		req.EstimatedDuration = minutes
+	}
+
+	if !req.JobScheduleInput.IsEmpty() {
+		if err := applySchedule(&models.Job{}, req.JobScheduleInput, loc); err != nil {
+			return fail("", err.Error())
+		}
+	}
+	if status == models.JobStatusCompleted && req.ScheduledStart == "" {
+		return fail(models.JobImportFieldScheduledStart, "completed jobs need the time the work started")
+	}
+
+	if address := value(models.JobImportFieldSiteAddress); address != "" {
+		if len(address) > 300 {
+			return fail(models.JobImportFieldSiteAddress, "address must be less than 300 characters")
+		}
+		req.Site = &models.JobSiteInput{Address: address}
+	}
+
+	customerID := value(models.JobImportFieldQBCustomerID)
+	lineItemID := value(models.JobImportFieldQBLineItemID)
+	if customerID != "" || lineItemID != "" {
+		req.QBInfoOnJob = &models.QBInfoOnJob{CustomerID: customerID, LineItemID: lineItemID}
+	}
+
+	return importRow{line: line, req: req, status: status}, nil
+}
+
+// splitImportList splits a list cell on semicolons
+func splitImportList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runImport creates the jobs of rows, storing the progress of jobImport as
+// it goes. Rows that fail are recorded and skipped.
+func (s *JobService) runImport(ctx context.Context, jobImport *models.JobImport, rows []importRow, createdBy string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Errorf("Job import %s stopped: %v\n%s", jobImport.ImportID, r, debug.Stack())
+			s.finishImport(ctx, jobImport, models.JobImportStatusFailed, fmt.Sprintf("stopped after %d rows", jobImport.ProcessedRows))
+		}
+	}()
+
+	for _, row := range rows {
+		if err := s.importJob(ctx, row, createdBy); err != nil {
+			jobImport.FailedRows++
+			if len(jobImport.Errors) < maxJobImportErrors {
+				jobImport.Errors = append(jobImport.Errors, models.JobImportRowError{Row: row.line, Message: err.Error()})
+			}
+		} else {
+			jobImport.CreatedJobs++
+		}
+		jobImport.ProcessedRows++
+
+		if jobImport.ProcessedRows%jobImportProgressInterval == 0 {
+			if _, err := s.jobImportRepo.UpdateJobImport(ctx, jobImport); err != nil {
+				s.logger.Warnf("Failed to store the progress of job import %s: %v", jobImport.ImportID, err)
+			}
+		}
+	}
+
+	s.finishImport(ctx, jobImport, models.JobImportStatusCompleted, "")
+	s.logger.Infof("Job import %s created %d of %d jobs", jobImport.ImportID, jobImport.CreatedJobs, jobImport.TotalRows)
+}
+
+// finishImport stores the final state of jobImport
+func (s *JobService) finishImport(ctx context.Context, jobImport *models.JobImport, status models.JobImportStatus, reason string) {
+	now := time.Now().UTC()
+	jobImport.Status = status
+	jobImport.Error = reason
+	jobImport.CompletedAt = &now
+	if _, err := s.jobImportRepo.UpdateJobImport(ctx, jobImport); err != nil {
+		s.logger.Errorf("Failed to store the outcome of job import %s: %v", jobImport.ImportID, err)
+	}
+}
+
+// importJob creates the job of row. Completed and cancelled rows are stored
+// as history, without checklist, assignment checks or SLA.
+func (s *JobService) importJob(ctx context.Context, row importRow, createdBy string) error {
+	job, err := s.newJob(ctx, row.req, createdBy)
+	if err != nil {
+		return err
+	}
+	if row.status != models.JobStatusPending {
+		closeImportedJob(job, row.status, createdBy)
+	}
+
+	_, err = s.insertJob(ctx, job, false, createdBy, "Job imported")
+	return err
+}
+
+// closeImportedJob moves job, an imported job, to status, completed or
+// cancelled. Completed jobs ran over their schedule.
+func closeImportedJob(job *models.Job, status models.JobStatus, actor string) {
+	now := time.Now().UTC()
+	switch status {
+	case models.JobStatusCompleted:
+		job.JobStartedAt = job.ScheduledStart
+		job.JobEndedAt = job.ScheduledStart
+		if job.ScheduledEnd != nil {
+			job.JobEndedAt = job.ScheduledEnd
+		}
+	case models.JobStatusCancelled:
+		job.CancelledData = &models.CancelledData{
+			UID:         actor,
+			UserStatus:  "active",
+			CancelledAt: now,
+			Reason:      "Imported",
+		}
+	}
+
+	job.JobStatus = status
+	job.Checklist = nil
+	job.StatusHistory = append(job.StatusHistory, models.StatusChange{
+		From:      models.JobStatusPending,
+		To:        status,
+		UID:       actor,
+		ChangedAt: now,
+		Reason:    "Imported",
+	})
+}
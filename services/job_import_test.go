@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"strings"
+	"testing"
+)
+
+func newImportService(maxRows int) *JobService {
+	return &JobService{
+		orgRepo:       &fakeOrgRepo{organizations: map[string]*models.Organization{"org-1": {ID: "org-1"}}},
+		importMaxRows: maxRows,
+		logger:        testLogger(),
+	}
+}
+
+func TestImportJobsDryRun(t *testing.T) {
+	file := "\ufeffJob Title,Customer,JOBTYPE,jobStatus,priority,estimatedDuration,scheduledStart,notes\n" +
+		"Boiler service,client-1,service,,high,60,2026-03-02T09:00:00Z,\n" +
+		"Repair,,repair,,,,,\n" +
+		"Inspection,client-1,survey,,,,,\n" +
+		",,,,,,,\n" +
+		"Install,client-1,installation,,urgent,,,\n" +
+		"Maintenance,client-1,maintenance,,,0,,\n" +
+		"Closed,client-1,repair,completed,,,,\n" +
+		"Quoted,client-1,service,,,,,\"two\nlines\"\n" +
+		"Last,client-1,service,pending,low,30,,\n"
+	mapping := &models.JobImportMapping{Columns: map[string]string{
+		models.JobImportFieldJobsName: "job title",
+		models.JobImportFieldClientID: "Customer",
+	}}
+
+	result, err := newImportService(100).ImportJobs(context.Background(), "org-1", "jobs.csv", strings.NewReader(file), mapping, true, "user-1")
+	if err != nil {
+		t.Fatalf("ImportJobs() error = %v", err)
+	}
+	if !result.DryRun || result.TotalRows != 8 || result.ValidRows != 3 || result.Import != nil {
+		t.Fatalf("ImportJobs() = dry run %v, %d rows, %d valid, import %v; want dry run of 8 rows, 3 valid", result.DryRun, result.TotalRows, result.ValidRows, result.Import)
+	}
+
+	want := []models.JobImportRowError{
+		{Row: 3, Field: ""},
+		{Row: 4, Field: models.JobImportFieldJobType},
+		{Row: 6, Field: models.JobImportFieldPriority},
+		{Row: 7, Field: models.JobImportFieldEstimatedDuration},
+		{Row: 8, Field: models.JobImportFieldScheduledStart},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("ImportJobs() errors = %+v, want rows 3, 4, 6, 7 and 8", result.Errors)
+	}
+	for i, rowErr := range result.Errors {
+		if rowErr.Row != want[i].Row || rowErr.Field != want[i].Field || rowErr.Message == "" {
+			t.Errorf("error %d = %+v, want row %d field %q with a message", i, rowErr, want[i].Row, want[i].Field)
+		}
+	}
+}
+
+func TestImportJobsDefaults(t *testing.T) {
+	file := "jobsName,clientID,jobType\nBoiler service,client-1,\nRepair,client-2,repair\n"
+	mapping := &models.JobImportMapping{Defaults: map[string]string{models.JobImportFieldJobType: "maintenance"}}
+
+	s := newImportService(100)
+	rows, rowErrors, total, err := s.readImport(strings.NewReader(file), "org-1", mapping, nil)
+	if err != nil || len(rowErrors) != 0 || total != 2 {
+		t.Fatalf("readImport() = %d rows, errors %v, %v; want 2 valid rows", total, rowErrors, err)
+	}
+	if rows[0].req.JobType != models.JobTypeMaintenance || rows[1].req.JobType != models.JobTypeRepair {
+		t.Fatalf("job types = %s, %s; want the default for the empty cell only", rows[0].req.JobType, rows[1].req.JobType)
+	}
+	if rows[0].status != models.JobStatusPending || rows[0].req.OrgID != "org-1" {
+		t.Fatalf("row = %+v, want a pending job of org-1", rows[0])
+	}
+}
+
+func TestImportJobsRejected(t *testing.T) {
+	valid := "jobsName,clientID,jobType\nBoiler service,client-1,service\n"
+
+	tests := []struct {
+		name    string
+		file    string
+		mapping *models.JobImportMapping
+		maxRows int
+	}{
+		{name: "empty file", file: ""},
+		{name: "header only", file: "jobsName,clientID,jobType\n"},
+		{name: "invalid row", file: valid + "Repair,,repair\n"},
+		{name: "too many rows", file: valid + "Repair,client-1,repair\n", maxRows: 1},
+		{name: "mapped column missing", file: valid, mapping: &models.JobImportMapping{Columns: map[string]string{models.JobImportFieldNotes: "Remarks"}}},
+		{name: "unknown mapped field", file: valid, mapping: &models.JobImportMapping{Columns: map[string]string{"invoice": "Invoice"}}},
+		{name: "unknown default", file: valid, mapping: &models.JobImportMapping{Defaults: map[string]string{"jobID": "job-1"}}},
+		{name: "malformed csv", file: "jobsName,clientID,jobType\n\"Boiler,client-1,service\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxRows := tt.maxRows
+			if maxRows == 0 {
+				maxRows = 100
+			}
+
+			_, err := newImportService(maxRows).ImportJobs(context.Background(), "org-1", "jobs.csv", strings.NewReader(tt.file), tt.mapping, false, "user-1")
+			if !errors.Is(err, ErrInvalidJobImport) {
+				t.Fatalf("ImportJobs() error = %v, want ErrInvalidJobImport", err)
+			}
+		})
+	}
+}
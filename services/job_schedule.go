@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrInvalidSchedule is returned for schedule times that cannot be parsed or
+// do not fit together
+var ErrInvalidSchedule = errors.New("invalid job schedule")
+
+// maxCalendarRange bounds the period a calendar request may cover
+const maxCalendarRange = 62 * 24 * time.Hour
+
+// defaultCalendarRange is the period covered when no end is requested
+const defaultCalendarRange = 7 * 24 * time.Hour
+
+// localTimeLayouts are the formats accepted for local times besides RFC 3339
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// parseScheduleTime reads value as RFC 3339 or as a local time in loc.
+// Schedules are kept to the minute so the stored values sort as strings.
+func parseScheduleTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Truncate(time.Minute), nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC().Truncate(time.Minute), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not an RFC 3339 or local time", ErrInvalidSchedule, value)
+}
+
+// organizationLocation returns the timezone of the organization orgID
+func organizationLocation(ctx context.Context, orgRepo repository.OrganizationRepositoryInterface, orgID string) (*time.Location, error) {
+	organization, err := orgRepo.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return organization.Location(), nil
+}
+
+// applySchedule merges in into the schedule of job, reading local times in
+// loc, and checks that the result is consistent
+func applySchedule(job *models.Job, in models.JobScheduleInput, loc *time.Location) error {
+	if in.ScheduledStart != "" {
+		start, err := parseScheduleTime(in.ScheduledStart, loc)
+		if err != nil {
+			return err
+		}
+		job.ScheduledStart = &start
+	}
+	if in.ScheduledEnd != "" {
+		end, err := parseScheduleTime(in.ScheduledEnd, loc)
+		if err != nil {
+			return err
+		}
+		job.ScheduledEnd = &end
+	}
+	if in.ArrivalWindow != nil {
+		start, err := parseScheduleTime(in.ArrivalWindow.Start, loc)
+		if err != nil {
+			return err
+		}
+		end, err := parseScheduleTime(in.ArrivalWindow.End, loc)
+		if err != nil {
+			return err
+		}
+		job.ArrivalWindow = &models.ArrivalWindow{Start: start, End: end}
+	}
+	if in.EstimatedDuration != 0 {
+		job.EstimatedDuration = in.EstimatedDuration
+		if in.ScheduledEnd == "" && job.ScheduledStart != nil {
+			end := job.ScheduledStart.Add(time.Duration(in.EstimatedDuration) * time.Minute)
+			job.ScheduledEnd = &end
+		}
+	}
+	job.Timezone = loc.String()
+
+	if job.ScheduledStart == nil && (job.ScheduledEnd != nil || job.ArrivalWindow != nil) {
+		return fmt.Errorf("%w: scheduledStart is required", ErrInvalidSchedule)
+	}
+	if job.ScheduledEnd != nil && !job.ScheduledEnd.After(*job.ScheduledStart) {
+		return fmt.Errorf("%w: scheduledEnd must be after scheduledStart", ErrInvalidSchedule)
+	}
+	if job.ArrivalWindow != nil && !job.ArrivalWindow.End.After(job.ArrivalWindow.Start) {
+		return fmt.Errorf("%w: arrival window must end after it starts", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// clearSchedule removes the schedule of job
+func clearSchedule(job *models.Job) {
+	job.ScheduledStart = nil
+	job.ScheduledEnd = nil
+	job.ArrivalWindow = nil
+	job.EstimatedDuration = 0
+	job.Timezone = ""
+}
+
+// GetCalendar returns the jobs of orgID scheduled to start between from and
+// to, grouped by technician and by day in the organization's timezone. from
+// and to are dates (to inclusive) or times; from defaults to today and to
+// to a week after from. A non-empty assignee limits the calendar to that
+// technician.
+func (s *JobService) GetCalendar(ctx context.Context, orgID, from, to, assignee string) (*models.JobCalendar, error) {
+	if orgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := calendarRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stored starts are whole minutes, so the second before end excludes it
+	jobs, err := s.jobRepo.GetScheduledJobs(ctx, orgID, start, end.Add(-time.Second), assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCalendar(orgID, loc, start, end, assignee, jobs), nil
+}
+
+// calendarRange resolves the requested period to [start, end) in loc
+func calendarRange(from, to string, loc *time.Location) (time.Time, time.Time, error) {
+	var start time.Time
+	if from == "" {
+		now := time.Now().In(loc)
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	} else {
+		var err error
+		if start, _, err = parseCalendarBound(from, loc); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	end := start.Add(defaultCalendarRange)
+	if to != "" {
+		bound, isDate, err := parseCalendarBound(to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end = bound
+		if isDate {
+			// A date includes the whole day
+			end = bound.AddDate(0, 0, 1)
+		}
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be after from", ErrInvalidSchedule)
+	}
+	if end.Sub(start) > maxCalendarRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: calendar range must not exceed %d days", ErrInvalidSchedule, int(maxCalendarRange.Hours()/24))
+	}
+	return start, end, nil
+}
+
+// parseCalendarBound reads value as a date at midnight in loc or as a time
+// and reports whether it was a date
+func parseCalendarBound(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := parseScheduleTime(value, loc)
+	return t, false, err
+}
+
+// buildCalendar groups jobs by assignee and by local start day. Jobs with
+// several assignees appear under each of them.
+func buildCalendar(orgID string, loc *time.Location, start, end time.Time, assignee string, jobs []*models.Job) *models.JobCalendar {
+	scheduled := make([]*models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if job.ScheduledStart != nil {
+			scheduled = append(scheduled, job)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].ScheduledStart.Before(*scheduled[j].ScheduledStart)
+	})
+
+	days := make(map[string]map[string][]*models.Job)
+	for _, job := range scheduled {
+		date := job.ScheduledStart.In(loc).Format("2006-01-02")
+
+		technicians := job.UsersAssignedToJob
+		if assignee != "" {
+			technicians = []string{assignee}
+		} else if len(technicians) == 0 {
+			technicians = []string{""}
+		}
+
+		for _, userID := range technicians {
+			if days[userID] == nil {
+				days[userID] = make(map[string][]*models.Job)
+			}
+			days[userID][date] = append(days[userID][date], job)
+		}
+	}
+
+	userIDs := make([]string, 0, len(days))
+	for userID := range days {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	calendar := &models.JobCalendar{
+		OrgID:       orgID,
+		Timezone:    loc.String(),
+		From:        start,
+		To:          end,
+		Technicians: make([]models.TechnicianSchedule, 0, len(userIDs)),
+	}
+	for _, userID := range userIDs {
+		dates := make([]string, 0, len(days[userID]))
+		for date := range days[userID] {
+			dates = append(dates, date)
+		}
+		sort.Strings(dates)
+
+		schedule := models.TechnicianSchedule{UserID: userID}
+		for _, date := range dates {
+			schedule.Days = append(schedule.Days, models.CalendarDay{Date: date, Jobs: days[userID][date]})
+		}
+		calendar.Technicians = append(calendar.Technicians, schedule)
+	}
+	return calendar
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/search"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidJobSearch is returned for searches with unknown filter values or
+// pages out of range
+var ErrInvalidJobSearch = errors.New("invalid job search")
+
+// ErrSearchUnavailable is returned when jobs are not indexed
+var ErrSearchUnavailable = errors.New("job search is not available")
+
+// defaultSearchPageSize is the number of jobs per page of a search that does
+// not set a limit
+const defaultSearchPageSize = 10
+
+// searchFacetSize is the number of assignees counted per search. Statuses
+// and types have fewer values.
+const searchFacetSize = 20
+
+// jobSearchBoosts weighs matches on the name of a job above its client and
+// address, and those above its notes
+var jobSearchBoosts = map[string]float64{
+	repository.JobSearchFieldName:    3,
+	repository.JobSearchFieldClient:  2,
+	repository.JobSearchFieldAddress: 2,
+	repository.JobSearchFieldNotes:   1,
+}
+
+// SearchJobs returns the jobs of an organization matching req, best matches
+// first, with the number of all matches by status, type, assignee and date
+// period. Jobs are read back from the database, so results reflect their
+// stored state even while the index catches up. Totals and facets are
+// counted by the index and may include jobs deleted since it last synced on
+// pages other than the one returned.
+func (s *JobService) SearchJobs(ctx context.Context, req *models.JobSearchRequest) (*models.JobSearchResult, error) {
+	if s.searchIndex == nil {
+		return nil, ErrSearchUnavailable
+	}
+	if req == nil || req.OrgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+	if err := s.validateJobSearch(req); err != nil {
+		return nil, err
+	}
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	periods := searchDateRanges(time.Now(), loc)
+
+	query := &search.Query{
+		OrgID:      req.OrgID,
+		Text:       strings.TrimSpace(req.Query),
+		Boosts:     jobSearchBoosts,
+		Filters:    make(map[string][]string),
+		Facets:     []string{repository.JobSearchFacetStatus, repository.JobSearchFacetType, repository.JobSearchFacetAssignee},
+		FacetSize:  searchFacetSize,
+		DateRanges: periods,
+		From:       (req.Page - 1) * req.Limit,
+		Size:       req.Limit,
+	}
+	for _, status := range req.Statuses {
+		query.Filters[repository.JobSearchFacetStatus] = append(query.Filters[repository.JobSearchFacetStatus], string(status))
+	}
+	for _, jobType := range req.Types {
+		query.Filters[repository.JobSearchFacetType] = append(query.Filters[repository.JobSearchFacetType], string(jobType))
+	}
+	query.Filters[repository.JobSearchFacetAssignee] = req.Assignees
+	if req.Date != "" {
+		for _, period := range periods {
+			if period.Name == string(req.Date) {
+				query.DateFilter = &period
+			}
+		}
+	}
+
+	found, err := s.searchIndex.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jobs: %w", err)
+	}
+
+	// Jobs deleted by another instance stay indexed until it syncs. They
+	// are left out of the page, taken out of the total and dropped from the
+	// index so later searches no longer count them.
+	jobs := make([]*models.Job, 0, len(found.Hits))
+	stale := 0
+	for _, hit := range found.Hits {
+		job, err := s.jobRepo.GetJob(ctx, hit.ID)
+		if err != nil && !errors.Is(err, repository.ErrJobNotFound) {
+			return nil, err
+		}
+		if err == nil && job[0].OrgID == req.OrgID {
+			jobs = append(jobs, job[0])
+			continue
+		}
+		stale++
+		if err := s.searchIndex.Delete(ctx, hit.ID); err != nil {
+			s.logger.Warnf("Failed to drop stale job %s from the search index: %v", hit.ID, err)
+		}
+	}
+	total := found.Total - stale
+
+	return &models.JobSearchResult{
+		Query: query.Text,
+		Jobs:  jobs,
+		Facets: models.JobSearchFacets{
+			Status:   searchFacets(found.Facets[repository.JobSearchFacetStatus]),
+			Type:     searchFacets(found.Facets[repository.JobSearchFacetType]),
+			Assignee: searchFacets(found.Facets[repository.JobSearchFacetAssignee]),
+			Date:     searchFacets(found.DateRanges),
+		},
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Total:      total,
+		TotalPages: (total + req.Limit - 1) / req.Limit,
+	}, nil
+}
+
+// validateJobSearch checks the filters of req and defaults its page
+func (s *JobService) validateJobSearch(req *models.JobSearchRequest) error {
+	for _, status := range req.Statuses {
+		switch status {
+		case models.JobStatusPending, models.JobStatusActive, models.JobStatusInProgress,
+			models.JobStatusCompleted, models.JobStatusCancelled, models.JobStatusOnHold:
+		default:
+			return fmt.Errorf("%w: job status must be one of: pending, active, in_progress, completed, cancelled, on_hold", ErrInvalidJobSearch)
+		}
+	}
+
+	for _, jobType := range req.Types {
+		switch jobType {
+		case models.JobTypeService, models.JobTypeMaintenance, models.JobTypeInstallation, models.JobTypeRepair, models.JobTypeInspection:
+		default:
+			return fmt.Errorf("%w: job type must be one of: service, maintenance, installation, repair, inspection", ErrInvalidJobSearch)
+		}
+	}
+
+	switch req.Date {
+	case "", models.JobSearchDateEarlier, models.JobSearchDateLast7Days, models.JobSearchDateToday,
+		models.JobSearchDateNext7Days, models.JobSearchDateLater:
+	default:
+		return fmt.Errorf("%w: date must be one of: earlier, last_7_days, today, next_7_days, later", ErrInvalidJobSearch)
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSearchPageSize
+	}
+	if req.Page < 0 {
+		return fmt.Errorf("%w: page must be at least 1", ErrInvalidJobSearch)
+	}
+	if req.Limit < 0 || req.Limit > s.searchMaxPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidJobSearch, s.searchMaxPageSize)
+	}
+	return nil
+}
+
+// searchDateRanges returns the job search periods as of now in
+// chronological order, with days starting at midnight in loc
+func searchDateRanges(now time.Time, loc *time.Location) []search.DateRange {
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	weekAgo := today.AddDate(0, 0, -7)
+	weekAhead := tomorrow.AddDate(0, 0, 7)
+
+	return []search.DateRange{
+		{Name: string(models.JobSearchDateEarlier), End: weekAgo},
+		{Name: string(models.JobSearchDateLast7Days), Start: weekAgo, End: today},
+		{Name: string(models.JobSearchDateToday), Start: today, End: tomorrow},
+		{Name: string(models.JobSearchDateNext7Days), Start: tomorrow, End: weekAhead},
+		{Name: string(models.JobSearchDateLater), Start: weekAhead},
+	}
+}
+
+// searchFacets converts facet counts of the index to their API form
+func searchFacets(counts []search.FacetCount) []models.JobSearchFacet {
+	facets := make([]models.JobSearchFacet, 0, len(counts))
+	for _, count := range counts {
+		facets = append(facets, models.JobSearchFacet{Value: count.Value, Count: count.Count})
+	}
+	return facets
+}
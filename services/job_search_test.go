@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/search"
+	"testing"
+	"time"
+)
+
+// TestSearchJobsDropsStaleHits leaves jobs deleted since they were indexed
+// out of the page and the total, and out of the index afterwards
+func TestSearchJobsDropsStaleHits(t *testing.T) {
+	ctx := context.Background()
+	index := search.NewMemoryIndex()
+	for _, id := range []string{"job-1", "job-2", "job-3"} {
+		doc := &search.Document{ID: id, OrgID: "org-1", Text: map[string]string{"name": "Boiler service"}, Date: time.Now()}
+		if err := index.Index(ctx, doc); err != nil {
+			t.Fatalf("Index() error = %v", err)
+		}
+	}
+
+	// job-2 was deleted by another instance
+	s := &JobService{
+		jobRepo: newFakeJobRepo(
+			&models.Job{JobID: "job-1", OrgID: "org-1"},
+			&models.Job{JobID: "job-3", OrgID: "org-1"},
+		),
+		orgRepo:           &fakeOrgRepo{organizations: map[string]*models.Organization{"org-1": {ID: "org-1"}}},
+		searchIndex:       index,
+		searchMaxPageSize: 50,
+		logger:            testLogger(),
+	}
+
+	req := &models.JobSearchRequest{OrgID: "org-1", Query: "boiler"}
+	result, err := s.SearchJobs(ctx, req)
+	if err != nil {
+		t.Fatalf("SearchJobs() error = %v", err)
+	}
+	if len(result.Jobs) != 2 || result.Total != 2 || result.TotalPages != 1 {
+		t.Fatalf("SearchJobs() = %d jobs, total %d in %d pages, want 2 in 1", len(result.Jobs), result.Total, result.TotalPages)
+	}
+
+	found, err := index.Search(ctx, &search.Query{OrgID: "org-1", Size: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if found.Total != 2 {
+		t.Fatalf("index holds %d jobs after the search, want 2", found.Total)
+	}
+}
@@ -5,28 +5,136 @@ import (
 	"errors"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/geo"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/notify"
+	"fieldfuze-backend/utils/search"
+	"fmt"
 	"strings"
 	"time"
 )
 
+// ErrInvalidJobTransition is returned when a job cannot move from its
+// current status to the requested one
+var ErrInvalidJobTransition = errors.New("invalid job status transition")
+
+// ErrStatusReasonRequired is returned when a job is held or cancelled
+// without a reason
+var ErrStatusReasonRequired = errors.New("reason is required")
+
 type JobService struct {
-	jobRepo repository.JobRepositoryInterface
-	logger  logger.Logger
+	jobRepo          repository.JobRepositoryInterface
+	orgRepo          repository.OrganizationRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	checklistRepo    repository.ChecklistRepositoryInterface
+	activityRepo     repository.ActivityRepositoryInterface
+	slaPolicyRepo    repository.SLAPolicyRepositoryInterface
+	jobTemplateRepo  repository.JobTemplateRepositoryInterface
+	jobImportRepo    repository.JobImportRepositoryInterface
+	projectRepo      repository.ProjectRepositoryInterface
+	requireChecklist bool
+	geocoder         geo.Geocoder
+	geofenceRadius   int
+	slaAtRiskPercent int
+	importMaxRows    int
+	importMaxSize    int64
+	logger           logger.Logger
+
+	// searchIndex holds the jobs kept in sync by jobRepo
+	searchIndex       search.Index
+	searchMaxPageSize int
+
+	// attachmentRepo and store keep sign-off signatures and completion
+	// summaries, which notifier emails as attachments
+	attachmentRepo    repository.AttachmentRepositoryInterface
+	store             blob.BlobStore
+	attachmentMaxSize int64
+	notifier          notify.Notifier
+}
+
+// JobServiceDeps holds the repositories, collaborators and limits of a
+// JobService
+type JobServiceDeps struct {
+	JobRepo          repository.JobRepositoryInterface
+	OrgRepo          repository.OrganizationRepositoryInterface
+	UserRepo         repository.UserRepositoryInterface
+	AvailabilityRepo repository.AvailabilityRepositoryInterface
+	ChecklistRepo    repository.ChecklistRepositoryInterface
+	ActivityRepo     repository.ActivityRepositoryInterface
+	SLAPolicyRepo    repository.SLAPolicyRepositoryInterface
+	JobTemplateRepo  repository.JobTemplateRepositoryInterface
+	JobImportRepo    repository.JobImportRepositoryInterface
+	ProjectRepo      repository.ProjectRepositoryInterface
+	AttachmentRepo   repository.AttachmentRepositoryInterface
+
+	RequireChecklist  bool         // Jobs cannot be completed while required checklist items are open
+	Geocoder          geo.Geocoder // Geocodes job sites; may be nil
+	GeofenceRadius    int          // Meters, unless the organization sets a radius of its own
+	SLAAtRiskPercent  int          // Jobs with at most this percentage of an SLA target left are at risk
+	ImportMaxRows     int
+	ImportMaxSize     int64        // Bytes
+	SearchIndex       search.Index // May be nil, which disables search
+	SearchMaxPageSize int
+
+	Store             blob.BlobStore  // Keeps sign-off signatures and completion summaries
+	AttachmentMaxSize int64           // Bytes of a sign-off signature
+	Notifier          notify.Notifier // Emails completion summaries
+	Logger            logger.Logger
 }
 
-func NewJobService(jobRepo repository.JobRepositoryInterface, logger logger.Logger) *JobService {
+// NewJobService creates a job service from deps
+func NewJobService(deps JobServiceDeps) *JobService {
 	return &JobService{
-		jobRepo: jobRepo,
-		logger:  logger,
+		jobRepo:          deps.JobRepo,
+		orgRepo:          deps.OrgRepo,
+		userRepo:         deps.UserRepo,
+		availabilityRepo: deps.AvailabilityRepo,
+		checklistRepo:    deps.ChecklistRepo,
+		activityRepo:     deps.ActivityRepo,
+		slaPolicyRepo:    deps.SLAPolicyRepo,
+		jobTemplateRepo:  deps.JobTemplateRepo,
+		jobImportRepo:    deps.JobImportRepo,
+		projectRepo:      deps.ProjectRepo,
+		requireChecklist: deps.RequireChecklist,
+		geocoder:         deps.Geocoder,
+		geofenceRadius:   deps.GeofenceRadius,
+		slaAtRiskPercent: deps.SLAAtRiskPercent,
+		importMaxRows:    deps.ImportMaxRows,
+		importMaxSize:    deps.ImportMaxSize,
+		logger:           deps.Logger,
+
+		searchIndex:       deps.SearchIndex,
+		searchMaxPageSize: deps.SearchMaxPageSize,
+
+		attachmentRepo:    deps.AttachmentRepo,
+		store:             deps.Store,
+		attachmentMaxSize: deps.AttachmentMaxSize,
+		notifier:          deps.Notifier,
 	}
 }
 
 func (s *JobService) CreateJob(ctx context.Context, req *models.CreateJobRequest, createdBy string) (*models.Job, error) {
+	job, err := s.newJob(ctx, req, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return s.insertJob(ctx, job, req.Force, createdBy, "Job created")
+}
+
+// newJob builds the pending job req asks for, with its schedule, site and
+// checklist, without storing it
+func (s *JobService) newJob(ctx context.Context, req *models.CreateJobRequest, createdBy string) (*models.Job, error) {
 	if err := s.validateCreateJob(req); err != nil {
 		return nil, err
 	}
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
 
+	now := time.Now().UTC()
 	job := &models.Job{
 		ClientID:              req.ClientID,
 		JobsName:              req.JobsName,
@@ -42,9 +150,90 @@ func (s *JobService) CreateJob(ctx context.Context, req *models.CreateJobRequest
 		},
 		JobImagesAfterService: []string{},
 		JobStatus:             models.JobStatusPending,
+		StatusHistory: []models.StatusChange{{
+			To:        models.JobStatusPending,
+			UID:       createdBy,
+			ChangedAt: now,
+		}},
+		Priority: req.Priority.OrDefault(),
+	}
+
+	if !req.JobScheduleInput.IsEmpty() {
+		loc, err := organizationLocation(ctx, s.orgRepo, req.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applySchedule(job, req.JobScheduleInput, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Site != nil {
+		site, err := s.resolveSite(ctx, req.Site)
+		if err != nil {
+			return nil, err
+		}
+		job.Site = site
+	}
+
+	if req.ProjectID != "" {
+		if err := s.checkProject(ctx, req.ProjectID, req.OrgID); err != nil {
+			return nil, err
+		}
+		job.ProjectID = req.ProjectID
+	}
+
+	checklist, err := newJobChecklist(ctx, s.checklistRepo, job.OrgID, job.JobType)
+	if err != nil {
+		return nil, err
+	}
+	job.Checklist = checklist
+	return job, nil
+}
+
+// insertJob checks the assignees of job, a new job, starts its SLA clocks
+// and stores it. summary describes the creation in the activity feed. Closed
+// jobs, which are imported history, are stored as they are.
+func (s *JobService) insertJob(ctx context.Context, job *models.Job, force bool, createdBy string, summary string) (*models.Job, error) {
+	// The job is stored with the rest of its organization's jobs
+	ctx, err := tenantContext(ctx, job.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []models.AssignmentConflict
+	open := job.JobStatus != models.JobStatusCompleted && job.JobStatus != models.JobStatusCancelled
+	if open && (len(job.UsersAssignedToJob) > 0 || len(job.VehiclesAssignedToJob) > 0) {
+		if warnings, err = s.checkAssignment(ctx, job, true, force); err != nil {
+			return nil, err
+		}
+	}
+
+	// SLA clocks start with the creation of the job
+	if open {
+		if job.SLA, err = newJobSLA(ctx, s.slaPolicyRepo, job.OrgID, job.JobType, job.Priority, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.jobRepo.CreateJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:   created.JobID,
+		OrgID:   created.OrgID,
+		Type:    models.ActivityJobCreated,
+		ActorID: createdBy,
+		Summary: summary,
+	})
+	if assignment := assignmentActivity(nil, created, createdBy); assignment != nil {
+		recordActivity(ctx, s.activityRepo, s.logger, assignment)
 	}
 
-	return s.jobRepo.CreateJob(ctx, job)
+	created.AssignmentWarnings = warnings
+	return created, nil
 }
 
 func (s *JobService) validateCreateJob(req *models.CreateJobRequest) error {
@@ -79,20 +268,26 @@ func (s *JobService) validateCreateJob(req *models.CreateJobRequest) error {
 	return nil
 }
 
-func (s *JobService) GetJobs(filter *models.JobFilter) ([]*models.Job, error) {
+func (s *JobService) GetJobs(ctx context.Context, filter *models.JobFilter) ([]*models.Job, error) {
 	if filter == nil {
 		filter = &models.JobFilter{}
 	}
-	return s.jobRepo.GetJobsByFilter(filter)
+	if filter.OrgID != "" {
+		var err error
+		if ctx, err = tenantContext(ctx, filter.OrgID); err != nil {
+			return nil, err
+		}
+	}
+	return s.jobRepo.GetJobsByFilter(ctx, filter)
 }
 
-func (s *JobService) GetJobByID(id string) (*models.Job, error) {
-	jobs, err := s.jobRepo.GetJob(id)
+func (s *JobService) GetJobByID(ctx context.Context, id string) (*models.Job, error) {
+	jobs, err := s.jobRepo.GetJob(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if len(jobs) == 0 {
-		return nil, errors.New("job not found")
+		return nil, repository.ErrJobNotFound
 	}
 	return jobs[0], nil
 }
@@ -103,7 +298,7 @@ func (s *JobService) UpdateJob(ctx context.Context, id string, req *models.Updat
 	}
 
 	// Get existing job
-	existing, err := s.GetJobByID(id)
+	existing, err := s.GetJobByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -115,12 +310,12 @@ func (s *JobService) UpdateJob(ctx context.Context, id string, req *models.Updat
 	if req.JobsName != "" {
 		updatedJob.JobsName = req.JobsName
 	}
-	if req.JobStatus != "" {
-		updatedJob.JobStatus = req.JobStatus
-	}
 	if req.JobType != "" {
 		updatedJob.JobType = req.JobType
 	}
+	if req.Priority != "" {
+		updatedJob.Priority = req.Priority
+	}
 	if req.Notes != "" {
 		updatedJob.Notes = req.Notes
 	}
@@ -133,11 +328,124 @@ func (s *JobService) UpdateJob(ctx context.Context, id string, req *models.Updat
 	if req.QBInfoOnJob != nil {
 		updatedJob.QBInfoOnJob = req.QBInfoOnJob
 	}
+	if req.ClearQBInfoOnJob {
+		updatedJob.QBInfoOnJob = nil
+	}
 	if req.JobImagesAfterService != nil {
 		updatedJob.JobImagesAfterService = req.JobImagesAfterService
 	}
+	if req.ClearSchedule {
+		clearSchedule(&updatedJob)
+	}
+	if req.ClearProject {
+		updatedJob.ProjectID = ""
+	}
+	if req.ProjectID != "" && req.ProjectID != existing.ProjectID {
+		if err := s.checkProject(ctx, req.ProjectID, existing.OrgID); err != nil {
+			return nil, err
+		}
+		updatedJob.ProjectID = req.ProjectID
+	}
+	if req.ClearSite {
+		updatedJob.Site = nil
+	}
+	if req.Site != nil {
+		site, err := s.resolveSite(ctx, req.Site)
+		if err != nil {
+			return nil, err
+		}
+		updatedJob.Site = site
+	}
+	if !req.JobScheduleInput.IsEmpty() {
+		loc, err := organizationLocation(ctx, s.orgRepo, existing.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applySchedule(&updatedJob, req.JobScheduleInput, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	if updatedJob.Priority.OrDefault() != existing.Priority.OrDefault() || updatedJob.JobType != existing.JobType {
+		if err := s.resetSLA(ctx, &updatedJob); err != nil {
+			return nil, err
+		}
+	}
+
+	// Assignees are checked again whenever they or the schedule change
+	var warnings []models.AssignmentConflict
+	assigneesChanged := req.UsersAssignedToJob != nil || req.VehiclesAssignedToJob != nil
+	if assigneesChanged || req.ClearSchedule || !req.JobScheduleInput.IsEmpty() {
+		if warnings, err = s.checkAssignment(ctx, &updatedJob, req.UsersAssignedToJob != nil, req.Force); err != nil {
+			return nil, err
+		}
+	}
+
+	var job *models.Job
+	// Status changes follow the same transition rules as the lifecycle
+	// endpoints. Without a device location the geofence checks of a start
+	// or completion are unverified.
+	if req.JobStatus != "" && req.JobStatus != existing.JobStatus {
+		if req.JobStatus == models.JobStatusCompleted {
+			if err := s.checkChecklist(existing); err != nil {
+				return nil, err
+			}
+			if err := s.checkCompletion(ctx, existing, &updatedJob, nil, updatedBy); err != nil {
+				return nil, err
+			}
+		}
+		if req.JobStatus == models.JobStatusInProgress && existing.JobStartedAt == nil {
+			if err := s.checkBlockers(ctx, existing); err != nil {
+				return nil, err
+			}
+			if err := s.checkStart(ctx, existing, &updatedJob, nil, updatedBy); err != nil {
+				return nil, err
+			}
+		}
+		if err := changeJobStatus(&updatedJob, req.JobStatus, updatedBy, req.StatusReason); err != nil {
+			return nil, err
+		}
+		trackSLA(&updatedJob, time.Now().UTC(), s.slaAtRiskPercent)
+		job, err = s.jobRepo.UpdateJobStatus(ctx, id, &updatedJob, existing.JobStatus)
+	} else {
+		job, err = s.jobRepo.UpdateJob(ctx, id, &updatedJob)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, activity := range jobUpdateActivities(existing, job, updatedBy) {
+		recordActivity(ctx, s.activityRepo, s.logger, activity)
+	}
+
+	job.AssignmentWarnings = warnings
+	return job, nil
+}
+
+// resetSLA recalculates the SLA due dates of job, whose priority or job type
+// changed, from when its clocks started. Response and resolution are kept.
+// Closed jobs keep the SLA they were closed with.
+func (s *JobService) resetSLA(ctx context.Context, job *models.Job) error {
+	if job.JobStatus == models.JobStatusCompleted || job.JobStatus == models.JobStatusCancelled {
+		return nil
+	}
+
+	start := job.CreatedAt
+	if job.SLA != nil {
+		start = job.SLA.StartedAt
+	}
+	sla, err := newJobSLA(ctx, s.slaPolicyRepo, job.OrgID, job.JobType, job.Priority.OrDefault(), start)
+	if err != nil || sla == nil {
+		job.SLA = nil
+		return err
+	}
 
-	return s.jobRepo.UpdateJob(id, &updatedJob)
+	if job.SLA != nil {
+		sla.RespondedAt = job.SLA.RespondedAt
+		sla.ResolvedAt = job.SLA.ResolvedAt
+	}
+	job.SLA = evaluateSLA(sla, time.Now().UTC(), s.slaAtRiskPercent)
+	return nil
 }
 
 func (s *JobService) validateUpdateJob(req *models.UpdateJobRequest) error {
@@ -153,92 +461,299 @@ func (s *JobService) validateUpdateJob(req *models.UpdateJobRequest) error {
 		return errors.New("notes must be less than 1000 characters")
 	}
 
+	if req.ClearProject && req.ProjectID != "" {
+		return errors.New("projectID cannot be set and cleared in the same request")
+	}
+
+	if req.ClearQBInfoOnJob && req.QBInfoOnJob != nil {
+		return errors.New("qbInfoOnJob cannot be set and cleared in the same request")
+	}
+
+	if req.ClearSchedule && !req.JobScheduleInput.IsEmpty() {
+		return errors.New("schedule cannot be set and cleared in the same request")
+	}
+
+	if req.ClearSite && req.Site != nil {
+		return errors.New("site cannot be set and cleared in the same request")
+	}
+
 	return nil
 }
 
-func (s *JobService) DeleteJob(id string) error {
-	return s.jobRepo.DeleteJob(id)
+func (s *JobService) DeleteJob(ctx context.Context, id string, deletedBy string, reason string) error {
+	if strings.TrimSpace(id) == "" {
+		return errors.New("job ID is required")
+	}
+
+	return s.jobRepo.DeleteJob(ctx, id, newDeletedData(deletedBy, reason))
 }
 
-func (s *JobService) StartJob(ctx context.Context, id string, startedBy string) (*models.Job, error) {
-	existing, err := s.GetJobByID(id)
-	if err != nil {
-		return nil, err
+func (s *JobService) RestoreJob(ctx context.Context, id string) (*models.Job, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("job ID is required")
 	}
 
-	if existing.JobStatus != models.JobStatusPending && existing.JobStatus != models.JobStatusActive {
-		return nil, errors.New("job cannot be started from current status")
+	return s.jobRepo.RestoreJob(ctx, id)
+}
+
+// StartJob starts a job, checking location, which may be nil, against the
+// geofence of the job site the first time the job starts. Jobs cannot start
+// before the jobs they are blocked by are completed.
+func (s *JobService) StartJob(ctx context.Context, id string, startedBy string, location *models.DeviceLocation) (*models.Job, error) {
+	existing, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
 	updatedJob := *existing
-	updatedJob.JobStatus = models.JobStatusInProgress
-	updatedJob.JobStartedAt = &now
-	updatedJob.StartedData = &models.StartedData{
-		UID:        startedBy,
-		UserStatus: "active",
-		StartedAt:  now,
+	if existing.JobStartedAt == nil {
+		if err := s.checkBlockers(ctx, existing); err != nil {
+			return nil, err
+		}
+		if err := s.checkStart(ctx, existing, &updatedJob, location, startedBy); err != nil {
+			return nil, err
+		}
 	}
 
-	return s.jobRepo.UpdateJob(id, &updatedJob)
+	job, err := s.applyTransition(ctx, existing, &updatedJob, models.JobStatusInProgress, startedBy, "")
+	if err != nil {
+		return nil, err
+	}
+	if existing.JobStartedAt == nil {
+		if activity := geofenceActivity(job, "Started", job.StartCheck); activity != nil {
+			recordActivity(ctx, s.activityRepo, s.logger, activity)
+		}
+	}
+	return job, nil
 }
 
-func (s *JobService) CompleteJob(ctx context.Context, id string, completedBy string) (*models.Job, error) {
-	existing, err := s.GetJobByID(id)
+// CompleteJob completes a job, checking the device location of req against
+// the geofence of the job site. While the service requires checklists it
+// refuses jobs with open required checklist items. With a sign-off in req
+// the signature and a completion summary are stored with the job, and the
+// summary is emailed to the signer when they give an email.
+func (s *JobService) CompleteJob(ctx context.Context, id string, completedBy string, req *models.CompleteJobRequest) (*models.Job, error) {
+	if req == nil {
+		req = &models.CompleteJobRequest{}
+	}
+
+	var sig *signature
+	if req.SignOff != nil {
+		var err error
+		if sig, err = s.decodeSignature(req.SignOff.Signature); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.GetJobByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if existing.JobStatus != models.JobStatusInProgress {
-		return nil, errors.New("job must be in progress to be completed")
+	if err := s.checkChecklist(existing); err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
 	updatedJob := *existing
-	updatedJob.JobStatus = models.JobStatusCompleted
-	updatedJob.JobEndedAt = &now
-	updatedJob.UpdatedBy = completedBy
+	if err := s.checkCompletion(ctx, existing, &updatedJob, &req.DeviceLocation, completedBy); err != nil {
+		return nil, err
+	}
+
+	var signOffFiles []*models.Attachment
+	if req.SignOff != nil {
+		// Refuse before storing anything for a job that cannot complete
+		if !existing.JobStatus.CanTransitionTo(models.JobStatusCompleted) {
+			return nil, fmt.Errorf("%w: job cannot move from %s to %s", ErrInvalidJobTransition, existing.JobStatus, models.JobStatusCompleted)
+		}
+		if signOffFiles, err = s.recordSignOff(ctx, &updatedJob, req.SignOff, sig, completedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := s.applyTransition(ctx, existing, &updatedJob, models.JobStatusCompleted, completedBy, "")
+	if err != nil {
+		s.discardSignOffFiles(ctx, signOffFiles, completedBy)
+		return nil, err
+	}
+	if activity := geofenceActivity(job, "Completed", job.CompletionCheck); activity != nil {
+		recordActivity(ctx, s.activityRepo, s.logger, activity)
+	}
+
+	if req.SignOff != nil && job.SignOff != nil {
+		recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+			JobID:        job.JobID,
+			OrgID:        job.OrgID,
+			Type:         models.ActivitySignOffRecorded,
+			ActorID:      completedBy,
+			Summary:      fmt.Sprintf("%s signed off the job", job.SignOff.SignerName),
+			AttachmentID: job.SignOff.SignatureAttachmentID,
+		})
+
+		// The sign-off is stored either way; the summary can be sent again
+		if job.SignOff.SignerEmail != "" {
+			if err := s.sendCompletionSummary(ctx, job, job.SignOff.SignerEmail, completedBy); err != nil {
+				s.logger.Warnf("Failed to email the completion summary of job %s: %v", job.JobID, err)
+			}
+		}
+	}
+	return job, nil
+}
+
+// checkChecklist returns ErrChecklistIncomplete when the service requires
+// checklists and required items of job are open
+func (s *JobService) checkChecklist(job *models.Job) error {
+	if !s.requireChecklist {
+		return nil
+	}
+
+	open := job.OpenRequiredChecklistItems()
+	if len(open) == 0 {
+		return nil
+	}
 
-	return s.jobRepo.UpdateJob(id, &updatedJob)
+	labels := make([]string, 0, len(open))
+	for _, item := range open {
+		labels = append(labels, item.Label)
+	}
+	return fmt.Errorf("%w: %d required items are open: %s", ErrChecklistIncomplete, len(open), strings.Join(labels, ", "))
 }
 
 func (s *JobService) CancelJob(ctx context.Context, id string, cancelledBy string, reason string) (*models.Job, error) {
-	existing, err := s.GetJobByID(id)
+	return s.transitionJob(ctx, id, models.JobStatusCancelled, cancelledBy, reason)
+}
+
+// HoldJob puts a job on hold until it is resumed
+func (s *JobService) HoldJob(ctx context.Context, id string, heldBy string, reason string) (*models.Job, error) {
+	return s.transitionJob(ctx, id, models.JobStatusOnHold, heldBy, reason)
+}
+
+// ResumeJob returns a job on hold to the status it was held from
+func (s *JobService) ResumeJob(ctx context.Context, id string, resumedBy string, reason string) (*models.Job, error) {
+	existing, err := s.GetJobByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if existing.JobStatus == models.JobStatusCompleted {
-		return nil, errors.New("completed job cannot be cancelled")
+	if existing.JobStatus != models.JobStatusOnHold {
+		return nil, fmt.Errorf("%w: job is %s, not on hold", ErrInvalidJobTransition, existing.JobStatus)
 	}
 
-	now := time.Now()
 	updatedJob := *existing
-	updatedJob.JobStatus = models.JobStatusCancelled
-	updatedJob.DeletedData = &models.DeletedData{
-		UID:        cancelledBy,
-		UserStatus: "active",
-		DeletedAt:  now,
-		Reason:     reason,
+	return s.applyTransition(ctx, existing, &updatedJob, heldFromStatus(existing), resumedBy, reason)
+}
+
+// transitionJob moves the job id to status to
+func (s *JobService) transitionJob(ctx context.Context, id string, to models.JobStatus, actor string, reason string) (*models.Job, error) {
+	existing, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	updatedJob := *existing
+	return s.applyTransition(ctx, existing, &updatedJob, to, actor, reason)
+}
+
+// applyTransition moves updatedJob, a copy of existing, to status to and
+// stores it, provided no one changed its status in the meantime
+func (s *JobService) applyTransition(ctx context.Context, existing *models.Job, updatedJob *models.Job, to models.JobStatus, actor string, reason string) (*models.Job, error) {
+	if err := changeJobStatus(updatedJob, to, actor, reason); err != nil {
+		return nil, err
+	}
+	trackSLA(updatedJob, time.Now().UTC(), s.slaAtRiskPercent)
+
+	job, err := s.jobRepo.UpdateJobStatus(ctx, existing.JobID, updatedJob, existing.JobStatus)
+	if err != nil {
+		return nil, err
 	}
-	updatedJob.UpdatedBy = cancelledBy
 
-	return s.jobRepo.UpdateJob(id, &updatedJob)
+	recordActivity(ctx, s.activityRepo, s.logger, statusActivity(job, existing.JobStatus, actor))
+
+	s.logger.Infof("Job %s moved from %s to %s by %s", existing.JobID, existing.JobStatus, to, actor)
+	return job, nil
+}
+
+// changeJobStatus moves job to status to, recording the change in its
+// status history together with the data of the new status
+func changeJobStatus(job *models.Job, to models.JobStatus, actor string, reason string) error {
+	from := job.JobStatus
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: job cannot move from %s to %s", ErrInvalidJobTransition, from, to)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if to.RequiresReason() && reason == "" {
+		return fmt.Errorf("%w to move a job to %s", ErrStatusReasonRequired, to)
+	}
+	if len(reason) > 500 {
+		return errors.New("reason must be less than 500 characters")
+	}
+
+	now := time.Now().UTC()
+	switch to {
+	case models.JobStatusInProgress:
+		// A job resumed from hold keeps its original start
+		if job.JobStartedAt == nil {
+			job.JobStartedAt = &now
+			job.StartedData = &models.StartedData{
+				UID:        actor,
+				UserStatus: "active",
+				StartedAt:  now,
+			}
+		}
+	case models.JobStatusCompleted:
+		job.JobEndedAt = &now
+	case models.JobStatusCancelled:
+		job.CancelledData = &models.CancelledData{
+			UID:         actor,
+			UserStatus:  "active",
+			CancelledAt: now,
+			Reason:      reason,
+		}
+	}
+
+	job.JobStatus = to
+	job.UpdatedBy = actor
+
+	history := make([]models.StatusChange, len(job.StatusHistory), len(job.StatusHistory)+1)
+	copy(history, job.StatusHistory)
+	job.StatusHistory = append(history, models.StatusChange{
+		From:      from,
+		To:        to,
+		UID:       actor,
+		ChangedAt: now,
+		Reason:    reason,
+	})
+	return nil
+}
+
+// heldFromStatus returns the status a job on hold was held from. Jobs held
+// before status history was recorded resume as pending.
+func heldFromStatus(job *models.Job) models.JobStatus {
+	for i := len(job.StatusHistory) - 1; i >= 0; i-- {
+		change := job.StatusHistory[i]
+		if change.To == models.JobStatusOnHold && change.From != "" && change.From != models.JobStatusOnHold {
+			return change.From
+		}
+	}
+	return models.JobStatusPending
 }
 
-func (s *JobService) GetJobsByOrganization(orgID string, status models.JobStatus) ([]*models.Job, error) {
+func (s *JobService) GetJobsByOrganization(ctx context.Context, orgID string, status models.JobStatus) ([]*models.Job, error) {
 	filter := &models.JobFilter{
 		OrgID: orgID,
 	}
 	if status != "" {
 		filter.JobStatus = status
 	}
-	return s.jobRepo.GetJobsByFilter(filter)
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.jobRepo.GetJobsByFilter(ctx, filter)
 }
 
-func (s *JobService) GetJobsByClient(clientID string) ([]*models.Job, error) {
+func (s *JobService) GetJobsByClient(ctx context.Context, clientID string) ([]*models.Job, error) {
 	filter := &models.JobFilter{
 		ClientID: clientID,
 	}
-	return s.jobRepo.GetJobsByFilter(filter)
-}
\ No newline at end of file
+	return s.jobRepo.GetJobsByFilter(ctx, filter)
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils"
+	"fieldfuze-backend/utils/notify"
+	"fieldfuze-backend/utils/pdf"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignOff is returned for sign-offs whose signature cannot be read
+// and for summaries that cannot be sent for lack of an email
+var ErrInvalidSignOff = errors.New("invalid sign-off")
+
+// ErrNoSignOff is returned when the completion summary of a job that was
+// completed without a sign-off is requested
+var ErrNoSignOff = errors.New("job has no sign-off")
+
+// signatureMaxSide is the largest width or height of a PNG signature in
+// pixels, which keeps decoding cheap
+const signatureMaxSide = 4000
+
+// summaryTimeLayout formats times in completion summaries
+const summaryTimeLayout = "2006-01-02 15:04 MST"
+
+// forbiddenSVGElements can run script or pull in content from elsewhere
+var forbiddenSVGElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+	"animate":       true,
+	"set":           true,
+	"handler":       true,
+	"listener":      true,
+}
+
+// signature is a decoded sign-off signature. PNG signatures also carry the
+// image, which is drawn into the completion summary.
+type signature struct {
+	data        []byte
+	contentType string
+	ext         string
+	image       image.Image
+}
+
+// SendCompletionSummary emails the completion summary of job id to email,
+// or to the signer when email is empty
+func (s *JobService) SendCompletionSummary(ctx context.Context, id string, email string, sentBy string) error {
+	job, err := s.GetJobByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.SignOff == nil {
+		return ErrNoSignOff
+	}
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		email = job.SignOff.SignerEmail
+	}
+	if email == "" {
+		return fmt.Errorf("%w: email is required as the signer gave none", ErrInvalidSignOff)
+	}
+	return s.sendCompletionSummary(ctx, job, email, sentBy)
+}
+
+// decodeSignature reads the signature of a sign-off, given base64 encoded
+// or as a data URL. The content type is sniffed from the content: PNG
+// images must decode, SVG documents must pass checkSVG.
+func (s *JobService) decodeSignature(value string) (*signature, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "data:") {
+		header, payload, found := strings.Cut(value, ",")
+		if !found || !strings.HasSuffix(header, ";base64") {
+			return nil, fmt.Errorf("%w: signature data URL must be base64 encoded", ErrInvalidSignOff)
+		}
+		value = payload
+	}
+	value = strings.Join(strings.Fields(value), "")
+
+	if int64(base64.StdEncoding.DecodedLen(len(value))) > s.attachmentMaxSize {
+		return nil, fmt.Errorf("%w: signatures may be at most %d MB", ErrAttachmentTooLarge, s.attachmentMaxSize>>20)
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature is not valid base64", ErrInvalidSignOff)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: signature is empty", ErrInvalidSignOff)
+	}
+
+	if http.DetectContentType(data) == "image/png" {
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: signature is not a valid PNG image", ErrInvalidSignOff)
+		}
+		if config.Width > signatureMaxSide || config.Height > signatureMaxSide {
+			return nil, fmt.Errorf("%w: signatures may be at most %d pixels wide and high", ErrInvalidSignOff, signatureMaxSide)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: signature is not a valid PNG image", ErrInvalidSignOff)
+		}
+		return &signature{data: data, contentType: "image/png", ext: ".png", image: img}, nil
+	}
+
+	if err := checkSVG(data); err != nil {
+		return nil, err
+	}
+	return &signature{data: data, contentType: "image/svg+xml", ext: ".svg"}, nil
+}
+
+// checkSVG accepts SVG documents that cannot run script or load anything
+// when opened: no DTD, no scripting or embedding elements, no event
+// handler attributes and no links outside the document. Anything that is
+// not XML with an svg root is not an accepted signature.
+func checkSVG(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+
+	root := true
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: signature must be a PNG or SVG image", ErrUnsupportedAttachment)
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			return fmt.Errorf("%w: SVG signatures may not declare a DTD", ErrInvalidSignOff)
+		case xml.ProcInst:
+			if t.Target != "xml" {
+				return fmt.Errorf("%w: SVG signatures may not contain processing instructions", ErrInvalidSignOff)
+			}
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if root && name != "svg" {
+				return fmt.Errorf("%w: signature must be a PNG or SVG image", ErrUnsupportedAttachment)
+			}
+			root = false
+
+			if forbiddenSVGElements[name] {
+				return fmt.Errorf("%w: SVG signatures may not contain %s elements", ErrInvalidSignOff, t.Name.Local)
+			}
+			for _, attr := range t.Attr {
+				attrName := strings.ToLower(attr.Name.Local)
+				if strings.HasPrefix(attrName, "on") {
+					return fmt.Errorf("%w: SVG signatures may not contain event handlers", ErrInvalidSignOff)
+				}
+				if attrName == "href" && !strings.HasPrefix(strings.TrimSpace(attr.Value), "#") {
+					return fmt.Errorf("%w: SVG signatures may only link within the document", ErrInvalidSignOff)
+				}
+			}
+		}
+	}
+
+	if root {
+		return fmt.Errorf("%w: signature must be a PNG or SVG image", ErrUnsupportedAttachment)
+	}
+	return nil
+}
+
+// recordSignOff stores the signature and the completion summary of job as
+// sign-off attachments and sets its sign-off. It returns the attachments
+// so they can be removed should the completion fail.
+func (s *JobService) recordSignOff(ctx context.Context, job *models.Job, in *models.SignOffInput, sig *signature, collectedBy string) ([]*models.Attachment, error) {
+	digest := sha256.Sum256(sig.data)
+	signOff := &models.CompletionSignOff{
+		SignerName:           strings.TrimSpace(in.SignerName),
+		SignerEmail:          strings.TrimSpace(in.SignerEmail),
+		SignedAt:             time.Now().UTC(),
+		SignatureContentType: sig.contentType,
+		SignatureSHA256:      hex.EncodeToString(digest[:]),
+		Rating:               in.Rating,
+		Feedback:             strings.TrimSpace(in.Feedback),
+		CollectedBy:          collectedBy,
+	}
+
+	signatureFile, err := s.storeSignOffFile(ctx, job, "signature"+sig.ext, sig.contentType, sig.data, collectedBy)
+	if err != nil {
+		return nil, err
+	}
+	signOff.SignatureAttachmentID = signatureFile.AttachmentID
+
+	summary, err := s.completionSummary(ctx, job, signOff, sig)
+	if err != nil {
+		s.discardSignOffFiles(ctx, []*models.Attachment{signatureFile}, collectedBy)
+		return nil, err
+	}
+	summaryFile, err := s.storeSignOffFile(ctx, job, "completion-summary.pdf", pdf.ContentType, summary, collectedBy)
+	if err != nil {
+		s.discardSignOffFiles(ctx, []*models.Attachment{signatureFile}, collectedBy)
+		return nil, err
+	}
+	signOff.SummaryAttachmentID = summaryFile.AttachmentID
+
+	job.SignOff = signOff
+	return []*models.Attachment{signatureFile, summaryFile}, nil
+}
+
+// storeSignOffFile stores data in the blob store as a sign-off attachment
+// of job
+func (s *JobService) storeSignOffFile(ctx context.Context, job *models.Job, fileName string, contentType string, data []byte, uploadedBy string) (*models.Attachment, error) {
+	attachmentID := utils.GenerateUUID()
+	ext := fileName[strings.LastIndex(fileName, "."):]
+	attachment := &models.Attachment{
+		AttachmentID: attachmentID,
+		JobID:        job.JobID,
+		OrgID:        job.OrgID,
+		Category:     models.AttachmentCategorySignOff,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		BlobKey:      fmt.Sprintf("orgs/%s/jobs/%s/%s%s", job.OrgID, job.JobID, attachmentID, ext),
+		UploadedBy:   uploadedBy,
+	}
+
+	if err := s.store.Put(ctx, attachment.BlobKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	created, err := s.attachmentRepo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		if err := s.store.Delete(ctx, attachment.BlobKey); err != nil {
+			s.logger.Warnf("Failed to delete blob %s of attachment %s: %v", attachment.BlobKey, attachmentID, err)
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// discardSignOffFiles removes the sign-off attachments of a completion
+// that failed, logging failures
+func (s *JobService) discardSignOffFiles(ctx context.Context, attachments []*models.Attachment, actor string) {
+	for _, attachment := range attachments {
+		if err := s.attachmentRepo.DeleteAttachment(ctx, attachment.AttachmentID, newDeletedData(actor, "Job completion failed")); err != nil {
+			s.logger.Warnf("Failed to delete sign-off attachment %s: %v", attachment.AttachmentID, err)
+		}
+		if err := s.store.Delete(ctx, attachment.BlobKey); err != nil {
+			s.logger.Warnf("Failed to delete blob %s of attachment %s: %v", attachment.BlobKey, attachment.AttachmentID, err)
+		}
+	}
+}
+
+// completionSummary renders the completion summary of job as a PDF, with
+// times in the timezone of its organization
+func (s *JobService) completionSummary(ctx context.Context, job *models.Job, signOff *models.CompletionSignOff, sig *signature) ([]byte, error) {
+	org, err := s.orgRepo.GetOrganizationByID(ctx, job.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	loc := org.Location()
+
+	doc := pdf.New()
+	doc.Heading("Job completion summary")
+	doc.Field("Organization", org.Name)
+	doc.Field("Job", job.JobsName)
+	doc.Field("Job ID", job.JobID)
+	doc.Field("Type", string(job.JobType))
+	doc.Field("Client", job.ClientID)
+	if job.Site != nil && job.Site.Address != "" {
+		doc.Field("Site", job.Site.Address)
+	}
+	if job.JobStartedAt != nil {
+		doc.Field("Started", job.JobStartedAt.In(loc).Format(summaryTimeLayout))
+	}
+	doc.Field("Completed", signOff.SignedAt.In(loc).Format(summaryTimeLayout))
+	doc.Field("Technician", s.userName(ctx, signOff.CollectedBy))
+	if job.Notes != "" {
+		doc.Field("Notes", job.Notes)
+	}
+
+	if len(job.Checklist) > 0 {
+		doc.Subheading("Checklist")
+		for _, item := range job.Checklist {
+			status := "Open"
+			if item.Completed {
+				status = "Done"
+			}
+			line := item.Label
+			if item.Value != "" {
+				line += ": " + item.Value
+			}
+			doc.Field(status, line)
+		}
+	}
+
+	doc.Subheading("Customer sign-off")
+	doc.Field("Signed by", signOff.SignerName)
+	if signOff.SignerEmail != "" {
+		doc.Field("Email", signOff.SignerEmail)
+	}
+	doc.Field("Signed at", signOff.SignedAt.In(loc).Format(summaryTimeLayout))
+	if signOff.Rating > 0 {
+		doc.Field("Satisfaction", fmt.Sprintf("%d of 5", signOff.Rating))
+	}
+	if signOff.Feedback != "" {
+		doc.Field("Feedback", signOff.Feedback)
+	}
+	doc.Field("Signature SHA-256", signOff.SignatureSHA256)
+	doc.Space()
+	if sig.image != nil {
+		if err := doc.Image(sig.image, 240, 100); err != nil {
+			return nil, fmt.Errorf("failed to add signature to completion summary: %w", err)
+		}
+	} else {
+		doc.Paragraph("The SVG signature is kept with the job under the digest above.")
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write completion summary: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// userName returns the full name of user userID, or the ID when it cannot
+// be read
+func (s *JobService) userName(ctx context.Context, userID string) string {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		return userID
+	}
+	return name
+}
+
+// sendCompletionSummary emails the completion summary of job to email. The
+// PDF is attached itself rather than linked, since signed links expire
+// long before customers look for the summary again.
+func (s *JobService) sendCompletionSummary(ctx context.Context, job *models.Job, email string, sentBy string) error {
+	if s.notifier == nil {
+		return errors.New("notifications are not configured")
+	}
+	signOff := job.SignOff
+
+	summary, err := s.attachmentRepo.GetAttachment(ctx, signOff.SummaryAttachmentID)
+	if err != nil {
+		return err
+	}
+	content, err := s.readBlob(ctx, summary.BlobKey)
+	if err != nil {
+		return fmt.Errorf("failed to read completion summary: %w", err)
+	}
+
+	recipient := notify.Recipient{Email: email}
+	if strings.EqualFold(email, signOff.SignerEmail) {
+		recipient.Name = signOff.SignerName
+	}
+
+	now := time.Now().UTC()
+	notification := &notify.Notification{
+		Event:      string(models.ActivitySummarySent),
+		Subject:    fmt.Sprintf("Completion summary: %s", job.JobsName),
+		Body:       fmt.Sprintf("The %s job %q was completed and signed off by %s. The completion summary is attached.", job.JobType, job.JobsName, signOff.SignerName),
+		Recipients: []notify.Recipient{recipient},
+		Data: map[string]string{
+			"jobID":      job.JobID,
+			"orgID":      job.OrgID,
+			"jobsName":   job.JobsName,
+			"signerName": signOff.SignerName,
+			"signedAt":   signOff.SignedAt.Format(time.RFC3339),
+		},
+		Attachments: []notify.Attachment{{
+			FileName:    summary.FileName,
+			ContentType: summary.ContentType,
+			Content:     content,
+		}},
+		SentAt: now,
+	}
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		return fmt.Errorf("failed to send completion summary: %w", err)
+	}
+
+	recordActivity(ctx, s.activityRepo, s.logger, &models.Activity{
+		JobID:        job.JobID,
+		OrgID:        job.OrgID,
+		Type:         models.ActivitySummarySent,
+		ActorID:      sentBy,
+		Summary:      fmt.Sprintf("Sent the completion summary to %s", email),
+		AttachmentID: summary.AttachmentID,
+	})
+	return nil
+}
+
+// readBlob returns the blob stored under key
+func (s *JobService) readBlob(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
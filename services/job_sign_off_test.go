@@ -0,0 +1,75 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/notify"
+	"testing"
+)
+
+// fakeAttachmentRepo answers GetAttachment from attachments
+type fakeAttachmentRepo struct {
+	repository.AttachmentRepositoryInterface
+	attachments map[string]*models.Attachment
+}
+
+func (r *fakeAttachmentRepo) GetAttachment(ctx context.Context, id string) (*models.Attachment, error) {
+	attachment, ok := r.attachments[id]
+	if !ok {
+		return nil, repository.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// recordingNotifier keeps the notifications sent through it
+type recordingNotifier struct {
+	sent []*notify.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *notify.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+// TestSendCompletionSummaryAttachesPDF sends the summary itself rather than
+// a link that expires
+func TestSendCompletionSummaryAttachesPDF(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir(), "http://localhost/blobs/", []byte("secret"))
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	pdf := []byte("%PDF-1.4 summary")
+	if err := store.Put(ctx, "summary.pdf", bytes.NewReader(pdf), int64(len(pdf)), "application/pdf"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	notifier := &recordingNotifier{}
+	s := &JobService{
+		attachmentRepo: &fakeAttachmentRepo{attachments: map[string]*models.Attachment{
+			"summary-1": {AttachmentID: "summary-1", FileName: "completion-summary.pdf", ContentType: "application/pdf", BlobKey: "summary.pdf"},
+		}},
+		activityRepo: &fakeActivityRepo{},
+		store:        store,
+		notifier:     notifier,
+		logger:       testLogger(),
+	}
+	job := &models.Job{JobID: "job-1", OrgID: "org-1", JobsName: "Boiler service", SignOff: &models.CompletionSignOff{
+		SignerName:          "Dana Customer",
+		SummaryAttachmentID: "summary-1",
+	}}
+
+	if err := s.sendCompletionSummary(ctx, job, "dana@example.com", "tech-1"); err != nil {
+		t.Fatalf("sendCompletionSummary() error = %v", err)
+	}
+	if len(notifier.sent) != 1 || len(notifier.sent[0].Attachments) != 1 {
+		t.Fatalf("sent %+v, want one notification with one attachment", notifier.sent)
+	}
+	attachment := notifier.sent[0].Attachments[0]
+	if !bytes.Equal(attachment.Content, pdf) || attachment.URL != "" {
+		t.Fatalf("attachment = %q, URL %q, want the PDF itself", attachment.Content, attachment.URL)
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"fieldfuze-backend/models"
+	"testing"
+	"time"
+)
+
+func TestChangeJobStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    models.JobStatus
+		to      models.JobStatus
+		reason  string
+		wantErr error
+	}{
+		{name: "start", from: models.JobStatusPending, to: models.JobStatusInProgress},
+		{name: "complete", from: models.JobStatusInProgress, to: models.JobStatusCompleted},
+		{name: "hold with reason", from: models.JobStatusInProgress, to: models.JobStatusOnHold, reason: "waiting for parts"},
+		{name: "hold without reason", from: models.JobStatusInProgress, to: models.JobStatusOnHold, reason: "  ", wantErr: ErrStatusReasonRequired},
+		{name: "cancel without reason", from: models.JobStatusPending, to: models.JobStatusCancelled, wantErr: ErrStatusReasonRequired},
+		{name: "reopen completed", from: models.JobStatusCompleted, to: models.JobStatusInProgress, wantErr: ErrInvalidJobTransition},
+		{name: "complete pending", from: models.JobStatusPending, to: models.JobStatusCompleted, wantErr: ErrInvalidJobTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.Job{JobStatus: tt.from}
+			err := changeJobStatus(job, tt.to, "user-1", tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("changeJobStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if job.JobStatus != tt.from || len(job.StatusHistory) != 0 {
+					t.Fatalf("rejected transition changed the job to %s with %d history entries", job.JobStatus, len(job.StatusHistory))
+				}
+				return
+			}
+
+			if job.JobStatus != tt.to {
+				t.Fatalf("JobStatus = %s, want %s", job.JobStatus, tt.to)
+			}
+			if len(job.StatusHistory) != 1 {
+				t.Fatalf("StatusHistory has %d entries, want 1", len(job.StatusHistory))
+			}
+			change := job.StatusHistory[0]
+			if change.From != tt.from || change.To != tt.to || change.UID != "user-1" {
+				t.Fatalf("StatusHistory[0] = %+v, want %s to %s by user-1", change, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+// TestChangeJobStatusResume resumes a job from hold, which keeps its
+// original start and extends rather than shares the history it was read with
+func TestChangeJobStatusResume(t *testing.T) {
+	started := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	history := make([]models.StatusChange, 1, 2)
+	history[0] = models.StatusChange{From: models.JobStatusInProgress, To: models.JobStatusOnHold}
+	job := &models.Job{JobStatus: models.JobStatusOnHold, JobStartedAt: &started, StatusHistory: history}
+
+	if err := changeJobStatus(job, models.JobStatusInProgress, "user-1", ""); err != nil {
+		t.Fatalf("changeJobStatus() error = %v", err)
+	}
+	if !job.JobStartedAt.Equal(started) {
+		t.Fatalf("JobStartedAt = %v, want %v", job.JobStartedAt, started)
+	}
+	if len(job.StatusHistory) != 2 {
+		t.Fatalf("StatusHistory has %d entries, want 2", len(job.StatusHistory))
+	}
+	if len(history[:2]) == 2 && history[:2][1].To == models.JobStatusInProgress {
+		t.Fatalf("changeJobStatus() appended to the history of the job it was read from")
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"strings"
+)
+
+type JobTemplateService struct {
+	jobTemplateRepo repository.JobTemplateRepositoryInterface
+	logger          logger.Logger
+}
+
+func NewJobTemplateService(jobTemplateRepo repository.JobTemplateRepositoryInterface, logger logger.Logger) *JobTemplateService {
+	return &JobTemplateService{
+		jobTemplateRepo: jobTemplateRepo,
+		logger:          logger,
+	}
+}
+
+func (s *JobTemplateService) CreateJobTemplate(ctx context.Context, req *models.CreateJobTemplateRequest, createdBy string) (*models.JobTemplate, error) {
+	if req == nil {
+		return nil, errors.New("job template request is required")
+	}
+
+	if strings.TrimSpace(req.OrgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("job template name is required")
+	}
+
+	if strings.TrimSpace(req.JobsName) == "" {
+		return nil, errors.New("job name is required")
+	}
+
+	if req.JobType == "" {
+		return nil, errors.New("job type is required")
+	}
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.JobTemplate{
+		OrgID:                 req.OrgID,
+		Name:                  strings.TrimSpace(req.Name),
+		JobsName:              strings.TrimSpace(req.JobsName),
+		JobType:               req.JobType,
+		Notes:                 req.Notes,
+		Priority:              req.Priority,
+		EstimatedDuration:     req.EstimatedDuration,
+		VehiclesAssignedToJob: req.VehiclesAssignedToJob,
+		QBInfoOnJob:           req.QBInfoOnJob,
+		Checklist:             newChecklistTemplateItems(req.Checklist),
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	}
+	if template.VehiclesAssignedToJob == nil {
+		template.VehiclesAssignedToJob = []string{}
+	}
+	return s.jobTemplateRepo.CreateJobTemplate(ctx, template)
+}
+
+func (s *JobTemplateService) GetJobTemplate(ctx context.Context, id string) (*models.JobTemplate, error) {
+	return s.jobTemplateRepo.GetJobTemplate(ctx, id)
+}
+
+func (s *JobTemplateService) GetJobTemplates(ctx context.Context, orgID string) ([]*models.JobTemplate, error) {
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.jobTemplateRepo.GetJobTemplatesByOrganization(ctx, orgID)
+}
+
+// UpdateJobTemplate changes a job template. Only jobs created afterwards
+// get the new fields.
+func (s *JobTemplateService) UpdateJobTemplate(ctx context.Context, id string, req *models.UpdateJobTemplateRequest, updatedBy string) (*models.JobTemplate, error) {
+	if req == nil {
+		return nil, errors.New("job template request is required")
+	}
+
+	if req.ClearQBInfoOnJob && req.QBInfoOnJob != nil {
+		return nil, errors.New("qbInfoOnJob cannot be set and cleared in the same request")
+	}
+
+	existing, err := s.jobTemplateRepo.GetJobTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = strings.TrimSpace(req.Name)
+	}
+	if req.JobsName != "" {
+		updated.JobsName = strings.TrimSpace(req.JobsName)
+	}
+	if req.JobType != "" {
+		updated.JobType = req.JobType
+	}
+	if req.Notes != "" {
+		updated.Notes = req.Notes
+	}
+	if req.Priority != "" {
+		updated.Priority = req.Priority
+	}
+	if req.EstimatedDuration != 0 {
+		updated.EstimatedDuration = req.EstimatedDuration
+	}
+	if req.VehiclesAssignedToJob != nil {
+		updated.VehiclesAssignedToJob = req.VehiclesAssignedToJob
+	}
+	if req.QBInfoOnJob != nil {
+		updated.QBInfoOnJob = req.QBInfoOnJob
+	}
+	if req.ClearQBInfoOnJob {
+		updated.QBInfoOnJob = nil
+	}
+	if req.Checklist != nil {
+		updated.Checklist = newChecklistTemplateItems(req.Checklist)
+	}
+	updated.UpdatedBy = updatedBy
+
+	return s.jobTemplateRepo.UpdateJobTemplate(ctx, id, &updated)
+}
+
+func (s *JobTemplateService) DeleteJobTemplate(ctx context.Context, id string, deletedBy string, reason string) error {
+	return s.jobTemplateRepo.DeleteJobTemplate(ctx, id, newDeletedData(deletedBy, reason))
+}
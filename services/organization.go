@@ -6,8 +6,10 @@ import (
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/repository"
 	"fieldfuze-backend/utils/logger"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 type OrganizationService struct {
@@ -95,6 +97,20 @@ func (s *OrganizationService) validateCreateOrganization(organization *models.Or
 		}
 	}
 
+	if strings.TrimSpace(organization.Timezone) != "" {
+		if _, err := time.LoadLocation(organization.Timezone); err != nil {
+			return errors.New("timezone must be an IANA timezone name such as America/New_York")
+		}
+	}
+
+	if organization.GeofencePolicy != "" && organization.GeofencePolicy != models.GeofencePolicyFlag && organization.GeofencePolicy != models.GeofencePolicyOverride {
+		return fmt.Errorf("geofence policy must be %q or %q", models.GeofencePolicyFlag, models.GeofencePolicyOverride)
+	}
+
+	if organization.GeofenceRadiusM != 0 && (organization.GeofenceRadiusM < 10 || organization.GeofenceRadiusM > 10000) {
+		return errors.New("geofence radius must be between 10 and 10000 meters")
+	}
+
 	return nil
 }
 
@@ -105,43 +121,40 @@ func isValidEmail(email string) bool {
 	return re.MatchString(email)
 }
 
-func (s *OrganizationService) GetOrganizations(key string) ([]*models.Organization, error) {
-	return s.organizationRepo.GetOrganization(key)
+func (s *OrganizationService) GetOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	return s.organizationRepo.GetOrganizations(ctx)
 }
 
-func (s *OrganizationService) GetOrganizationByID(id string) (*models.Organization, error) {
-	organizations, err := s.organizationRepo.GetOrganization(id)
-	if err != nil {
-		return nil, err
-	}
-	if len(organizations) == 0 {
-		return nil, errors.New("organization not found")
-	}
-	return organizations[0], nil
+func (s *OrganizationService) GetOrganizationByID(ctx context.Context, id string) (*models.Organization, error) {
+	return s.organizationRepo.GetOrganizationByID(ctx, id)
 }
 
-func (s *OrganizationService) UpdateOrganization(id string, req *models.Organization, updatedBy string) (*models.Organization, error) {
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, id string, req *models.Organization, updatedBy string) (*models.Organization, error) {
 	if err := s.validateCreateOrganization(req); err != nil {
 		return nil, err
 	}
 
 	req.UpdatedBy = updatedBy
-	return s.organizationRepo.UpdateOrganization(id, req)
+	return s.organizationRepo.UpdateOrganization(ctx, id, req)
+}
+
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id string, deletedBy string, reason string) error {
+	return s.organizationRepo.DeleteOrganization(ctx, id, newDeletedData(deletedBy, reason))
 }
 
-func (s *OrganizationService) DeleteOrganization(id string) error {
-	return s.organizationRepo.DeleteOrganization(id)
+func (s *OrganizationService) RestoreOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	return s.organizationRepo.RestoreOrganization(ctx, id)
 }
 
-func (s *OrganizationService) GetOrganizationAssignmentsByStatus(status string) ([]*models.Organization, error) {
+func (s *OrganizationService) GetOrganizationAssignmentsByStatus(ctx context.Context, status string) ([]*models.Organization, error) {
 	// For now, return all organizations since there's no status-based filtering in the repository
-	return s.organizationRepo.GetOrganization("")
+	return s.organizationRepo.GetOrganizations(ctx)
 }
 
-func (s *OrganizationService) UpdateOrganizationAssignment(id string, organizationAssignment *models.Organization, updatedBy string) (*models.Organization, error) {
-	return s.UpdateOrganization(id, organizationAssignment, updatedBy)
+func (s *OrganizationService) UpdateOrganizationAssignment(ctx context.Context, id string, organizationAssignment *models.Organization, updatedBy string) (*models.Organization, error) {
+	return s.UpdateOrganization(ctx, id, organizationAssignment, updatedBy)
 }
 
-func (s *OrganizationService) DeleteOrganizationAssignment(id string) error {
-	return s.DeleteOrganization(id)
+func (s *OrganizationService) DeleteOrganizationAssignment(ctx context.Context, id string, deletedBy string, reason string) error {
+	return s.DeleteOrganization(ctx, id, deletedBy, reason)
 }
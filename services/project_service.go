@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"strings"
+)
+
+// ErrInvalidProject is returned when a job is added to a project that does
+// not exist or belongs to another organization
+var ErrInvalidProject = errors.New("invalid project")
+
+type ProjectService struct {
+	projectRepo repository.ProjectRepositoryInterface
+	jobRepo     repository.JobRepositoryInterface
+	logger      logger.Logger
+}
+
+func NewProjectService(projectRepo repository.ProjectRepositoryInterface, jobRepo repository.JobRepositoryInterface, logger logger.Logger) *ProjectService {
+	return &ProjectService{
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		logger:      logger,
+	}
+}
+
+func (s *ProjectService) CreateProject(ctx context.Context, req *models.CreateProjectRequest, createdBy string) (*models.Project, error) {
+	if req == nil {
+		return nil, errors.New("project request is required")
+	}
+
+	if strings.TrimSpace(req.OrgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("project name is required")
+	}
+	ctx, err := tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &models.Project{
+		OrgID:       req.OrgID,
+		ClientID:    strings.TrimSpace(req.ClientID),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	}
+
+	created, err := s.projectRepo.CreateProject(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	created.Progress = projectProgress(nil)
+	return created, nil
+}
+
+// GetProject returns a project with its jobs and their progress
+func (s *ProjectService) GetProject(ctx context.Context, id string) (*models.Project, error) {
+	project, err := s.projectRepo.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.projectJobs(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	project.Jobs = jobs
+	project.Progress = projectProgress(jobs)
+	return project, nil
+}
+
+// GetProjects returns the projects of orgID with their progress. The jobs
+// themselves are left out.
+func (s *ProjectService) GetProjects(ctx context.Context, orgID string) ([]*models.Project, error) {
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectRepo.GetProjectsByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		jobs, err := s.projectJobs(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		project.Progress = projectProgress(jobs)
+	}
+	return projects, nil
+}
+
+func (s *ProjectService) UpdateProject(ctx context.Context, id string, req *models.UpdateProjectRequest, updatedBy string) (*models.Project, error) {
+	if req == nil {
+		return nil, errors.New("project request is required")
+	}
+
+	existing, err := s.projectRepo.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.ClientID != "" {
+		updated.ClientID = strings.TrimSpace(req.ClientID)
+	}
+	if req.Name != "" {
+		updated.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Description != "" {
+		updated.Description = req.Description
+	}
+	updated.UpdatedBy = updatedBy
+
+	project, err := s.projectRepo.UpdateProject(ctx, id, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.projectJobs(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	project.Progress = projectProgress(jobs)
+	return project, nil
+}
+
+// DeleteProject deletes a project. Its jobs are kept and still carry its ID.
+func (s *ProjectService) DeleteProject(ctx context.Context, id string, deletedBy string, reason string) error {
+	return s.projectRepo.DeleteProject(ctx, id, newDeletedData(deletedBy, reason))
+}
+
+// projectJobs returns the jobs of project, which are stored with the rest
+// of its organization's jobs
+func (s *ProjectService) projectJobs(ctx context.Context, project *models.Project) ([]*models.Job, error) {
+	ctx, err := tenantContext(ctx, project.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.jobRepo.GetJobsByProject(ctx, project.ProjectID)
+}
+
+// projectProgress rolls the statuses of jobs up into the progress of their
+// project
+func projectProgress(jobs []*models.Job) *models.ProjectProgress {
+	progress := &models.ProjectProgress{
+		Status:       models.ProjectStatusPlanned,
+		TotalJobs:    len(jobs),
+		JobsByStatus: make(map[models.JobStatus]int),
+	}
+
+	started := false
+	for _, job := range jobs {
+		progress.JobsByStatus[job.JobStatus]++
+		switch job.JobStatus {
+		case models.JobStatusCompleted:
+			progress.CompletedJobs++
+			started = true
+		case models.JobStatusCancelled:
+			progress.CancelledJobs++
+		case models.JobStatusOnHold:
+			// Jobs held before they started leave the project planned
+			started = started || job.JobStartedAt != nil
+		case models.JobStatusActive, models.JobStatusInProgress:
+			started = true
+		}
+	}
+
+	if remaining := progress.TotalJobs - progress.CancelledJobs; remaining > 0 {
+		progress.PercentComplete = progress.CompletedJobs * 100 / remaining
+	}
+
+	switch {
+	case progress.TotalJobs == 0:
+	case progress.CancelledJobs == progress.TotalJobs:
+		progress.Status = models.ProjectStatusCancelled
+	case progress.CompletedJobs+progress.CancelledJobs == progress.TotalJobs:
+		progress.Status = models.ProjectStatusCompleted
+	case started:
+		progress.Status = models.ProjectStatusInProgress
+	}
+	return progress
+}
@@ -0,0 +1,496 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"fieldfuze-backend/utils/rrule"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidRecurrence is returned for recurrence rules and exception dates
+// that cannot be parsed
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// recurrenceNamespace derives the IDs of recurring job instances, so every
+// occurrence maps to one job however often it is materialised
+var recurrenceNamespace = uuid.MustParse("6f1c2a9e-4b7d-4f3a-9c55-2e8d0b7a4e61")
+
+type RecurringJobService struct {
+	recurringRepo repository.RecurringJobRepositoryInterface
+	jobRepo       repository.JobRepositoryInterface
+	orgRepo       repository.OrganizationRepositoryInterface
+	checklistRepo repository.ChecklistRepositoryInterface
+	horizon       time.Duration
+	logger        logger.Logger
+}
+
+// NewRecurringJobService creates a recurring job service that materialises
+// instances horizonDays ahead
+func NewRecurringJobService(recurringRepo repository.RecurringJobRepositoryInterface, jobRepo repository.JobRepositoryInterface, orgRepo repository.OrganizationRepositoryInterface, checklistRepo repository.ChecklistRepositoryInterface, horizonDays int, logger logger.Logger) *RecurringJobService {
+	return &RecurringJobService{
+		recurringRepo: recurringRepo,
+		jobRepo:       jobRepo,
+		orgRepo:       orgRepo,
+		checklistRepo: checklistRepo,
+		horizon:       time.Duration(horizonDays) * 24 * time.Hour,
+		logger:        logger,
+	}
+}
+
+// CreateRecurringJob stores a recurring job and materialises its first
+// instances
+func (s *RecurringJobService) CreateRecurringJob(ctx context.Context, req *models.CreateRecurringJobRequest, createdBy string) (*models.RecurringJob, error) {
+	if err := validateCreateRecurringJob(req); err != nil {
+		return nil, err
+	}
+
+	rule, err := parseRecurrenceRule(req.RRule)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExceptionDates(req.ExceptionDates); err != nil {
+		return nil, err
+	}
+	ctx, err = tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	startAt, err := parseScheduleTime(req.StartAt, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	recurringJob := &models.RecurringJob{
+		OrgID:                 req.OrgID,
+		ClientID:              req.ClientID,
+		JobsName:              req.JobsName,
+		JobType:               req.JobType,
+		Notes:                 req.Notes,
+		UsersAssignedToJob:    req.UsersAssignedToJob,
+		VehiclesAssignedToJob: req.VehiclesAssignedToJob,
+		EstimatedDuration:     req.EstimatedDuration,
+		RRule:                 rule.String(),
+		StartAt:               startAt,
+		Timezone:              loc.String(),
+		ExceptionDates:        req.ExceptionDates,
+		Status:                models.RecurringJobStatusActive,
+		Revision:              1,
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	}
+
+	created, err := s.recurringRepo.CreateRecurringJob(ctx, recurringJob)
+	if err != nil {
+		return nil, err
+	}
+
+	// The worker catches up on the next run if this fails
+	if _, err := s.Materialize(ctx, created); err != nil {
+		s.logger.Warnf("Failed to materialise recurring job %s: %v", created.RecurringJobID, err)
+	}
+	return created, nil
+}
+
+func validateCreateRecurringJob(req *models.CreateRecurringJobRequest) error {
+	if req == nil {
+		return errors.New("recurring job request is required")
+	}
+
+	if strings.TrimSpace(req.JobsName) == "" {
+		return errors.New("job name is required")
+	}
+
+	if strings.TrimSpace(req.ClientID) == "" {
+		return errors.New("client ID is required")
+	}
+
+	if strings.TrimSpace(req.OrgID) == "" {
+		return errors.New("organization ID is required")
+	}
+
+	if req.JobType == "" {
+		return errors.New("job type is required")
+	}
+
+	if strings.TrimSpace(req.StartAt) == "" {
+		return errors.New("start time is required")
+	}
+
+	return nil
+}
+
+func (s *RecurringJobService) GetRecurringJob(ctx context.Context, id string) (*models.RecurringJob, error) {
+	return s.recurringRepo.GetRecurringJob(ctx, id)
+}
+
+func (s *RecurringJobService) GetRecurringJobs(ctx context.Context, orgID string) ([]*models.RecurringJob, error) {
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recurringRepo.GetRecurringJobsByOrganization(ctx, orgID)
+}
+
+// UpdateRecurringJob changes a recurring job. A changed rule or start time
+// starts a new revision that is materialised from now on. With
+// req.ApplyToFuture the pending future instances follow the template: they
+// are removed and materialised again when the schedule changed and updated
+// otherwise. Pending future instances on an exception date are always
+// removed.
+func (s *RecurringJobService) UpdateRecurringJob(ctx context.Context, id string, req *models.UpdateRecurringJobRequest, updatedBy string) (*models.RecurringJob, error) {
+	if req == nil {
+		return nil, errors.New("recurring job request is required")
+	}
+
+	existing, err := s.recurringRepo.GetRecurringJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	scheduleChanged := false
+
+	if req.JobsName != "" {
+		updated.JobsName = req.JobsName
+	}
+	if req.JobType != "" {
+		updated.JobType = req.JobType
+	}
+	if req.Notes != "" {
+		updated.Notes = req.Notes
+	}
+	if req.UsersAssignedToJob != nil {
+		updated.UsersAssignedToJob = req.UsersAssignedToJob
+	}
+	if req.VehiclesAssignedToJob != nil {
+		updated.VehiclesAssignedToJob = req.VehiclesAssignedToJob
+	}
+	if req.EstimatedDuration != 0 {
+		updated.EstimatedDuration = req.EstimatedDuration
+	}
+	if req.RRule != "" {
+		rule, err := parseRecurrenceRule(req.RRule)
+		if err != nil {
+			return nil, err
+		}
+		if rule.String() != existing.RRule {
+			updated.RRule = rule.String()
+			scheduleChanged = true
+		}
+	}
+	if req.StartAt != "" {
+		startAt, err := parseScheduleTime(req.StartAt, existing.Location())
+		if err != nil {
+			return nil, err
+		}
+		if !startAt.Equal(existing.StartAt) {
+			updated.StartAt = startAt
+			scheduleChanged = true
+		}
+	}
+	if req.ExceptionDates != nil {
+		if err := validateExceptionDates(req.ExceptionDates); err != nil {
+			return nil, err
+		}
+		updated.ExceptionDates = req.ExceptionDates
+	}
+	if req.Status != "" {
+		updated.Status = req.Status
+	}
+
+	updated.UpdatedBy = updatedBy
+	if scheduleChanged {
+		updated.Revision++
+		updated.MaterializedThrough = nil
+	}
+
+	saved, err := s.recurringRepo.UpdateRecurringJob(ctx, id, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ApplyToFuture || req.ExceptionDates != nil {
+		if err := s.reconcileInstances(ctx, saved, req.ApplyToFuture && scheduleChanged, req.ApplyToFuture, updatedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if saved.Status == models.RecurringJobStatusActive {
+		if _, err := s.Materialize(ctx, saved); err != nil {
+			s.logger.Warnf("Failed to materialise recurring job %s: %v", id, err)
+		}
+	}
+	return saved, nil
+}
+
+// DeleteRecurringJob soft-deletes a recurring job together with its pending
+// future instances
+func (s *RecurringJobService) DeleteRecurringJob(ctx context.Context, id string, deletedBy string, reason string) error {
+	existing, err := s.recurringRepo.GetRecurringJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.recurringRepo.DeleteRecurringJob(ctx, id, newDeletedData(deletedBy, reason)); err != nil {
+		return err
+	}
+	return s.reconcileInstances(ctx, existing, true, false, deletedBy)
+}
+
+// reconcileInstances walks the pending instances of recurringJob scheduled
+// after now. Instances on an exception date are removed, as are all of them
+// when remove is set; with propagate the others take over the template's
+// details.
+func (s *RecurringJobService) reconcileInstances(ctx context.Context, recurringJob *models.RecurringJob, remove, propagate bool, actor string) error {
+	ctx, err := tenantContext(ctx, recurringJob.OrgID)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := s.jobRepo.GetJobsByRecurringJob(ctx, recurringJob.RecurringJobID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, job := range jobs {
+		if job.JobStatus != models.JobStatusPending || job.ScheduledStart == nil || !job.ScheduledStart.After(now) {
+			continue
+		}
+
+		switch {
+		case remove || recurringJob.IsException(job.OccurrenceDate):
+			err = s.jobRepo.DeleteJob(ctx, job.JobID, newDeletedData(actor, "recurring job changed"))
+		case propagate:
+			applyRecurringJob(job, recurringJob)
+			job.UpdatedBy = actor
+			_, err = s.jobRepo.UpdateJob(ctx, job.JobID, job)
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update instance %s: %w", job.JobID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PreviewOccurrences lists the occurrences of a recurring job between from
+// and to, read like the bounds of GetCalendar, together with the instances
+// materialised for them
+func (s *RecurringJobService) PreviewOccurrences(ctx context.Context, id, from, to string) ([]models.RecurringJobOccurrence, error) {
+	recurringJob, err := s.recurringRepo.GetRecurringJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := parseRecurrenceRule(recurringJob.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := recurringJob.Location()
+	start, end, err := calendarRange(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantCtx, err := tenantContext(ctx, recurringJob.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.jobRepo.GetJobsByRecurringJob(tenantCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]*models.Job, len(jobs))
+	for _, job := range jobs {
+		instances[job.OccurrenceDate] = job
+	}
+
+	occurrences := []models.RecurringJobOccurrence{}
+	for _, at := range rule.Between(recurringJob.StartAt.In(loc), start, end.Add(-time.Second)) {
+		date := at.Format("2006-01-02")
+		occurrence := models.RecurringJobOccurrence{
+			Date:           date,
+			ScheduledStart: at.UTC(),
+			Skipped:        recurringJob.IsException(date),
+		}
+		if job, ok := instances[date]; ok {
+			occurrence.JobID = job.JobID
+			occurrence.JobStatus = job.JobStatus
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+// MaterializeDue materialises the upcoming instances of every active
+// recurring job of the organizations in orgIDs
+func (s *RecurringJobService) MaterializeDue(ctx context.Context, orgIDs []string) error {
+	var errs []error
+	created, total := 0, 0
+	for _, orgID := range orgIDs {
+		// Recurring jobs are stored with the rest of their organization's jobs
+		tenantCtx := requestctx.WithTenant(ctx, orgID)
+
+		recurringJobs, err := s.recurringRepo.GetActiveRecurringJobs(tenantCtx, orgID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", orgID, err))
+			continue
+		}
+
+		for _, recurringJob := range recurringJobs {
+			n, err := s.Materialize(tenantCtx, recurringJob)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("recurring job %s: %w", recurringJob.RecurringJobID, err))
+			}
+			created += n
+		}
+		total += len(recurringJobs)
+	}
+
+	s.logger.Infof("Materialised %d job instances from %d recurring jobs", created, total)
+	return errors.Join(errs...)
+}
+
+// Materialize creates the instances of recurringJob from now, or from where
+// the last run stopped, up to the horizon and returns how many it created.
+// Exception dates and dates that already have an instance are skipped.
+func (s *RecurringJobService) Materialize(ctx context.Context, recurringJob *models.RecurringJob) (int, error) {
+	rule, err := parseRecurrenceRule(recurringJob.RRule)
+	if err != nil {
+		return 0, err
+	}
+	ctx, err = tenantContext(ctx, recurringJob.OrgID)
+	if err != nil {
+		return 0, err
+	}
+
+	loc := recurringJob.Location()
+	now := time.Now().UTC()
+	through := now.Add(s.horizon)
+	from := now
+	if recurringJob.MaterializedThrough != nil && recurringJob.MaterializedThrough.After(from) {
+		from = recurringJob.MaterializedThrough.Add(time.Second)
+	}
+
+	created := 0
+	occurrences := rule.Between(recurringJob.StartAt.In(loc), from, through)
+	if len(occurrences) > 0 {
+		existing, err := s.jobRepo.GetJobsByRecurringJob(ctx, recurringJob.RecurringJobID)
+		if err != nil {
+			return 0, err
+		}
+		dates := make(map[string]bool, len(existing))
+		for _, job := range existing {
+			dates[job.OccurrenceDate] = true
+		}
+
+		checklist, err := newJobChecklist(ctx, s.checklistRepo, recurringJob.OrgID, recurringJob.JobType)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, at := range occurrences {
+			date := at.Format("2006-01-02")
+			if dates[date] || recurringJob.IsException(date) {
+				continue
+			}
+
+			instance := newRecurringInstance(recurringJob, at, date)
+			instance.Checklist = checklist
+			ok, err := s.jobRepo.CreateJobInstance(ctx, instance)
+			if err != nil {
+				return created, err
+			}
+			if ok {
+				created++
+			}
+		}
+	}
+
+	if err := s.recurringRepo.SetMaterializedThrough(ctx, recurringJob.RecurringJobID, through); err != nil {
+		return created, err
+	}
+	recurringJob.MaterializedThrough = &through
+	return created, nil
+}
+
+// newRecurringInstance builds the job for the occurrence of recurringJob at
+// at, local date date. The ID is derived from the template, its revision
+// and the date.
+func newRecurringInstance(recurringJob *models.RecurringJob, at time.Time, date string) *models.Job {
+	name := fmt.Sprintf("%s/%d/%s", recurringJob.RecurringJobID, recurringJob.Revision, date)
+	start := at.UTC()
+
+	job := &models.Job{
+		JobID:                 uuid.NewSHA1(recurrenceNamespace, []byte(name)).String(),
+		ClientID:              recurringJob.ClientID,
+		CreatedData:           recurringJob.CreatedData,
+		JobImagesAfterService: []string{},
+		JobStatus:             models.JobStatusPending,
+		OrgID:                 recurringJob.OrgID,
+		ScheduledStart:        &start,
+		Timezone:              recurringJob.Timezone,
+		RecurringJobID:        recurringJob.RecurringJobID,
+		OccurrenceDate:        date,
+		StatusHistory: []models.StatusChange{{
+			To:        models.JobStatusPending,
+			UID:       recurringJob.CreatedData.UID,
+			ChangedAt: time.Now().UTC(),
+		}},
+	}
+	applyRecurringJob(job, recurringJob)
+	return job
+}
+
+// applyRecurringJob copies the details of recurringJob onto an instance
+func applyRecurringJob(job *models.Job, recurringJob *models.RecurringJob) {
+	job.JobsName = recurringJob.JobsName
+	job.JobType = recurringJob.JobType
+	job.Notes = recurringJob.Notes
+	job.UsersAssignedToJob = recurringJob.UsersAssignedToJob
+	job.VehiclesAssignedToJob = recurringJob.VehiclesAssignedToJob
+	job.EstimatedDuration = recurringJob.EstimatedDuration
+	job.ScheduledEnd = nil
+	if job.ScheduledStart != nil && recurringJob.EstimatedDuration > 0 {
+		end := job.ScheduledStart.Add(time.Duration(recurringJob.EstimatedDuration) * time.Minute)
+		job.ScheduledEnd = &end
+	}
+}
+
+// parseRecurrenceRule parses value as a recurrence rule
+func parseRecurrenceRule(value string) (*rrule.Rule, error) {
+	rule, err := rrule.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return rule, nil
+}
+
+// validateExceptionDates checks that dates are YYYY-MM-DD dates
+func validateExceptionDates(dates []string) error {
+	for _, date := range dates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("%w: exception date %q is not YYYY-MM-DD", ErrInvalidRecurrence, date)
+		}
+	}
+	return nil
+}
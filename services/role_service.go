@@ -10,6 +10,13 @@ import (
 	"strings"
 )
 
+// ErrRoleIDRequired is returned when a role is addressed without its ID
+var ErrRoleIDRequired = errors.New("role ID is required")
+
+// ErrRoleAssignmentIDRequired is returned when a role assignment is
+// addressed without its ID
+var ErrRoleAssignmentIDRequired = errors.New("role assignment ID is required")
+
 type RoleService struct {
 	roleRepo repository.RoleRepositoryInterface
 	logger   logger.Logger
@@ -33,47 +40,38 @@ func (s *RoleService) CreateRole(ctx context.Context, roleAssignment *models.Rol
 	return s.roleRepo.CreateRoleAssignment(ctx, roleAssignment)
 }
 
-func (s *RoleService) GetRoleAssignments() ([]*models.RoleAssignment, error) {
-	return s.roleRepo.GetRoleAssignments("")
+func (s *RoleService) GetRoleAssignments(ctx context.Context) ([]*models.RoleAssignment, error) {
+	return s.roleRepo.GetRoleAssignments(ctx, "")
 }
 
-func (s *RoleService) GetRoleAssignmentByID(id string) (*models.RoleAssignment, error) {
+func (s *RoleService) GetRoleAssignmentByID(ctx context.Context, id string) (*models.RoleAssignment, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("role assignment ID is required")
+		return nil, ErrRoleAssignmentIDRequired
 	}
 
-	roles, err := s.roleRepo.GetRoleAssignments(id)
+	roles, err := s.roleRepo.GetRoleAssignments(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(roles) == 0 {
-		return nil, errors.New("role assignment not found")
+		return nil, repository.ErrRoleAssignmentNotFound
 	}
 
 	return roles[0], nil
 }
 
-func (s *RoleService) GetRoleByName(name string) (*models.Role, error) {
+func (s *RoleService) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, errors.New("role name is required")
 	}
 
-	roles, err := s.roleRepo.GetRole(name)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(roles) == 0 {
-		return nil, errors.New("role not found")
-	}
-
-	return roles[0], nil
+	return s.roleRepo.GetRoleByName(ctx, name)
 }
 
-func (s *RoleService) UpdateRole(id string, req *models.UpdateRoleRequest, updatedBy string) (*models.Role, error) {
+func (s *RoleService) UpdateRole(ctx context.Context, id string, req *models.UpdateRoleRequest, updatedBy string) (*models.Role, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("role ID is required")
+		return nil, ErrRoleIDRequired
 	}
 
 	if err := s.validateUpdateRoleRequest(req); err != nil {
@@ -100,28 +98,36 @@ func (s *RoleService) UpdateRole(id string, req *models.UpdateRoleRequest, updat
 		role.Status = req.Status
 	}
 
-	return s.roleRepo.UpdateRole(id, role)
+	return s.roleRepo.UpdateRole(ctx, id, role)
 }
 
-func (s *RoleService) DeleteRole(id string) error {
+func (s *RoleService) DeleteRole(ctx context.Context, id string, deletedBy string, reason string) error {
 	if strings.TrimSpace(id) == "" {
-		return errors.New("role ID is required")
+		return ErrRoleIDRequired
 	}
 
-	return s.roleRepo.DeleteRole(id)
+	return s.roleRepo.DeleteRole(ctx, id, newDeletedData(deletedBy, reason))
 }
 
-func (s *RoleService) GetRoleAssignmentsByStatus(status string) ([]*models.RoleAssignment, error) {
+func (s *RoleService) RestoreRole(ctx context.Context, id string) (*models.Role, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, ErrRoleIDRequired
+	}
+
+	return s.roleRepo.RestoreRole(ctx, id)
+}
+
+func (s *RoleService) GetRoleAssignmentsByStatus(ctx context.Context, status string) ([]*models.RoleAssignment, error) {
 	if status == "" {
 		return nil, errors.New("status is required")
 	}
 
-	return s.roleRepo.GetRoleAssignmentsByStatus(status)
+	return s.roleRepo.GetRoleAssignmentsByStatus(ctx, status)
 }
 
-func (s *RoleService) UpdateRoleAssignment(id string, roleAssignment *models.RoleAssignment, updatedBy string) (*models.RoleAssignment, error) {
+func (s *RoleService) UpdateRoleAssignment(ctx context.Context, id string, roleAssignment *models.RoleAssignment, updatedBy string) (*models.RoleAssignment, error) {
 	if strings.TrimSpace(id) == "" {
-		return nil, errors.New("role assignment ID is required")
+		return nil, ErrRoleAssignmentIDRequired
 	}
 
 	if err := s.validateCreateRoleAssignment(roleAssignment); err != nil {
@@ -131,15 +137,23 @@ func (s *RoleService) UpdateRoleAssignment(id string, roleAssignment *models.Rol
 	roleAssignment.RoleID = id
 	roleAssignment.RoleName = strings.TrimSpace(roleAssignment.RoleName)
 
-	return s.roleRepo.UpdateRoleAssignment(id, roleAssignment)
+	return s.roleRepo.UpdateRoleAssignment(ctx, id, roleAssignment)
+}
+
+func (s *RoleService) DeleteRoleAssignment(ctx context.Context, id string, deletedBy string, reason string) error {
+	if strings.TrimSpace(id) == "" {
+		return ErrRoleAssignmentIDRequired
+	}
+
+	return s.roleRepo.DeleteRoleAssignment(ctx, id, newDeletedData(deletedBy, reason))
 }
 
-func (s *RoleService) DeleteRoleAssignment(id string) error {
+func (s *RoleService) RestoreRoleAssignment(ctx context.Context, id string) (*models.RoleAssignment, error) {
 	if strings.TrimSpace(id) == "" {
-		return errors.New("role assignment ID is required")
+		return nil, ErrRoleAssignmentIDRequired
 	}
 
-	return s.roleRepo.DeleteRoleAssignment(id)
+	return s.roleRepo.RestoreRoleAssignment(ctx, id)
 }
 
 func (s *RoleService) validateCreateRoleAssignment(roleAssignment *models.RoleAssignment) error {
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/geo"
+	"math"
+	"sort"
+	"time"
+)
+
+// maxExhaustiveStops is the largest route planned by trying every order of
+// its stops. Longer routes are planned greedily and then improved.
+const maxExhaustiveStops = 8
+
+// maxImprovementPasses bounds the local search of longer routes
+const maxImprovementPasses = 50
+
+// routeJob is a job to be placed on a route
+type routeJob struct {
+	job      *models.Job
+	duration time.Duration
+}
+
+// routeCost ranks routes: the least time past arrival windows first, then
+// the least travel, then the earliest finish
+type routeCost struct {
+	late   time.Duration
+	travel time.Duration
+	finish time.Time
+}
+
+func (c routeCost) less(other routeCost) bool {
+	if c.late != other.late {
+		return c.late < other.late
+	}
+	if c.travel != other.travel {
+		return c.travel < other.travel
+	}
+	return c.finish.Before(other.finish)
+}
+
+// routePlanner orders the jobs of one technician's day. legs holds the
+// travel between the jobs, preceded by the start location when hasOrigin.
+type routePlanner struct {
+	start     time.Time
+	hasOrigin bool
+	legs      [][]geo.Leg
+	jobs      []routeJob
+}
+
+// leg returns the travel to job to from job from, or from the start
+// location when from is -1. Routes without a start location begin at their
+// first job.
+func (p *routePlanner) leg(from, to int) geo.Leg {
+	offset := 0
+	if p.hasOrigin {
+		offset = 1
+	}
+	if from < 0 {
+		if !p.hasOrigin {
+			return geo.Leg{}
+		}
+		return p.legs[0][to+offset]
+	}
+	return p.legs[from+offset][to+offset]
+}
+
+// travelTime rounds the duration of leg up to whole minutes, so planned
+// times stay on the minute like stored schedules
+func travelTime(leg geo.Leg) time.Duration {
+	d := leg.Duration.Truncate(time.Minute)
+	if d < leg.Duration {
+		d += time.Minute
+	}
+	return d
+}
+
+// simulate drives the jobs in order and returns the cost of the route. With
+// record it also returns its stops.
+func (p *routePlanner) simulate(order []int, record bool) (routeCost, []models.RouteStop) {
+	var cost routeCost
+	var stops []models.RouteStop
+	if record {
+		stops = make([]models.RouteStop, 0, len(order))
+	}
+
+	now := p.start
+	previous := -1
+	for _, i := range order {
+		leg := p.leg(previous, i)
+		travel := travelTime(leg)
+		arrive := now.Add(travel)
+		start := arrive
+		var late time.Duration
+
+		job := p.jobs[i].job
+		if window := job.ArrivalWindow; window != nil {
+			if arrive.Before(window.Start) {
+				start = window.Start
+			} else if arrive.After(window.End) {
+				late = arrive.Sub(window.End)
+			}
+		}
+		end := start.Add(p.jobs[i].duration)
+
+		cost.travel += travel
+		cost.late += late
+		if record {
+			stops = append(stops, models.RouteStop{
+				Sequence:       len(stops) + 1,
+				JobID:          job.JobID,
+				JobsName:       job.JobsName,
+				Site:           job.Site,
+				ArrivalWindow:  job.ArrivalWindow,
+				TravelMeters:   math.Round(leg.Meters),
+				TravelMinutes:  int(travel / time.Minute),
+				ArriveAt:       arrive,
+				WaitMinutes:    int(start.Sub(arrive) / time.Minute),
+				StartAt:        start,
+				EndAt:          end,
+				LateMinutes:    int(late / time.Minute),
+				ScheduledStart: job.ScheduledStart,
+			})
+		}
+
+		now = end
+		previous = i
+	}
+	cost.finish = now
+	return cost, stops
+}
+
+// plan returns the best order of the jobs found. Longer routes are
+// improved from the greedy route and from the jobs in the order of their
+// arrival windows, and the better result is kept.
+func (p *routePlanner) plan() []int {
+	if len(p.jobs) <= maxExhaustiveStops {
+		return p.exhaustive()
+	}
+
+	best := p.improve(p.greedy())
+	bestCost, _ := p.simulate(best, false)
+	alternative := p.improve(p.byWindow())
+	if cost, _ := p.simulate(alternative, false); cost.less(bestCost) {
+		return alternative
+	}
+	return best
+}
+
+// byWindow orders the jobs by the end of their arrival window, jobs
+// without one last
+func (p *routePlanner) byWindow() []int {
+	order := make([]int, len(p.jobs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		wa, wb := p.jobs[order[a]].job.ArrivalWindow, p.jobs[order[b]].job.ArrivalWindow
+		switch {
+		case wa == nil:
+			return false
+		case wb == nil:
+			return true
+		default:
+			return wa.End.Before(wb.End)
+		}
+	})
+	return order
+}
+
+// exhaustive tries every order of the jobs
+func (p *routePlanner) exhaustive() []int {
+	order := make([]int, len(p.jobs))
+	for i := range order {
+		order[i] = i
+	}
+	best := append([]int(nil), order...)
+	bestCost, _ := p.simulate(best, false)
+
+	// Heap's algorithm visits every permutation by single swaps
+	c := make([]int, len(order))
+	for i := 1; i < len(order); {
+		if c[i] < i {
+			if i%2 == 0 {
+				order[0], order[i] = order[i], order[0]
+			} else {
+				order[c[i]], order[i] = order[i], order[c[i]]
+			}
+			if cost, _ := p.simulate(order, false); cost.less(bestCost) {
+				bestCost = cost
+				copy(best, order)
+			}
+			c[i]++
+			i = 1
+		} else {
+			c[i] = 0
+			i++
+		}
+	}
+	return best
+}
+
+// greedy builds a route by repeatedly appending the job that keeps the
+// route cheapest
+func (p *routePlanner) greedy() []int {
+	order := make([]int, 0, len(p.jobs))
+	used := make([]bool, len(p.jobs))
+	for len(order) < len(p.jobs) {
+		next := -1
+		var nextCost routeCost
+		for i := range p.jobs {
+			if used[i] {
+				continue
+			}
+			cost, _ := p.simulate(append(order, i), false)
+			if next < 0 || cost.less(nextCost) {
+				next, nextCost = i, cost
+			}
+		}
+		used[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// improve moves runs of up to three jobs and reverses runs of jobs while
+// that makes the route cheaper
+func (p *routePlanner) improve(order []int) []int {
+	best := append([]int(nil), order...)
+	bestCost, _ := p.simulate(best, false)
+	candidate := make([]int, len(best))
+
+	for pass := 0; pass < maxImprovementPasses; pass++ {
+		improved := false
+		for i := range best {
+			for j := range best {
+				if i == j {
+					continue
+				}
+
+				// Move the run of jobs starting at i to j
+				for length := 1; length <= 3 && i+length <= len(best) && j+length <= len(best); length++ {
+					relocate(candidate, best, i, j, length)
+					if cost, _ := p.simulate(candidate, false); cost.less(bestCost) {
+						bestCost = cost
+						copy(best, candidate)
+						improved = true
+					}
+				}
+
+				// Reverse the jobs from i to j
+				if i < j {
+					copy(candidate, best)
+					for a, b := i, j; a < b; a, b = a+1, b-1 {
+						candidate[a], candidate[b] = candidate[b], candidate[a]
+					}
+					if cost, _ := p.simulate(candidate, false); cost.less(bestCost) {
+						bestCost = cost
+						copy(best, candidate)
+						improved = true
+					}
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return best
+}
+
+// relocate writes order to dst with the length elements starting at from
+// moved to start at index to
+func relocate(dst, order []int, from, to, length int) {
+	rest := make([]int, 0, len(order)-length)
+	rest = append(rest, order[:from]...)
+	rest = append(rest, order[from+length:]...)
+
+	copy(dst, rest[:to])
+	copy(dst[to:], order[from:from+length])
+	copy(dst[to+length:], rest[to:])
+}
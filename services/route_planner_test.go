@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/geo"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+var routeDay = time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+// linePlanner returns a planner for jobs at kms along a road from a start
+// location at 0, driven at a kilometer a minute. Each job takes 30 minutes.
+func linePlanner(kms []float64, windows map[int]*models.ArrivalWindow) *routePlanner {
+	positions := append([]float64{0}, kms...)
+	legs := make([][]geo.Leg, len(positions))
+	for i, from := range positions {
+		legs[i] = make([]geo.Leg, len(positions))
+		for j, to := range positions {
+			km := math.Abs(to - from)
+			legs[i][j] = geo.Leg{Meters: km * 1000, Duration: time.Duration(km * float64(time.Minute))}
+		}
+	}
+
+	p := &routePlanner{start: routeDay, hasOrigin: true, legs: legs}
+	for i := range kms {
+		job := &models.Job{JobID: fmt.Sprintf("job-%d", i), ArrivalWindow: windows[i]}
+		p.jobs = append(p.jobs, routeJob{job: job, duration: 30 * time.Minute})
+	}
+	return p
+}
+
+// window returns the arrival window from..to hours into the route day
+func window(from, to float64) *models.ArrivalWindow {
+	return &models.ArrivalWindow{
+		Start: routeDay.Add(time.Duration(from * float64(time.Hour))),
+		End:   routeDay.Add(time.Duration(to * float64(time.Hour))),
+	}
+}
+
+func TestSimulateArrivalWindows(t *testing.T) {
+	// job-0 opens after the technician arrives, job-1 closed before
+	p := linePlanner([]float64{10, 20}, map[int]*models.ArrivalWindow{
+		0: window(1, 2),
+		1: window(0, 1),
+	})
+
+	cost, stops := p.simulate([]int{0, 1}, true)
+	if len(stops) != 2 {
+		t.Fatalf("simulate() returned %d stops, want 2", len(stops))
+	}
+
+	first, second := stops[0], stops[1]
+	if first.TravelMinutes != 10 || first.WaitMinutes != 50 || !first.StartAt.Equal(routeDay.Add(time.Hour)) {
+		t.Errorf("first stop = travel %d, wait %d, start %v, want 10, 50, 09:00", first.TravelMinutes, first.WaitMinutes, first.StartAt)
+	}
+	// Arrives 10 minutes after 09:30, 40 minutes past the window
+	if second.TravelMinutes != 10 || second.LateMinutes != 40 || second.Sequence != 2 {
+		t.Errorf("second stop = travel %d, late %d, sequence %d, want 10, 40, 2", second.TravelMinutes, second.LateMinutes, second.Sequence)
+	}
+	if cost.late != 40*time.Minute || cost.travel != 20*time.Minute || !cost.finish.Equal(routeDay.Add(130*time.Minute)) {
+		t.Errorf("simulate() cost = %+v", cost)
+	}
+}
+
+func TestTravelTimeRoundsUp(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     time.Duration
+	}{
+		{duration: 0, want: 0},
+		{duration: time.Minute, want: time.Minute},
+		{duration: time.Minute + time.Second, want: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := travelTime(geo.Leg{Duration: tt.duration}); got != tt.want {
+			t.Errorf("travelTime(%v) = %v, want %v", tt.duration, got, tt.want)
+		}
+	}
+}
+
+// TestPlanKeepsArrivalWindows puts a job out of its way first when that is
+// the only way to meet its window
+func TestPlanKeepsArrivalWindows(t *testing.T) {
+	p := linePlanner([]float64{5, 10, 60}, map[int]*models.ArrivalWindow{
+		2: window(0, 1),
+	})
+
+	if got, want := p.plan(), []int{2, 1, 0}; !slices.Equal(got, want) {
+		t.Fatalf("plan() = %v, want %v", got, want)
+	}
+	if cost, _ := p.simulate(p.plan(), false); cost.late != 0 {
+		t.Fatalf("plan() is %v late", cost.late)
+	}
+}
+
+func TestByWindow(t *testing.T) {
+	p := linePlanner([]float64{1, 2, 3, 4}, map[int]*models.ArrivalWindow{
+		1: window(2, 4),
+		3: window(0, 2),
+	})
+
+	if got, want := p.byWindow(), []int{3, 1, 0, 2}; !slices.Equal(got, want) {
+		t.Fatalf("byWindow() = %v, want %v", got, want)
+	}
+}
+
+func TestGreedyAndImprove(t *testing.T) {
+	// Driving to the nearest job next zigzags across the start location
+	p := linePlanner([]float64{1, -2, 6, -9}, nil)
+
+	greedy := p.greedy()
+	if want := []int{0, 1, 3, 2}; !slices.Equal(greedy, want) {
+		t.Fatalf("greedy() = %v, want %v", greedy, want)
+	}
+	greedyCost, _ := p.simulate(greedy, false)
+	if greedyCost.travel != 26*time.Minute {
+		t.Fatalf("greedy() travels %v, want 26m", greedyCost.travel)
+	}
+
+	// Covering one side before the other is shortest: 1 and 6, then back
+	// to -2 and -9
+	improved := p.improve(greedy)
+	if cost, _ := p.simulate(improved, false); cost.travel != 21*time.Minute {
+		t.Fatalf("improve() = %v travels %v, want 21m", improved, cost.travel)
+	}
+}
+
+// TestPlanCutover plans up to maxExhaustiveStops jobs optimally and longer
+// routes at least as well as their greedy start
+func TestPlanCutover(t *testing.T) {
+	kms := []float64{7, -3, 12, -8, 2, 15, -1, 9, -12, 4}
+
+	for n := maxExhaustiveStops - 1; n <= len(kms); n++ {
+		p := linePlanner(kms[:n], map[int]*models.ArrivalWindow{0: window(0, 0.5)})
+		order := p.plan()
+
+		sorted := slices.Sorted(slices.Values(order))
+		for i, job := range sorted {
+			if job != i {
+				t.Fatalf("plan() of %d jobs = %v, not every job once", n, order)
+			}
+		}
+
+		cost, _ := p.simulate(order, false)
+		if n <= maxExhaustiveStops {
+			best, _ := p.simulate(p.exhaustive(), false)
+			if cost != best {
+				t.Errorf("plan() of %d jobs costs %+v, want the optimum %+v", n, cost, best)
+			}
+			continue
+		}
+		for name, start := range map[string][]int{"greedy": p.greedy(), "byWindow": p.byWindow()} {
+			if startCost, _ := p.simulate(start, false); startCost.less(cost) {
+				t.Errorf("plan() of %d jobs costs %+v, more than %s with %+v", n, cost, name, startCost)
+			}
+		}
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fieldfuze-backend/dal"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/blob"
+	"fieldfuze-backend/utils/geo"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/notify"
+	"time"
 )
 
 // Service implements ServiceContainerInterface
@@ -15,6 +19,15 @@ type Service struct {
 	infrastructureService InfrastructureServiceInterface
 	organizationService   OrganizationServiceInterface
 	jobService            JobServiceInterface
+	recurringJobService   RecurringJobServiceInterface
+	checklistService      ChecklistServiceInterface
+	attachmentService     AttachmentServiceInterface
+	activityService       ActivityServiceInterface
+	timesheetService      TimesheetServiceInterface
+	dispatchService       DispatchServiceInterface
+	slaService            SLAServiceInterface
+	jobTemplateService    JobTemplateServiceInterface
+	projectService        ProjectServiceInterface
 }
 
 // NewService creates a new service container with all dependencies injected
@@ -22,15 +35,59 @@ func NewService(
 	ctx context.Context,
 	repoContainer repository.RepositoryContainerInterface,
 	dalContainer dal.DALContainerInterface,
+	blobStore blob.BlobStore,
+	routeMatrix geo.DistanceMatrix,
+	notifier notify.Notifier,
 	logger logger.Logger,
 	config *models.Config,
 ) ServiceContainerInterface {
 	return &Service{
-		userService:           NewUserService(ctx, repoContainer.GetUserRepository(), logger),
+		userService:           NewUserService(repoContainer.GetUserRepository(), logger),
 		roleService:           NewRoleService(repoContainer.GetRoleRepository(), logger),
 		infrastructureService: NewInfrastructureService(ctx, dalContainer.GetDatabaseClient(), logger, config),
 		organizationService:   NewOrganizationService(repoContainer.GetOrganizationRepository(), logger),
-		jobService:            NewJobService(repoContainer.GetJobRepository(), logger),
+		jobService: NewJobService(JobServiceDeps{
+			JobRepo:           repoContainer.GetJobRepository(),
+			OrgRepo:           repoContainer.GetOrganizationRepository(),
+			UserRepo:          repoContainer.GetUserRepository(),
+			AvailabilityRepo:  repoContainer.GetAvailabilityRepository(),
+			ChecklistRepo:     repoContainer.GetChecklistRepository(),
+			ActivityRepo:      repoContainer.GetActivityRepository(),
+			SLAPolicyRepo:     repoContainer.GetSLAPolicyRepository(),
+			JobTemplateRepo:   repoContainer.GetJobTemplateRepository(),
+			JobImportRepo:     repoContainer.GetJobImportRepository(),
+			ProjectRepo:       repoContainer.GetProjectRepository(),
+			AttachmentRepo:    repoContainer.GetAttachmentRepository(),
+			RequireChecklist:  config.ChecklistRequiredToComplete,
+			Geocoder:          geo.New(config),
+			GeofenceRadius:    config.GeofenceRadiusMeters,
+			SLAAtRiskPercent:  config.SLAAtRiskPercent,
+			ImportMaxRows:     config.JobImportMaxRows,
+			ImportMaxSize:     int64(config.JobImportMaxUploadMB) << 20,
+			SearchIndex:       repoContainer.GetJobSearchIndex(),
+			SearchMaxPageSize: config.SearchMaxPageSize,
+			Store:             blobStore,
+			AttachmentMaxSize: int64(config.AttachmentMaxSizeMB) << 20,
+			Notifier:          notifier,
+			Logger:            logger,
+		}),
+		recurringJobService: NewRecurringJobService(repoContainer.GetRecurringJobRepository(), repoContainer.GetJobRepository(),
+			repoContainer.GetOrganizationRepository(), repoContainer.GetChecklistRepository(), config.RecurrenceHorizonDays, logger),
+		checklistService: NewChecklistService(repoContainer.GetChecklistRepository(), logger),
+		attachmentService: NewAttachmentService(repoContainer.GetAttachmentRepository(), repoContainer.GetJobRepository(),
+			repoContainer.GetActivityRepository(), blobStore,
+			int64(config.AttachmentMaxSizeMB)<<20, time.Duration(config.BlobURLTTLSeconds)*time.Second, logger),
+		activityService: NewActivityService(repoContainer.GetActivityRepository(), repoContainer.GetJobRepository(),
+			repoContainer.GetUserRepository(), logger),
+		timesheetService: NewTimesheetService(repoContainer.GetTimeEntryRepository(), repoContainer.GetTimesheetRepository(),
+			repoContainer.GetJobRepository(), repoContainer.GetAvailabilityRepository(), repoContainer.GetUserRepository(), logger),
+		dispatchService: NewDispatchService(repoContainer.GetJobRepository(), repoContainer.GetOrganizationRepository(),
+			repoContainer.GetAvailabilityRepository(), repoContainer.GetActivityRepository(), routeMatrix, logger),
+		slaService: NewSLAService(repoContainer.GetSLAPolicyRepository(), repoContainer.GetJobRepository(),
+			repoContainer.GetOrganizationRepository(), repoContainer.GetUserRepository(), repoContainer.GetActivityRepository(),
+			notifier, config.SLAAtRiskPercent, logger),
+		jobTemplateService: NewJobTemplateService(repoContainer.GetJobTemplateRepository(), logger),
+		projectService:     NewProjectService(repoContainer.GetProjectRepository(), repoContainer.GetJobRepository(), logger),
 	}
 }
 
@@ -58,3 +115,58 @@ func (s *Service) GetOrganizationService() OrganizationServiceInterface {
 func (s *Service) GetJobService() JobServiceInterface {
 	return s.jobService
 }
+
+// GetRecurringJobService returns the recurring job service interface
+func (s *Service) GetRecurringJobService() RecurringJobServiceInterface {
+	return s.recurringJobService
+}
+
+// GetChecklistService returns the checklist template service interface
+func (s *Service) GetChecklistService() ChecklistServiceInterface {
+	return s.checklistService
+}
+
+// GetAttachmentService returns the job attachment service interface
+func (s *Service) GetAttachmentService() AttachmentServiceInterface {
+	return s.attachmentService
+}
+
+// GetActivityService returns the job activity service interface
+func (s *Service) GetActivityService() ActivityServiceInterface {
+	return s.activityService
+}
+
+// GetTimesheetService returns the time tracking service interface
+func (s *Service) GetTimesheetService() TimesheetServiceInterface {
+	return s.timesheetService
+}
+
+// GetDispatchService returns the dispatch service interface
+func (s *Service) GetDispatchService() DispatchServiceInterface {
+	return s.dispatchService
+}
+
+// GetSLAService returns the SLA service interface
+func (s *Service) GetSLAService() SLAServiceInterface {
+	return s.slaService
+}
+
+// GetJobTemplateService returns the job template service interface
+func (s *Service) GetJobTemplateService() JobTemplateServiceInterface {
+	return s.jobTemplateService
+}
+
+// GetProjectService returns the project service interface
+func (s *Service) GetProjectService() ProjectServiceInterface {
+	return s.projectService
+}
+
+// newDeletedData records who soft-deleted an item and why
+func newDeletedData(deletedBy, reason string) *models.DeletedData {
+	return &models.DeletedData{
+		UID:        deletedBy,
+		UserStatus: "active",
+		DeletedAt:  time.Now(),
+		Reason:     reason,
+	}
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/notify"
+	"fieldfuze-backend/utils/requestctx"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrSLAPolicyExists is returned when an organization already has an SLA
+// policy for the job type of a new or changed policy
+var ErrSLAPolicyExists = errors.New("SLA policy already exists")
+
+// ErrInvalidSLAPolicy is returned for SLA policies with contradicting
+// targets
+var ErrInvalidSLAPolicy = errors.New("invalid SLA policy")
+
+// supervisorLevel is the role level, Supervisor and up, that is alerted to
+// jobs at risk of missing their SLA
+const supervisorLevel = 7
+
+type SLAService struct {
+	slaPolicyRepo repository.SLAPolicyRepositoryInterface
+	jobRepo       repository.JobRepositoryInterface
+	orgRepo       repository.OrganizationRepositoryInterface
+	userRepo      repository.UserRepositoryInterface
+	activityRepo  repository.ActivityRepositoryInterface
+	notifier      notify.Notifier
+	atRiskPercent int
+	logger        logger.Logger
+}
+
+// NewSLAService creates an SLA service. Jobs with at most atRiskPercent of
+// the time of a target left are at risk; supervisors are alerted to them
+// through notifier.
+func NewSLAService(slaPolicyRepo repository.SLAPolicyRepositoryInterface, jobRepo repository.JobRepositoryInterface, orgRepo repository.OrganizationRepositoryInterface, userRepo repository.UserRepositoryInterface, activityRepo repository.ActivityRepositoryInterface, notifier notify.Notifier, atRiskPercent int, logger logger.Logger) *SLAService {
+	return &SLAService{
+		slaPolicyRepo: slaPolicyRepo,
+		jobRepo:       jobRepo,
+		orgRepo:       orgRepo,
+		userRepo:      userRepo,
+		activityRepo:  activityRepo,
+		notifier:      notifier,
+		atRiskPercent: atRiskPercent,
+		logger:        logger,
+	}
+}
+
+func (s *SLAService) CreateSLAPolicy(ctx context.Context, req *models.CreateSLAPolicyRequest, createdBy string) (*models.SLAPolicy, error) {
+	if req == nil {
+		return nil, errors.New("SLA policy request is required")
+	}
+
+	if strings.TrimSpace(req.OrgID) == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("SLA policy name is required")
+	}
+
+	targets, err := newSLATargets(req.Targets)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = tenantContext(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUniqueJobType(ctx, req.OrgID, req.JobType, ""); err != nil {
+		return nil, err
+	}
+
+	policy := &models.SLAPolicy{
+		OrgID:   req.OrgID,
+		JobType: req.JobType,
+		Name:    strings.TrimSpace(req.Name),
+		Targets: targets,
+		CreatedData: models.CreatedData{
+			UID:        createdBy,
+			UserStatus: "active",
+		},
+	}
+	return s.slaPolicyRepo.CreateSLAPolicy(ctx, policy)
+}
+
+func (s *SLAService) GetSLAPolicy(ctx context.Context, id string) (*models.SLAPolicy, error) {
+	_, policy, err := s.getSLAPolicy(ctx, id)
+	return policy, err
+}
+
+// getSLAPolicy returns the policy id together with ctx scoped to its
+// organization, which the authenticated user must belong to
+func (s *SLAService) getSLAPolicy(ctx context.Context, id string) (context.Context, *models.SLAPolicy, error) {
+	policy, err := s.slaPolicyRepo.GetSLAPolicy(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err = tenantContext(ctx, policy.OrgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, policy, nil
+}
+
+func (s *SLAService) GetSLAPolicies(ctx context.Context, orgID string) ([]*models.SLAPolicy, error) {
+	ctx, err := tenantContext(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return s.slaPolicyRepo.GetSLAPoliciesByOrganization(ctx, orgID)
+}
+
+// UpdateSLAPolicy changes an SLA policy. Jobs created before keep their due
+// dates until their priority or job type changes.
+func (s *SLAService) UpdateSLAPolicy(ctx context.Context, id string, req *models.UpdateSLAPolicyRequest, updatedBy string) (*models.SLAPolicy, error) {
+	if req == nil {
+		return nil, errors.New("SLA policy request is required")
+	}
+
+	if req.AnyJobType && req.JobType != "" {
+		return nil, errors.New("jobType cannot be set together with anyJobType")
+	}
+
+	ctx, existing, err := s.getSLAPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = strings.TrimSpace(req.Name)
+	}
+	if req.JobType != "" {
+		updated.JobType = req.JobType
+	}
+	if req.AnyJobType {
+		updated.JobType = ""
+	}
+	if req.Targets != nil {
+		if updated.Targets, err = newSLATargets(req.Targets); err != nil {
+			return nil, err
+		}
+	}
+	updated.UpdatedBy = updatedBy
+
+	if updated.JobType != existing.JobType {
+		if err := s.checkUniqueJobType(ctx, existing.OrgID, updated.JobType, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.slaPolicyRepo.UpdateSLAPolicy(ctx, id, &updated)
+}
+
+func (s *SLAService) DeleteSLAPolicy(ctx context.Context, id string, deletedBy string, reason string) error {
+	ctx, _, err := s.getSLAPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.slaPolicyRepo.DeleteSLAPolicy(ctx, id, newDeletedData(deletedBy, reason))
+}
+
+// checkUniqueJobType returns ErrSLAPolicyExists when another policy of
+// orgID than the policy exceptID already covers jobType
+func (s *SLAService) checkUniqueJobType(ctx context.Context, orgID string, jobType models.JobType, exceptID string) error {
+	policies, err := s.slaPolicyRepo.GetSLAPoliciesByOrganization(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		if policy.PolicyID == exceptID || policy.JobType != jobType {
+			continue
+		}
+		if jobType == "" {
+			return fmt.Errorf("%w: %s applies to every job type", ErrSLAPolicyExists, policy.Name)
+		}
+		return fmt.Errorf("%w: %s applies to %s jobs", ErrSLAPolicyExists, policy.Name, jobType)
+	}
+	return nil
+}
+
+// newSLATargets checks the targets of a policy request. Every priority may
+// have one target, and jobs must be resolvable no sooner than responded to.
+func newSLATargets(inputs []models.SLATargetInput) ([]models.SLATarget, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("SLA policy needs at least one target")
+	}
+
+	seen := make(map[models.JobPriority]bool, len(inputs))
+	targets := make([]models.SLATarget, 0, len(inputs))
+	for _, input := range inputs {
+		if seen[input.Priority] {
+			return nil, fmt.Errorf("%w: more than one target for %s priority", ErrInvalidSLAPolicy, input.Priority)
+		}
+		seen[input.Priority] = true
+
+		if input.ResponseMinutes < 1 || input.ResolutionMinutes < 1 {
+			return nil, fmt.Errorf("%w: targets of %s priority must be at least 1 minute", ErrInvalidSLAPolicy, input.Priority)
+		}
+		if input.ResolutionMinutes < input.ResponseMinutes {
+			return nil, fmt.Errorf("%w: resolution time of %s priority is shorter than its response time", ErrInvalidSLAPolicy, input.Priority)
+		}
+
+		targets = append(targets, models.SLATarget{
+			Priority:          input.Priority,
+			ResponseMinutes:   input.ResponseMinutes,
+			ResolutionMinutes: input.ResolutionMinutes,
+		})
+	}
+	return targets, nil
+}
+
+// newJobSLA returns the SLA of a job of jobType and priority in orgID whose
+// clocks start at start, or nil when no policy of the organization sets a
+// target for it. A policy for the job type takes precedence over one for
+// every job type.
+func newJobSLA(ctx context.Context, slaPolicyRepo repository.SLAPolicyRepositoryInterface, orgID string, jobType models.JobType, priority models.JobPriority, start time.Time) (*models.JobSLA, error) {
+	policies, err := slaPolicyRepo.GetSLAPoliciesByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SLA policies: %w", err)
+	}
+
+	var policy *models.SLAPolicy
+	for _, candidate := range policies {
+		if candidate.JobType == jobType {
+			policy = candidate
+			break
+		}
+		if candidate.JobType == "" {
+			policy = candidate
+		}
+	}
+	if policy == nil {
+		return nil, nil
+	}
+
+	target := policy.Target(priority.OrDefault())
+	if target == nil {
+		return nil, nil
+	}
+
+	start = start.UTC()
+	return &models.JobSLA{
+		PolicyID:        policy.PolicyID,
+		Status:          models.SLAStatusOnTrack,
+		StartedAt:       start,
+		ResponseDueAt:   start.Add(time.Duration(target.ResponseMinutes) * time.Minute),
+		ResolutionDueAt: start.Add(time.Duration(target.ResolutionMinutes) * time.Minute),
+	}, nil
+}
+
+// evaluateSLA returns a copy of sla as of now. A target not met by its due
+// date is breached. A pending target with at most atRiskPercent of its time
+// left puts the job at risk.
+func evaluateSLA(sla *models.JobSLA, now time.Time, atRiskPercent int) *models.JobSLA {
+	evaluated := *sla
+	evaluated.ResponseBreached = missedSLATarget(sla.ResponseDueAt, sla.RespondedAt, now)
+	evaluated.ResolutionBreached = missedSLATarget(sla.ResolutionDueAt, sla.ResolvedAt, now)
+
+	switch {
+	case evaluated.ResponseBreached || evaluated.ResolutionBreached:
+		evaluated.Status = models.SLAStatusBreached
+	case sla.ResolvedAt != nil:
+		evaluated.Status = models.SLAStatusMet
+	case nearSLATarget(sla.StartedAt, sla.ResponseDueAt, sla.RespondedAt, now, atRiskPercent),
+		nearSLATarget(sla.StartedAt, sla.ResolutionDueAt, sla.ResolvedAt, now, atRiskPercent):
+		evaluated.Status = models.SLAStatusAtRisk
+	default:
+		evaluated.Status = models.SLAStatusOnTrack
+	}
+	return &evaluated
+}
+
+// missedSLATarget reports whether a target due at due was missed: met after
+// it, or not met by now although due
+func missedSLATarget(due time.Time, metAt *time.Time, now time.Time) bool {
+	if metAt != nil {
+		return metAt.After(due)
+	}
+	return now.After(due)
+}
+
+// nearSLATarget reports whether a pending target running from start to due
+// has at most atRiskPercent of its time left at now
+func nearSLATarget(start, due time.Time, metAt *time.Time, now time.Time, atRiskPercent int) bool {
+	if metAt != nil {
+		return false
+	}
+	return due.Sub(now)*100 <= due.Sub(start)*time.Duration(atRiskPercent)
+}
+
+// trackSLA records on job, just moved to its current status, the response
+// or resolution of its SLA and evaluates the SLA as of now
+func trackSLA(job *models.Job, now time.Time, atRiskPercent int) {
+	if job.SLA == nil {
+		return
+	}
+
+	sla := *job.SLA
+	switch job.JobStatus {
+	case models.JobStatusInProgress:
+		if sla.RespondedAt == nil {
+			sla.RespondedAt = &now
+		}
+	case models.JobStatusCompleted:
+		if sla.RespondedAt == nil {
+			sla.RespondedAt = &now
+		}
+		sla.ResolvedAt = &now
+	}
+	job.SLA = evaluateSLA(&sla, now, atRiskPercent)
+}
+
+// CheckSLAs evaluates the open jobs with an SLA of every organization in
+// orgIDs. Jobs that became at risk or missed a target are flagged, get an
+// entry in their activity feed, and the supervisors of their organization
+// are notified.
+func (s *SLAService) CheckSLAs(ctx context.Context, orgIDs []string) error {
+	var users []*models.User
+	var errs []error
+	flagged := 0
+	for _, orgID := range orgIDs {
+		// Jobs are stored with the rest of their organization's jobs
+		tenantCtx := requestctx.WithTenant(ctx, orgID)
+
+		jobs, err := s.jobRepo.GetOpenSLAJobs(tenantCtx, orgID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", orgID, err))
+			continue
+		}
+
+		now := time.Now().UTC()
+		var alerts []*slaAlert
+		for _, job := range jobs {
+			alert, err := s.checkJobSLA(tenantCtx, job, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("job %s: %w", job.JobID, err))
+				continue
+			}
+			if alert != nil {
+				alerts = append(alerts, alert)
+			}
+		}
+		if len(alerts) == 0 {
+			continue
+		}
+		flagged += len(alerts)
+
+		// Users are only listed once alerts are due
+		if users == nil {
+			if users, err = s.userRepo.GetUsers(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("failed to list supervisors: %w", err))
+				users = []*models.User{}
+			}
+		}
+		s.notifySupervisors(ctx, orgID, supervisorsOf(users, orgID), alerts)
+	}
+
+	s.logger.Infof("Checked SLAs of %d organizations, %d jobs flagged", len(orgIDs), flagged)
+	return errors.Join(errs...)
+}
+
+// slaAlert is a job whose SLA got worse in a check. Missed lists the
+// targets it newly missed.
+type slaAlert struct {
+	job    *models.Job
+	missed []string
+}
+
+// checkJobSLA evaluates the SLA of job as of now and stores it if it
+// changed. It returns an alert when the job became at risk or missed a
+// target.
+func (s *SLAService) checkJobSLA(ctx context.Context, job *models.Job, now time.Time) (*slaAlert, error) {
+	before := job.SLA
+	after := evaluateSLA(before, now, s.atRiskPercent)
+	if *after == *before {
+		return nil, nil
+	}
+
+	updated, err := s.jobRepo.UpdateJobSLA(ctx, job.JobID, after, before)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobSLAChanged) {
+			// The job changed since it was read; the next check sees it
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var missed []string
+	if after.ResponseBreached && !before.ResponseBreached {
+		missed = append(missed, "response")
+	}
+	if after.ResolutionBreached && !before.ResolutionBreached {
+		missed = append(missed, "resolution")
+	}
+
+	var activity *models.Activity
+	switch {
+	case len(missed) > 0:
+		activity = &models.Activity{
+			Type:    models.ActivitySLABreached,
+			Summary: "Missed SLA " + strings.Join(missed, " and ") + " time",
+		}
+	case after.Status == models.SLAStatusAtRisk && before.Status == models.SLAStatusOnTrack:
+		activity = &models.Activity{
+			Type:    models.ActivitySLAAtRisk,
+			Summary: "SLA at risk",
+		}
+	default:
+		return nil, nil
+	}
+
+	activity.JobID = updated.JobID
+	activity.OrgID = updated.OrgID
+	recordActivity(ctx, s.activityRepo, s.logger, activity)
+	return &slaAlert{job: updated, missed: missed}, nil
+}
+
+// notifySupervisors sends one notification per alert to supervisors.
+// Failed notifications are logged: the jobs are flagged either way.
+func (s *SLAService) notifySupervisors(ctx context.Context, orgID string, supervisors []notify.Recipient, alerts []*slaAlert) {
+	if len(supervisors) == 0 {
+		s.logger.Warnf("Organization %s has no supervisors to alert to %d jobs at risk of missing their SLA", orgID, len(alerts))
+		return
+	}
+
+	loc, err := organizationLocation(ctx, s.orgRepo, orgID)
+	if err != nil {
+		s.logger.Warnf("Failed to get timezone of organization %s, SLA alerts use UTC: %v", orgID, err)
+		loc = time.UTC
+	}
+
+	for _, alert := range alerts {
+		if err := s.notifier.Notify(ctx, slaNotification(alert, supervisors, loc)); err != nil {
+			s.logger.Warnf("Failed to alert supervisors to SLA of job %s: %v", alert.job.JobID, err)
+		}
+	}
+}
+
+// slaNotification describes alert to supervisors, with times in loc
+func slaNotification(alert *slaAlert, supervisors []notify.Recipient, loc *time.Location) *notify.Notification {
+	job := alert.job
+	sla := job.SLA
+	priority := job.Priority.OrDefault()
+
+	notification := &notify.Notification{
+		Recipients: supervisors,
+		SentAt:     time.Now().UTC(),
+		Data: map[string]string{
+			"jobID":           job.JobID,
+			"orgID":           job.OrgID,
+			"jobsName":        job.JobsName,
+			"priority":        string(priority),
+			"slaStatus":       string(sla.Status),
+			"responseDueAt":   sla.ResponseDueAt.Format(time.RFC3339),
+			"resolutionDueAt": sla.ResolutionDueAt.Format(time.RFC3339),
+		},
+	}
+
+	due := sla.ResolutionDueAt
+	target := "resolution"
+	if sla.RespondedAt == nil && (len(alert.missed) == 0 || alert.missed[0] == "response") {
+		due = sla.ResponseDueAt
+		target = "response"
+	}
+
+	if len(alert.missed) > 0 {
+		notification.Event = string(models.ActivitySLABreached)
+		notification.Subject = fmt.Sprintf("SLA breached: %s", job.JobsName)
+		notification.Body = fmt.Sprintf("The %s %s job %q missed its SLA %s time, due %s.",
+			priority, job.JobType, job.JobsName, strings.Join(alert.missed, " and "), due.In(loc).Format("2006-01-02 15:04 MST"))
+	} else {
+		notification.Event = string(models.ActivitySLAAtRisk)
+		notification.Subject = fmt.Sprintf("SLA at risk: %s", job.JobsName)
+		notification.Body = fmt.Sprintf("The %s %s job %q is due for %s by %s.",
+			priority, job.JobType, job.JobsName, target, due.In(loc).Format("2006-01-02 15:04 MST"))
+	}
+	return notification
+}
+
+// supervisorsOf returns the active users with a role of Supervisor level
+// or higher scoped to orgID itself. Users whose roles are unscoped or
+// scoped to every organization ("*") are left out so they are not alerted
+// to every organization.
+func supervisorsOf(users []*models.User, orgID string) []notify.Recipient {
+	var supervisors []notify.Recipient
+	for _, user := range users {
+		if user.Status != models.UserStatusActive {
+			continue
+		}
+		for _, role := range user.Roles {
+			if role.DeletedData != nil || role.Level < supervisorLevel {
+				continue
+			}
+			if role.Context["organization_id"] != orgID {
+				continue
+			}
+
+			recipient := notify.Recipient{
+				UserID: user.ID,
+				Name:   strings.TrimSpace(user.FirstName + " " + user.LastName),
+				Email:  user.Email,
+			}
+			if user.Phone != nil {
+				recipient.Phone = *user.Phone
+			}
+			supervisors = append(supervisors, recipient)
+			break
+		}
+	}
+	return supervisors
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeSLAPolicyRepo answers GetSLAPolicy from policies and records the
+// written policies
+type fakeSLAPolicyRepo struct {
+	repository.SLAPolicyRepositoryInterface
+	policies map[string]*models.SLAPolicy
+	written  []string
+}
+
+func (r *fakeSLAPolicyRepo) GetSLAPolicy(ctx context.Context, id string) (*models.SLAPolicy, error) {
+	policy, ok := r.policies[id]
+	if !ok {
+		return nil, repository.ErrSLAPolicyNotFound
+	}
+	stored := *policy
+	return &stored, nil
+}
+
+func (r *fakeSLAPolicyRepo) UpdateSLAPolicy(ctx context.Context, id string, policy *models.SLAPolicy) (*models.SLAPolicy, error) {
+	r.written = append(r.written, id)
+	return policy, nil
+}
+
+func (r *fakeSLAPolicyRepo) DeleteSLAPolicy(ctx context.Context, id string, deletedData *models.DeletedData) error {
+	r.written = append(r.written, id)
+	return nil
+}
+
+func TestSLAPolicyOfOtherOrganization(t *testing.T) {
+	repo := &fakeSLAPolicyRepo{policies: map[string]*models.SLAPolicy{
+		"policy-1": {PolicyID: "policy-1", OrgID: "org-1", Name: "Standard"},
+		"policy-2": {PolicyID: "policy-2", OrgID: "org-2", Name: "Standard"},
+	}}
+	s := &SLAService{slaPolicyRepo: repo, logger: testLogger()}
+	ctx := managerContext()
+
+	if _, err := s.GetSLAPolicy(ctx, "policy-1"); err != nil {
+		t.Fatalf("GetSLAPolicy() of own organization error = %v", err)
+	}
+
+	if _, err := s.GetSLAPolicy(ctx, "policy-2"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("GetSLAPolicy() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+	req := &models.UpdateSLAPolicyRequest{Name: "Premium"}
+	if _, err := s.UpdateSLAPolicy(ctx, "policy-2", req, "manager"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("UpdateSLAPolicy() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+	if err := s.DeleteSLAPolicy(ctx, "policy-2", "manager", "unused"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("DeleteSLAPolicy() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+	if len(repo.written) != 0 {
+		t.Fatalf("policies %v of another organization were written", repo.written)
+	}
+}
+
+func TestEvaluateSLA(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	at := func(hours float64) *time.Time {
+		when := start.Add(time.Duration(hours * float64(time.Hour)))
+		return &when
+	}
+	// Response is due after 4 hours, resolution after 24
+	sla := func(respondedAt, resolvedAt *time.Time) *models.JobSLA {
+		return &models.JobSLA{
+			StartedAt:       start,
+			ResponseDueAt:   *at(4),
+			ResolutionDueAt: *at(24),
+			RespondedAt:     respondedAt,
+			ResolvedAt:      resolvedAt,
+		}
+	}
+
+	tests := []struct {
+		name               string
+		sla                *models.JobSLA
+		now                time.Time
+		want               models.SLAStatus
+		responseBreached   bool
+		resolutionBreached bool
+	}{
+		{name: "on track", sla: sla(nil, nil), now: *at(1), want: models.SLAStatusOnTrack},
+		{name: "response at risk", sla: sla(nil, nil), now: *at(3.5), want: models.SLAStatusAtRisk},
+		{name: "response missed", sla: sla(nil, nil), now: *at(5), want: models.SLAStatusBreached, responseBreached: true},
+		{name: "responded late", sla: sla(at(5), nil), now: *at(6), want: models.SLAStatusBreached, responseBreached: true},
+		{name: "responded in time", sla: sla(at(1), nil), now: *at(6), want: models.SLAStatusOnTrack},
+		{name: "resolution at risk", sla: sla(at(1), nil), now: *at(22), want: models.SLAStatusAtRisk},
+		{name: "resolution missed", sla: sla(at(1), nil), now: *at(25), want: models.SLAStatusBreached, resolutionBreached: true},
+		{name: "met", sla: sla(at(1), at(20)), now: *at(30), want: models.SLAStatusMet},
+		{name: "resolved late", sla: sla(at(1), at(26)), now: *at(30), want: models.SLAStatusBreached, resolutionBreached: true},
+	}
+
+	for _, tt := range tests {
+		got := evaluateSLA(tt.sla, tt.now, 20)
+		if got.Status != tt.want || got.ResponseBreached != tt.responseBreached || got.ResolutionBreached != tt.resolutionBreached {
+			t.Errorf("evaluateSLA(%s) = %s, response breached %t, resolution breached %t, want %s, %t, %t",
+				tt.name, got.Status, got.ResponseBreached, got.ResolutionBreached, tt.want, tt.responseBreached, tt.resolutionBreached)
+		}
+		if tt.sla.Status != "" {
+			t.Errorf("evaluateSLA(%s) changed its argument", tt.name)
+		}
+	}
+}
+
+func TestNearSLATarget(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	due := start.Add(10 * time.Hour)
+	met := start.Add(9 * time.Hour)
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		metAt *time.Time
+		want  bool
+	}{
+		{name: "most time left", now: start.Add(time.Hour), want: false},
+		{name: "just over the threshold", now: start.Add(7*time.Hour + 59*time.Minute), want: false},
+		{name: "at the threshold", now: start.Add(8 * time.Hour), want: true},
+		{name: "past due", now: start.Add(11 * time.Hour), want: true},
+		{name: "already met", now: start.Add(9 * time.Hour), metAt: &met, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := nearSLATarget(start, due, tt.metAt, tt.now, 20); got != tt.want {
+			t.Errorf("nearSLATarget(%s) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSupervisorsOf(t *testing.T) {
+	role := func(level int, orgID string) models.RoleAssignment {
+		return models.RoleAssignment{Level: level, Context: map[string]string{"organization_id": orgID}}
+	}
+	users := []*models.User{
+		{ID: "supervisor", Status: models.UserStatusActive, Roles: []models.RoleAssignment{role(supervisorLevel, "org-1")}},
+		{ID: "manager", Status: models.UserStatusActive, Roles: []models.RoleAssignment{role(1, "org-1"), role(supervisorLevel+1, "org-1")}},
+		{ID: "technician", Status: models.UserStatusActive, Roles: []models.RoleAssignment{role(supervisorLevel-1, "org-1")}},
+		{ID: "inactive", Status: models.UserStatusInactive, Roles: []models.RoleAssignment{role(supervisorLevel, "org-1")}},
+		{ID: "other organization", Status: models.UserStatusActive, Roles: []models.RoleAssignment{role(supervisorLevel, "org-2")}},
+		{ID: "platform admin", Status: models.UserStatusActive, Roles: []models.RoleAssignment{role(10, "*")}},
+		{ID: "unscoped", Status: models.UserStatusActive, Roles: []models.RoleAssignment{{Level: 10}}},
+		{ID: "deleted role", Status: models.UserStatusActive, Roles: []models.RoleAssignment{
+			{Level: supervisorLevel, Context: map[string]string{"organization_id": "org-1"}, DeletedData: &models.DeletedData{}},
+		}},
+	}
+
+	var got []string
+	for _, recipient := range supervisorsOf(users, "org-1") {
+		got = append(got, recipient.UserID)
+	}
+	if want := []string{"supervisor", "manager"}; !slices.Equal(got, want) {
+		t.Fatalf("supervisorsOf() = %v, want %v", got, want)
+	}
+}
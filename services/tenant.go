@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/requestctx"
+	"fmt"
+)
+
+// ErrOrganizationAccessDenied is returned when a request acts on an
+// organization its user does not belong to
+var ErrOrganizationAccessDenied = errors.New("organization access denied")
+
+// tenantContext scopes ctx to the organization orgID, which selects its
+// tables in isolated tenancy mode, once the authenticated user is found to
+// belong to it. Contexts without a user, those of the workers, may act on
+// any organization.
+func tenantContext(ctx context.Context, orgID string) (context.Context, error) {
+	if claims, ok := requestctx.Claims(ctx); ok && !claims.BelongsTo(orgID) {
+		return nil, fmt.Errorf("%w: user is not a member of organization %s", ErrOrganizationAccessDenied, orgID)
+	}
+	return requestctx.WithTenant(ctx, orgID), nil
+}
+
+// userTenantContext scopes ctx to the organization of the authenticated
+// user once both the user and userID, the user a request acts on, are found
+// to belong to it. Requests on the users of other organizations are denied.
+func userTenantContext(ctx context.Context, userRepo repository.UserRepositoryInterface, userID string) (context.Context, error) {
+	orgID := requestctx.TenantID(ctx)
+	if orgID == "" {
+		return nil, fmt.Errorf("%w: no organization to act on user %s in", ErrOrganizationAccessDenied, userID)
+	}
+
+	user, err := userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !belongsToOrganization(user, orgID) {
+		return nil, fmt.Errorf("%w: user %s is not a member of organization %s", ErrOrganizationAccessDenied, userID, orgID)
+	}
+	return tenantContext(ctx, orgID)
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/requestctx"
+	"testing"
+)
+
+func TestTenantContext(t *testing.T) {
+	member := requestctx.WithClaims(context.Background(), &models.JWTClaims{
+		UserID:  "user-1",
+		Context: models.UserContext{OrganizationID: "org-1"},
+	})
+
+	ctx, err := tenantContext(member, "org-1")
+	if err != nil {
+		t.Fatalf("tenantContext() of a member error = %v", err)
+	}
+	if got := requestctx.TenantID(ctx); got != "org-1" {
+		t.Fatalf("TenantID() = %q, want org-1", got)
+	}
+
+	if _, err := tenantContext(member, "org-2"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("tenantContext() of another organization error = %v, want ErrOrganizationAccessDenied", err)
+	}
+
+	// Workers act without a user
+	ctx, err = tenantContext(context.Background(), "org-2")
+	if err != nil {
+		t.Fatalf("tenantContext() without a user error = %v", err)
+	}
+	if got := requestctx.TenantID(ctx); got != "org-2" {
+		t.Fatalf("TenantID() = %q, want org-2", got)
+	}
+}
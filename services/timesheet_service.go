@@ -0,0 +1,560 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTimeClockConflict is returned when a clock action does not fit the
+// technician's running time entry or a timesheet cannot change state
+var ErrTimeClockConflict = errors.New("time tracking conflict")
+
+// ErrTimesheetLocked is returned when time entries of an approved week
+// would change. The time entry repository enforces it on every write.
+var ErrTimesheetLocked = repository.ErrTimesheetLocked
+
+// ErrInvalidTimeEntry is returned for time entry corrections and weeks that
+// cannot be parsed or do not fit together
+var ErrInvalidTimeEntry = errors.New("invalid time entry")
+
+// weekLayout is the format of week start dates
+const weekLayout = "2006-01-02"
+
+type TimesheetService struct {
+	timeEntryRepo    repository.TimeEntryRepositoryInterface
+	timesheetRepo    repository.TimesheetRepositoryInterface
+	jobRepo          repository.JobRepositoryInterface
+	availabilityRepo repository.AvailabilityRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	logger           logger.Logger
+}
+
+func NewTimesheetService(timeEntryRepo repository.TimeEntryRepositoryInterface, timesheetRepo repository.TimesheetRepositoryInterface, jobRepo repository.JobRepositoryInterface, availabilityRepo repository.AvailabilityRepositoryInterface, userRepo repository.UserRepositoryInterface, logger logger.Logger) *TimesheetService {
+	return &TimesheetService{
+		timeEntryRepo:    timeEntryRepo,
+		timesheetRepo:    timesheetRepo,
+		jobRepo:          jobRepo,
+		availabilityRepo: availabilityRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// ClockIn starts a time entry of userID on job jobID. Clocking in to the
+// other category of the running entry on the same job switches over;
+// technicians running an entry on another job must clock out first.
+func (s *TimesheetService) ClockIn(ctx context.Context, jobID string, req *models.ClockInRequest, userID string) (*models.TimeEntry, error) {
+	if req == nil {
+		return nil, errors.New("clock in request is required")
+	}
+
+	ctx, job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.JobStatus == models.JobStatusCompleted || job.JobStatus == models.JobStatusCancelled {
+		return nil, fmt.Errorf("%w: time cannot be tracked on a %s job", ErrTimeClockConflict, job.JobStatus)
+	}
+
+	category := req.Category
+	if category == "" {
+		category = models.TimeEntryLabor
+	}
+
+	running, err := s.timeEntryRepo.GetRunningTimeEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		if running.JobID != job.JobID {
+			return nil, fmt.Errorf("%w: clocked in on job %s, clock out first", ErrTimeClockConflict, running.JobID)
+		}
+		if running.Category == category {
+			return nil, fmt.Errorf("%w: already clocked in for %s", ErrTimeClockConflict, category)
+		}
+	}
+
+	return s.startEntry(ctx, job, userID, category, strings.TrimSpace(req.Notes), running)
+}
+
+// PauseTime ends the running time entry of userID on job jobID until it is
+// resumed
+func (s *TimesheetService) PauseTime(ctx context.Context, jobID string, userID string, notes string) (*models.TimeEntry, error) {
+	return s.endRunning(ctx, jobID, userID, models.TimeEntryPaused, notes)
+}
+
+// ResumeTime starts a new entry in the category of the paused entry of
+// userID on job jobID
+func (s *TimesheetService) ResumeTime(ctx context.Context, jobID string, userID string) (*models.TimeEntry, error) {
+	ctx, job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.timeEntryRepo.GetRunningTimeEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, fmt.Errorf("%w: clocked in on job %s, nothing to resume", ErrTimeClockConflict, running.JobID)
+	}
+
+	entries, err := s.timeEntryRepo.GetTimeEntriesByJob(ctx, job.JobID)
+	if err != nil {
+		return nil, err
+	}
+	var last *models.TimeEntry
+	for _, entry := range entries {
+		if entry.UserID == userID {
+			last = entry
+		}
+	}
+	if last == nil || last.EndReason != models.TimeEntryPaused {
+		return nil, fmt.Errorf("%w: no paused time on this job", ErrTimeClockConflict)
+	}
+
+	return s.startEntry(ctx, job, userID, last.Category, "", nil)
+}
+
+// ClockOut ends the running time entry of userID on job jobID
+func (s *TimesheetService) ClockOut(ctx context.Context, jobID string, userID string, notes string) (*models.TimeEntry, error) {
+	return s.endRunning(ctx, jobID, userID, models.TimeEntryClockedOut, notes)
+}
+
+// startEntry ends running, when given, and starts an entry of userID on job
+// now, provided the current week of the technician is not locked
+func (s *TimesheetService) startEntry(ctx context.Context, job *models.Job, userID string, category models.TimeEntryCategory, notes string, running *models.TimeEntry) (*models.TimeEntry, error) {
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository rejects entries of an approved week atomically; checking
+	// first keeps a locked week from ending the running entry
+	now := time.Now().UTC().Truncate(time.Second)
+	weekStart := weekStartOf(now, loc)
+	if err := s.checkUnlocked(ctx, userID, weekStart); err != nil {
+		return nil, err
+	}
+
+	if running != nil {
+		if _, err := s.endEntry(ctx, running, now, models.TimeEntrySwitched, "", userID); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := s.timeEntryRepo.CreateTimeEntry(ctx, &models.TimeEntry{
+		JobID:     job.JobID,
+		OrgID:     job.OrgID,
+		UserID:    userID,
+		Category:  category,
+		StartedAt: now,
+		WeekStart: weekStart,
+		Notes:     notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("%s clocked in for %s on job %s", userID, category, job.JobID)
+	return entry, nil
+}
+
+// endRunning ends the running time entry of userID on job jobID for reason
+func (s *TimesheetService) endRunning(ctx context.Context, jobID string, userID string, reason models.TimeEntryEndReason, notes string) (*models.TimeEntry, error) {
+	if len(notes) > 500 {
+		return nil, errors.New("notes must be less than 500 characters")
+	}
+
+	ctx, job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.timeEntryRepo.GetRunningTimeEntry(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil || running.JobID != job.JobID {
+		return nil, fmt.Errorf("%w: not clocked in on this job", ErrTimeClockConflict)
+	}
+
+	entry, err := s.endEntry(ctx, running, time.Now().UTC().Truncate(time.Second), reason, strings.TrimSpace(notes), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("%s stopped %s on job %s (%s, %d minutes)", userID, entry.Category, job.JobID, reason, entry.Minutes)
+	return entry, nil
+}
+
+// endEntry ends entry at end
+func (s *TimesheetService) endEntry(ctx context.Context, entry *models.TimeEntry, end time.Time, reason models.TimeEntryEndReason, notes string, actor string) (*models.TimeEntry, error) {
+	ended := *entry
+	ended.EndedAt = &end
+	ended.EndReason = reason
+	ended.Minutes = entryMinutes(entry.StartedAt, end)
+	if notes != "" {
+		ended.Notes = notes
+	}
+	return s.timeEntryRepo.EndTimeEntry(ctx, &ended, actor)
+}
+
+// GetJobTimeEntries returns the time entries of job jobID in start order
+func (s *TimesheetService) GetJobTimeEntries(ctx context.Context, jobID string) ([]*models.TimeEntry, error) {
+	ctx, job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeEntryRepo.GetTimeEntriesByJob(ctx, job.JobID)
+}
+
+// UpdateTimeEntry corrects the category, times or notes of a time entry.
+// Setting the end of a running entry clocks it out. Neither the entry's
+// current week nor the week it moves to may be approved.
+func (s *TimesheetService) UpdateTimeEntry(ctx context.Context, entryID string, req *models.UpdateTimeEntryRequest, updatedBy string) (*models.TimeEntry, error) {
+	if req == nil {
+		return nil, errors.New("time entry request is required")
+	}
+
+	entry, err := s.timeEntryRepo.GetTimeEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	// The job must be visible to the caller's organization
+	ctx, _, err = s.getJob(ctx, entry.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *entry
+	if req.Category != "" {
+		updated.Category = req.Category
+	}
+	if req.Notes != "" {
+		updated.Notes = strings.TrimSpace(req.Notes)
+	}
+	if req.StartedAt != "" {
+		startedAt, err := time.Parse(time.RFC3339, req.StartedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startedAt %q is not an RFC 3339 time", ErrInvalidTimeEntry, req.StartedAt)
+		}
+		updated.StartedAt = startedAt.UTC().Truncate(time.Second)
+	}
+	if req.EndedAt != "" {
+		endedAt, err := time.Parse(time.RFC3339, req.EndedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: endedAt %q is not an RFC 3339 time", ErrInvalidTimeEntry, req.EndedAt)
+		}
+		endedAt = endedAt.UTC().Truncate(time.Second)
+		updated.EndedAt = &endedAt
+		if updated.EndReason == "" {
+			updated.EndReason = models.TimeEntryClockedOut
+		}
+	}
+
+	now := time.Now().UTC()
+	if updated.StartedAt.After(now) {
+		return nil, fmt.Errorf("%w: startedAt is in the future", ErrInvalidTimeEntry)
+	}
+	if updated.EndedAt != nil {
+		if updated.EndedAt.After(now) {
+			return nil, fmt.Errorf("%w: endedAt is in the future", ErrInvalidTimeEntry)
+		}
+		if !updated.EndedAt.After(updated.StartedAt) {
+			return nil, fmt.Errorf("%w: endedAt must be after startedAt", ErrInvalidTimeEntry)
+		}
+		updated.Minutes = entryMinutes(updated.StartedAt, *updated.EndedAt)
+	}
+
+	loc, err := s.userLocation(ctx, entry.UserID)
+	if err != nil {
+		return nil, err
+	}
+	updated.WeekStart = weekStartOf(updated.StartedAt, loc)
+
+	// Fails with ErrTimesheetLocked when the old or the new week is approved
+	saved, err := s.timeEntryRepo.UpdateTimeEntry(ctx, &updated, entry.WeekStart, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Time entry %s of %s corrected by %s", entryID, entry.UserID, updatedBy)
+	return saved, nil
+}
+
+// GetTimesheet aggregates the time entries of userID for the week containing
+// week (YYYY-MM-DD, the current week when empty) in the technician's timezone
+func (s *TimesheetService) GetTimesheet(ctx context.Context, userID string, week string) (*models.TimesheetSummary, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	ctx, err := userTenantContext(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	weekStart, err := parseWeek(week, loc)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTimesheet(ctx, userID, weekStart, loc)
+}
+
+// ApproveTimesheet approves and locks the week containing week of userID.
+// The week must be over and no entry of it may be running; technicians
+// cannot approve their own week.
+func (s *TimesheetService) ApproveTimesheet(ctx context.Context, userID string, week string, approvedBy string) (*models.TimesheetSummary, error) {
+	if userID == approvedBy {
+		return nil, fmt.Errorf("%w: timesheets cannot be approved by their own technician", ErrTimeClockConflict)
+	}
+
+	ctx, err := userTenantContext(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	weekStart, err := parseWeek(week, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	start, _ := time.ParseInLocation(weekLayout, weekStart, loc)
+	if time.Now().Before(start.AddDate(0, 0, 7)) {
+		return nil, fmt.Errorf("%w: the week of %s is not over yet", ErrTimeClockConflict, weekStart)
+	}
+
+	summary, err := s.buildTimesheet(ctx, userID, weekStart, loc)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Status == models.TimesheetApproved {
+		return nil, fmt.Errorf("%w: the week of %s is already approved", ErrTimesheetLocked, weekStart)
+	}
+	if summary.RunningEntries > 0 {
+		return nil, fmt.Errorf("%w: %d time entries of the week are still running", ErrTimeClockConflict, summary.RunningEntries)
+	}
+
+	now := time.Now().UTC()
+	timesheet := summary.Timesheet
+	timesheet.Status = models.TimesheetApproved
+	timesheet.ApprovedBy = approvedBy
+	timesheet.ApprovedAt = &now
+	if _, err := s.timesheetRepo.PutTimesheet(ctx, &timesheet); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Timesheet of %s for week %s approved by %s", userID, weekStart, approvedBy)
+	summary.Timesheet = timesheet
+	return summary, nil
+}
+
+// ReopenTimesheet unlocks the approved week containing week of userID so
+// its entries can be corrected
+func (s *TimesheetService) ReopenTimesheet(ctx context.Context, userID string, week string, req *models.ReopenTimesheetRequest, reopenedBy string) (*models.TimesheetSummary, error) {
+	if req == nil || strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w to reopen a timesheet", ErrStatusReasonRequired)
+	}
+
+	ctx, err := userTenantContext(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	weekStart, err := parseWeek(week, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.timesheetRepo.GetTimesheet(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet == nil || timesheet.Status != models.TimesheetApproved {
+		return nil, fmt.Errorf("%w: the week of %s is not approved", ErrTimeClockConflict, weekStart)
+	}
+
+	now := time.Now().UTC()
+	timesheet.Status = models.TimesheetOpen
+	timesheet.ReopenedBy = reopenedBy
+	timesheet.ReopenedAt = &now
+	timesheet.ReopenReason = strings.TrimSpace(req.Reason)
+	if _, err := s.timesheetRepo.PutTimesheet(ctx, timesheet); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Timesheet of %s for week %s reopened by %s", userID, weekStart, reopenedBy)
+	return s.buildTimesheet(ctx, userID, weekStart, loc)
+}
+
+// buildTimesheet totals the entries of userID started in the week beginning
+// on weekStart per day and per job. Running entries are not counted.
+func (s *TimesheetService) buildTimesheet(ctx context.Context, userID string, weekStart string, loc *time.Location) (*models.TimesheetSummary, error) {
+	timesheet, err := s.timesheetRepo.GetTimesheet(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet == nil {
+		timesheet = &models.Timesheet{
+			UserID:    userID,
+			WeekStart: weekStart,
+			Status:    models.TimesheetOpen,
+		}
+	}
+
+	entries, err := s.timeEntryRepo.GetTimeEntriesByWeek(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	start, _ := time.ParseInLocation(weekLayout, weekStart, loc)
+	summary := &models.TimesheetSummary{
+		WeekEnd:  start.AddDate(0, 0, 6).Format(weekLayout),
+		Timezone: loc.String(),
+		Days:     make([]models.TimesheetDay, 7),
+		Jobs:     []models.TimesheetJob{},
+		Entries:  entries,
+	}
+	if summary.Entries == nil {
+		summary.Entries = []*models.TimeEntry{}
+	}
+	for i := range summary.Days {
+		summary.Days[i].Date = start.AddDate(0, 0, i).Format(weekLayout)
+	}
+
+	// Totals of an approved week are those it was approved with
+	approved := timesheet.Status == models.TimesheetApproved
+	if !approved {
+		timesheet.LaborMinutes = 0
+		timesheet.TravelMinutes = 0
+	}
+
+	jobIndex := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Running() {
+			summary.RunningEntries++
+			continue
+		}
+
+		// Entries count towards the day they started on. Entries stored
+		// before the technician changed timezone go by weekday.
+		started := entry.StartedAt.In(loc)
+		day := (int(started.Weekday()) + 6) % 7
+		for i := range summary.Days {
+			if summary.Days[i].Date == started.Format(weekLayout) {
+				day = i
+				break
+			}
+		}
+
+		index, ok := jobIndex[entry.JobID]
+		if !ok {
+			index = len(summary.Jobs)
+			jobIndex[entry.JobID] = index
+			summary.Jobs = append(summary.Jobs, models.TimesheetJob{JobID: entry.JobID})
+		}
+
+		switch entry.Category {
+		case models.TimeEntryTravel:
+			summary.Days[day].TravelMinutes += entry.Minutes
+			summary.Jobs[index].TravelMinutes += entry.Minutes
+			if !approved {
+				timesheet.TravelMinutes += entry.Minutes
+			}
+		default:
+			summary.Days[day].LaborMinutes += entry.Minutes
+			summary.Jobs[index].LaborMinutes += entry.Minutes
+			if !approved {
+				timesheet.LaborMinutes += entry.Minutes
+			}
+		}
+	}
+
+	summary.Timesheet = *timesheet
+	summary.TotalMinutes = timesheet.LaborMinutes + timesheet.TravelMinutes
+	return summary, nil
+}
+
+// checkUnlocked returns ErrTimesheetLocked when the week of userID beginning
+// on weekStart is approved
+func (s *TimesheetService) checkUnlocked(ctx context.Context, userID string, weekStart string) error {
+	timesheet, err := s.timesheetRepo.GetTimesheet(ctx, userID, weekStart)
+	if err != nil {
+		return err
+	}
+	if timesheet != nil && timesheet.Status == models.TimesheetApproved {
+		return fmt.Errorf("%w: the week of %s was approved", ErrTimesheetLocked, weekStart)
+	}
+	return nil
+}
+
+// userLocation returns the timezone of the working hours of userID, or UTC
+// when none are set
+func (s *TimesheetService) userLocation(ctx context.Context, userID string) (*time.Location, error) {
+	availability, err := s.availabilityRepo.GetAvailability(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if availability == nil {
+		return time.UTC, nil
+	}
+	return availability.Location(), nil
+}
+
+// getJob returns job jobID and ctx scoped to its organization, which the
+// authenticated user must belong to
+func (s *TimesheetService) getJob(ctx context.Context, jobID string) (context.Context, *models.Job, error) {
+	jobs, err := s.jobRepo.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil, repository.ErrJobNotFound
+	}
+	ctx, err = tenantContext(ctx, jobs[0].OrgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, jobs[0], nil
+}
+
+// weekStartOf returns the Monday of the week containing t in loc
+func weekStartOf(t time.Time, loc *time.Location) string {
+	local := t.In(loc)
+	offset := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc).Format(weekLayout)
+}
+
+// parseWeek returns the Monday of the week containing the date week, or of
+// the current week when week is empty
+func parseWeek(week string, loc *time.Location) (string, error) {
+	if week == "" {
+		return weekStartOf(time.Now(), loc), nil
+	}
+	date, err := time.ParseInLocation(weekLayout, week, loc)
+	if err != nil {
+		return "", fmt.Errorf("%w: week %q is not a YYYY-MM-DD date", ErrInvalidTimeEntry, week)
+	}
+	return weekStartOf(date, loc), nil
+}
+
+// entryMinutes returns the duration from start to end in whole minutes
+func entryMinutes(start, end time.Time) int {
+	return int(end.Sub(start).Round(time.Minute) / time.Minute)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/requestctx"
+	"testing"
+	"time"
+)
+
+// fakeTimeEntryRepo answers GetTimeEntry from entries
+type fakeTimeEntryRepo struct {
+	repository.TimeEntryRepositoryInterface
+	entries map[string]*models.TimeEntry
+}
+
+func (r *fakeTimeEntryRepo) GetTimeEntry(ctx context.Context, id string) (*models.TimeEntry, error) {
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, repository.ErrTimeEntryNotFound
+	}
+	stored := *entry
+	return &stored, nil
+}
+
+func (r *fakeTimeEntryRepo) GetTimeEntriesByWeek(ctx context.Context, userID, weekStart string) ([]*models.TimeEntry, error) {
+	var entries []*models.TimeEntry
+	for _, entry := range r.entries {
+		if entry.UserID == userID && entry.WeekStart == weekStart {
+			stored := *entry
+			entries = append(entries, &stored)
+		}
+	}
+	return entries, nil
+}
+
+// fakeTimesheetRepo keeps timesheets in memory
+type fakeTimesheetRepo struct {
+	repository.TimesheetRepositoryInterface
+	timesheets map[string]*models.Timesheet
+}
+
+func (r *fakeTimesheetRepo) GetTimesheet(ctx context.Context, userID, weekStart string) (*models.Timesheet, error) {
+	timesheet, ok := r.timesheets[userID+"/"+weekStart]
+	if !ok {
+		return nil, nil
+	}
+	stored := *timesheet
+	return &stored, nil
+}
+
+// newTimesheetService returns a service for the technicians tech-1 of org-1
+// and tech-2 of org-2
+func newTimesheetService(jobs ...*models.Job) *TimesheetService {
+	return &TimesheetService{
+		timeEntryRepo: &fakeTimeEntryRepo{entries: map[string]*models.TimeEntry{
+			"entry-2": {EntryID: "entry-2", JobID: "job-2", OrgID: "org-2", UserID: "tech-2", StartedAt: time.Now().Add(-time.Hour)},
+		}},
+		timesheetRepo:    &fakeTimesheetRepo{timesheets: map[string]*models.Timesheet{}},
+		jobRepo:          newFakeJobRepo(jobs...),
+		availabilityRepo: &fakeAvailabilityRepo{},
+		userRepo: &fakeUserRepo{users: map[string]*models.User{
+			"tech-1": {ID: "tech-1", Roles: []models.RoleAssignment{{RoleName: "technician", Context: map[string]string{"organization_id": "org-1"}}}},
+			"tech-2": {ID: "tech-2", Roles: []models.RoleAssignment{{RoleName: "technician", Context: map[string]string{"organization_id": "org-2"}}}},
+		}},
+		logger: testLogger(),
+	}
+}
+
+// managerContext returns the context of a request by a manager of org-1
+func managerContext() context.Context {
+	return requestctx.WithClaims(context.Background(), &models.JWTClaims{
+		UserID:  "manager",
+		Context: models.UserContext{OrganizationID: "org-1"},
+	})
+}
+
+func TestTimesheetsOfOtherOrganizations(t *testing.T) {
+	s := newTimesheetService()
+	ctx := managerContext()
+
+	if _, err := s.GetTimesheet(ctx, "tech-1", "2026-03-02"); err != nil {
+		t.Fatalf("GetTimesheet() of a member error = %v", err)
+	}
+
+	if _, err := s.GetTimesheet(ctx, "tech-2", "2026-03-02"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("GetTimesheet() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+	if _, err := s.ApproveTimesheet(ctx, "tech-2", "2026-03-02", "manager"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("ApproveTimesheet() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+	req := &models.ReopenTimesheetRequest{Reason: "missing travel"}
+	if _, err := s.ReopenTimesheet(ctx, "tech-2", "2026-03-02", req, "manager"); !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("ReopenTimesheet() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+}
+
+func TestUpdateTimeEntryOfOtherOrganization(t *testing.T) {
+	job := &models.Job{JobID: "job-2", OrgID: "org-2", JobStatus: models.JobStatusInProgress}
+	s := newTimesheetService(job)
+
+	_, err := s.UpdateTimeEntry(managerContext(), "entry-2", &models.UpdateTimeEntryRequest{Notes: "corrected"}, "manager")
+	if !errors.Is(err, ErrOrganizationAccessDenied) {
+		t.Fatalf("UpdateTimeEntry() error = %v, want ErrOrganizationAccessDenied", err)
+	}
+}
@@ -12,20 +12,18 @@ import (
 )
 
 type UserService struct {
-	ctx    context.Context
 	repo   repository.UserRepositoryInterface
 	logger logger.Logger
 }
 
-func NewUserService(ctx context.Context, repo repository.UserRepositoryInterface, logger logger.Logger) *UserService {
+func NewUserService(repo repository.UserRepositoryInterface, logger logger.Logger) *UserService {
 	return &UserService{
-		ctx:    ctx,
 		repo:   repo,
 		logger: logger,
 	}
 }
 
-func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
+func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	if err := s.validateCreateUser(user); err != nil {
 		return nil, err
 	}
@@ -36,65 +34,38 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
 	user.FirstName = strings.TrimSpace(user.FirstName)
 	user.LastName = strings.TrimSpace(user.LastName)
 
-	return s.repo.CreateUser(s.ctx, user)
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *UserService) GetUsers() ([]*models.User, error) {
-	return s.repo.GetUser("")
+func (s *UserService) GetUsers(ctx context.Context) ([]*models.User, error) {
+	return s.repo.GetUsers(ctx)
 }
 
-func (s *UserService) GetUserByID(id string) (*models.User, error) {
+func (s *UserService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, errors.New("user ID is required")
 	}
 
-	users, err := s.repo.GetUser(id)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return nil, errors.New("user not found")
-	}
-
-	return users[0], nil
+	return s.repo.GetUserByID(ctx, id)
 }
 
-func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	if strings.TrimSpace(email) == "" {
 		return nil, errors.New("email is required")
 	}
 
-	users, err := s.repo.GetUser(email)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return nil, errors.New("user not found")
-	}
-
-	return users[0], nil
+	return s.repo.GetUserByEmail(ctx, email)
 }
 
-func (s *UserService) GetUserByUsername(username string) (*models.User, error) {
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	if strings.TrimSpace(username) == "" {
 		return nil, errors.New("username is required")
 	}
 
-	users, err := s.repo.GetUser(username)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return nil, errors.New("user not found")
-	}
-
-	return users[0], nil
+	return s.repo.GetUserByUsername(ctx, username)
 }
 
-func (s *UserService) UpdateUser(id string, user *models.User) (*models.User, error) {
+func (s *UserService) UpdateUser(ctx context.Context, id string, user *models.User) (*models.User, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, errors.New("user ID is required")
 	}
@@ -111,10 +82,10 @@ func (s *UserService) UpdateUser(id string, user *models.User) (*models.User, er
 		user.LastName = strings.TrimSpace(user.LastName)
 	}
 
-	return s.repo.UpdateUser(id, user)
+	return s.repo.UpdateUser(ctx, id, user)
 }
 
-func (s *UserService) AssignRolesToUser(userID string, roleAssignments []models.RoleAssignment) (*models.User, error) {
+func (s *UserService) AssignRolesToUser(ctx context.Context, userID string, roleAssignments []models.RoleAssignment) (*models.User, error) {
 	if strings.TrimSpace(userID) == "" {
 		return nil, errors.New("user ID is required")
 	}
@@ -123,10 +94,10 @@ func (s *UserService) AssignRolesToUser(userID string, roleAssignments []models.
 		return nil, errors.New("at least one role assignment is required")
 	}
 
-	return s.repo.AssignRoles(s.ctx, userID, roleAssignments)
+	return s.repo.AssignRoles(ctx, userID, roleAssignments)
 }
 
-func (s *UserService) AddRoleToUser(userID string, roleAssignment models.RoleAssignment) (*models.User, error) {
+func (s *UserService) AddRoleToUser(ctx context.Context, userID string, roleAssignment models.RoleAssignment) (*models.User, error) {
 	if strings.TrimSpace(userID) == "" {
 		return nil, errors.New("user ID is required")
 	}
@@ -135,40 +106,40 @@ func (s *UserService) AddRoleToUser(userID string, roleAssignment models.RoleAss
 		return nil, err
 	}
 
-	return s.repo.AddRoleToUser(s.ctx, userID, roleAssignment)
+	return s.repo.AddRoleToUser(ctx, userID, roleAssignment)
 }
 
-func (s *UserService) AssignRoleToUser(userID, roleID string) (*models.User, error) {
+func (s *UserService) AssignRoleToUser(ctx context.Context, userID, roleID string) (*models.User, error) {
 	if strings.TrimSpace(userID) == "" {
 		return nil, errors.New("user ID is required")
 	}
 
 	if strings.TrimSpace(roleID) == "" {
-		return nil, errors.New("role ID is required")
+		return nil, ErrRoleIDRequired
 	}
 
-	return s.repo.AssignRoleToUser(s.ctx, userID, roleID)
+	return s.repo.AssignRoleToUser(ctx, userID, roleID)
 }
 
-func (s *UserService) RemoveRoleFromUser(userID, roleID string) (*models.User, error) {
+func (s *UserService) RemoveRoleFromUser(ctx context.Context, userID, roleID string) (*models.User, error) {
 	if strings.TrimSpace(userID) == "" {
 		return nil, errors.New("user ID is required")
 	}
 
 	if strings.TrimSpace(roleID) == "" {
-		return nil, errors.New("role ID is required")
+		return nil, ErrRoleIDRequired
 	}
 
-	return s.repo.RemoveRoleFromUser(s.ctx, userID, roleID)
+	return s.repo.RemoveRoleFromUser(ctx, userID, roleID)
 }
 
-func (s *UserService) GetUsersByStatus(status models.UserStatus) ([]*models.User, error) {
+func (s *UserService) GetUsersByStatus(ctx context.Context, status models.UserStatus) ([]*models.User, error) {
 	if status == "" {
 		return nil, errors.New("status is required")
 	}
 
 	// Get all users and filter by status
-	users, err := s.repo.GetUser("")
+	users, err := s.repo.GetUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -282,7 +253,7 @@ func (s *UserService) validateRoleAssignment(roleAssignment *models.RoleAssignme
 	}
 
 	if strings.TrimSpace(roleAssignment.RoleID) == "" {
-		return errors.New("role ID is required")
+		return ErrRoleIDRequired
 	}
 
 	if strings.TrimSpace(roleAssignment.RoleName) == "" {
@@ -0,0 +1,105 @@
+// Package blob stores uploaded files such as job photos and documents behind
+// the BlobStore interface, on the local filesystem or in an S3-compatible
+// object store. Files are handed out through short-lived signed URLs rather
+// than served by the API directly.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// ErrNotFound is returned for keys that hold no blob
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned for empty keys and keys that would escape the
+// store, such as ones containing ".."
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore stores blobs under slash-separated keys
+type BlobStore interface {
+	// Put stores size bytes read from body under key, replacing any blob
+	// already there
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get opens the blob stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL the blob under key can be downloaded from
+	// without further authentication until expiry has passed
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ValidateKey checks that key is a relative slash-separated path without
+// empty, "." or ".." segments
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
+
+// New creates the store selected by cfg.BlobBackend. Local signed URLs point
+// at basePath/blobs unless cfg.BlobBaseURL is set and are signed with the
+// blob signing secret, falling back to the JWT secret. The S3 store uses its
+// own credentials when configured and the AWS credential chain otherwise.
+func New(ctx context.Context, cfg *models.Config) (BlobStore, error) {
+	switch cfg.BlobBackend {
+	case models.BlobBackendLocal:
+		baseURL := cfg.BlobBaseURL
+		if baseURL == "" {
+			baseURL = strings.TrimSuffix(cfg.BasePath, "/") + "/blobs"
+		}
+		secret := cfg.BlobSigningSecret
+		if secret == "" {
+			secret = cfg.JWTSecret
+		}
+		return NewLocalStore(cfg.BlobLocalPath, strings.TrimSuffix(baseURL, "/"), []byte(secret))
+
+	case models.BlobBackendS3:
+		region := cfg.BlobS3Region
+		if region == "" {
+			region = cfg.AWSRegion
+		}
+
+		var provider aws.CredentialsProvider
+		if cfg.BlobS3AccessKeyID != "" && cfg.BlobS3SecretAccessKey != "" {
+			provider = credentials.NewStaticCredentialsProvider(cfg.BlobS3AccessKeyID, cfg.BlobS3SecretAccessKey, "")
+		} else if cfg.AWSAccessKeyID != "" && cfg.AWSSecretAccessKey != "" {
+			provider = credentials.NewStaticCredentialsProvider(cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey, "")
+		} else {
+			awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config: %w", err)
+			}
+			provider = awsCfg.Credentials
+		}
+
+		return NewS3Store(S3Options{
+			Endpoint:    cfg.BlobS3Endpoint,
+			Bucket:      cfg.BlobS3Bucket,
+			Region:      region,
+			PathStyle:   cfg.BlobS3PathStyle,
+			Credentials: aws.NewCredentialsCache(provider),
+		})
+	}
+	return nil, fmt.Errorf("unknown blob backend %q", cfg.BlobBackend)
+}
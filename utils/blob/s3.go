@@ -0,0 +1,226 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// unsignedPayload skips hashing request bodies; S3 accepts it over HTTPS and
+// for presigned URLs
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Options configures an S3Store
+type S3Options struct {
+	Endpoint    string // e.g. https://s3.eu-west-1.amazonaws.com or a MinIO URL
+	Bucket      string
+	Region      string
+	PathStyle   bool // Address the bucket in the path instead of the host name
+	Credentials aws.CredentialsProvider
+	HTTPClient  *http.Client
+}
+
+// S3Store keeps blobs in a bucket of Amazon S3 or an S3-compatible service.
+// Requests are signed with SigV4 and downloads use presigned GET URLs.
+type S3Store struct {
+	endpoint    *url.URL
+	bucket      string
+	region      string
+	pathStyle   bool
+	credentials aws.CredentialsProvider
+	client      *http.Client
+	signer      *v4.Signer
+}
+
+// NewS3Store creates a store for the bucket described by opts. An empty
+// endpoint means Amazon S3 in opts.Region.
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("blob bucket is required")
+	}
+	if opts.Region == "" {
+		return nil, errors.New("blob region is required")
+	}
+	if opts.Credentials == nil {
+		return nil, errors.New("blob credentials are required")
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid blob endpoint %q", endpoint)
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+
+	return &S3Store{
+		endpoint:    parsed,
+		bucket:      opts.Bucket,
+		region:      opts.Region,
+		pathStyle:   opts.PathStyle,
+		credentials: opts.Credentials,
+		client:      client,
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 signs the path as sent rather than escaping it again
+			o.DisableURIPathEscaping = true
+		}),
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// SignedURL presigns a GET of key valid for expiry, at most the seven days
+// S3 allows
+func (s *S3Store) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if expiry > 7*24*time.Hour {
+		expiry = 7 * 24 * time.Hour
+	}
+	query := req.URL.Query()
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiry/time.Second), 10))
+	req.URL.RawQuery = query.Encode()
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve blob credentials: %w", err)
+	}
+
+	signed, _, err := s.signer.PresignHTTP(ctx, credentials, req, unsignedPayload, "s3", s.region, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to presign blob URL: %w", err)
+	}
+	return signed, nil
+}
+
+// newRequest builds an unsigned request for key
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	target := *s.endpoint
+	escaped := escapeKey(key)
+	if s.pathStyle {
+		target.Path = "/" + s.bucket + "/" + key
+		target.RawPath = "/" + s.bucket + "/" + escaped
+	} else {
+		target.Host = s.bucket + "." + target.Host
+		target.Path = "/" + key
+		target.RawPath = "/" + escaped
+	}
+
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+// do signs and sends req, turning error responses into errors
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	credentials, err := s.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve blob credentials: %w", err)
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	if err := s.signer.SignHTTP(req.Context(), credentials, req, unsignedPayload, "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign blob request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("blob store returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
+
+// escapeKey percent-encodes every segment of key except the unreserved
+// characters, as SigV4 canonical paths require
+func escapeKey(key string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&15])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+// Package cache provides a two-tier read-through cache: an in-process LRU
+// with TTL in front of an optional shared backend such as Redis or
+// Memcached, plus hit/miss metrics.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fieldfuze-backend/utils/logger"
+	"sync/atomic"
+	"time"
+)
+
+// Store is a shared cache backend used behind the in-process LRU so that
+// several instances see each other's invalidations. Values are the JSON
+// encoding of the cached type, so fields tagged json:"-" are not shared.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Stats reports cache effectiveness
+type Stats struct {
+	Name       string `json:"name"`
+	Hits       int64  `json:"hits"`
+	SharedHits int64  `json:"shared_hits"`
+	Misses     int64  `json:"misses"`
+	Evictions  int64  `json:"evictions"`
+	Size       int    `json:"size"`
+}
+
+// HitRatio returns the share of lookups served from either tier
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Cache is a typed two-tier cache
+type Cache[T any] struct {
+	name   string
+	ttl    time.Duration
+	local  *LRU
+	shared Store
+	logger logger.Logger
+
+	hits       int64
+	sharedHits int64
+	misses     int64
+}
+
+// New creates a cache holding up to capacity entries locally for ttl. shared
+// may be nil for a purely in-process cache.
+func New[T any](name string, capacity int, ttl time.Duration, shared Store, log logger.Logger) *Cache[T] {
+	return &Cache[T]{
+		name:   name,
+		ttl:    ttl,
+		local:  NewLRU(capacity),
+		shared: shared,
+		logger: log,
+	}
+}
+
+// Get returns the value cached under key, consulting the shared backend on a
+// local miss. Backend failures are logged and treated as misses.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool) {
+	if value, ok := c.local.Get(key); ok {
+		atomic.AddInt64(&c.hits, 1)
+		return value.(T), true
+	}
+
+	var zero T
+	if c.shared == nil {
+		atomic.AddInt64(&c.misses, 1)
+		return zero, false
+	}
+
+	data, ok, err := c.shared.Get(ctx, c.sharedKey(key))
+	if err != nil {
+		c.logger.Warnf("Shared cache %s get failed for %s: %v", c.name, key, err)
+	}
+	if err != nil || !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return zero, false
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		c.logger.Warnf("Shared cache %s holds an invalid entry for %s: %v", c.name, key, err)
+		atomic.AddInt64(&c.misses, 1)
+		return zero, false
+	}
+
+	c.local.Set(key, value, c.ttl)
+	atomic.AddInt64(&c.hits, 1)
+	atomic.AddInt64(&c.sharedHits, 1)
+	return value, true
+}
+
+// Peek returns the value held locally under key without touching the shared
+// backend or the metrics
+func (c *Cache[T]) Peek(key string) (T, bool) {
+	if value, ok := c.local.Get(key); ok {
+		return value.(T), true
+	}
+	var zero T
+	return zero, false
+}
+
+// Set stores value under key in both tiers
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) {
+	c.local.Set(key, value, c.ttl)
+
+	if c.shared == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Warnf("Failed to encode %s for shared cache %s: %v", key, c.name, err)
+		return
+	}
+	if err := c.shared.Set(ctx, c.sharedKey(key), data, c.ttl); err != nil {
+		c.logger.Warnf("Shared cache %s set failed for %s: %v", c.name, key, err)
+	}
+}
+
+// Delete removes keys from both tiers
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	c.local.Delete(keys...)
+
+	if c.shared == nil {
+		return
+	}
+
+	sharedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		sharedKeys[i] = c.sharedKey(key)
+	}
+	if err := c.shared.Delete(ctx, sharedKeys...); err != nil {
+		c.logger.Warnf("Shared cache %s delete failed for %v: %v", c.name, keys, err)
+	}
+}
+
+// Clear empties the in-process tier. Shared entries expire through their TTL.
+func (c *Cache[T]) Clear() {
+	c.local.Clear()
+}
+
+// Stats returns the cache metrics
+func (c *Cache[T]) Stats() Stats {
+	return Stats{
+		Name:       c.name,
+		Hits:       atomic.LoadInt64(&c.hits),
+		SharedHits: atomic.LoadInt64(&c.sharedHits),
+		Misses:     atomic.LoadInt64(&c.misses),
+		Evictions:  c.local.Evictions(),
+		Size:       c.local.Len(),
+	}
+}
+
+// sharedKey namespaces key by cache name in the shared backend
+func (c *Cache[T]) sharedKey(key string) string {
+	return c.name + ":" + key
+}
@@ -0,0 +1,60 @@
+// Package geo measures distances between coordinates and looks up the
+// coordinates of postal addresses through the Geocoder interface
+package geo
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"math"
+	"net/http"
+	"time"
+)
+
+// earthRadiusMeters is the mean radius of the earth
+const earthRadiusMeters = 6371008.8
+
+// ErrAddressNotFound is returned when a geocoder knows no coordinates for an
+// address
+var ErrAddressNotFound = errors.New("address not found")
+
+// Point is a position in decimal degrees
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Valid reports whether p lies within the range of latitudes and longitudes
+func (p Point) Valid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// Distance returns the great-circle distance between a and b in meters
+func Distance(a, b Point) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Geocoder looks up the coordinates of addresses
+type Geocoder interface {
+	// Geocode returns the coordinates of address, or ErrAddressNotFound
+	Geocode(ctx context.Context, address string) (Point, error)
+}
+
+// New creates the geocoder configured by cfg. It returns nil when no
+// geocoder URL is set; job sites then need explicit coordinates.
+func New(cfg *models.Config) Geocoder {
+	if cfg.GeocoderURL == "" {
+		return nil
+	}
+	return &NominatimGeocoder{
+		baseURL:   cfg.GeocoderURL,
+		userAgent: cfg.GeocoderUserAgent,
+		client:    &http.Client{Timeout: time.Duration(cfg.GeocoderTimeoutMs) * time.Millisecond},
+	}
+}
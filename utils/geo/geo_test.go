@@ -0,0 +1,56 @@
+package geo
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	paris := Point{Latitude: 48.8566, Longitude: 2.3522}
+	london := Point{Latitude: 51.5074, Longitude: -0.1278}
+
+	tests := []struct {
+		name string
+		a, b Point
+		want float64 // Meters
+	}{
+		{name: "Paris to London", a: paris, b: london, want: 343_557},
+		{name: "London to Paris", a: london, b: paris, want: 343_557},
+		{name: "one degree along the equator", a: Point{0, 0}, b: Point{0, 1}, want: 111_195},
+		{name: "same point", a: paris, b: paris, want: 0},
+		{name: "antipodes", a: Point{0, 0}, b: Point{0, 180}, want: math.Pi * earthRadiusMeters},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+			t.Errorf("Distance(%s) = %.0f m, want %.0f m", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGreatCircleMatrix(t *testing.T) {
+	m := &GreatCircleMatrix{speedKmh: 36, detourFactor: 1.5}
+	points := []Point{{0, 0}, {0, 1}}
+
+	legs, err := m.Matrix(context.Background(), points)
+	if err != nil {
+		t.Fatalf("Matrix() error = %v", err)
+	}
+	if legs[0][0] != (Leg{}) {
+		t.Fatalf("leg to the same point = %+v, want none", legs[0][0])
+	}
+
+	// 111.2 km stretched to 166.8 km and driven at 10 m/s
+	leg := legs[0][1]
+	if math.Abs(leg.Meters-166_793) > 1 {
+		t.Fatalf("Meters = %.0f, want 166793", leg.Meters)
+	}
+	if want := 16_679 * time.Second; leg.Duration.Round(time.Second) != want {
+		t.Fatalf("Duration = %v, want %v", leg.Duration, want)
+	}
+	if legs[1][0] != leg {
+		t.Fatalf("legs differ by direction: %+v and %+v", legs[1][0], leg)
+	}
+}
@@ -0,0 +1,63 @@
+package geo
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fmt"
+	"time"
+)
+
+// Leg is the estimated travel from one point to another
+type Leg struct {
+	Meters   float64
+	Duration time.Duration
+}
+
+// DistanceMatrix estimates travel between points
+type DistanceMatrix interface {
+	// Matrix returns the leg from every point to every other point; the
+	// element [i][j] leads from points[i] to points[j]
+	Matrix(ctx context.Context, points []Point) ([][]Leg, error)
+}
+
+// NewDistanceMatrix creates the provider selected by cfg.RoutingProvider
+func NewDistanceMatrix(cfg *models.Config) (DistanceMatrix, error) {
+	switch cfg.RoutingProvider {
+	case models.RoutingProviderGreatCircle:
+		return &GreatCircleMatrix{
+			speedKmh:     cfg.RoutingAverageSpeedKmh,
+			detourFactor: cfg.RoutingDetourFactor,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown routing provider %q", cfg.RoutingProvider)
+	}
+}
+
+// GreatCircleMatrix estimates travel from straight-line distances, so it
+// works without a routing service. Distances are stretched by detourFactor
+// to allow for roads not running straight, and driven at speedKmh.
+type GreatCircleMatrix struct {
+	speedKmh     float64
+	detourFactor float64
+}
+
+// Matrix returns the estimated legs between points
+func (m *GreatCircleMatrix) Matrix(ctx context.Context, points []Point) ([][]Leg, error) {
+	metersPerSecond := m.speedKmh * 1000 / 3600
+
+	legs := make([][]Leg, len(points))
+	for i := range points {
+		legs[i] = make([]Leg, len(points))
+		for j := range points {
+			if i == j {
+				continue
+			}
+			meters := Distance(points[i], points[j]) * m.detourFactor
+			legs[i][j] = Leg{
+				Meters:   meters,
+				Duration: time.Duration(meters / metersPerSecond * float64(time.Second)),
+			}
+		}
+	}
+	return legs, nil
+}
@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NominatimGeocoder geocodes through the search API of Nominatim, or of any
+// service that answers /search?format=json the same way
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+// nominatimPlace is one search result. Coordinates are returned as strings.
+type nominatimPlace struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode returns the coordinates of the best match for address
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (Point, error) {
+	query := url.Values{}
+	query.Set("q", address)
+	query.Set("format", "json")
+	query.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(g.baseURL, "/")+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return Point{}, fmt.Errorf("failed to build geocoding request: %w", err)
+	}
+	if g.userAgent != "" {
+		req.Header.Set("User-Agent", g.userAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return Point{}, fmt.Errorf("failed to geocode address: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, fmt.Errorf("failed to geocode address: geocoder returned %s", resp.Status)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return Point{}, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(places) == 0 {
+		return Point{}, fmt.Errorf("%w: %q", ErrAddressNotFound, address)
+	}
+
+	lat, err := strconv.ParseFloat(places[0].Lat, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid latitude in geocoding response: %w", err)
+	}
+	lon, err := strconv.ParseFloat(places[0].Lon, 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid longitude in geocoding response: %w", err)
+	}
+
+	point := Point{Latitude: lat, Longitude: lon}
+	if !point.Valid() {
+		return Point{}, fmt.Errorf("geocoder returned coordinates out of range: %v", point)
+	}
+	return point, nil
+}
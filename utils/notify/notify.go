@@ -0,0 +1,85 @@
+// Package notify delivers alerts to users through the Notifier interface
+package notify
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Recipient is a user a notification is addressed to, or an outside
+// contact such as a customer, who has no user ID
+type Recipient struct {
+	UserID string `json:"userID"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Phone  string `json:"phone,omitempty"`
+}
+
+// Attachment is a file sent with a notification. Content carries the file
+// itself, so the recipient can open it for as long as they keep the
+// message. Otherwise URL is a signed download link that expires at
+// ExpiresAt, so gateways fetch the file before then.
+type Attachment struct {
+	FileName    string     `json:"fileName"`
+	ContentType string     `json:"contentType"`
+	Content     []byte     `json:"content,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Notification is one alert to its recipients. Event names the kind of
+// alert, such as sla_breached, and Data carries its details for templates.
+type Notification struct {
+	Event       string            `json:"event"`
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`
+	Recipients  []Recipient       `json:"recipients"`
+	Data        map[string]string `json:"data,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	SentAt      time.Time         `json:"sentAt"`
+}
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+// New creates the notifier selected by cfg.NotificationProvider
+func New(cfg *models.Config, log logger.Logger) (Notifier, error) {
+	switch cfg.NotificationProvider {
+	case models.NotificationProviderLog:
+		return &LogNotifier{logger: log}, nil
+	case models.NotificationProviderWebhook:
+		return &WebhookNotifier{
+			url:    cfg.NotificationWebhookURL,
+			client: &http.Client{Timeout: time.Duration(cfg.NotificationTimeoutMs) * time.Millisecond},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification provider %q", cfg.NotificationProvider)
+	}
+}
+
+// LogNotifier writes notifications to the log, for deployments that pick
+// alerts up from there
+type LogNotifier struct {
+	logger logger.Logger
+}
+
+// Notify logs notification
+func (n *LogNotifier) Notify(ctx context.Context, notification *Notification) error {
+	to := make([]string, 0, len(notification.Recipients))
+	for _, recipient := range notification.Recipients {
+		if recipient.UserID == "" {
+			to = append(to, recipient.Email)
+			continue
+		}
+		to = append(to, recipient.UserID)
+	}
+	n.logger.Warnf("Notification %s to %s: %s - %s", notification.Event, strings.Join(to, ", "), notification.Subject, notification.Body)
+	return nil
+}
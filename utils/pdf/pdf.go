@@ -0,0 +1,258 @@
+// Package pdf writes simple documents as PDF: headings, wrapped paragraphs,
+// label and value lines and images on A4 pages in the standard Helvetica
+// fonts. Only what completion summaries need is supported; text outside the
+// Windows-1252 character set is replaced by question marks.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of PDF files
+const ContentType = "application/pdf"
+
+// Page layout in points
+const (
+	pageWidth   = 595.0
+	pageHeight  = 842.0
+	margin      = 50.0
+	textSize    = 10.0
+	headingSize = 16.0
+	lineSpacing = 1.4
+
+	// charWidth is the average width of a Helvetica character relative to
+	// its size, used to wrap lines
+	charWidth = 0.52
+)
+
+// winAnsi maps the characters of Windows-1252 outside Latin-1 to their code
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// Document is a PDF being written. Content flows down from the top of the
+// first page and continues on a new page when a page is full.
+type Document struct {
+	pages  []*bytes.Buffer
+	images []jpegImage
+	y      float64
+}
+
+type jpegImage struct {
+	data          []byte
+	width, height int
+}
+
+// New creates a document with one empty page
+func New() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+// Heading adds text in large bold type
+func (d *Document) Heading(text string) {
+	d.Space()
+	d.lines(text, "F2", headingSize, 0)
+	d.Space()
+}
+
+// Subheading adds text in bold type
+func (d *Document) Subheading(text string) {
+	d.Space()
+	d.lines(text, "F2", textSize+2, 0)
+}
+
+// Paragraph adds text wrapped to the width of the page
+func (d *Document) Paragraph(text string) {
+	d.lines(text, "F1", textSize, 0)
+}
+
+// Field adds a bold label followed by value. Long values wrap below the
+// label's column.
+func (d *Document) Field(label, value string) {
+	const labelWidth = 140.0
+	d.ensure(textSize * lineSpacing)
+	d.text(margin, d.y-textSize, "F2", textSize, label)
+	d.lines(value, "F1", textSize, labelWidth)
+}
+
+// Space adds an empty line
+func (d *Document) Space() {
+	d.y -= textSize * lineSpacing
+}
+
+// Image adds img scaled to fit within maxWidth by maxHeight points, on a
+// white background in place of transparency
+func (d *Document) Image(img image.Image, maxWidth, maxHeight float64) error {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return fmt.Errorf("image is empty")
+	}
+
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 90}); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	scale := min(maxWidth/float64(bounds.Dx()), maxHeight/float64(bounds.Dy()), (pageWidth-2*margin)/float64(bounds.Dx()))
+	width, height := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+
+	d.ensure(height)
+	d.y -= height
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, margin, d.y, len(d.images))
+	d.images = append(d.images, jpegImage{data: buf.Bytes(), width: bounds.Dx(), height: bounds.Dy()})
+	return nil
+}
+
+// Write writes the document to w
+func (d *Document) Write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	stream := func(dict string, data []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< %s /Length %d >>\nstream\n", len(offsets), dict, len(data))
+		out.Write(data)
+		out.WriteString("\nendstream\nendobj\n")
+	}
+
+	// Objects 1 to 4 are the catalog, the page tree and the fonts, followed
+	// by the images and then each page with its content
+	firstImage := 5
+	firstPage := firstImage + len(d.images)
+
+	var kids, xObjects strings.Builder
+	for i := range d.pages {
+		fmt.Fprintf(&kids, "%d 0 R ", firstPage+2*i)
+	}
+	for i := range d.images {
+		fmt.Fprintf(&xObjects, "/Im%d %d 0 R ", i, firstImage+i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for _, img := range d.images {
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			img.width, img.height), img.data)
+	}
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> /XObject << %s>> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, xObjects.String(), firstPage+2*i+1))
+		stream("", page.Bytes())
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensure starts a new page unless height points fit on the current one
+func (d *Document) ensure(height float64) {
+	if d.y-height < margin {
+		d.newPage()
+	}
+}
+
+// lines writes text wrapped to the page, indented by indent points
+func (d *Document) lines(text, font string, size, indent float64) {
+	perLine := int((pageWidth - 2*margin - indent) / (size * charWidth))
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(paragraph, perLine) {
+			d.ensure(size * lineSpacing)
+			d.text(margin+indent, d.y-size, font, size, line)
+			d.y -= size * lineSpacing
+		}
+	}
+}
+
+// text writes one line with its baseline at x, y
+func (d *Document) text(x, y float64, font string, size float64, line string) {
+	fmt.Fprintf(d.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, encode(line))
+}
+
+// wrap breaks text into lines of at most width characters at spaces, and
+// within words longer than a line
+func wrap(text string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// encode converts text to a Windows-1252 PDF string body
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		var c byte
+		switch code, ok := winAnsi[r]; {
+		case ok:
+			c = code
+		case r < 0x20:
+			c = ' '
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			c = byte(r)
+		default:
+			c = '?'
+		}
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
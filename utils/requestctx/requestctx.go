@@ -0,0 +1,63 @@
+package requestctx
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	claimsKey
+	tenantKey
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithClaims returns a copy of ctx carrying the authenticated user's claims
+func WithClaims(ctx context.Context, claims *models.JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// Claims returns the authenticated user's claims carried by ctx
+func Claims(ctx context.Context) (*models.JWTClaims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*models.JWTClaims)
+	return claims, ok && claims != nil
+}
+
+// UserID returns the authenticated user's ID, or an empty string
+func UserID(ctx context.Context) string {
+	if claims, ok := Claims(ctx); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+// WithTenant returns a copy of ctx scoped to the organization orgID,
+// overriding the organization of the authenticated user
+func WithTenant(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, tenantKey, orgID)
+}
+
+// TenantID returns the organization ctx is scoped to: the one set with
+// WithTenant, else the authenticated user's organization, else an empty
+// string
+func TenantID(ctx context.Context) string {
+	if orgID, _ := ctx.Value(tenantKey).(string); orgID != "" {
+		return orgID
+	}
+	if claims, ok := Claims(ctx); ok {
+		return claims.Context.OrganizationID
+	}
+	return ""
+}
@@ -0,0 +1,425 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used for
+// recurring jobs:
+//
+//	FREQ=DAILY|WEEKLY|MONTHLY|YEARLY
+//	INTERVAL=n
+//	COUNT=n or UNTIL=YYYYMMDD[THHMMSSZ]
+//	BYDAY=MO,WE or, for MONTHLY and YEARLY, 1MO,-1FR
+//	BYMONTHDAY=1,15,-1
+//	BYMONTH=1,4,7,10
+//
+// Occurrences are computed on the local calendar of the start time, so a
+// visit at 09:00 stays at 09:00 across daylight saving changes.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the unit a rule repeats in
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds how many periods a single expansion may walk
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry. N is the occurrence within the month
+// (negative counts from the end) or 0 for every such weekday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+}
+
+// Parse reads a rule such as "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1". An
+// optional "RRULE:" prefix is ignored.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("recurrence rule is empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positiveInt(val)
+		case "COUNT":
+			rule.Count, err = positiveInt(val)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(val, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(val, 1, 12)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported rule part %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ToUpper(name), err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot both be set")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return nil, errors.New("numbered BYDAY entries require FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	return rule, nil
+}
+
+// String formats the rule in RFC 5545 syntax
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := strings.ToUpper(day.Weekday.String()[:2])
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, month := range r.ByMonth {
+			months = append(months, int(month))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrences of the rule starting at start that fall
+// within [from, to], in order. start is the first candidate occurrence; its
+// location and clock time apply to every occurrence.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	count := 0
+
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.expand(start, period)
+		if len(candidates) == 0 && r.periodStart(start, period).After(to) {
+			break
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Before(start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return occurrences
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+			if candidate.After(to) {
+				return occurrences
+			}
+			if !candidate.Before(from) {
+				occurrences = append(occurrences, candidate)
+			}
+		}
+	}
+	return occurrences
+}
+
+// periodStart returns the first day of the given period
+func (r *Rule) periodStart(start time.Time, period int) time.Time {
+	n := period * r.Interval
+	switch r.Freq {
+	case Daily:
+		return start.AddDate(0, 0, n)
+	case Weekly:
+		monday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		return monday.AddDate(0, 0, 7*n)
+	case Monthly:
+		first := time.Date(start.Year(), start.Month(), 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		return first.AddDate(0, n, 0)
+	default:
+		return time.Date(start.Year()+n, time.January, 1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+}
+
+// expand returns the sorted candidate occurrences of one period
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	first := r.periodStart(start, period)
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		days = []time.Time{first}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			days = []time.Time{first.AddDate(0, 0, (int(start.Weekday())+6)%7)}
+		} else {
+			for i := 0; i < 7; i++ {
+				days = append(days, first.AddDate(0, 0, i))
+			}
+		}
+	case Monthly:
+		days = r.expandMonth(start, first.Year(), first.Month())
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, month := range months {
+			days = append(days, r.expandMonth(start, first.Year(), month)...)
+		}
+	}
+
+	var candidates []time.Time
+	for _, day := range days {
+		if r.matches(day) {
+			candidates = append(candidates, time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location()))
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// expandMonth returns the days of a month selected by BYMONTHDAY and BYDAY,
+// or the start's day of the month when neither is set
+func (r *Rule) expandMonth(start time.Time, year int, month time.Month) []time.Time {
+	loc := start.Location()
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+
+	var days []time.Time
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay < 0 {
+				monthDay = daysInMonth + monthDay + 1
+			}
+			if monthDay >= 1 && monthDay <= daysInMonth {
+				days = append(days, time.Date(year, month, monthDay, 0, 0, 0, 0, loc))
+			}
+		}
+	case len(r.ByDay) > 0:
+		for _, byDay := range r.ByDay {
+			var matching []time.Time
+			for monthDay := 1; monthDay <= daysInMonth; monthDay++ {
+				day := time.Date(year, month, monthDay, 0, 0, 0, 0, loc)
+				if day.Weekday() == byDay.Weekday {
+					matching = append(matching, day)
+				}
+			}
+			switch {
+			case byDay.N == 0:
+				days = append(days, matching...)
+			case byDay.N > 0 && byDay.N <= len(matching):
+				days = append(days, matching[byDay.N-1])
+			case byDay.N < 0 && -byDay.N <= len(matching):
+				days = append(days, matching[len(matching)+byDay.N])
+			}
+		}
+	default:
+		// Months without the start's day are skipped, as RFC 5545 requires
+		if start.Day() <= daysInMonth {
+			days = append(days, time.Date(year, month, start.Day(), 0, 0, 0, 0, loc))
+		}
+	}
+	return days
+}
+
+// matches applies the BY* filters that limit, rather than expand, the
+// candidates of the rule's frequency
+func (r *Rule) matches(day time.Time) bool {
+	if len(r.ByMonth) > 0 && r.Freq != Yearly && !containsMonth(r.ByMonth, day.Month()) {
+		return false
+	}
+	if len(r.ByDay) > 0 && (r.Freq == Daily || r.Freq == Weekly) {
+		found := false
+		for _, byDay := range r.ByDay {
+			if byDay.Weekday == day.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.ByMonthDay) > 0 && (r.Freq == Daily || r.Freq == Weekly) {
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		found := false
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay < 0 {
+				monthDay = daysInMonth + monthDay + 1
+			}
+			if monthDay == day.Day() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	// Monthly and yearly rules combining BYMONTHDAY and BYDAY keep the
+	// month days that fall on one of the weekdays
+	if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 && (r.Freq == Monthly || r.Freq == Yearly) {
+		found := false
+		for _, byDay := range r.ByDay {
+			if byDay.Weekday == day.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func positiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive integer", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(strings.ToUpper(value), ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		weekday, ok := weekdays[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", entry)
+		}
+		n := 0
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", entry)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: weekday})
+	}
+	return days, nil
+}
+
+func parseIntList(value string, min, max int) ([]int, error) {
+	var values []int
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(entry)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("invalid value %q", entry)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, strconv.Itoa(value))
+	}
+	return strings.Join(parts, ",")
+}
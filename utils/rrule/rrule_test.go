@@ -0,0 +1,151 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func dates(occurrences []time.Time) []string {
+	out := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		out = append(out, occurrence.Format("2006-01-02"))
+	}
+	return out
+}
+
+func equalDates(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRuleBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		want  []string
+	}{
+		{name: "daily count", rule: "FREQ=DAILY;COUNT=3", start: "2026-01-05", want: []string{"2026-01-05", "2026-01-06", "2026-01-07"}},
+		{name: "daily until date", rule: "FREQ=DAILY;UNTIL=20260107", start: "2026-01-05", want: []string{"2026-01-05", "2026-01-06", "2026-01-07"}},
+		{name: "weekly on weekdays", rule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", start: "2026-01-05", want: []string{"2026-01-05", "2026-01-07", "2026-01-12", "2026-01-14"}},
+		{name: "fortnightly", rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=3", start: "2026-01-05", want: []string{"2026-01-05", "2026-01-19", "2026-02-02"}},
+		{name: "weekdays before the start are skipped", rule: "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=2", start: "2026-01-07", want: []string{"2026-01-09", "2026-01-12"}},
+		{name: "second tuesday", rule: "FREQ=MONTHLY;BYDAY=2TU;COUNT=2", start: "2026-01-01", want: []string{"2026-01-13", "2026-02-10"}},
+		{name: "last friday", rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", start: "2026-01-01", want: []string{"2026-01-30", "2026-02-27", "2026-03-27"}},
+		{name: "last day of month", rule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", start: "2026-01-01", want: []string{"2026-01-31", "2026-02-28", "2026-03-31"}},
+		{name: "months without the day are skipped", rule: "FREQ=MONTHLY;COUNT=3", start: "2026-01-31", want: []string{"2026-01-31", "2026-03-31", "2026-05-31"}},
+		{name: "quarterly", rule: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1;COUNT=3", start: "2026-01-01", want: []string{"2026-01-01", "2026-04-01", "2026-07-01"}},
+		{name: "leap day", rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=2", start: "2026-01-01", want: []string{"2028-02-29", "2032-02-29"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			start, _ := time.Parse("2006-01-02", tt.start)
+			start = start.Add(9 * time.Hour)
+
+			got := dates(rule.Between(start, start, start.AddDate(10, 0, 0)))
+			if !equalDates(got, tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleBetweenWindow(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 12, 23, 0, 0, 0, time.UTC)
+
+	want := []string{"2026-01-10", "2026-01-11", "2026-01-12"}
+	if got := dates(rule.Between(start, from, to)); !equalDates(got, want) {
+		t.Fatalf("Between() = %v, want %v", got, want)
+	}
+
+	// COUNT is counted from the start, not from the window
+	rule, _ = Parse("FREQ=DAILY;COUNT=10")
+	want = []string{"2026-01-10"}
+	if got := dates(rule.Between(start, from, to)); !equalDates(got, want) {
+		t.Fatalf("Between() with COUNT = %v, want %v", got, want)
+	}
+}
+
+// TestRuleBetweenDaylightSaving keeps the local clock time of the start
+// across the change to daylight saving time
+func TestRuleBetweenDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	rule, err := Parse("FREQ=WEEKLY;COUNT=3")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, loc)
+	occurrences := rule.Between(start, start, start.AddDate(0, 1, 0))
+	if len(occurrences) != 3 {
+		t.Fatalf("Between() returned %d occurrences, want 3", len(occurrences))
+	}
+	for _, occurrence := range occurrences {
+		if occurrence.Hour() != 9 || occurrence.Minute() != 0 {
+			t.Errorf("occurrence %v is not at 09:00 local time", occurrence)
+		}
+	}
+	if offset := occurrences[1].Sub(occurrences[0]); offset != 7*24*time.Hour-time.Hour {
+		t.Errorf("week across the change lasted %v, want 167h", offset)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "RRULE:FREQ=monthly;INTERVAL=3;BYDAY=1MO,-1FR", want: "FREQ=MONTHLY;INTERVAL=3;BYDAY=1MO,-1FR"},
+		{value: "FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=15;COUNT=4", want: "FREQ=YEARLY;COUNT=4;BYMONTHDAY=15;BYMONTH=1,7"},
+		{value: "FREQ=DAILY;UNTIL=20260131T170000Z;WKST=MO", want: "FREQ=DAILY;UNTIL=20260131T170000Z"},
+		{value: "", wantErr: true},
+		{value: "INTERVAL=2", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20260101", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{value: "FREQ=MONTHLY;BYDAY=XX", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{value: "FREQ=DAILY;WKST=SU", wantErr: true},
+		{value: "FREQ=DAILY;BYSETPOS=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want an error", tt.value, rule)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.value, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Analyze splits text into the lower-case words it is indexed and searched
+// by. Anything but letters and digits separates words.
+func Analyze(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,313 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultFacetSize is the number of values counted per facet when a query
+// does not set FacetSize
+const defaultFacetSize = 10
+
+// prefixMinLength is the shortest last word of a query that also matches
+// longer words starting with it. Shorter ones would match most documents.
+const prefixMinLength = 2
+
+// prefixWeight scales matches on the start of a word against whole words
+const prefixWeight = 0.5
+
+// MemoryIndex is an inverted index held in memory. Each organization has a
+// shard of its own, so queries never see another organization's documents.
+type MemoryIndex struct {
+	mu     sync.RWMutex
+	shards map[string]*shard
+	owners map[string]string // Organization of each document
+}
+
+// shard holds the documents of one organization and, for each word, the
+// documents and fields it occurs in
+type shard struct {
+	docs     map[string]*Document
+	postings map[string]map[string][]string
+}
+
+// NewMemoryIndex creates an empty index
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		shards: make(map[string]*shard),
+		owners: make(map[string]string),
+	}
+}
+
+// Index adds doc, replacing the document with the same ID
+func (m *MemoryIndex) Index(ctx context.Context, doc *Document) error {
+	if doc == nil || doc.ID == "" || doc.OrgID == "" {
+		return errors.New("document ID and organization ID are required")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.ID)
+	s, ok := m.shards[doc.OrgID]
+	if !ok {
+		s = newShard()
+		m.shards[doc.OrgID] = s
+	}
+	s.add(doc)
+	m.owners[doc.ID] = doc.OrgID
+	return nil
+}
+
+// Delete removes document id. Unknown documents are ignored.
+func (m *MemoryIndex) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(id)
+	return nil
+}
+
+// Replace swaps the documents of orgID for docs
+func (m *MemoryIndex) Replace(ctx context.Context, orgID string, docs []*Document) error {
+	s := newShard()
+	for _, doc := range docs {
+		if doc.ID == "" || doc.OrgID != orgID {
+			return errors.New("documents must have an ID and belong to the organization they replace")
+		}
+		s.remove(doc.ID)
+		s.add(doc)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if old, ok := m.shards[orgID]; ok {
+		for id := range old.docs {
+			delete(m.owners, id)
+		}
+	}
+	for id := range s.docs {
+		// A document that moved here from another organization leaves it
+		m.remove(id)
+		m.owners[id] = orgID
+	}
+	m.shards[orgID] = s
+	return nil
+}
+
+// remove drops document id from the shard it is in
+func (m *MemoryIndex) remove(id string) {
+	orgID, ok := m.owners[id]
+	if !ok {
+		return
+	}
+	delete(m.owners, id)
+	if s, ok := m.shards[orgID]; ok {
+		s.remove(id)
+	}
+}
+
+// Search runs query against the documents of its organization
+func (m *MemoryIndex) Search(ctx context.Context, query *Query) (*Result, error) {
+	if query == nil || query.OrgID == "" {
+		return nil, errors.New("organization ID is required")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := &Result{Hits: []Hit{}, Facets: make(map[string][]FacetCount)}
+	s, ok := m.shards[query.OrgID]
+	if !ok {
+		s = newShard()
+	}
+
+	var hits []Hit
+	for id, score := range s.match(Analyze(query.Text), query.Boosts) {
+		if doc := s.docs[id]; matchesFilters(doc, query) {
+			hits = append(hits, Hit{ID: id, Score: score})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		a, b := s.docs[hits[i].ID].Date, s.docs[hits[j].ID].Date
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	result.Total = len(hits)
+	for _, facet := range query.Facets {
+		result.Facets[facet] = countFacet(s, hits, facet, query.FacetSize)
+	}
+	for _, dateRange := range query.DateRanges {
+		count := 0
+		for _, hit := range hits {
+			if date := s.docs[hit.ID].Date; !date.IsZero() && dateRange.Contains(date) {
+				count++
+			}
+		}
+		result.DateRanges = append(result.DateRanges, FacetCount{Value: dateRange.Name, Count: count})
+	}
+
+	from := min(max(query.From, 0), len(hits))
+	to := len(hits)
+	if query.Size > 0 {
+		to = min(from+query.Size, len(hits))
+	}
+	result.Hits = append(result.Hits, hits[from:to]...)
+	return result, nil
+}
+
+func newShard() *shard {
+	return &shard{
+		docs:     make(map[string]*Document),
+		postings: make(map[string]map[string][]string),
+	}
+}
+
+// add indexes doc, which must not be indexed yet
+func (s *shard) add(doc *Document) {
+	s.docs[doc.ID] = doc
+	for field, text := range doc.Text {
+		for _, word := range Analyze(text) {
+			docs, ok := s.postings[word]
+			if !ok {
+				docs = make(map[string][]string)
+				s.postings[word] = docs
+			}
+			if fields := docs[doc.ID]; len(fields) == 0 || fields[len(fields)-1] != field {
+				docs[doc.ID] = append(fields, field)
+			}
+		}
+	}
+}
+
+// remove drops document id and its postings
+func (s *shard) remove(id string) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return
+	}
+	delete(s.docs, id)
+	for _, text := range doc.Text {
+		for _, word := range Analyze(text) {
+			if docs, ok := s.postings[word]; ok {
+				delete(docs, id)
+				if len(docs) == 0 {
+					delete(s.postings, word)
+				}
+			}
+		}
+	}
+}
+
+// match scores the documents containing every word, the last one also as
+// the start of a longer word. No words match every document with score 0.
+func (s *shard) match(words []string, boosts map[string]float64) map[string]float64 {
+	scores := make(map[string]float64)
+	if len(words) == 0 {
+		for id := range s.docs {
+			scores[id] = 0
+		}
+		return scores
+	}
+
+	for i, word := range words {
+		wordScores := make(map[string]float64)
+		for id, fields := range s.postings[word] {
+			wordScores[id] = fieldScore(fields, boosts, 1)
+		}
+		if i == len(words)-1 && len([]rune(word)) >= prefixMinLength {
+			for indexed, docs := range s.postings {
+				if indexed == word || !strings.HasPrefix(indexed, word) {
+					continue
+				}
+				for id, fields := range docs {
+					wordScores[id] = max(wordScores[id], fieldScore(fields, boosts, prefixWeight))
+				}
+			}
+		}
+
+		if i == 0 {
+			scores = wordScores
+			continue
+		}
+		for id, score := range scores {
+			if wordScore, ok := wordScores[id]; ok {
+				scores[id] = score + wordScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// fieldScore sums the boosts of fields, scaled by weight
+func fieldScore(fields []string, boosts map[string]float64, weight float64) float64 {
+	score := 0.0
+	for _, field := range fields {
+		boost, ok := boosts[field]
+		if !ok {
+			boost = 1
+		}
+		score += boost * weight
+	}
+	return score
+}
+
+// matchesFilters reports whether doc passes the facet and date filters of
+// query
+func matchesFilters(doc *Document, query *Query) bool {
+	for facet, wanted := range query.Filters {
+		if len(wanted) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(doc.Facets[facet], func(value string) bool { return slices.Contains(wanted, value) }) {
+			return false
+		}
+	}
+	if query.DateFilter != nil && (doc.Date.IsZero() || !query.DateFilter.Contains(doc.Date)) {
+		return false
+	}
+	return true
+}
+
+// countFacet counts the values of facet among hits, most frequent first
+func countFacet(s *shard, hits []Hit, facet string, size int) []FacetCount {
+	if size <= 0 {
+		size = defaultFacetSize
+	}
+
+	counts := make(map[string]int)
+	for _, hit := range hits {
+		for _, value := range s.docs[hit.ID].Facets[facet] {
+			counts[value]++
+		}
+	}
+
+	values := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > size {
+		values = values[:size]
+	}
+	return values
+}
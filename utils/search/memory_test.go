@@ -0,0 +1,201 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+var searchDay = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+func doc(id, orgID, name, status string, assignees ...string) *Document {
+	return &Document{
+		ID:     id,
+		OrgID:  orgID,
+		Text:   map[string]string{"name": name},
+		Facets: map[string][]string{"status": {status}, "assignee": assignees},
+		Date:   searchDay,
+	}
+}
+
+func newTestIndex(t *testing.T, docs ...*Document) *MemoryIndex {
+	t.Helper()
+	index := NewMemoryIndex()
+	for _, d := range docs {
+		if err := index.Index(context.Background(), d); err != nil {
+			t.Fatalf("Index(%s) error = %v", d.ID, err)
+		}
+	}
+	return index
+}
+
+func hitIDs(result *Result) []string {
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestMemoryIndexOrganizationScope(t *testing.T) {
+	ctx := context.Background()
+	index := newTestIndex(t,
+		doc("a-1", "org-a", "Boiler service", "pending"),
+		doc("a-2", "org-a", "Boiler repair", "pending"),
+		doc("b-1", "org-b", "Boiler service", "pending"),
+	)
+
+	search := func(orgID string) []string {
+		t.Helper()
+		result, err := index.Search(ctx, &Query{OrgID: orgID, Text: "boiler"})
+		if err != nil {
+			t.Fatalf("Search(%s) error = %v", orgID, err)
+		}
+		ids := hitIDs(result)
+		slices.Sort(ids)
+		return ids
+	}
+
+	if got := search("org-a"); !slices.Equal(got, []string{"a-1", "a-2"}) {
+		t.Fatalf("Search(org-a) = %v, want a-1 and a-2", got)
+	}
+	if got := search("org-b"); !slices.Equal(got, []string{"b-1"}) {
+		t.Fatalf("Search(org-b) = %v, want b-1", got)
+	}
+	if got := search("org-c"); len(got) != 0 {
+		t.Fatalf("Search(org-c) = %v, want nothing", got)
+	}
+	if _, err := index.Search(ctx, &Query{Text: "boiler"}); err == nil {
+		t.Fatalf("Search() without organization succeeded")
+	}
+
+	// A document indexed again under another organization leaves the first
+	if err := index.Index(ctx, doc("a-2", "org-b", "Boiler repair", "pending")); err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	if got := search("org-a"); !slices.Equal(got, []string{"a-1"}) {
+		t.Fatalf("Search(org-a) after the move = %v, want a-1", got)
+	}
+
+	// Replacing an organization leaves the others alone
+	if err := index.Replace(ctx, "org-b", []*Document{doc("b-2", "org-b", "Boiler install", "active")}); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got := search("org-b"); !slices.Equal(got, []string{"b-2"}) {
+		t.Fatalf("Search(org-b) after Replace() = %v, want b-2", got)
+	}
+	if got := search("org-a"); !slices.Equal(got, []string{"a-1"}) {
+		t.Fatalf("Search(org-a) after Replace() = %v, want a-1", got)
+	}
+	if err := index.Replace(ctx, "org-b", []*Document{doc("a-1", "org-a", "Boiler", "pending")}); err == nil {
+		t.Fatalf("Replace() accepted a document of another organization")
+	}
+}
+
+func TestMemoryIndexFacets(t *testing.T) {
+	ctx := context.Background()
+	index := newTestIndex(t,
+		doc("1", "org", "Boiler service", "pending", "ann"),
+		doc("2", "org", "Boiler repair", "pending", "ann", "bob"),
+		doc("3", "org", "Boiler inspection", "completed", "bob"),
+		doc("4", "org", "Boiler install", "active", "cid"),
+		doc("5", "org", "Gutter cleaning", "pending", "ann"),
+	)
+
+	query := &Query{
+		OrgID:     "org",
+		Text:      "boiler",
+		Facets:    []string{"status", "assignee"},
+		FacetSize: 2,
+		Size:      1,
+	}
+	result, err := index.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	// Facets count every match, not just the page, most frequent first and
+	// then by value, cut to FacetSize
+	if result.Total != 4 || len(result.Hits) != 1 {
+		t.Fatalf("Search() = %d hits of %d, want 1 of 4", len(result.Hits), result.Total)
+	}
+	wantStatus := []FacetCount{{Value: "pending", Count: 2}, {Value: "active", Count: 1}}
+	if !slices.Equal(result.Facets["status"], wantStatus) {
+		t.Fatalf("status facet = %v, want %v", result.Facets["status"], wantStatus)
+	}
+	wantAssignee := []FacetCount{{Value: "ann", Count: 2}, {Value: "bob", Count: 2}}
+	if !slices.Equal(result.Facets["assignee"], wantAssignee) {
+		t.Fatalf("assignee facet = %v, want %v", result.Facets["assignee"], wantAssignee)
+	}
+
+	// Filters narrow the matches the facets are counted over
+	query.Filters = map[string][]string{"assignee": {"bob"}}
+	result, err = index.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	wantStatus = []FacetCount{{Value: "completed", Count: 1}, {Value: "pending", Count: 1}}
+	if result.Total != 2 || !slices.Equal(result.Facets["status"], wantStatus) {
+		t.Fatalf("filtered Search() = total %d, status %v, want 2, %v", result.Total, result.Facets["status"], wantStatus)
+	}
+}
+
+func TestMemoryIndexDateRanges(t *testing.T) {
+	ctx := context.Background()
+	earlier := doc("1", "org", "Boiler service", "pending")
+	earlier.Date = searchDay.AddDate(0, 0, -3)
+	undated := doc("3", "org", "Boiler service", "pending")
+	undated.Date = time.Time{}
+	index := newTestIndex(t, earlier, doc("2", "org", "Boiler service", "pending"), undated)
+
+	past := DateRange{Name: "past", End: searchDay.AddDate(0, 0, -1)}
+	today := DateRange{Name: "today", Start: searchDay.Truncate(24 * time.Hour), End: searchDay.Truncate(24*time.Hour).AddDate(0, 0, 1)}
+	result, err := index.Search(ctx, &Query{OrgID: "org", DateRanges: []DateRange{past, today}, DateFilter: &today})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if got := hitIDs(result); !slices.Equal(got, []string{"2"}) {
+		t.Fatalf("Search() with a date filter = %v, want 2", got)
+	}
+	want := []FacetCount{{Value: "past", Count: 0}, {Value: "today", Count: 1}}
+	if !slices.Equal(result.DateRanges, want) {
+		t.Fatalf("date ranges = %v, want %v", result.DateRanges, want)
+	}
+}
+
+func TestMemoryIndexRanking(t *testing.T) {
+	ctx := context.Background()
+	older := &Document{ID: "notes", OrgID: "org", Text: map[string]string{"notes": "replace boiler valve"}, Date: searchDay.AddDate(0, 0, -1)}
+	newer := &Document{ID: "notes-newer", OrgID: "org", Text: map[string]string{"notes": "boiler check"}, Date: searchDay}
+	name := &Document{ID: "name", OrgID: "org", Text: map[string]string{"name": "Boilerplate install"}, Date: searchDay}
+	index := newTestIndex(t, older, newer, name)
+
+	boosts := map[string]float64{"name": 3, "notes": 1}
+	result, err := index.Search(ctx, &Query{OrgID: "org", Text: "boil", Boosts: boosts})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	// The prefix matches the boosted name above notes; equal scores put the
+	// most recent first
+	if got, want := hitIDs(result), []string{"name", "notes-newer", "notes"}; !slices.Equal(got, want) {
+		t.Fatalf("Search(boil) = %v, want %v", got, want)
+	}
+
+	result, err = index.Search(ctx, &Query{OrgID: "org", Text: "boiler valve", From: 0, Size: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if got := hitIDs(result); !slices.Equal(got, []string{"notes"}) {
+		t.Fatalf("Search(boiler valve) = %v, want notes", got)
+	}
+
+	if err := index.Delete(ctx, "notes"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	result, err = index.Search(ctx, &Query{OrgID: "org", Text: "valve"})
+	if err != nil || result.Total != 0 {
+		t.Fatalf("Search(valve) after Delete() = %v, %v, want nothing", result, err)
+	}
+}
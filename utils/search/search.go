@@ -0,0 +1,110 @@
+// Package search provides full-text and faceted search over documents of an
+// organization through the Index interface
+package search
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fmt"
+	"time"
+)
+
+// Document is the searchable form of an item. Text is matched against the
+// words of a query, weighted by the boost of its field; Facets hold the
+// exact values results are counted and filtered by; Date places the
+// document in date ranges.
+type Document struct {
+	ID     string
+	OrgID  string
+	Text   map[string]string
+	Facets map[string][]string
+	Date   time.Time
+}
+
+// DateRange is a named period [Start, End). A zero Start or End leaves that
+// side open.
+type DateRange struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether t lies within r
+func (r DateRange) Contains(t time.Time) bool {
+	if !r.Start.IsZero() && t.Before(r.Start) {
+		return false
+	}
+	if !r.End.IsZero() && !t.Before(r.End) {
+		return false
+	}
+	return true
+}
+
+// Query searches the documents of OrgID. Every word of Text must match a
+// field, the last one also as the start of a longer word so results follow
+// typing; an empty Text matches every document. Boosts weighs matches by
+// field, 1 for fields not listed. Filters keep the documents having one of
+// the listed values of each facet, and DateFilter those whose date lies
+// within it.
+type Query struct {
+	OrgID      string
+	Text       string
+	Boosts     map[string]float64
+	Filters    map[string][]string
+	DateFilter *DateRange
+
+	// Facets names the facets whose values are counted over all matches,
+	// at most FacetSize values each, and DateRanges the periods matches are
+	// counted in
+	Facets     []string
+	FacetSize  int
+	DateRanges []DateRange
+
+	From int
+	Size int
+}
+
+// Hit is a matching document. Higher scores match the query better; hits
+// of equal score are ordered by date, most recent first.
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// FacetCount is the number of matches having a facet value or falling
+// within a date range
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// Result is the page of hits of a query with the total number of matches
+// and their facet counts
+type Result struct {
+	Total      int
+	Hits       []Hit
+	Facets     map[string][]FacetCount
+	DateRanges []FacetCount
+}
+
+// Index keeps documents searchable. Indexing a document replaces the one
+// with the same ID.
+type Index interface {
+	Index(ctx context.Context, doc *Document) error
+	Delete(ctx context.Context, id string) error
+	Search(ctx context.Context, query *Query) (*Result, error)
+
+	// Replace swaps every document of orgID for docs at once, so an
+	// organization can be indexed again from scratch while it is searched
+	Replace(ctx context.Context, orgID string, docs []*Document) error
+}
+
+// New creates the index selected by cfg.SearchProvider
+func New(cfg *models.Config) (Index, error) {
+	switch cfg.SearchProvider {
+	case models.SearchProviderMemory:
+		return NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("unknown search provider %q", cfg.SearchProvider)
+	}
+}
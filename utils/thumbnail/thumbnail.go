@@ -0,0 +1,111 @@
+// Package thumbnail scales uploaded photos down to small JPEG previews using
+// only the standard library image decoders.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Register the decoders for the photo formats accepted as attachments
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ContentType is the content type of generated thumbnails
+const ContentType = "image/jpeg"
+
+// maxPixels bounds the decoded size of an image so a small but highly
+// compressed upload cannot exhaust memory
+const maxPixels = 50_000_000
+
+// ErrUnsupported is returned for images the decoders cannot read
+var ErrUnsupported = errors.New("unsupported image format")
+
+// Generate decodes the image read from r and returns a JPEG thumbnail that
+// fits in a size x size square, keeping the aspect ratio. Images that
+// already fit are re-encoded without scaling. Transparent areas become white.
+func Generate(r io.Reader, size int) ([]byte, error) {
+	if size < 1 {
+		return nil, errors.New("thumbnail size must be positive")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d image is too large", ErrUnsupported, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	width, height := fit(config.Width, config.Height, size)
+	dst := scale(src, width, height)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// fit returns the dimensions of a width x height image scaled to fit in a
+// size x size square
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// scale resizes src to width x height by averaging the source pixels that
+// fall into each destination pixel, over a white background
+func scale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcWidth/width)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					// Blend premultiplied colour onto white
+					r += uint64(pr + 0xffff - pa)
+					g += uint64(pg + 0xffff - pa)
+					b += uint64(pb + 0xffff - pa)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
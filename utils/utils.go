@@ -99,6 +99,12 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("strict_role_validation", false)
 	v.SetDefault("log_permission_changes", true)
 
+	// Repository cache defaults
+	v.SetDefault("repository_cache_enabled", true)
+	v.SetDefault("repository_cache_size", 10000)
+	v.SetDefault("user_cache_ttl_seconds", 60)
+	v.SetDefault("role_cache_ttl_seconds", 300)
+
 	// AWS defaults
 	v.SetDefault("aws_region", "us-east-1")
 	v.SetDefault("aws_access_key_id", "")
@@ -106,6 +112,17 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("dynamodb_endpoint", "")
 	v.SetDefault("dynamodb_table_prefix", "dev")
 
+	// DAL resilience defaults
+	v.SetDefault("dal_operation_timeout_ms", 5000)
+	v.SetDefault("dal_scan_timeout_ms", 30000)
+	v.SetDefault("dal_max_retries", 3)
+	v.SetDefault("dal_retry_base_delay_ms", 50)
+	v.SetDefault("dal_retry_max_delay_ms", 2000)
+	v.SetDefault("dal_breaker_failure_percent", 50)
+	v.SetDefault("dal_breaker_min_requests", 20)
+	v.SetDefault("dal_breaker_window_seconds", 30)
+	v.SetDefault("dal_breaker_open_timeout_seconds", 15)
+
 	// Telnyx defaults
 	v.SetDefault("telnyx_api_key", "")
 	v.SetDefault("telnyx_app_id", "")
@@ -121,11 +138,74 @@ func setDefaults(v *viper.Viper) {
 	// Rate limiting defaults
 	v.SetDefault("rate_limit_requests_per_minute", 100)
 
+	// Data retention defaults
+	v.SetDefault("soft_delete_retention_days", 30)
+	v.SetDefault("purge_cron_schedule", "0 0 3 * * *") // Daily at 03:00
+
+	// Multi-tenancy defaults
+	v.SetDefault("tenancy_mode", models.TenancyShared)
+	v.SetDefault("tenant_provision_schedule", "0 */5 * * * *") // Every 5 minutes
+
+	// Recurring job defaults
+	v.SetDefault("recurrence_schedule", "0 0 * * * *") // Hourly
+	v.SetDefault("recurrence_horizon_days", 30)
+
+	// Job checklist defaults
+	v.SetDefault("checklist_required_to_complete", true)
+
+	// Blob storage defaults
+	v.SetDefault("blob_backend", models.BlobBackendLocal)
+	v.SetDefault("blob_local_path", "./data/blobs")
+	v.SetDefault("blob_base_url", "") // Defaults to the API's blob download route
+	v.SetDefault("blob_signing_secret", "")
+	v.SetDefault("blob_s3_endpoint", "")
+	v.SetDefault("blob_s3_bucket", "")
+	v.SetDefault("blob_s3_region", "")
+	v.SetDefault("blob_s3_path_style", false)
+	v.SetDefault("blob_s3_access_key_id", "")
+	v.SetDefault("blob_s3_secret_access_key", "")
+	v.SetDefault("blob_url_ttl_seconds", 300)
+	v.SetDefault("attachment_max_size_mb", 25)
+
+	// Geofence defaults
+	v.SetDefault("geofence_radius_m", 150)
+	v.SetDefault("geocoder_url", "") // Job sites need coordinates without a geocoder
+	v.SetDefault("geocoder_user_agent", "FieldFuze Backend")
+	v.SetDefault("geocoder_timeout_ms", 5000)
+
+	// Route optimization defaults
+	v.SetDefault("routing_provider", models.RoutingProviderGreatCircle)
+	v.SetDefault("routing_average_speed_kmh", 40)
+	v.SetDefault("routing_detour_factor", 1.3) // Roads are rarely straight
+
+	// Job SLA defaults
+	v.SetDefault("sla_schedule", "0 */5 * * * *") // Every 5 minutes
+	v.SetDefault("sla_at_risk_percent", 25)
+
+	// Notification defaults
+	v.SetDefault("notification_provider", models.NotificationProviderLog)
+	v.SetDefault("notification_webhook_url", "")
+	v.SetDefault("notification_timeout_ms", 5000)
+
+	// Job import defaults
+	v.SetDefault("job_import_max_rows", 5000)
+	v.SetDefault("job_import_max_upload_mb", 10)
+
+	// Job search defaults
+	v.SetDefault("search_provider", models.SearchProviderMemory)
+	v.SetDefault("search_reindex_schedule", "0 0 * * * *") // Hourly
+	v.SetDefault("search_max_page_size", 100)
+
+	// Change data capture defaults
+	v.SetDefault("stream_consumer_enabled", false)
+	v.SetDefault("stream_poll_interval_ms", 1000)
+	v.SetDefault("stream_batch_size", 100)
+
 	// Base Path default
 	v.SetDefault("basePath", "/api/v1")
 
 	// setup tables to create
-	v.SetDefault("tables", []string{"users1"})
+	v.SetDefault("tables", []string{"users1", "role", "roles", "organization", "jobs", "checkpoints", "recurring", "availability", "checklists", "attachments", "activity", "timeentries", "timesheets", "slapolicies", "jobtemplates", "jobimports", "projects"})
 }
 
 // validate checks if all required configuration is provided
@@ -140,6 +220,82 @@ func validate(c *models.Config) error {
 		fmt.Println("No AWS credentials provided, assuming IAM role is used")
 	}
 
+	if c.TenancyMode != models.TenancyShared && c.TenancyMode != models.TenancyIsolated {
+		return fmt.Errorf("tenancy mode must be %q or %q, got %q", models.TenancyShared, models.TenancyIsolated, c.TenancyMode)
+	}
+
+	if c.RecurrenceHorizonDays < 1 {
+		return fmt.Errorf("recurrence horizon must be at least 1 day, got %d", c.RecurrenceHorizonDays)
+	}
+
+	if c.BlobBackend != models.BlobBackendLocal && c.BlobBackend != models.BlobBackendS3 {
+		return fmt.Errorf("blob backend must be %q or %q, got %q", models.BlobBackendLocal, models.BlobBackendS3, c.BlobBackend)
+	}
+
+	if c.BlobBackend == models.BlobBackendS3 && c.BlobS3Bucket == "" {
+		return fmt.Errorf("blob bucket must be set for the s3 blob backend")
+	}
+
+	if c.BlobURLTTLSeconds < 1 {
+		return fmt.Errorf("blob URL lifetime must be at least 1 second, got %d", c.BlobURLTTLSeconds)
+	}
+
+	if c.AttachmentMaxSizeMB < 1 {
+		return fmt.Errorf("attachment size limit must be at least 1 MB, got %d", c.AttachmentMaxSizeMB)
+	}
+
+	if c.GeofenceRadiusMeters < 1 {
+		return fmt.Errorf("geofence radius must be at least 1 meter, got %d", c.GeofenceRadiusMeters)
+	}
+
+	if c.GeocoderURL != "" && c.GeocoderTimeoutMs < 1 {
+		return fmt.Errorf("geocoder timeout must be at least 1 ms, got %d", c.GeocoderTimeoutMs)
+	}
+
+	if c.RoutingProvider != models.RoutingProviderGreatCircle {
+		return fmt.Errorf("routing provider must be %q, got %q", models.RoutingProviderGreatCircle, c.RoutingProvider)
+	}
+
+	if c.RoutingAverageSpeedKmh <= 0 {
+		return fmt.Errorf("routing average speed must be positive, got %v", c.RoutingAverageSpeedKmh)
+	}
+
+	if c.RoutingDetourFactor < 1 {
+		return fmt.Errorf("routing detour factor must be at least 1, got %v", c.RoutingDetourFactor)
+	}
+
+	if c.SLAAtRiskPercent < 1 || c.SLAAtRiskPercent > 99 {
+		return fmt.Errorf("SLA at-risk percent must be between 1 and 99, got %d", c.SLAAtRiskPercent)
+	}
+
+	if c.JobImportMaxRows < 1 {
+		return fmt.Errorf("job import row limit must be at least 1, got %d", c.JobImportMaxRows)
+	}
+
+	if c.JobImportMaxUploadMB < 1 {
+		return fmt.Errorf("job import size limit must be at least 1 MB, got %d", c.JobImportMaxUploadMB)
+	}
+
+	if c.SearchProvider != models.SearchProviderMemory {
+		return fmt.Errorf("search provider must be %q, got %q", models.SearchProviderMemory, c.SearchProvider)
+	}
+
+	if c.SearchMaxPageSize < 1 {
+		return fmt.Errorf("search page size limit must be at least 1, got %d", c.SearchMaxPageSize)
+	}
+
+	if c.NotificationProvider != models.NotificationProviderLog && c.NotificationProvider != models.NotificationProviderWebhook {
+		return fmt.Errorf("notification provider must be %q or %q, got %q", models.NotificationProviderLog, models.NotificationProviderWebhook, c.NotificationProvider)
+	}
+
+	if c.NotificationProvider == models.NotificationProviderWebhook && c.NotificationWebhookURL == "" {
+		return fmt.Errorf("notification webhook URL must be set for the webhook provider")
+	}
+
+	if c.NotificationTimeoutMs < 1 {
+		return fmt.Errorf("notification timeout must be at least 1 ms, got %d", c.NotificationTimeoutMs)
+	}
+
 	return nil
 }
 
@@ -230,6 +386,190 @@ func flattenNestedConfig(v *viper.Viper) {
 		v.Set("rate_limit_requests_per_minute", v.GetInt("rate_limit.requests_per_minute"))
 	}
 
+	// Cache section
+	if v.IsSet("cache.enabled") {
+		v.Set("repository_cache_enabled", v.GetBool("cache.enabled"))
+	}
+	if v.IsSet("cache.size") {
+		v.Set("repository_cache_size", v.GetInt("cache.size"))
+	}
+	if v.IsSet("cache.user_ttl_seconds") {
+		v.Set("user_cache_ttl_seconds", v.GetInt("cache.user_ttl_seconds"))
+	}
+	if v.IsSet("cache.role_ttl_seconds") {
+		v.Set("role_cache_ttl_seconds", v.GetInt("cache.role_ttl_seconds"))
+	}
+
+	// DAL section
+	if v.IsSet("dal.operation_timeout_ms") {
+		v.Set("dal_operation_timeout_ms", v.GetInt("dal.operation_timeout_ms"))
+	}
+	if v.IsSet("dal.scan_timeout_ms") {
+		v.Set("dal_scan_timeout_ms", v.GetInt("dal.scan_timeout_ms"))
+	}
+	if v.IsSet("dal.max_retries") {
+		v.Set("dal_max_retries", v.GetInt("dal.max_retries"))
+	}
+	if v.IsSet("dal.retry_base_delay_ms") {
+		v.Set("dal_retry_base_delay_ms", v.GetInt("dal.retry_base_delay_ms"))
+	}
+	if v.IsSet("dal.retry_max_delay_ms") {
+		v.Set("dal_retry_max_delay_ms", v.GetInt("dal.retry_max_delay_ms"))
+	}
+	if v.IsSet("dal.breaker_failure_percent") {
+		v.Set("dal_breaker_failure_percent", v.GetInt("dal.breaker_failure_percent"))
+	}
+	if v.IsSet("dal.breaker_min_requests") {
+		v.Set("dal_breaker_min_requests", v.GetInt("dal.breaker_min_requests"))
+	}
+	if v.IsSet("dal.breaker_window_seconds") {
+		v.Set("dal_breaker_window_seconds", v.GetInt("dal.breaker_window_seconds"))
+	}
+	if v.IsSet("dal.breaker_open_timeout_seconds") {
+		v.Set("dal_breaker_open_timeout_seconds", v.GetInt("dal.breaker_open_timeout_seconds"))
+	}
+
+	// Retention section
+	if v.IsSet("retention.soft_delete_days") {
+		v.Set("soft_delete_retention_days", v.GetInt("retention.soft_delete_days"))
+	}
+	if v.IsSet("retention.purge_schedule") {
+		v.Set("purge_cron_schedule", v.GetString("retention.purge_schedule"))
+	}
+
+	// Tenancy section
+	if v.IsSet("tenancy.mode") {
+		v.Set("tenancy_mode", v.GetString("tenancy.mode"))
+	}
+	if v.IsSet("tenancy.provision_schedule") {
+		v.Set("tenant_provision_schedule", v.GetString("tenancy.provision_schedule"))
+	}
+
+	// Recurrence section
+	if v.IsSet("recurrence.schedule") {
+		v.Set("recurrence_schedule", v.GetString("recurrence.schedule"))
+	}
+	if v.IsSet("recurrence.horizon_days") {
+		v.Set("recurrence_horizon_days", v.GetInt("recurrence.horizon_days"))
+	}
+
+	// Checklists section
+	if v.IsSet("checklists.required_to_complete") {
+		v.Set("checklist_required_to_complete", v.GetBool("checklists.required_to_complete"))
+	}
+
+	// Blob section
+	if v.IsSet("blob.backend") {
+		v.Set("blob_backend", v.GetString("blob.backend"))
+	}
+	if v.IsSet("blob.local_path") {
+		v.Set("blob_local_path", v.GetString("blob.local_path"))
+	}
+	if v.IsSet("blob.base_url") {
+		v.Set("blob_base_url", v.GetString("blob.base_url"))
+	}
+	if v.IsSet("blob.signing_secret") {
+		v.Set("blob_signing_secret", v.GetString("blob.signing_secret"))
+	}
+	if v.IsSet("blob.url_ttl_seconds") {
+		v.Set("blob_url_ttl_seconds", v.GetInt("blob.url_ttl_seconds"))
+	}
+	if v.IsSet("blob.max_upload_mb") {
+		v.Set("attachment_max_size_mb", v.GetInt("blob.max_upload_mb"))
+	}
+	if v.IsSet("blob.s3.endpoint") {
+		v.Set("blob_s3_endpoint", v.GetString("blob.s3.endpoint"))
+	}
+	if v.IsSet("blob.s3.bucket") {
+		v.Set("blob_s3_bucket", v.GetString("blob.s3.bucket"))
+	}
+	if v.IsSet("blob.s3.region") {
+		v.Set("blob_s3_region", v.GetString("blob.s3.region"))
+	}
+	if v.IsSet("blob.s3.path_style") {
+		v.Set("blob_s3_path_style", v.GetBool("blob.s3.path_style"))
+	}
+	if v.IsSet("blob.s3.access_key_id") {
+		v.Set("blob_s3_access_key_id", v.GetString("blob.s3.access_key_id"))
+	}
+	if v.IsSet("blob.s3.secret_access_key") {
+		v.Set("blob_s3_secret_access_key", v.GetString("blob.s3.secret_access_key"))
+	}
+
+	// Geofence section
+	if v.IsSet("geofence.radius_m") {
+		v.Set("geofence_radius_m", v.GetInt("geofence.radius_m"))
+	}
+	if v.IsSet("geofence.geocoder.url") {
+		v.Set("geocoder_url", v.GetString("geofence.geocoder.url"))
+	}
+	if v.IsSet("geofence.geocoder.user_agent") {
+		v.Set("geocoder_user_agent", v.GetString("geofence.geocoder.user_agent"))
+	}
+	if v.IsSet("geofence.geocoder.timeout_ms") {
+		v.Set("geocoder_timeout_ms", v.GetInt("geofence.geocoder.timeout_ms"))
+	}
+
+	// Routing section
+	if v.IsSet("routing.provider") {
+		v.Set("routing_provider", v.GetString("routing.provider"))
+	}
+	if v.IsSet("routing.average_speed_kmh") {
+		v.Set("routing_average_speed_kmh", v.GetFloat64("routing.average_speed_kmh"))
+	}
+	if v.IsSet("routing.detour_factor") {
+		v.Set("routing_detour_factor", v.GetFloat64("routing.detour_factor"))
+	}
+
+	// SLA section
+	if v.IsSet("sla.schedule") {
+		v.Set("sla_schedule", v.GetString("sla.schedule"))
+	}
+	if v.IsSet("sla.at_risk_percent") {
+		v.Set("sla_at_risk_percent", v.GetInt("sla.at_risk_percent"))
+	}
+
+	// Notifications section
+	if v.IsSet("notifications.provider") {
+		v.Set("notification_provider", v.GetString("notifications.provider"))
+	}
+	if v.IsSet("notifications.webhook.url") {
+		v.Set("notification_webhook_url", v.GetString("notifications.webhook.url"))
+	}
+	if v.IsSet("notifications.webhook.timeout_ms") {
+		v.Set("notification_timeout_ms", v.GetInt("notifications.webhook.timeout_ms"))
+	}
+
+	// Imports section
+	if v.IsSet("imports.max_rows") {
+		v.Set("job_import_max_rows", v.GetInt("imports.max_rows"))
+	}
+	if v.IsSet("imports.max_upload_mb") {
+		v.Set("job_import_max_upload_mb", v.GetInt("imports.max_upload_mb"))
+	}
+
+	// Search section
+	if v.IsSet("search.provider") {
+		v.Set("search_provider", v.GetString("search.provider"))
+	}
+	if v.IsSet("search.reindex_schedule") {
+		v.Set("search_reindex_schedule", v.GetString("search.reindex_schedule"))
+	}
+	if v.IsSet("search.max_page_size") {
+		v.Set("search_max_page_size", v.GetInt("search.max_page_size"))
+	}
+
+	// Streams section
+	if v.IsSet("streams.enabled") {
+		v.Set("stream_consumer_enabled", v.GetBool("streams.enabled"))
+	}
+	if v.IsSet("streams.poll_interval_ms") {
+		v.Set("stream_poll_interval_ms", v.GetInt("streams.poll_interval_ms"))
+	}
+	if v.IsSet("streams.batch_size") {
+		v.Set("stream_batch_size", v.GetInt("streams.batch_size"))
+	}
+
 	// Base Path
 	if v.IsSet("basePath") {
 		v.Set("basePath", v.GetString("basePath"))
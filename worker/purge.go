@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// PurgeWorker permanently removes soft-deleted items once their retention
+// period has passed. DynamoDB TTL on the purgeAt attribute does the bulk of
+// the work; the scheduled sweep covers TTL's deletion lag and environments
+// such as DynamoDB Local that do not expire items.
+type PurgeWorker struct {
+	db     dal.DatabaseClientInterface
+	config *models.Config
+	logger logger.Logger
+	cron   *cron.Cron
+}
+
+// NewPurgeWorker creates a retention purge worker
+func NewPurgeWorker(cfg *models.Config, log logger.Logger) (*PurgeWorker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	return &PurgeWorker{
+		db:     dal.NewResilientClient(dbClient, cfg, log),
+		config: cfg,
+		logger: log,
+		cron:   cron.New(),
+	}, nil
+}
+
+// Start enables TTL on the soft-deletable tables and schedules the sweep.
+// It does nothing when retention is disabled.
+func (p *PurgeWorker) Start() error {
+	if p.config.SoftDeleteRetentionDays <= 0 {
+		p.logger.Info("Soft delete retention disabled, purge worker not started")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	p.enableTTL(ctx)
+
+	if err := p.cron.AddFunc(p.config.PurgeCronSchedule, p.sweepJob); err != nil {
+		return fmt.Errorf("failed to add purge job: %w", err)
+	}
+	p.cron.Start()
+
+	p.logger.Infof("Purge worker started with schedule %s and %d day retention", p.config.PurgeCronSchedule, p.config.SoftDeleteRetentionDays)
+	return nil
+}
+
+// Stop stops the scheduled sweep
+func (p *PurgeWorker) Stop() {
+	p.cron.Stop()
+}
+
+// enableTTL turns on DynamoDB TTL for every soft-deletable table. Failures
+// are logged only, as the sweep still purges items without TTL.
+func (p *PurgeWorker) enableTTL(ctx context.Context) {
+	for _, table := range repository.SoftDeleteTables() {
+		tableName := table.FullName(p.config.DynamoDBTablePrefix)
+		if err := p.db.EnableTTL(ctx, tableName, repository.PurgeAtAttribute); err != nil {
+			p.logger.Warnf("Failed to enable TTL on %s: %v", tableName, err)
+		}
+	}
+}
+
+// sweepJob is the cron entry point for Sweep
+func (p *PurgeWorker) sweepJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	purged, err := p.Sweep(ctx)
+	if err != nil {
+		p.logger.Errorf("Purge sweep finished with errors after removing %d items: %v", purged, err)
+		return
+	}
+	p.logger.Infof("Purge sweep removed %d expired items", purged)
+}
+
+// Sweep deletes every soft-deleted item whose purgeAt has passed and returns
+// how many were removed
+func (p *PurgeWorker) Sweep(ctx context.Context) (int, error) {
+	now := time.Now().Unix()
+	expired := dal.And(
+		dal.AttributeExists("deletedData"),
+		dal.LessOrEqual(repository.PurgeAtAttribute, now),
+	)
+
+	purged := 0
+	var errs []error
+
+	for _, table := range repository.SoftDeleteTables() {
+		// In isolated tenancy mode every organization has its own copy
+		tableNames, err := repository.DeployedTableNames(ctx, p.db, p.config, table)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list tables of %s: %w", table.Name, err))
+			continue
+		}
+
+		for _, tableName := range tableNames {
+			removed, err := p.sweepTable(ctx, tableName, table.PartitionKey, expired)
+			purged += removed
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+// sweepTable deletes the expired items of one table and returns how many
+// were removed
+func (p *PurgeWorker) sweepTable(ctx context.Context, tableName, keyName string, expired dal.Condition) (int, error) {
+	var items []map[string]interface{}
+	if err := p.db.ScanWhere(ctx, tableName, expired, &items); err != nil {
+		if dal.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("scan %s: %w", tableName, err)
+	}
+
+	purged := 0
+	var errs []error
+
+	for _, item := range items {
+		key, ok := item[keyName].(string)
+		if !ok || key == "" {
+			continue
+		}
+
+		// Re-check the condition so an item restored since the scan survives
+		err := p.db.DeleteItem(ctx, tableName, keyName, key, expired)
+		if err != nil {
+			if dal.IsConflict(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("delete %s from %s: %w", key, tableName, err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/search"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// RecurrenceWorker materialises the upcoming instances of recurring jobs on
+// a schedule, keeping the configured number of days ahead filled
+type RecurrenceWorker struct {
+	recurring services.RecurringJobServiceInterface
+	db        dal.DatabaseClientInterface
+	config    *models.Config
+	logger    logger.Logger
+	cron      *cron.Cron
+}
+
+// NewRecurrenceWorker creates a recurring job worker. The instances it
+// creates are indexed in jobIndex, which may be nil.
+func NewRecurrenceWorker(cfg *models.Config, log logger.Logger, jobIndex search.Index) (*RecurrenceWorker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	db := dal.NewResilientClient(dbClient, cfg, log)
+
+	var jobRepo repository.JobRepositoryInterface = repository.NewJobRepository(db, cfg, log)
+	if jobIndex != nil {
+		jobRepo = repository.NewIndexedJobRepository(jobRepo, jobIndex, log)
+	}
+
+	return &RecurrenceWorker{
+		recurring: services.NewRecurringJobService(
+			repository.NewRecurringJobRepository(db, cfg, log),
+			jobRepo,
+			repository.NewOrganizationRepository(db, cfg, log),
+			repository.NewChecklistRepository(db, cfg, log),
+			cfg.RecurrenceHorizonDays,
+			log,
+		),
+		db:     db,
+		config: cfg,
+		logger: log,
+		cron:   cron.New(),
+	}, nil
+}
+
+// Start runs a first materialisation and schedules the next ones
+func (r *RecurrenceWorker) Start() error {
+	r.materializeJob()
+
+	if err := r.cron.AddFunc(r.config.RecurrenceSchedule, r.materializeJob); err != nil {
+		return fmt.Errorf("failed to add recurrence job: %w", err)
+	}
+	r.cron.Start()
+
+	r.logger.Infof("Recurrence worker started with schedule %s and %d day horizon", r.config.RecurrenceSchedule, r.config.RecurrenceHorizonDays)
+	return nil
+}
+
+// Stop stops the scheduled materialisation
+func (r *RecurrenceWorker) Stop() {
+	r.cron.Stop()
+}
+
+// materializeJob is the cron entry point for MaterializeDue
+func (r *RecurrenceWorker) materializeJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	orgIDs, err := repository.TenantIDs(ctx, r.db, r.config)
+	if err != nil {
+		r.logger.Errorf("Recurring job materialisation skipped: %v", err)
+		return
+	}
+
+	if err := r.recurring.MaterializeDue(ctx, orgIDs); err != nil {
+		r.logger.Errorf("Recurring job materialisation finished with errors: %v", err)
+	}
+}
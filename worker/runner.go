@@ -4,26 +4,78 @@ import (
 	"context"
 	"fieldfuze-backend/models"
 	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/search"
 	"fmt"
 	"time"
 )
 
 // Service wraps the infrastructure worker for easy integration
 type Service struct {
-	worker *models.Worker
-	logger logger.Logger
+	worker    *models.Worker
+	purge     *PurgeWorker
+	tenants   *TenantProvisioner
+	recurring *RecurrenceWorker
+	sla       *SLAWorker
+	search    *SearchIndexer
+	streams   *StreamConsumer
+	logger    logger.Logger
 }
 
-// NewService creates a new worker service
-func NewService(ctx context.Context, cfg *models.Config, log logger.Logger) (*Service, error) {
+// NewService creates a new worker service. Jobs the workers write are
+// indexed in jobIndex, which is also rebuilt on a schedule.
+func NewService(ctx context.Context, cfg *models.Config, log logger.Logger, jobIndex search.Index) (*Service, error) {
 	worker, err := NewWorker(ctx, cfg, log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create infrastructure worker: %w", err)
 	}
 
+	purge, err := NewPurgeWorker(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create purge worker: %w", err)
+	}
+
+	tenants, err := NewTenantProvisioner(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant provisioner: %w", err)
+	}
+
+	recurring, err := NewRecurrenceWorker(cfg, log, jobIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recurrence worker: %w", err)
+	}
+
+	sla, err := NewSLAWorker(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SLA worker: %w", err)
+	}
+
+	indexer, err := NewSearchIndexer(cfg, jobIndex, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search indexer: %w", err)
+	}
+
+	var streams *StreamConsumer
+	if cfg.StreamConsumerEnabled {
+		streams, err = NewDynamoStreamConsumer(ctx, cfg, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stream consumer: %w", err)
+		}
+		// New organizations get their tables without waiting for the reconcile
+		streams.OnOrganizationChange(tenants.OnOrganizationChange)
+		// Jobs written by other instances become searchable without waiting
+		// for the next rebuild
+		streams.OnJobChange(indexer.OnJobChange)
+	}
+
 	return &Service{
-		worker: worker,
-		logger: log,
+		worker:    worker,
+		purge:     purge,
+		tenants:   tenants,
+		recurring: recurring,
+		sla:       sla,
+		search:    indexer,
+		streams:   streams,
+		logger:    log,
 	}, nil
 }
 
@@ -43,6 +95,42 @@ func (s *Service) StartInBackground() error {
 		}
 	}()
 
+	go func() {
+		if err := s.purge.Start(); err != nil {
+			s.logger.Errorf("Purge worker failed to start: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := s.tenants.Start(); err != nil {
+			s.logger.Errorf("Tenant provisioner failed to start: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := s.recurring.Start(); err != nil {
+			s.logger.Errorf("Recurrence worker failed to start: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := s.sla.Start(); err != nil {
+			s.logger.Errorf("SLA worker failed to start: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := s.search.Start(); err != nil {
+			s.logger.Errorf("Search indexer failed to start: %v", err)
+		}
+	}()
+
+	if s.streams != nil {
+		if err := s.streams.Start(context.Background()); err != nil {
+			return fmt.Errorf("failed to start stream consumer: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -52,9 +140,23 @@ func (s *Service) Stop() error {
 	worker := s.worker
 	w := &Worker{Worker: worker} // Use
 	s.logger.Info("Stopping infrastructure worker service")
+	s.purge.Stop()
+	s.tenants.Stop()
+	s.recurring.Stop()
+	s.sla.Stop()
+	s.search.Stop()
+	if s.streams != nil {
+		s.streams.Stop()
+	}
 	return w.Stop()
 }
 
+// Streams returns the change stream consumer handlers subscribe to, or nil
+// when the consumer is disabled
+func (s *Service) Streams() *StreamConsumer {
+	return s.streams
+}
+
 // GetStatus returns the current infrastructure setup status
 func (s *Service) GetStatus() (*models.ExecutionResult, error) {
 	// Use the models.Worker directly without copying it
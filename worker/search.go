@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"fieldfuze-backend/utils/search"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// SearchIndexer rebuilds the job search index of every organization on start
+// and on a schedule. Between rebuilds the index follows the jobs this
+// instance writes through its job repository and, with the stream consumer
+// enabled, the job changes of every instance.
+type SearchIndexer struct {
+	index  search.Index
+	jobs   repository.JobRepositoryInterface
+	db     dal.DatabaseClientInterface
+	config *models.Config
+	logger logger.Logger
+	cron   *cron.Cron
+}
+
+// NewSearchIndexer creates a search indexer for index
+func NewSearchIndexer(cfg *models.Config, index search.Index, log logger.Logger) (*SearchIndexer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	db := dal.NewResilientClient(dbClient, cfg, log)
+
+	return &SearchIndexer{
+		index:  index,
+		jobs:   repository.NewJobRepository(db, cfg, log),
+		db:     db,
+		config: cfg,
+		logger: log,
+		cron:   cron.New(),
+	}, nil
+}
+
+// Start runs a first rebuild and schedules the next ones
+func (s *SearchIndexer) Start() error {
+	s.reindexJob()
+
+	if err := s.cron.AddFunc(s.config.SearchReindexSchedule, s.reindexJob); err != nil {
+		return fmt.Errorf("failed to add search reindex job: %w", err)
+	}
+	s.cron.Start()
+
+	s.logger.Infof("Search indexer started with schedule %s", s.config.SearchReindexSchedule)
+	return nil
+}
+
+// Stop stops the scheduled rebuilds
+func (s *SearchIndexer) Stop() {
+	s.cron.Stop()
+}
+
+// OnJobChange indexes a changed job, or drops it once it is deleted or
+// purged
+func (s *SearchIndexer) OnJobChange(ctx context.Context, event models.JobChangeEvent) error {
+	if event.Type == models.ChangeRemove {
+		if event.OldImage == nil {
+			return nil
+		}
+		return s.index.Delete(ctx, event.OldImage.JobID)
+	}
+	if event.NewImage == nil {
+		return nil
+	}
+	return repository.IndexJob(ctx, s.index, event.NewImage)
+}
+
+// reindexJob is the cron entry point for Reindex
+func (s *SearchIndexer) reindexJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if err := s.Reindex(ctx); err != nil {
+		s.logger.Errorf("Search reindex finished with errors: %v", err)
+	}
+}
+
+// Reindex replaces the indexed jobs of every organization with the jobs
+// stored for it. Organizations that fail keep their previous documents.
+func (s *SearchIndexer) Reindex(ctx context.Context) error {
+	orgIDs, err := repository.TenantIDs(ctx, s.db, s.config)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	indexed := 0
+	for _, orgID := range orgIDs {
+		jobs, err := s.jobs.GetJobsByFilter(requestctx.WithTenant(ctx, orgID), &models.JobFilter{OrgID: orgID})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read the jobs of organization %s: %w", orgID, err))
+			continue
+		}
+
+		docs := make([]*search.Document, 0, len(jobs))
+		for _, job := range jobs {
+			docs = append(docs, repository.JobDocument(job))
+		}
+		if err := s.index.Replace(ctx, orgID, docs); err != nil {
+			errs = append(errs, fmt.Errorf("failed to index the jobs of organization %s: %w", orgID, err))
+			continue
+		}
+		indexed += len(docs)
+	}
+
+	s.logger.Infof("Search index rebuilt with %d jobs of %d organizations", indexed, len(orgIDs))
+	return errors.Join(errs...)
+}
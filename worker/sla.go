@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/services"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/notify"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// SLAWorker checks the open jobs of every organization against their SLA
+// targets on a schedule and alerts supervisors of jobs at risk or breached
+type SLAWorker struct {
+	sla    services.SLAServiceInterface
+	db     dal.DatabaseClientInterface
+	config *models.Config
+	logger logger.Logger
+	cron   *cron.Cron
+}
+
+// NewSLAWorker creates an SLA worker
+func NewSLAWorker(cfg *models.Config, log logger.Logger) (*SLAWorker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	db := dal.NewResilientClient(dbClient, cfg, log)
+
+	notifier, err := notify.New(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifier: %w", err)
+	}
+
+	return &SLAWorker{
+		sla: services.NewSLAService(
+			repository.NewSLAPolicyRepository(db, cfg, log),
+			repository.NewJobRepository(db, cfg, log),
+			repository.NewOrganizationRepository(db, cfg, log),
+			repository.NewUserRepository(db, cfg, log),
+			repository.NewActivityRepository(db, cfg, log),
+			notifier,
+			cfg.SLAAtRiskPercent,
+			log,
+		),
+		db:     db,
+		config: cfg,
+		logger: log,
+		cron:   cron.New(),
+	}, nil
+}
+
+// Start runs a first check and schedules the next ones
+func (w *SLAWorker) Start() error {
+	w.checkJob()
+
+	if err := w.cron.AddFunc(w.config.SLASchedule, w.checkJob); err != nil {
+		return fmt.Errorf("failed to add SLA job: %w", err)
+	}
+	w.cron.Start()
+
+	w.logger.Infof("SLA worker started with schedule %s, at risk within %d%% of a target", w.config.SLASchedule, w.config.SLAAtRiskPercent)
+	return nil
+}
+
+// Stop stops the scheduled checks
+func (w *SLAWorker) Stop() {
+	w.cron.Stop()
+}
+
+// checkJob is the cron entry point for CheckSLAs
+func (w *SLAWorker) checkJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	orgIDs, err := repository.TenantIDs(ctx, w.db, w.config)
+	if err != nil {
+		w.logger.Errorf("SLA check skipped: %v", err)
+		return
+	}
+
+	if err := w.sla.CheckSLAs(ctx, orgIDs); err != nil {
+		w.logger.Errorf("SLA check finished with errors: %v", err)
+	}
+}
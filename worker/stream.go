@@ -0,0 +1,362 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// streamShardRefreshInterval is how often the consumer looks for new shards
+// and restarts shards whose tail stopped on an error
+const streamShardRefreshInterval = 30 * time.Second
+
+// ChangeHandler reacts to a change of an item of type T. Returning an error
+// stops the shard at the failed record, which is retried from the last
+// checkpoint, so handlers must be idempotent.
+type ChangeHandler[T any] func(ctx context.Context, event models.ChangeEvent[T]) error
+
+// recordHandler decodes a raw record for a typed ChangeHandler
+type recordHandler func(ctx context.Context, table string, record StreamRecord) error
+
+// StreamConsumer tails the DynamoDB Streams of the subscribed tables and
+// dispatches every change to the handlers registered for its table. It keeps
+// one reader per shard, reads child shards only after their parent is
+// exhausted and checkpoints after every batch, giving at-least-once delivery
+// in item order.
+type StreamConsumer struct {
+	source      StreamSource
+	checkpoints CheckpointStore
+	config      *models.Config
+	logger      logger.Logger
+	tableNames  func(ctx context.Context, table repository.TableDefinition) ([]string, error)
+
+	mu       sync.Mutex
+	tables   map[string]repository.TableDefinition
+	handlers map[string][]recordHandler
+	tailing  map[string]bool
+	finished map[string]bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewStreamConsumer creates a consumer reading from source
+func NewStreamConsumer(source StreamSource, checkpoints CheckpointStore, cfg *models.Config, log logger.Logger) *StreamConsumer {
+	return &StreamConsumer{
+		source:      source,
+		checkpoints: checkpoints,
+		config:      cfg,
+		logger:      log,
+		tableNames: func(ctx context.Context, table repository.TableDefinition) ([]string, error) {
+			return []string{table.FullName(cfg.DynamoDBTablePrefix)}, nil
+		},
+		tables:   make(map[string]repository.TableDefinition),
+		handlers: make(map[string][]recordHandler),
+		tailing:  make(map[string]bool),
+		finished: make(map[string]bool),
+	}
+}
+
+// NewDynamoStreamConsumer creates a consumer reading DynamoDB Streams and
+// checkpointing to the checkpoints table
+func NewDynamoStreamConsumer(ctx context.Context, cfg *models.Config, log logger.Logger) (*StreamConsumer, error) {
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	db := dal.NewResilientClient(dbClient, cfg, log)
+
+	source, err := NewDynamoStreamSource(ctx, db, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream source: %w", err)
+	}
+
+	consumer := NewStreamConsumer(source, NewDynamoCheckpointStore(db, cfg), cfg, log)
+	// Tenant tables are tailed alongside the shared table
+	consumer.tableNames = func(ctx context.Context, table repository.TableDefinition) ([]string, error) {
+		return repository.DeployedTableNames(ctx, db, cfg, table)
+	}
+	return consumer, nil
+}
+
+// Subscribe registers handler for changes to table, decoding the item
+// images into T. Handlers of a table run in registration order. Subscribing
+// to a table after Start begins tailing it at its last checkpoint.
+func Subscribe[T any](c *StreamConsumer, table repository.TableDefinition, handler ChangeHandler[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, known := c.tables[table.Name]
+	c.tables[table.Name] = table
+	c.handlers[table.Name] = append(c.handlers[table.Name], func(ctx context.Context, tableName string, record StreamRecord) error {
+		event, err := decodeChangeEvent[T](tableName, record)
+		if err != nil {
+			// Retrying cannot fix a record that does not decode, so skip it
+			c.logger.Errorf("Skipping undecodable %s stream record %s: %v", tableName, record.EventID, err)
+			return nil
+		}
+		return handler(ctx, event)
+	})
+
+	if !known && c.ctx != nil {
+		c.wg.Add(1)
+		go c.tailTable(c.ctx, table)
+	}
+}
+
+// OnJobChange registers a handler for job changes
+func (c *StreamConsumer) OnJobChange(handler ChangeHandler[models.Job]) {
+	Subscribe(c, repository.JobsTable, handler)
+}
+
+// OnUserChange registers a handler for user changes
+func (c *StreamConsumer) OnUserChange(handler ChangeHandler[models.User]) {
+	Subscribe(c, repository.UsersTable, handler)
+}
+
+// OnOrganizationChange registers a handler for organization changes
+func (c *StreamConsumer) OnOrganizationChange(handler ChangeHandler[models.Organization]) {
+	Subscribe(c, repository.OrganizationsTable, handler)
+}
+
+// Start begins tailing every subscribed table
+func (c *StreamConsumer) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ctx != nil {
+		return errors.New("stream consumer already started")
+	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+
+	names := make([]string, 0, len(c.tables))
+	for name := range c.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.wg.Add(1)
+		go c.tailTable(c.ctx, c.tables[name])
+	}
+
+	c.logger.Infof("Stream consumer started for tables %v", names)
+	return nil
+}
+
+// Stop stops all readers and waits for in-flight batches to finish
+func (c *StreamConsumer) Stop() {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	c.wg.Wait()
+}
+
+// tailTable keeps a reader running for every readable shard of the streams
+// of each deployed copy of the table until ctx is done
+func (c *StreamConsumer) tailTable(ctx context.Context, table repository.TableDefinition) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(streamShardRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		// Tables and streams are resolved every time as tenant tables are
+		// added and streams change when disabled and re-enabled on a table
+		tableNames, err := c.tableNames(ctx, table)
+		if err != nil {
+			c.logger.Warnf("Failed to resolve tables of %s: %v", table.Name, err)
+		}
+		for _, tableName := range tableNames {
+			streamArn, err := c.source.StreamArn(ctx, tableName)
+			if err != nil {
+				c.logger.Warnf("Failed to resolve stream of %s: %v", tableName, err)
+				continue
+			}
+			c.startShards(ctx, table.Name, tableName, streamArn)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startShards starts a reader for every shard that is neither being read
+// nor finished and whose parent, if still in the stream, has been read to
+// the end. Records are dispatched to the handlers of table as changes of
+// tableName.
+func (c *StreamConsumer) startShards(ctx context.Context, table, tableName, streamArn string) {
+	shards, err := c.source.Shards(ctx, streamArn)
+	if err != nil {
+		c.logger.Warnf("Failed to list shards of %s: %v", streamArn, err)
+		return
+	}
+
+	present := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		present[shard.ID] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, shard := range shards {
+		key := shardKey(streamArn, shard.ID)
+		if c.tailing[key] || c.finished[key] {
+			continue
+		}
+		if shard.ParentID != "" && present[shard.ParentID] && !c.finished[shardKey(streamArn, shard.ParentID)] {
+			continue
+		}
+
+		c.tailing[key] = true
+		c.wg.Add(1)
+		go c.tailShard(ctx, table, tableName, streamArn, shard.ID)
+	}
+}
+
+// tailShard reads one shard and records whether it was read to the end
+func (c *StreamConsumer) tailShard(ctx context.Context, table, tableName, streamArn, shardID string) {
+	defer c.wg.Done()
+
+	err := c.readShard(ctx, table, tableName, streamArn, shardID)
+
+	key := shardKey(streamArn, shardID)
+	c.mu.Lock()
+	delete(c.tailing, key)
+	if err == nil {
+		c.finished[key] = true
+	}
+	c.mu.Unlock()
+
+	switch {
+	case err == nil:
+		// Children of the shard can be read now
+		c.logger.Infof("Finished reading shard %s of %s", shardID, tableName)
+		c.startShards(ctx, table, tableName, streamArn)
+	case ctx.Err() != nil:
+	default:
+		c.logger.Errorf("Stopped reading shard %s of %s, retrying from the last checkpoint: %v", shardID, tableName, err)
+	}
+}
+
+// readShard dispatches the shard's records from the last checkpoint on. It
+// returns nil once a closed shard has been read to the end.
+func (c *StreamConsumer) readShard(ctx context.Context, table, tableName, streamArn, shardID string) error {
+	sequence, err := c.checkpoints.Checkpoint(ctx, streamArn, shardID)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	iterator, err := c.source.ShardIterator(ctx, streamArn, shardID, sequence)
+	if err != nil {
+		return fmt.Errorf("failed to get shard iterator: %w", err)
+	}
+
+	pollInterval := time.Duration(c.config.StreamPollIntervalMs) * time.Millisecond
+
+	for iterator != "" {
+		records, next, err := c.source.Records(ctx, iterator, c.config.StreamBatchSize)
+		if errors.Is(err, ErrIteratorExpired) {
+			iterator, err = c.source.ShardIterator(ctx, streamArn, shardID, sequence)
+			if err != nil {
+				return fmt.Errorf("failed to renew shard iterator: %w", err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read records: %w", err)
+		}
+
+		processed := sequence
+		for _, record := range records {
+			if err := c.dispatch(ctx, table, tableName, record); err != nil {
+				c.saveCheckpoint(ctx, streamArn, shardID, sequence, processed)
+				return fmt.Errorf("handler failed on record %s: %w", record.EventID, err)
+			}
+			processed = record.SequenceNumber
+		}
+
+		if err := c.saveCheckpoint(ctx, streamArn, shardID, sequence, processed); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		sequence = processed
+		iterator = next
+
+		// An open shard with nothing new is polled at the configured interval
+		if len(records) == 0 && iterator != "" {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+
+	return nil
+}
+
+// saveCheckpoint stores processed when it moved past previous
+func (c *StreamConsumer) saveCheckpoint(ctx context.Context, streamArn, shardID, previous, processed string) error {
+	if processed == previous {
+		return nil
+	}
+	return c.checkpoints.SaveCheckpoint(ctx, streamArn, shardID, processed)
+}
+
+// dispatch passes record, a change of tableName, to every handler of table
+// in order
+func (c *StreamConsumer) dispatch(ctx context.Context, table, tableName string, record StreamRecord) error {
+	c.mu.Lock()
+	handlers := c.handlers[table]
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, tableName, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeChangeEvent unmarshals the images of record into T
+func decodeChangeEvent[T any](table string, record StreamRecord) (models.ChangeEvent[T], error) {
+	event := models.ChangeEvent[T]{
+		EventID:        record.EventID,
+		Type:           record.EventName,
+		Table:          table,
+		SequenceNumber: record.SequenceNumber,
+		CreatedAt:      record.CreatedAt,
+	}
+
+	if len(record.OldImage) > 0 {
+		event.OldImage = new(T)
+		if err := attributevalue.UnmarshalMap(record.OldImage, event.OldImage); err != nil {
+			return event, fmt.Errorf("failed to decode old image: %w", err)
+		}
+	}
+	if len(record.NewImage) > 0 {
+		event.NewImage = new(T)
+		if err := attributevalue.UnmarshalMap(record.NewImage, event.NewImage); err != nil {
+			return event, fmt.Errorf("failed to decode new image: %w", err)
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists how far the stream consumer got on each shard so
+// that it resumes there after a restart
+type CheckpointStore interface {
+	// Checkpoint returns the last processed sequence number of the shard, or
+	// an empty string when the shard has not been read yet
+	Checkpoint(ctx context.Context, streamArn, shardID string) (string, error)
+	SaveCheckpoint(ctx context.Context, streamArn, shardID, sequenceNumber string) error
+}
+
+// shardKey identifies a shard across streams
+func shardKey(streamArn, shardID string) string {
+	return streamArn + "/" + shardID
+}
+
+// DynamoCheckpointStore keeps checkpoints in the checkpoints table
+type DynamoCheckpointStore struct {
+	checkpoints *repository.Repository[models.StreamCheckpoint]
+}
+
+// NewDynamoCheckpointStore creates a checkpoint store on db
+func NewDynamoCheckpointStore(db dal.DatabaseClientInterface, cfg *models.Config) *DynamoCheckpointStore {
+	return &DynamoCheckpointStore{
+		checkpoints: repository.NewTypedRepository[models.StreamCheckpoint](db, cfg, repository.CheckpointsTable),
+	}
+}
+
+// Checkpoint returns the stored sequence number of the shard
+func (s *DynamoCheckpointStore) Checkpoint(ctx context.Context, streamArn, shardID string) (string, error) {
+	checkpoint, err := s.checkpoints.ByID(ctx, shardKey(streamArn, shardID))
+	if err != nil {
+		if dal.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return checkpoint.SequenceNumber, nil
+}
+
+// SaveCheckpoint stores the sequence number of the shard
+func (s *DynamoCheckpointStore) SaveCheckpoint(ctx context.Context, streamArn, shardID, sequenceNumber string) error {
+	return s.checkpoints.Put(ctx, &models.StreamCheckpoint{
+		ShardKey:       shardKey(streamArn, shardID),
+		StreamArn:      streamArn,
+		ShardID:        shardID,
+		SequenceNumber: sequenceNumber,
+		UpdatedAt:      time.Now().UTC(),
+	})
+}
+
+// MemoryCheckpointStore keeps checkpoints in process. It is meant for tests
+// and local runs against FakeStream.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]string
+}
+
+// NewMemoryCheckpointStore creates an empty in-process checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]string)}
+}
+
+// Checkpoint returns the stored sequence number of the shard
+func (s *MemoryCheckpointStore) Checkpoint(ctx context.Context, streamArn, shardID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[shardKey(streamArn, shardID)], nil
+}
+
+// SaveCheckpoint stores the sequence number of the shard
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, streamArn, shardID, sequenceNumber string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[shardKey(streamArn, shardID)] = sequenceNumber
+	return nil
+}
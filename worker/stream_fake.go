@@ -0,0 +1,230 @@
+package worker
+
+import (
+	"context"
+	"fieldfuze-backend/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// FakeStream is an in-memory StreamSource for tests and local runs without
+// DynamoDB Streams. Changes are appended with Insert, Modify and Remove and
+// read back through the same interface the consumer uses against AWS.
+type FakeStream struct {
+	mu         sync.Mutex
+	streams    map[string]*fakeTableStream
+	sequence   int64
+	generation int
+}
+
+// fakeTableStream is the stream of one table
+type fakeTableStream struct {
+	arn    string
+	shards []*fakeShard
+}
+
+// fakeShard holds the records of one shard; only the last shard is open
+type fakeShard struct {
+	id       string
+	parentID string
+	records  []StreamRecord
+	closed   bool
+}
+
+// NewFakeStream creates an empty fake stream
+func NewFakeStream() *FakeStream {
+	return &FakeStream{streams: make(map[string]*fakeTableStream)}
+}
+
+// Insert records the creation of item in tableName
+func (f *FakeStream) Insert(tableName string, item interface{}) error {
+	return f.Put(tableName, models.ChangeInsert, nil, item)
+}
+
+// Modify records a change of an item in tableName from oldItem to newItem
+func (f *FakeStream) Modify(tableName string, oldItem, newItem interface{}) error {
+	return f.Put(tableName, models.ChangeModify, oldItem, newItem)
+}
+
+// Remove records the deletion of item from tableName
+func (f *FakeStream) Remove(tableName string, item interface{}) error {
+	return f.Put(tableName, models.ChangeRemove, item, nil)
+}
+
+// Put appends a change to the open shard of tableName. Either image may be
+// nil.
+func (f *FakeStream) Put(tableName string, change models.ChangeType, oldItem, newItem interface{}) error {
+	record := StreamRecord{EventName: change, CreatedAt: time.Now().UTC()}
+
+	var err error
+	if oldItem != nil {
+		if record.OldImage, err = attributevalue.MarshalMap(oldItem); err != nil {
+			return fmt.Errorf("failed to marshal old image: %w", err)
+		}
+	}
+	if newItem != nil {
+		if record.NewImage, err = attributevalue.MarshalMap(newItem); err != nil {
+			return fmt.Errorf("failed to marshal new image: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sequence++
+	record.SequenceNumber = fmt.Sprintf("%021d", f.sequence)
+	record.EventID = "fake-" + record.SequenceNumber
+
+	shards := f.stream(tableName).shards
+	shard := shards[len(shards)-1]
+	shard.records = append(shard.records, record)
+	return nil
+}
+
+// SplitShard closes the open shard of tableName and opens a child shard, as
+// DynamoDB does when it rotates shards
+func (f *FakeStream) SplitShard(tableName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stream := f.stream(tableName)
+	parent := stream.shards[len(stream.shards)-1]
+	parent.closed = true
+	stream.shards = append(stream.shards, &fakeShard{
+		id:       fmt.Sprintf("shard-%04d", len(stream.shards)),
+		parentID: parent.id,
+	})
+}
+
+// ExpireIterators invalidates every iterator handed out so far
+func (f *FakeStream) ExpireIterators() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.generation++
+}
+
+// StreamArn returns the stream of tableName, creating it when needed
+func (f *FakeStream) StreamArn(ctx context.Context, tableName string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stream(tableName).arn, nil
+}
+
+// Shards lists the shards of the stream, parents first
+func (f *FakeStream) Shards(ctx context.Context, streamArn string) ([]StreamShard, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stream, err := f.streamByArn(streamArn)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]StreamShard, 0, len(stream.shards))
+	for _, shard := range stream.shards {
+		shards = append(shards, StreamShard{ID: shard.id, ParentID: shard.parentID})
+	}
+	return shards, nil
+}
+
+// ShardIterator returns an iterator after sequenceNumber, or at the first
+// record when sequenceNumber is empty
+func (f *FakeStream) ShardIterator(ctx context.Context, streamArn, shardID, sequenceNumber string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	shard, err := f.shard(streamArn, shardID)
+	if err != nil {
+		return "", err
+	}
+
+	position := 0
+	if sequenceNumber != "" {
+		for i, record := range shard.records {
+			if record.SequenceNumber <= sequenceNumber {
+				position = i + 1
+			}
+		}
+	}
+	return f.iterator(streamArn, shardID, position), nil
+}
+
+// Records returns up to limit records from the iterator's position
+func (f *FakeStream) Records(ctx context.Context, iterator string, limit int) ([]StreamRecord, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(iterator, "|")
+	if len(parts) != 4 {
+		return nil, "", fmt.Errorf("invalid shard iterator %q", iterator)
+	}
+	if generation, _ := strconv.Atoi(parts[3]); generation != f.generation {
+		return nil, "", ErrIteratorExpired
+	}
+
+	streamArn, shardID := parts[0], parts[1]
+	position, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid shard iterator %q", iterator)
+	}
+
+	shard, err := f.shard(streamArn, shardID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	end := len(shard.records)
+	if limit > 0 && position+limit < end {
+		end = position + limit
+	}
+	records := append([]StreamRecord(nil), shard.records[position:end]...)
+
+	if shard.closed && end == len(shard.records) {
+		return records, "", nil
+	}
+	return records, f.iterator(streamArn, shardID, end), nil
+}
+
+// stream returns the stream of tableName, creating it with one open shard
+func (f *FakeStream) stream(tableName string) *fakeTableStream {
+	stream, ok := f.streams[tableName]
+	if !ok {
+		stream = &fakeTableStream{
+			arn:    "arn:fake:dynamodb:stream/" + tableName,
+			shards: []*fakeShard{{id: "shard-0000"}},
+		}
+		f.streams[tableName] = stream
+	}
+	return stream
+}
+
+func (f *FakeStream) streamByArn(streamArn string) (*fakeTableStream, error) {
+	for _, stream := range f.streams {
+		if stream.arn == streamArn {
+			return stream, nil
+		}
+	}
+	return nil, fmt.Errorf("stream %s not found", streamArn)
+}
+
+func (f *FakeStream) shard(streamArn, shardID string) (*fakeShard, error) {
+	stream, err := f.streamByArn(streamArn)
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range stream.shards {
+		if shard.id == shardID {
+			return shard, nil
+		}
+	}
+	return nil, fmt.Errorf("shard %s not found in %s", shardID, streamArn)
+}
+
+func (f *FakeStream) iterator(streamArn, shardID string, position int) string {
+	return fmt.Sprintf("%s|%s|%d|%d", streamArn, shardID, position, f.generation)
+}
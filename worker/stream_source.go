@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+// ErrIteratorExpired is returned by a StreamSource when a shard iterator can
+// no longer be used and has to be requested again from the checkpoint
+var ErrIteratorExpired = errors.New("shard iterator expired")
+
+// StreamShard is one shard of a table's stream
+type StreamShard struct {
+	ID       string
+	ParentID string
+}
+
+// StreamRecord is a change read from a stream with its images already in
+// the DynamoDB attribute value format used by the rest of the DAL
+type StreamRecord struct {
+	EventID        string
+	EventName      models.ChangeType
+	SequenceNumber string
+	CreatedAt      time.Time
+	Keys           map[string]types.AttributeValue
+	OldImage       map[string]types.AttributeValue
+	NewImage       map[string]types.AttributeValue
+}
+
+// StreamSource reads the change streams of DynamoDB tables. It is
+// implemented by DynamoStreamSource against AWS and by FakeStream in memory.
+type StreamSource interface {
+	// StreamArn returns the current stream of the table
+	StreamArn(ctx context.Context, tableName string) (string, error)
+	// Shards lists the shards of a stream, parents before children
+	Shards(ctx context.Context, streamArn string) ([]StreamShard, error)
+	// ShardIterator returns an iterator positioned after sequenceNumber, or
+	// at the oldest record when sequenceNumber is empty
+	ShardIterator(ctx context.Context, streamArn, shardID, sequenceNumber string) (string, error)
+	// Records reads up to limit records and returns the iterator to continue
+	// from. The next iterator is empty once a closed shard is exhausted.
+	Records(ctx context.Context, iterator string, limit int) ([]StreamRecord, string, error)
+}
+
+// DynamoStreamSource reads DynamoDB Streams through the AWS SDK
+type DynamoStreamSource struct {
+	db      dal.DatabaseClientInterface
+	streams *dynamodbstreams.Client
+}
+
+// NewDynamoStreamSource creates a stream source for the configured account
+// and endpoint
+func NewDynamoStreamSource(ctx context.Context, db dal.DatabaseClientInterface, cfg *models.Config) (*DynamoStreamSource, error) {
+	awsCfg, err := dal.LoadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DynamoStreamSource{
+		db:      db,
+		streams: dynamodbstreams.NewFromConfig(awsCfg),
+	}, nil
+}
+
+// StreamArn returns the latest stream of the table
+func (s *DynamoStreamSource) StreamArn(ctx context.Context, tableName string) (string, error) {
+	desc, err := s.db.DescribeTable(ctx, tableName)
+	if err != nil {
+		return "", err
+	}
+
+	if desc.Table == nil || desc.Table.LatestStreamArn == nil {
+		return "", fmt.Errorf("streams are not enabled on %s", tableName)
+	}
+	return *desc.Table.LatestStreamArn, nil
+}
+
+// Shards lists every shard of the stream, following pagination
+func (s *DynamoStreamSource) Shards(ctx context.Context, streamArn string) ([]StreamShard, error) {
+	var shards []StreamShard
+	input := &dynamodbstreams.DescribeStreamInput{StreamArn: aws.String(streamArn)}
+
+	for {
+		output, err := s.streams.DescribeStream(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, shard := range output.StreamDescription.Shards {
+			shards = append(shards, StreamShard{
+				ID:       aws.ToString(shard.ShardId),
+				ParentID: aws.ToString(shard.ParentShardId),
+			})
+		}
+
+		if output.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		input.ExclusiveStartShardId = output.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// ShardIterator returns an iterator after sequenceNumber, or at the trim
+// horizon when there is no checkpoint yet
+func (s *DynamoStreamSource) ShardIterator(ctx context.Context, streamArn, shardID, sequenceNumber string) (string, error) {
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: streamtypes.ShardIteratorTypeTrimHorizon,
+	}
+	if sequenceNumber != "" {
+		input.ShardIteratorType = streamtypes.ShardIteratorTypeAfterSequenceNumber
+		input.SequenceNumber = aws.String(sequenceNumber)
+	}
+
+	output, err := s.streams.GetShardIterator(ctx, input)
+	if err != nil {
+		// The checkpointed record has aged out of the stream, so resume
+		// from the oldest record still available
+		var trimmed *streamtypes.TrimmedDataAccessException
+		if sequenceNumber != "" && errors.As(err, &trimmed) {
+			return s.ShardIterator(ctx, streamArn, shardID, "")
+		}
+		return "", err
+	}
+	return aws.ToString(output.ShardIterator), nil
+}
+
+// Records reads the next batch of records from the shard
+func (s *DynamoStreamSource) Records(ctx context.Context, iterator string, limit int) ([]StreamRecord, string, error) {
+	input := &dynamodbstreams.GetRecordsInput{ShardIterator: aws.String(iterator)}
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(limit))
+	}
+
+	output, err := s.streams.GetRecords(ctx, input)
+	if err != nil {
+		var expired *streamtypes.ExpiredIteratorException
+		if errors.As(err, &expired) {
+			return nil, "", ErrIteratorExpired
+		}
+		return nil, "", err
+	}
+
+	records := make([]StreamRecord, 0, len(output.Records))
+	for _, record := range output.Records {
+		converted, err := convertStreamRecord(record)
+		if err != nil {
+			return nil, "", err
+		}
+		records = append(records, converted)
+	}
+
+	return records, aws.ToString(output.NextShardIterator), nil
+}
+
+// convertStreamRecord turns an SDK stream record into a StreamRecord
+func convertStreamRecord(record streamtypes.Record) (StreamRecord, error) {
+	converted := StreamRecord{
+		EventID:   aws.ToString(record.EventID),
+		EventName: models.ChangeType(record.EventName),
+	}
+
+	change := record.Dynamodb
+	if change == nil {
+		return converted, nil
+	}
+
+	converted.SequenceNumber = aws.ToString(change.SequenceNumber)
+	if change.ApproximateCreationDateTime != nil {
+		converted.CreatedAt = *change.ApproximateCreationDateTime
+	}
+
+	var err error
+	if converted.Keys, err = attributevalue.FromDynamoDBStreamsMap(change.Keys); err != nil {
+		return converted, fmt.Errorf("failed to convert keys of %s: %w", converted.EventID, err)
+	}
+	if converted.OldImage, err = attributevalue.FromDynamoDBStreamsMap(change.OldImage); err != nil {
+		return converted, fmt.Errorf("failed to convert old image of %s: %w", converted.EventID, err)
+	}
+	if converted.NewImage, err = attributevalue.FromDynamoDBStreamsMap(change.NewImage); err != nil {
+		return converted, fmt.Errorf("failed to convert new image of %s: %w", converted.EventID, err)
+	}
+	return converted, nil
+}
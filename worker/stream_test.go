@@ -0,0 +1,196 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jobsTableName is the deployed jobs table of the test config
+const jobsTableName = "ff_jobs"
+
+func newTestStreamConsumer(stream *FakeStream, checkpoints CheckpointStore) *StreamConsumer {
+	cfg := &models.Config{DynamoDBTablePrefix: "ff", StreamBatchSize: 2, StreamPollIntervalMs: 5}
+	return NewStreamConsumer(stream, checkpoints, cfg, logger.NewLogger("error", "json"))
+}
+
+// jobRecorder collects the IDs of the jobs passed to its handler in order
+type jobRecorder struct {
+	mu   sync.Mutex
+	ids  []string
+	fail func(id string) error
+}
+
+func (r *jobRecorder) handle(ctx context.Context, event models.ChangeEvent[models.Job]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := event.NewImage.JobID
+	if r.fail != nil {
+		if err := r.fail(id); err != nil {
+			return err
+		}
+	}
+	r.ids = append(r.ids, id)
+	return nil
+}
+
+// wait returns the IDs handled once there are n of them
+func (r *jobRecorder) wait(t *testing.T, n int) []string {
+	t.Helper()
+	var ids []string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		ids = append([]string(nil), r.ids...)
+		r.mu.Unlock()
+		if len(ids) >= n {
+			return ids
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("handled %v, want %d jobs", ids, n)
+	return nil
+}
+
+func insertJobs(t *testing.T, stream *FakeStream, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := stream.Insert(jobsTableName, &models.Job{JobID: id}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStreamConsumerResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	stream := NewFakeStream()
+	insertJobs(t, stream, "job-1", "job-2", "job-3", "job-4")
+
+	// A previous run got through job-2
+	streamArn, _ := stream.StreamArn(ctx, jobsTableName)
+	checkpoints := NewMemoryCheckpointStore()
+	if err := checkpoints.SaveCheckpoint(ctx, streamArn, "shard-0000", "000000000000000000002"); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+
+	consumer := newTestStreamConsumer(stream, checkpoints)
+	recorder := &jobRecorder{}
+	consumer.OnJobChange(recorder.handle)
+	if err := consumer.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer consumer.Stop()
+
+	if got := recorder.wait(t, 2); !equalIDs(got, []string{"job-3", "job-4"}) {
+		t.Fatalf("handled %v, want [job-3 job-4]", got)
+	}
+
+	waitForCheckpoint(t, checkpoints, streamArn, "shard-0000", "000000000000000000004")
+}
+
+func TestStreamConsumerReadsParentShardFirst(t *testing.T) {
+	ctx := context.Background()
+	stream := NewFakeStream()
+	insertJobs(t, stream, "job-1", "job-2", "job-3")
+	stream.SplitShard(jobsTableName)
+	insertJobs(t, stream, "job-4", "job-5")
+	stream.SplitShard(jobsTableName)
+	insertJobs(t, stream, "job-6")
+
+	consumer := newTestStreamConsumer(stream, NewMemoryCheckpointStore())
+	recorder := &jobRecorder{}
+	consumer.OnJobChange(recorder.handle)
+	if err := consumer.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer consumer.Stop()
+
+	want := []string{"job-1", "job-2", "job-3", "job-4", "job-5", "job-6"}
+	if got := recorder.wait(t, len(want)); !equalIDs(got, want) {
+		t.Fatalf("handled %v, want %v", got, want)
+	}
+}
+
+func TestStreamConsumerRetriesFailedRecord(t *testing.T) {
+	ctx := context.Background()
+	stream := NewFakeStream()
+	insertJobs(t, stream, "job-1", "job-2", "job-3")
+
+	checkpoints := NewMemoryCheckpointStore()
+	consumer := newTestStreamConsumer(stream, checkpoints)
+	failures := 0
+	recorder := &jobRecorder{fail: func(id string) error {
+		if id == "job-2" && failures == 0 {
+			failures++
+			return errors.New("index unavailable")
+		}
+		return nil
+	}}
+	consumer.OnJobChange(recorder.handle)
+	if err := consumer.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer consumer.Stop()
+
+	// The shard stops at job-2 with job-1 checkpointed
+	recorder.wait(t, 1)
+	streamArn, _ := stream.StreamArn(ctx, jobsTableName)
+	waitForShard(t, consumer, streamArn, "shard-0000")
+	waitForCheckpoint(t, checkpoints, streamArn, "shard-0000", "000000000000000000001")
+
+	// The next shard refresh picks the shard up again at the failed record
+	consumer.startShards(consumer.ctx, repository.JobsTable.Name, jobsTableName, streamArn)
+	want := []string{"job-1", "job-2", "job-3"}
+	if got := recorder.wait(t, len(want)); !equalIDs(got, want) {
+		t.Fatalf("handled %v, want %v", got, want)
+	}
+}
+
+// waitForShard waits until the consumer stopped reading the shard
+func waitForShard(t *testing.T, consumer *StreamConsumer, streamArn, shardID string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		consumer.mu.Lock()
+		tailing := consumer.tailing[shardKey(streamArn, shardID)]
+		consumer.mu.Unlock()
+		if !tailing {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("shard %s is still being read", shardID)
+}
+
+// waitForCheckpoint waits until the shard is checkpointed at sequence
+func waitForCheckpoint(t *testing.T, checkpoints CheckpointStore, streamArn, shardID, sequence string) {
+	t.Helper()
+	var got string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		got, _ = checkpoints.Checkpoint(context.Background(), streamArn, shardID)
+		if got == sequence {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("checkpoint of %s = %q, want %q", shardID, got, sequence)
+}
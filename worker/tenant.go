@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fieldfuze-backend/dal"
+	"fieldfuze-backend/models"
+	"fieldfuze-backend/repository"
+	"fieldfuze-backend/utils/logger"
+	"fieldfuze-backend/utils/requestctx"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// TenantProvisioner creates the tables of every organization in isolated
+// tenancy mode. Organizations are provisioned as soon as their creation is
+// seen on the change stream, and a scheduled reconcile covers organizations
+// missed while the stream consumer was down or disabled.
+type TenantProvisioner struct {
+	setup  *InfrastructureSetup
+	db     dal.DatabaseClientInterface
+	config *models.Config
+	logger logger.Logger
+	cron   *cron.Cron
+
+	mu          sync.Mutex
+	provisioned map[string]bool
+}
+
+// NewTenantProvisioner creates a tenant table provisioner
+func NewTenantProvisioner(cfg *models.Config, log logger.Logger) (*TenantProvisioner, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config cannot be nil")
+	}
+
+	dbClient, err := dal.NewDynamoDBClient(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	return &TenantProvisioner{
+		setup: &InfrastructureSetup{
+			InfrastructureSetup: models.InfrastructureSetup{
+				Config:   cfg,
+				Logger:   log,
+				DBClient: dbClient,
+			},
+		},
+		db:          dal.NewResilientClient(dbClient, cfg, log),
+		config:      cfg,
+		logger:      log,
+		cron:        cron.New(),
+		provisioned: make(map[string]bool),
+	}, nil
+}
+
+// Start provisions the existing organizations and schedules the reconcile.
+// It does nothing unless tenancy mode is isolated.
+func (t *TenantProvisioner) Start() error {
+	if t.config.TenancyMode != models.TenancyIsolated {
+		t.logger.Info("Shared tenancy mode, tenant provisioner not started")
+		return nil
+	}
+
+	t.reconcileJob()
+
+	if err := t.cron.AddFunc(t.config.TenantProvisionSchedule, t.reconcileJob); err != nil {
+		return fmt.Errorf("failed to add tenant provision job: %w", err)
+	}
+	t.cron.Start()
+
+	t.logger.Infof("Tenant provisioner started with schedule %s", t.config.TenantProvisionSchedule)
+	return nil
+}
+
+// Stop stops the scheduled reconcile
+func (t *TenantProvisioner) Stop() {
+	t.cron.Stop()
+}
+
+// OnOrganizationChange provisions the tables of newly created organizations.
+// It is registered with the stream consumer.
+func (t *TenantProvisioner) OnOrganizationChange(ctx context.Context, event models.OrganizationChangeEvent) error {
+	if t.config.TenancyMode != models.TenancyIsolated || event.Type != models.ChangeInsert || event.NewImage == nil {
+		return nil
+	}
+	return t.ProvisionTenant(ctx, event.NewImage.ID)
+}
+
+// reconcileJob is the cron entry point for Reconcile
+func (t *TenantProvisioner) reconcileJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if err := t.Reconcile(ctx); err != nil {
+		t.logger.Errorf("Tenant provisioning finished with errors: %v", err)
+	}
+}
+
+// Reconcile provisions every organization whose tables were not provisioned
+// by this process yet
+func (t *TenantProvisioner) Reconcile(ctx context.Context) error {
+	orgIDs, err := repository.TenantIDs(ctx, t.db, t.config)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, orgID := range orgIDs {
+		if err := t.ProvisionTenant(ctx, orgID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ProvisionTenant creates the tenant-scoped tables of orgID, waits for them
+// to become active and enables TTL on the soft-deletable ones. Tables that
+// already exist are left as they are.
+func (t *TenantProvisioner) ProvisionTenant(ctx context.Context, orgID string) error {
+	t.mu.Lock()
+	done := t.provisioned[orgID]
+	t.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	tenantCtx := requestctx.WithTenant(ctx, orgID)
+
+	var tables []*models.TableInfo
+	for _, table := range repository.TenantTables() {
+		tableName, err := repository.ResolveTableName(tenantCtx, t.config, table)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s table of organization %s: %w", table.Name, orgID, err)
+		}
+
+		tableInfo := &models.TableInfo{Name: tableName}
+		if err := t.setup.createTableWithRetry(ctx, tableInfo); err != nil {
+			return fmt.Errorf("failed to provision organization %s: %w", orgID, err)
+		}
+		tables = append(tables, tableInfo)
+	}
+
+	if err := t.setup.waitForTablesActive(ctx, tables); err != nil {
+		return fmt.Errorf("failed to provision organization %s: %w", orgID, err)
+	}
+
+	if t.config.SoftDeleteRetentionDays > 0 {
+		for _, table := range repository.TenantTables() {
+			if !table.SoftDelete {
+				continue
+			}
+			tableName := table.FullName(repository.TenantTablePrefix(t.config, orgID))
+			if err := t.db.EnableTTL(ctx, tableName, repository.PurgeAtAttribute); err != nil {
+				t.logger.Warnf("Failed to enable TTL on %s: %v", tableName, err)
+			}
+		}
+	}
+
+	t.mu.Lock()
+	t.provisioned[orgID] = true
+	t.mu.Unlock()
+
+	t.logger.Infof("Provisioned tables of organization %s", orgID)
+	return nil
+}